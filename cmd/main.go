@@ -6,13 +6,14 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
-	
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/joshsoftware/code-curiosity-2025/internal/app"
 	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/db/migrations"
 )
 
 func main() {
@@ -32,10 +33,45 @@ func main() {
 	}
 	defer db.Close()
 
-	dependencies := app.InitDependencies(db,cfg)
+	if cfg.Database.AutoMigrate {
+		err = migrations.Up(ctx, db, cfg)
+		if err != nil {
+			slog.Error("error applying migrations", "error", err)
+			return
+		}
+	}
+
+	err = migrations.CheckVersion(cfg)
+	if err != nil {
+		slog.Error("refusing to serve with an outdated database schema", "error", err)
+		return
+	}
+
+	dependencies, err := app.InitDependencies(db, cfg)
+	if err != nil {
+		slog.Error("error initializing dependencies", "error", err)
+		return
+	}
 
 	router := app.NewRouter(dependencies)
 
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	var workers sync.WaitGroup
+
+	workers.Add(3)
+	go func() {
+		defer workers.Done()
+		dependencies.JobService.Run(workerCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		dependencies.EventBus.Run(workerCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		dependencies.SchedulerService.Run(workerCtx)
+	}()
+
 	server := http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.HTTPServer.Port),
 		Handler: router,
@@ -71,5 +107,8 @@ func main() {
 		slog.Error("cannot shut HTTP server down gracefully", "error", err)
 	}
 
+	stopWorkers()
+	workers.Wait()
+
 	slog.Info("server shutdown successfully")
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/githubtoken"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/envelope"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/github"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+// rotatekeys re-encrypts every stored GitHub token under the active key in
+// encryption.active_key_id. Run it after adding a new key to the config and
+// before removing the old one.
+func main() {
+	ctx := context.Background()
+
+	cfg, err := config.LoadAppConfig()
+	if err != nil {
+		slog.Error("error loading app config", "error", err)
+		os.Exit(1)
+	}
+
+	db, err := config.InitDataStore(cfg)
+	if err != nil {
+		slog.Error("error initializing database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	tokenEnvelope, err := envelope.New(cfg)
+	if err != nil {
+		slog.Error("error initializing encryption", "error", err)
+		os.Exit(1)
+	}
+
+	githubTokenService := githubtoken.NewService(repository.NewGithubTokenRepository(db), tokenEnvelope, github.NewMemoryETagStore(1), github.NewTrackers(), cfg)
+
+	rotated, err := githubTokenService.RotateKeys(ctx)
+	if err != nil {
+		slog.Error("key rotation failed", "rotated", rotated, "error", err)
+		db.Close()
+		os.Exit(1)
+	}
+
+	slog.Info("key rotation completed", "rotated", rotated, "active_key_id", tokenEnvelope.ActiveKeyID())
+}
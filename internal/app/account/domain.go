@@ -0,0 +1,62 @@
+package account
+
+import (
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+)
+
+const (
+	AnonymizeUserJob = "account.anonymize_user"
+
+	ExportFileName = "code-curiosity-export.zip"
+)
+
+type AnonymizeUserPayload struct {
+	UserId int `json:"user_id"`
+}
+
+// Export holds everything stored about a user. Each field is written to its
+// own JSON file in the export archive.
+type Export struct {
+	Profile       Profile                     `json:"profile"`
+	Contributions []contribution.Contribution `json:"contributions"`
+	Transactions  []Transaction               `json:"transactions"`
+	Badges        []Badge                     `json:"badges"`
+	Summaries     []Summary                   `json:"summaries"`
+}
+
+type Profile struct {
+	Id             int       `json:"user_id"`
+	GithubId       int       `json:"github_id"`
+	GithubUsername string    `json:"github_username"`
+	Email          string    `json:"email"`
+	AvatarUrl      string    `json:"avatar_url"`
+	CurrentBalance int       `json:"current_balance"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type Transaction struct {
+	Id                int       `json:"id"`
+	ContributionId    *int64    `json:"contribution_id"`
+	RedemptionId      *int64    `json:"redemption_id"`
+	IsRedeemed        bool      `json:"is_redeemed"`
+	IsGained          bool      `json:"is_gained"`
+	TransactedBalance int       `json:"transacted_balance"`
+	TransactedAt      time.Time `json:"transacted_at"`
+}
+
+type Badge struct {
+	Id        int       `json:"id"`
+	BadgeType string    `json:"badge_type"`
+	EarnedAt  time.Time `json:"earned_at"`
+}
+
+type Summary struct {
+	Id          int `json:"id"`
+	MonthYear   int `json:"month_year"`
+	NetBalance  int `json:"net_balance"`
+	BadgesCount int `json:"badges_count"`
+	Rank        int `json:"rank"`
+}
@@ -0,0 +1,104 @@
+package account
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	accountService Service
+}
+
+type Handler interface {
+	DeleteAccount(w http.ResponseWriter, r *http.Request)
+	ExportData(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(accountService Service) Handler {
+	return &handler{
+		accountService: accountService,
+	}
+}
+
+func (h *handler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := h.accountService.DeleteAccount(ctx)
+	if err != nil {
+		slog.Error("failed to delete account", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "account deleted successfully", nil)
+}
+
+// ExportData responds with a ZIP archive holding one JSON file per kind of
+// data stored about the user. The archive is built before anything is
+// written, so a failure still gets a proper error response.
+func (h *handler) ExportData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	export, err := h.accountService.ExportData(ctx)
+	if err != nil {
+		slog.Error("failed to export account data", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"contributions.json", export.Contributions},
+		{"transactions.json", export.Transactions},
+		{"badges.json", export.Badges},
+		{"summaries.json", export.Summaries},
+	}
+
+	for _, file := range files {
+		fileWriter, err := archive.Create(file.name)
+		if err != nil {
+			slog.Error("failed to add file to export archive", "file", file.name, "error", err)
+			response.WriteJson(w, http.StatusInternalServerError, apperrors.ErrInternalServer.Error(), nil)
+			return
+		}
+
+		encoder := json.NewEncoder(fileWriter)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(file.data)
+		if err != nil {
+			slog.Error("failed to write export file", "file", file.name, "error", err)
+			response.WriteJson(w, http.StatusInternalServerError, apperrors.ErrInternalServer.Error(), nil)
+			return
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		slog.Error("failed to finish export archive", "error", err)
+		response.WriteJson(w, http.StatusInternalServerError, apperrors.ErrInternalServer.Error(), nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", ExportFileName))
+	w.Header().Set("Content-Length", strconv.Itoa(buffer.Len()))
+	_, err = buffer.WriteTo(w)
+	if err != nil {
+		slog.Error("failed to send export archive", "error", err)
+	}
+}
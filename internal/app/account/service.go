@@ -0,0 +1,224 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/job"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/team"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/user"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	userRepository        repository.UserRepository
+	githubTokenRepository repository.GithubTokenRepository
+	leaderboardRepository repository.LeaderboardRepository
+	transactionRepository repository.TransactionRepository
+	badgeRepository       repository.BadgeRepository
+	summaryRepository     repository.SummaryRepository
+	userService           user.Service
+	contributionService   contribution.Service
+	teamService           team.Service
+	jobService            job.Service
+	gracePeriod           time.Duration
+}
+
+type Service interface {
+	DeleteAccount(ctx context.Context) error
+	AnonymizeUser(ctx context.Context, payload AnonymizeUserPayload) error
+	ExportData(ctx context.Context) (Export, error)
+}
+
+func NewService(userRepository repository.UserRepository, githubTokenRepository repository.GithubTokenRepository, leaderboardRepository repository.LeaderboardRepository, transactionRepository repository.TransactionRepository, badgeRepository repository.BadgeRepository, summaryRepository repository.SummaryRepository, userService user.Service, contributionService contribution.Service, teamService team.Service, jobService job.Service, appCfg config.AppConfig) Service {
+	return &service{
+		userRepository:        userRepository,
+		githubTokenRepository: githubTokenRepository,
+		leaderboardRepository: leaderboardRepository,
+		transactionRepository: transactionRepository,
+		badgeRepository:       badgeRepository,
+		summaryRepository:     summaryRepository,
+		userService:           userService,
+		contributionService:   contributionService,
+		teamService:           teamService,
+		jobService:            jobService,
+		gracePeriod:           appCfg.Accounts.DeletionGracePeriod,
+	}
+}
+
+// DeleteAccount soft-deletes the logged in user, revokes their sessions and
+// GitHub token, removes them from the leaderboard and their teams and
+// schedules the anonymization of their personal data once the grace period
+// is over.
+func (s *service) DeleteAccount(ctx context.Context) (err error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	tx, err := s.userRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		txErr := s.userRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	err = s.userRepository.SoftDeleteUser(ctx, tx, userId)
+	if err != nil {
+		slog.Error("failed to delete user", "user_id", userId, "error", err)
+		return err
+	}
+
+	err = s.githubTokenRepository.MarkGithubTokenInvalid(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+
+	err = s.leaderboardRepository.DeleteUserLeaderboardEntries(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+
+	err = s.teamService.RemoveUser(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.jobService.Enqueue(ctx, tx, AnonymizeUserJob, AnonymizeUserPayload{UserId: userId}, job.EnqueueOptions{
+		UniqueKey: fmt.Sprintf("anonymize_user:%d", userId),
+		RunAt:     time.Now().Add(s.gracePeriod),
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("user account deleted", "user_id", userId, "anonymize_after", s.gracePeriod)
+	return nil
+}
+
+// AnonymizeUser is the job handler scrubbing personal data of a deleted
+// user. Contributions and transactions are kept so past leaderboards and
+// summaries stay consistent, but no longer point to an identifiable person.
+func (s *service) AnonymizeUser(ctx context.Context, payload AnonymizeUserPayload) (err error) {
+	tx, err := s.userRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		txErr := s.userRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	err = s.userRepository.AnonymizeUser(ctx, tx, payload.UserId)
+	if err != nil {
+		return err
+	}
+
+	err = s.githubTokenRepository.DeleteGithubToken(ctx, tx, payload.UserId)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("user anonymized", "user_id", payload.UserId)
+	return nil
+}
+
+func (s *service) ExportData(ctx context.Context) (Export, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Export{}, apperrors.ErrInternalServer
+	}
+
+	userInfo, err := s.userService.GetUserById(ctx, userId)
+	if err != nil {
+		return Export{}, err
+	}
+
+	contributions, err := s.contributionService.GetContributionsByUserId(ctx, userId)
+	if err != nil {
+		return Export{}, err
+	}
+
+	transactions, err := s.transactionRepository.GetTransactionsByUserId(ctx, nil, userId)
+	if err != nil {
+		return Export{}, err
+	}
+
+	badges, err := s.badgeRepository.GetBadgesByUserId(ctx, nil, userId)
+	if err != nil {
+		return Export{}, err
+	}
+
+	summaries, err := s.summaryRepository.GetSummariesByUserId(ctx, nil, userId)
+	if err != nil {
+		return Export{}, err
+	}
+
+	export := Export{
+		Profile: Profile{
+			Id:             userInfo.Id,
+			GithubId:       userInfo.GithubId,
+			GithubUsername: userInfo.GithubUsername,
+			Email:          userInfo.Email,
+			AvatarUrl:      userInfo.AvatarUrl,
+			CurrentBalance: userInfo.CurrentBalance,
+			CreatedAt:      userInfo.CreatedAt,
+			UpdatedAt:      userInfo.UpdatedAt,
+		},
+		Contributions: contributions,
+		Transactions:  make([]Transaction, 0, len(transactions)),
+		Badges:        make([]Badge, 0, len(badges)),
+		Summaries:     make([]Summary, 0, len(summaries)),
+	}
+
+	for _, transaction := range transactions {
+		exported := Transaction{
+			Id:                transaction.Id,
+			IsRedeemed:        transaction.IsRedeemed,
+			IsGained:          transaction.IsGained,
+			TransactedBalance: transaction.TransactedBalance,
+			TransactedAt:      transaction.TransactedAt,
+		}
+		if transaction.ContributionId.Valid {
+			exported.ContributionId = &transaction.ContributionId.Int64
+		}
+		if transaction.RedemptionId.Valid {
+			exported.RedemptionId = &transaction.RedemptionId.Int64
+		}
+		export.Transactions = append(export.Transactions, exported)
+	}
+
+	for _, badge := range badges {
+		export.Badges = append(export.Badges, Badge{
+			Id:        badge.Id,
+			BadgeType: badge.BadgeType,
+			EarnedAt:  badge.EarnedAt,
+		})
+	}
+
+	for _, summary := range summaries {
+		export.Summaries = append(export.Summaries, Summary{
+			Id:          summary.Id,
+			MonthYear:   summary.MonthYear,
+			NetBalance:  summary.NetBalance,
+			BadgesCount: summary.BadgesCount,
+			Rank:        summary.Rank,
+		})
+	}
+
+	return export, nil
+}
@@ -6,12 +6,13 @@ import (
 )
 
 const (
-	LoginWithGithubFailed = "LoginWithGithubFailed"
-	AccessTokenCookieName = "AccessToken"
-	GitHubOAuthState      = "state"
-	GithubOauthScope      = "read:user"
-	GetUserGithubUrl      = "https://api.github.com/user"
-	GetUserEmailUrl       = "https://api.github.com/user/emails"
+	LoginWithGithubFailed  = "LoginWithGithubFailed"
+	AccountPendingDeletion = "AccountPendingDeletion"
+	AccessTokenCookieName  = "AccessToken"
+	GitHubOAuthState       = "state"
+	GithubOauthScope       = "read:user repo"
+	GetUserGithubUrl       = "https://api.github.com/user"
+	GetUserEmailUrl        = "https://api.github.com/user/emails"
 )
 
 type User struct {
@@ -22,19 +23,33 @@ type User struct {
 	AvatarUrl           string        `json:"avatar_url"`
 	CurrentBalance      int           `json:"current_balance"`
 	CurrentActiveGoalId sql.NullInt64 `json:"current_active_goal_id"`
-	IsBlocked           bool          `json:"is_blocked"`
-	IsAdmin             bool          `json:"is_admin"`
-	Password            string        `json:"password"`
-	IsDeleted           bool          `json:"is_deleted"`
-	DeletedAt           sql.NullTime  `json:"deleted_at"`
+	IsBlocked           bool          `json:"-"`
+	Password            string        `json:"-"`
+	IsDeleted           bool          `json:"-"`
+	DeletedAt           sql.NullTime  `json:"-"`
+	Timezone            string        `json:"timezone"`
 	CreatedAt           time.Time     `json:"created_at"`
 	UpdatedAt           time.Time     `json:"updated_at"`
 }
 
+// LoggedInUser is the response DTO for the current user. Credentials and
+// moderation flags are never part of it.
+type LoggedInUser struct {
+	Id                  int       `json:"user_id"`
+	GithubId            int       `json:"github_id"`
+	GithubUsername      string    `json:"github_username"`
+	Email               string    `json:"email"`
+	AvatarUrl           string    `json:"avatar_url"`
+	CurrentBalance      int       `json:"current_balance"`
+	CurrentActiveGoalId *int64    `json:"current_active_goal_id"`
+	Timezone            string    `json:"timezone"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
 type GithubUserResponse struct {
 	GithubId       int    `json:"id"`
 	GithubUsername string `json:"login"`
 	AvatarUrl      string `json:"avatar_url"`
 	Email          string `json:"email"`
-	IsAdmin        bool   `json:"is_admin"`
 }
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -44,7 +45,11 @@ func (h *handler) GithubOAuthLoginCallback(w http.ResponseWriter, r *http.Reques
 	token, err := h.authService.GithubOAuthLoginCallback(ctx, code)
 	if err != nil {
 		slog.Error("failed to login with github", "error", err)
-		http.Redirect(w, r, fmt.Sprintf("%s?authError=%s", h.appConfig.ClientURL, LoginWithGithubFailed), http.StatusTemporaryRedirect)
+		authError := LoginWithGithubFailed
+		if errors.Is(err, apperrors.ErrAccountPendingDeletion) {
+			authError = AccountPendingDeletion
+		}
+		http.Redirect(w, r, fmt.Sprintf("%s?authError=%s", h.appConfig.ClientURL, authError), http.StatusTemporaryRedirect)
 		return
 	}
 
@@ -70,5 +75,24 @@ func (h *handler) GetLoggedInUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.WriteJson(w, http.StatusOK, "logged in user fetched successfully", userInfo)
+	response.WriteJson(w, http.StatusOK, "logged in user fetched successfully", newLoggedInUser(userInfo))
+}
+
+func newLoggedInUser(userInfo User) LoggedInUser {
+	loggedInUser := LoggedInUser{
+		Id:             userInfo.Id,
+		GithubId:       userInfo.GithubId,
+		GithubUsername: userInfo.GithubUsername,
+		Email:          userInfo.Email,
+		AvatarUrl:      userInfo.AvatarUrl,
+		CurrentBalance: userInfo.CurrentBalance,
+		Timezone:       userInfo.Timezone,
+		CreatedAt:      userInfo.CreatedAt,
+		UpdatedAt:      userInfo.UpdatedAt,
+	}
+	if userInfo.CurrentActiveGoalId.Valid {
+		loggedInUser.CurrentActiveGoalId = &userInfo.CurrentActiveGoalId.Int64
+	}
+
+	return loggedInUser
 }
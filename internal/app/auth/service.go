@@ -2,12 +2,14 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"log/slog"
 
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/githubtoken"
 	"github.com/joshsoftware/code-curiosity-2025/internal/app/user"
 	"github.com/joshsoftware/code-curiosity-2025/internal/config"
 	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	ghclient "github.com/joshsoftware/code-curiosity-2025/internal/pkg/github"
 	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/jwt"
 	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
 	"golang.org/x/oauth2"
@@ -15,9 +17,11 @@ import (
 )
 
 type service struct {
-	githubOAuth2 oauth2.Config
-	userService  user.Service
-	appCfg       config.AppConfig
+	githubOAuth2       oauth2.Config
+	userService        user.Service
+	githubTokenService githubtoken.Service
+	etagStore          ghclient.ETagStore
+	appCfg             config.AppConfig
 }
 
 type Service interface {
@@ -26,7 +30,7 @@ type Service interface {
 	GetLoggedInUser(ctx context.Context) (User, error)
 }
 
-func NewService(userService user.Service, appCfg config.AppConfig) Service {
+func NewService(userService user.Service, githubTokenService githubtoken.Service, etagStore ghclient.ETagStore, appCfg config.AppConfig) Service {
 	oauth2Config := oauth2.Config{
 		ClientID:     appCfg.GithubOauth.ClientID,
 		ClientSecret: appCfg.GithubOauth.ClientSecret,
@@ -36,9 +40,11 @@ func NewService(userService user.Service, appCfg config.AppConfig) Service {
 	}
 
 	return &service{
-		githubOAuth2: oauth2Config,
-		userService:  userService,
-		appCfg:       appCfg,
+		githubOAuth2:       oauth2Config,
+		userService:        userService,
+		githubTokenService: githubTokenService,
+		etagStore:          etagStore,
+		appCfg:             appCfg,
 	}
 }
 
@@ -53,23 +59,26 @@ func (s *service) GithubOAuthLoginCallback(ctx context.Context, code string) (st
 		return "", apperrors.ErrGithubTokenExchangeFailed
 	}
 
-	client := s.githubOAuth2.Client(ctx, token)
-	resp, err := client.Get(GetUserGithubUrl)
+	// the user is not known until this request answers, so the freshly
+	// exchanged token starts without rate limit state
+	client := ghclient.NewClient(ghclient.NewOAuthTokenSource(token), s.etagStore, nil)
+
+	var userInfo GithubUserResponse
+	_, err = client.Get(ctx, GetUserGithubUrl, &userInfo)
 	if err != nil {
 		slog.Error("failed to get user info", "error", err)
 		return "", apperrors.ErrFailedToGetGithubUser
 	}
-	defer resp.Body.Close()
 
-	var userInfo GithubUserResponse
-	err = json.NewDecoder(resp.Body).Decode(&userInfo)
+	// deleted users keep their GitHub id until they are anonymized, so they
+	// cannot sign up again before then
+	userData, err := s.userService.GetUserByGithubIdIncludingDeleted(ctx, userInfo.GithubId)
 	if err != nil {
-		slog.Error("failed to unmarshal user info", "error", err)
-		return "", apperrors.ErrInternalServer
-	}
+		if !errors.Is(err, apperrors.ErrUserNotFound) {
+			slog.Error("failed to get user by github id", "error", err)
+			return "", err
+		}
 
-	userData, err := s.userService.GetUserByGithubId(ctx, userInfo.GithubId)
-	if err != nil {
 		userData, err = s.userService.CreateUser(ctx, user.CreateUserRequestBody(userInfo))
 		if err != nil {
 			slog.Error("failed to create user", "error", err)
@@ -77,7 +86,17 @@ func (s *service) GithubOAuthLoginCallback(ctx context.Context, code string) (st
 		}
 	}
 
-	jwtToken, err := jwt.GenerateJWT(userData.Id, userInfo.IsAdmin, s.appCfg)
+	if userData.IsDeleted {
+		return "", apperrors.ErrAccountPendingDeletion
+	}
+
+	err = s.githubTokenService.SaveToken(ctx, userData.Id, token)
+	if err != nil {
+		slog.Error("failed to store github token", "error", err)
+		return "", apperrors.ErrInternalServer
+	}
+
+	jwtToken, err := jwt.GenerateJWT(userData.Id, s.appCfg)
 	if err != nil {
 		slog.Error("error generating jwt", "error", err)
 		return "", apperrors.ErrInternalServer
@@ -0,0 +1,25 @@
+package badge
+
+import "time"
+
+// badge types, kept in sync with the badges_badge_type_check constraint
+const (
+	FirstContribution = "FirstContribution"
+	BeginnerGoal      = "BeginnerGoal"
+	IntermediateGoal  = "IntermediateGoal"
+	AdvancedGoal      = "AdvancedGoal"
+)
+
+var BadgeTypes = []string{FirstContribution, BeginnerGoal, IntermediateGoal, AdvancedGoal}
+
+// EventSubscriber names the badge subscriber's rows in the event outbox.
+const EventSubscriber = "badges"
+
+type Badge struct {
+	Id        int       `json:"id"`
+	UserId    int       `json:"user_id"`
+	BadgeType string    `json:"badge_type"`
+	EarnedAt  time.Time `json:"earned_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
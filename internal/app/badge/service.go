@@ -0,0 +1,60 @@
+package badge
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/notification"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	badgeRepository     repository.BadgeRepository
+	notificationService notification.Service
+	eventBus            events.Bus
+}
+
+type Service interface {
+	HandleContributionRecorded(ctx context.Context, tx *sqlx.Tx, event events.Event, data events.ContributionRecordedData) error
+}
+
+func NewService(badgeRepository repository.BadgeRepository, notificationService notification.Service, eventBus events.Bus) Service {
+	return &service{
+		badgeRepository:     badgeRepository,
+		notificationService: notificationService,
+		eventBus:            eventBus,
+	}
+}
+
+// HandleContributionRecorded subscribes to recorded contributions and
+// awards the FirstContribution badge. Later contributions find the badge
+// already awarded and do nothing.
+func (s *service) HandleContributionRecorded(ctx context.Context, tx *sqlx.Tx, event events.Event, data events.ContributionRecordedData) error {
+	return s.award(ctx, tx, data.UserId, FirstContribution)
+}
+
+func (s *service) award(ctx context.Context, tx *sqlx.Tx, userId int, badgeType string) error {
+	badge, err := s.badgeRepository.AwardBadge(ctx, tx, userId, badgeType)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrBadgeAlreadyAwarded) {
+			return nil
+		}
+		return err
+	}
+
+	err = s.notificationService.Notify(ctx, tx, userId, notification.TypeBadgeUnlocked, map[string]any{
+		"badge_type": badge.BadgeType,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.eventBus.Publish(ctx, tx, events.New(events.BadgeAwarded, userId, events.BadgeAwardedData{
+		UserId:    userId,
+		BadgeType: badge.BadgeType,
+		EarnedAt:  badge.EarnedAt,
+	}))
+}
@@ -0,0 +1,93 @@
+package challenge
+
+import "time"
+
+// challenge statuses, kept in sync with the challenges_status_check
+// constraint. An open challenge accepts participants until it ends and is
+// finalized by the challenge_finalize task.
+const (
+	StatusOpen      = "open"
+	StatusFinalized = "finalized"
+	StatusCancelled = "cancelled"
+)
+
+var Statuses = []string{StatusOpen, StatusFinalized, StatusCancelled}
+
+// maxMultiplier bounds challenge multipliers well inside the NUMERIC(6, 2)
+// columns storing them.
+const maxMultiplier = 100
+
+type Challenge struct {
+	Id          int64      `json:"id"`
+	Slug        string     `json:"slug"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	Status      string     `json:"status"`
+	FinalizedAt *time.Time `json:"finalized_at"`
+}
+
+// ChallengeDetail describes how a challenge is scored. A contribution is
+// eligible when its repository is listed or has one of the topics, or when
+// its pull request or issue has one of the labels. With none of them set
+// every contribution is eligible.
+type ChallengeDetail struct {
+	Challenge
+	Repositories     []Repository `json:"repositories"`
+	Topics           []string     `json:"topics"`
+	Labels           []string     `json:"labels"`
+	Multiplier       float64      `json:"multiplier"`
+	Multipliers      []Multiplier `json:"multipliers"`
+	Prizes           []Prize      `json:"prizes"`
+	ParticipantCount int          `json:"participant_count"`
+}
+
+type Repository struct {
+	Id       int    `json:"id"`
+	FullName string `json:"full_name"`
+}
+
+// Multiplier replaces the challenge multiplier for one contribution type.
+type Multiplier struct {
+	ContributionType string  `json:"contribution_type"`
+	Multiplier       float64 `json:"multiplier"`
+}
+
+// Prize is paid to every participant ranked RankFrom to RankTo, inclusive.
+type Prize struct {
+	RankFrom int `json:"rank_from"`
+	RankTo   int `json:"rank_to"`
+	Points   int `json:"points"`
+}
+
+type Standing struct {
+	Rank           int    `json:"rank"`
+	UserId         int    `json:"user_id"`
+	GithubUsername string `json:"github_username"`
+	AvatarUrl      string `json:"avatar_url"`
+	Contributions  int    `json:"contributions"`
+	Points         int    `json:"points"`
+	PrizePoints    int    `json:"prize_points"`
+}
+
+// Leaderboard is live until the challenge is finalized, after which
+// IsFinal is set and the standings no longer change.
+type Leaderboard struct {
+	IsFinal   bool       `json:"is_final"`
+	Standings []Standing `json:"standings"`
+}
+
+type CreateChallengeRequest struct {
+	Slug          string       `json:"slug"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	StartsAt      time.Time    `json:"starts_at"`
+	EndsAt        time.Time    `json:"ends_at"`
+	RepositoryIds []int        `json:"repository_ids"`
+	Topics        []string     `json:"topics"`
+	Labels        []string     `json:"labels"`
+	Multiplier    float64      `json:"multiplier"`
+	Multipliers   []Multiplier `json:"multipliers"`
+	Prizes        []Prize      `json:"prizes"`
+}
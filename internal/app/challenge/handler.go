@@ -0,0 +1,149 @@
+package challenge
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	challengeService Service
+}
+
+type Handler interface {
+	CreateChallenge(w http.ResponseWriter, r *http.Request)
+	CancelChallenge(w http.ResponseWriter, r *http.Request)
+	ListChallenges(w http.ResponseWriter, r *http.Request)
+	GetChallenge(w http.ResponseWriter, r *http.Request)
+	GetLeaderboard(w http.ResponseWriter, r *http.Request)
+	JoinChallenge(w http.ResponseWriter, r *http.Request)
+	LeaveChallenge(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(challengeService Service) Handler {
+	return &handler{
+		challengeService: challengeService,
+	}
+}
+
+func (h *handler) CreateChallenge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody CreateChallengeRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	challenge, err := h.challengeService.CreateChallenge(ctx, requestBody)
+	if err != nil {
+		slog.Error("failed to create challenge", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "challenge created successfully", challenge)
+}
+
+func (h *handler) CancelChallenge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := h.challengeService.CancelChallenge(ctx, r.PathValue("slug"))
+	if err != nil {
+		slog.Error("failed to cancel challenge", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "challenge cancelled successfully", nil)
+}
+
+func (h *handler) ListChallenges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	challenges, err := h.challengeService.ListChallenges(ctx, r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		slog.Error("failed to list challenges", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "challenges fetched successfully", challenges)
+}
+
+func (h *handler) GetChallenge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	challenge, err := h.challengeService.GetChallenge(ctx, r.PathValue("slug"))
+	if err != nil {
+		slog.Error("failed to get challenge", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "challenge fetched successfully", challenge)
+}
+
+func (h *handler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	leaderboard, err := h.challengeService.GetLeaderboard(ctx, r.PathValue("slug"), limit, offset)
+	if err != nil {
+		slog.Error("failed to get challenge leaderboard", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "challenge leaderboard fetched successfully", leaderboard)
+}
+
+func (h *handler) JoinChallenge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := h.challengeService.JoinChallenge(ctx, r.PathValue("slug"))
+	if err != nil {
+		slog.Error("failed to join challenge", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "joined challenge successfully", nil)
+}
+
+func (h *handler) LeaveChallenge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := h.challengeService.LeaveChallenge(ctx, r.PathValue("slug"))
+	if err != nil {
+		slog.Error("failed to leave challenge", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "left challenge successfully", nil)
+}
@@ -0,0 +1,576 @@
+package challenge
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/notification"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,48}[a-z0-9]$`)
+
+type service struct {
+	challengeRepository   repository.ChallengeRepository
+	userRepository        repository.UserRepository
+	transactionRepository repository.TransactionRepository
+	notificationService   notification.Service
+	eventBus              events.Bus
+	appCfg                config.AppConfig
+}
+
+type Service interface {
+	CreateChallenge(ctx context.Context, request CreateChallengeRequest) (ChallengeDetail, error)
+	CancelChallenge(ctx context.Context, slug string) error
+	ListChallenges(ctx context.Context, status string, limit int, offset int) ([]Challenge, error)
+	GetChallenge(ctx context.Context, slug string) (ChallengeDetail, error)
+	GetLeaderboard(ctx context.Context, slug string, limit int, offset int) (Leaderboard, error)
+	JoinChallenge(ctx context.Context, slug string) error
+	LeaveChallenge(ctx context.Context, slug string) error
+	FinalizeEndedChallenges(ctx context.Context) (int, error)
+}
+
+func NewService(challengeRepository repository.ChallengeRepository, userRepository repository.UserRepository, transactionRepository repository.TransactionRepository, notificationService notification.Service, eventBus events.Bus, appCfg config.AppConfig) Service {
+	return &service{
+		challengeRepository:   challengeRepository,
+		userRepository:        userRepository,
+		transactionRepository: transactionRepository,
+		notificationService:   notificationService,
+		eventBus:              eventBus,
+		appCfg:                appCfg,
+	}
+}
+
+// CreateChallenge creates an open challenge with its eligible repositories,
+// multipliers and prize tiers. Prize tiers may not overlap.
+func (s *service) CreateChallenge(ctx context.Context, request CreateChallengeRequest) (created ChallengeDetail, err error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return ChallengeDetail{}, apperrors.ErrInternalServer
+	}
+
+	slug := strings.ToLower(strings.TrimSpace(request.Slug))
+	name := strings.TrimSpace(request.Name)
+	if !slugPattern.MatchString(slug) || name == "" {
+		return ChallengeDetail{}, apperrors.ErrInvalidRequestBody
+	}
+
+	if !request.EndsAt.After(request.StartsAt) || !request.EndsAt.After(time.Now()) {
+		return ChallengeDetail{}, apperrors.ErrInvalidChallengeWindow
+	}
+
+	multiplier := request.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	if multiplier < 0 || multiplier > maxMultiplier {
+		return ChallengeDetail{}, apperrors.ErrInvalidRequestBody
+	}
+
+	seenTypes := make(map[string]bool, len(request.Multipliers))
+	for _, typeMultiplier := range request.Multipliers {
+		if !slices.Contains(contribution.ContributionTypes, typeMultiplier.ContributionType) || seenTypes[typeMultiplier.ContributionType] {
+			return ChallengeDetail{}, apperrors.ErrInvalidRequestBody
+		}
+		if typeMultiplier.Multiplier < 0 || typeMultiplier.Multiplier > maxMultiplier {
+			return ChallengeDetail{}, apperrors.ErrInvalidRequestBody
+		}
+		seenTypes[typeMultiplier.ContributionType] = true
+	}
+
+	prizes, ok := sortPrizes(request.Prizes)
+	if !ok {
+		return ChallengeDetail{}, apperrors.ErrInvalidRequestBody
+	}
+
+	topics := normalizeNames(request.Topics)
+	labels := normalizeNames(request.Labels)
+
+	repositoryIds := slices.Clone(request.RepositoryIds)
+	slices.Sort(repositoryIds)
+	repositoryIds = slices.Compact(repositoryIds)
+
+	tx, err := s.challengeRepository.BeginTx(ctx)
+	if err != nil {
+		return ChallengeDetail{}, err
+	}
+
+	defer func() {
+		txErr := s.challengeRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	challenge, err := s.challengeRepository.CreateChallenge(ctx, tx, repository.Challenge{
+		Slug:        slug,
+		Name:        name,
+		Description: strings.TrimSpace(request.Description),
+		StartsAt:    request.StartsAt,
+		EndsAt:      request.EndsAt,
+		Topics:      topics,
+		Labels:      labels,
+		Multiplier:  multiplier,
+		CreatedBy:   userId,
+	})
+	if err != nil {
+		return ChallengeDetail{}, err
+	}
+
+	if len(repositoryIds) > 0 {
+		err = s.challengeRepository.AddChallengeRepositories(ctx, tx, challenge.Id, repositoryIds)
+		if err != nil {
+			return ChallengeDetail{}, err
+		}
+	}
+
+	for _, typeMultiplier := range request.Multipliers {
+		err = s.challengeRepository.CreateChallengeMultiplier(ctx, tx, repository.ChallengeMultiplier{
+			ChallengeId:      challenge.Id,
+			ContributionType: typeMultiplier.ContributionType,
+			Multiplier:       typeMultiplier.Multiplier,
+		})
+		if err != nil {
+			return ChallengeDetail{}, err
+		}
+	}
+
+	for _, prize := range prizes {
+		err = s.challengeRepository.CreateChallengePrize(ctx, tx, repository.ChallengePrize{
+			ChallengeId: challenge.Id,
+			RankFrom:    prize.RankFrom,
+			RankTo:      prize.RankTo,
+			Points:      prize.Points,
+		})
+		if err != nil {
+			return ChallengeDetail{}, err
+		}
+	}
+
+	detail, err := s.getChallengeDetail(ctx, tx, challenge)
+	if err != nil {
+		return ChallengeDetail{}, err
+	}
+
+	slog.Info("challenge created", "challenge_id", challenge.Id, "slug", challenge.Slug, "created_by", userId)
+
+	return detail, nil
+}
+
+// CancelChallenge stops an open challenge. Nobody is ranked or paid.
+func (s *service) CancelChallenge(ctx context.Context, slug string) (err error) {
+	tx, err := s.challengeRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.challengeRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	challenge, err := s.challengeRepository.GetChallengeBySlug(ctx, tx, slug)
+	if err != nil {
+		return err
+	}
+
+	challenge, err = s.challengeRepository.LockChallenge(ctx, tx, challenge.Id)
+	if err != nil {
+		return err
+	}
+	if challenge.Status != StatusOpen {
+		return apperrors.ErrChallengeClosed
+	}
+
+	err = s.challengeRepository.UpdateChallengeStatus(ctx, tx, challenge.Id, StatusCancelled, sql.NullTime{})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("challenge cancelled", "challenge_id", challenge.Id)
+
+	return nil
+}
+
+func (s *service) ListChallenges(ctx context.Context, status string, limit int, offset int) ([]Challenge, error) {
+	if status != "" && !slices.Contains(Statuses, status) {
+		return nil, apperrors.ErrInvalidQueryParams
+	}
+
+	challenges, err := s.challengeRepository.ListChallenges(ctx, nil, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped := make([]Challenge, 0, len(challenges))
+	for _, challenge := range challenges {
+		mapped = append(mapped, mapChallenge(challenge))
+	}
+
+	return mapped, nil
+}
+
+func (s *service) GetChallenge(ctx context.Context, slug string) (ChallengeDetail, error) {
+	challenge, err := s.challengeRepository.GetChallengeBySlug(ctx, nil, slug)
+	if err != nil {
+		return ChallengeDetail{}, err
+	}
+
+	return s.getChallengeDetail(ctx, nil, challenge)
+}
+
+// GetLeaderboard computes the standings of an unfinished challenge from
+// the participants' contributions and reads the frozen results of a
+// finalized one.
+func (s *service) GetLeaderboard(ctx context.Context, slug string, limit int, offset int) (Leaderboard, error) {
+	challenge, err := s.challengeRepository.GetChallengeBySlug(ctx, nil, slug)
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	isFinal := challenge.Status == StatusFinalized
+
+	var standings []repository.ChallengeStanding
+	if isFinal {
+		standings, err = s.challengeRepository.ListFinalChallengeStandings(ctx, nil, challenge.Id, limit, offset)
+	} else {
+		standings, err = s.challengeRepository.ListChallengeStandings(ctx, nil, challenge.Id, limit, offset)
+	}
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	leaderboard := Leaderboard{
+		IsFinal:   isFinal,
+		Standings: make([]Standing, 0, len(standings)),
+	}
+	for _, standing := range standings {
+		leaderboard.Standings = append(leaderboard.Standings, Standing{
+			Rank:           standing.Rank,
+			UserId:         standing.UserId,
+			GithubUsername: standing.GithubUsername,
+			AvatarUrl:      standing.AvatarUrl,
+			Contributions:  standing.Contributions,
+			Points:         standing.Points,
+			PrizePoints:    standing.PrizePoints,
+		})
+	}
+
+	return leaderboard, nil
+}
+
+// JoinChallenge opts the caller in. Contributions made earlier in the
+// window count as well.
+func (s *service) JoinChallenge(ctx context.Context, slug string) error {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	challenge, err := s.challengeRepository.GetChallengeBySlug(ctx, nil, slug)
+	if err != nil {
+		return err
+	}
+	if !isJoinable(challenge) {
+		return apperrors.ErrChallengeClosed
+	}
+
+	err = s.challengeRepository.AddChallengeParticipant(ctx, nil, challenge.Id, userId)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("user joined challenge", "challenge_id", challenge.Id, "user_id", userId)
+
+	return nil
+}
+
+func (s *service) LeaveChallenge(ctx context.Context, slug string) error {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	challenge, err := s.challengeRepository.GetChallengeBySlug(ctx, nil, slug)
+	if err != nil {
+		return err
+	}
+	if !isJoinable(challenge) {
+		return apperrors.ErrChallengeClosed
+	}
+
+	err = s.challengeRepository.RemoveChallengeParticipant(ctx, nil, challenge.Id, userId)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("user left challenge", "challenge_id", challenge.Id, "user_id", userId)
+
+	return nil
+}
+
+// FinalizeEndedChallenges freezes the results of open challenges that
+// ended at least FinalizeDelay ago and pays their prizes. A challenge that
+// fails is logged and left open for the next run so it does not hold back
+// the others. It returns the number of challenges finalized.
+func (s *service) FinalizeEndedChallenges(ctx context.Context) (int, error) {
+	challengeIds, err := s.challengeRepository.ListDueChallengeIds(ctx, nil, time.Now().Add(-s.appCfg.Challenges.FinalizeDelay))
+	if err != nil {
+		return 0, err
+	}
+
+	finalized := 0
+	for _, challengeId := range challengeIds {
+		if ctx.Err() != nil {
+			return finalized, ctx.Err()
+		}
+
+		err = s.finalizeChallenge(ctx, challengeId)
+		if err != nil {
+			slog.Error("failed to finalize challenge", "challenge_id", challengeId, "error", err)
+			continue
+		}
+		finalized++
+	}
+
+	return finalized, nil
+}
+
+// finalizeChallenge ranks the participants once and for all. Participants
+// sharing a rank each win that rank's prize, and only participants who
+// scored points win anything. Blocked and deleted users are left out of the
+// standings, so they neither win nor take a prize rank.
+func (s *service) finalizeChallenge(ctx context.Context, challengeId int64) (err error) {
+	tx, err := s.challengeRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.challengeRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	challenge, err := s.challengeRepository.LockChallenge(ctx, tx, challengeId)
+	if err != nil {
+		return err
+	}
+	// another replica finalized or an admin cancelled it since it was listed
+	if challenge.Status != StatusOpen {
+		return nil
+	}
+
+	standings, err := s.challengeRepository.ListChallengeStandings(ctx, tx, challenge.Id, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	prizes, err := s.challengeRepository.ListChallengePrizes(ctx, tx, challenge.Id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	paid := 0
+	for _, standing := range standings {
+		if standing.Points > 0 {
+			standing.PrizePoints = prizeFor(prizes, standing.Rank)
+		}
+
+		var transactionId sql.NullInt64
+		if standing.PrizePoints > 0 {
+			transactionId, err = s.payPrize(ctx, tx, challenge, standing, now)
+			if err != nil {
+				return err
+			}
+			paid++
+		}
+
+		err = s.challengeRepository.FinalizeChallengeParticipant(ctx, tx, challenge.Id, standing, transactionId)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.challengeRepository.UpdateChallengeStatus(ctx, tx, challenge.Id, StatusFinalized, sql.NullTime{Time: now, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("challenge finalized", "challenge_id", challenge.Id, "participants", len(standings), "winners", paid)
+
+	return nil
+}
+
+// payPrize credits the prize to the participant's wallet and returns the
+// transaction recording it.
+func (s *service) payPrize(ctx context.Context, tx *sqlx.Tx, challenge repository.Challenge, standing repository.ChallengeStanding, paidAt time.Time) (sql.NullInt64, error) {
+	err := s.userRepository.IncrementUserBalance(ctx, tx, standing.UserId, standing.PrizePoints)
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+
+	transaction, err := s.transactionRepository.CreateTransaction(ctx, tx, repository.Transaction{
+		UserId:            standing.UserId,
+		IsRedeemed:        false,
+		IsGained:          true,
+		TransactedBalance: standing.PrizePoints,
+		TransactedAt:      paidAt,
+	})
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+
+	err = s.notificationService.Notify(ctx, tx, standing.UserId, notification.TypeChallengePrizeWon, map[string]any{
+		"challenge":    challenge.Name,
+		"challenge_id": challenge.Id,
+		"rank":         standing.Rank,
+		"points":       standing.PrizePoints,
+	})
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+
+	err = s.eventBus.Publish(ctx, tx, events.New(events.BalanceChanged, standing.UserId, events.BalanceChangedData{
+		UserId: standing.UserId,
+		Delta:  standing.PrizePoints,
+		Reason: events.BalanceReasonChallengePrize,
+	}))
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+
+	return sql.NullInt64{Int64: int64(transaction.Id), Valid: true}, nil
+}
+
+func (s *service) getChallengeDetail(ctx context.Context, tx *sqlx.Tx, challenge repository.Challenge) (ChallengeDetail, error) {
+	repositories, err := s.challengeRepository.ListChallengeRepositories(ctx, tx, challenge.Id)
+	if err != nil {
+		return ChallengeDetail{}, err
+	}
+
+	multipliers, err := s.challengeRepository.ListChallengeMultipliers(ctx, tx, challenge.Id)
+	if err != nil {
+		return ChallengeDetail{}, err
+	}
+
+	prizes, err := s.challengeRepository.ListChallengePrizes(ctx, tx, challenge.Id)
+	if err != nil {
+		return ChallengeDetail{}, err
+	}
+
+	participantCount, err := s.challengeRepository.CountChallengeParticipants(ctx, tx, challenge.Id)
+	if err != nil {
+		return ChallengeDetail{}, err
+	}
+
+	detail := ChallengeDetail{
+		Challenge:        mapChallenge(challenge),
+		Repositories:     make([]Repository, 0, len(repositories)),
+		Topics:           challenge.Topics,
+		Labels:           challenge.Labels,
+		Multiplier:       challenge.Multiplier,
+		Multipliers:      make([]Multiplier, 0, len(multipliers)),
+		Prizes:           make([]Prize, 0, len(prizes)),
+		ParticipantCount: participantCount,
+	}
+	if detail.Topics == nil {
+		detail.Topics = []string{}
+	}
+	if detail.Labels == nil {
+		detail.Labels = []string{}
+	}
+	for _, eligible := range repositories {
+		detail.Repositories = append(detail.Repositories, Repository{Id: eligible.RepositoryId, FullName: eligible.FullName})
+	}
+	for _, typeMultiplier := range multipliers {
+		detail.Multipliers = append(detail.Multipliers, Multiplier{ContributionType: typeMultiplier.ContributionType, Multiplier: typeMultiplier.Multiplier})
+	}
+	for _, prize := range prizes {
+		detail.Prizes = append(detail.Prizes, Prize{RankFrom: prize.RankFrom, RankTo: prize.RankTo, Points: prize.Points})
+	}
+
+	return detail, nil
+}
+
+// isJoinable reports whether participants can still join or leave.
+func isJoinable(challenge repository.Challenge) bool {
+	return challenge.Status == StatusOpen && time.Now().Before(challenge.EndsAt)
+}
+
+// sortPrizes orders the prize tiers by rank and reports whether each one
+// is a valid range paying points and no two of them overlap.
+func sortPrizes(prizes []Prize) ([]Prize, bool) {
+	sorted := slices.Clone(prizes)
+	slices.SortFunc(sorted, func(a, b Prize) int { return a.RankFrom - b.RankFrom })
+	for i, prize := range sorted {
+		if prize.RankFrom < 1 || prize.RankTo < prize.RankFrom || prize.Points <= 0 {
+			return nil, false
+		}
+		if i > 0 && prize.RankFrom <= sorted[i-1].RankTo {
+			return nil, false
+		}
+	}
+
+	return sorted, true
+}
+
+// prizeFor is the points paid to the given final rank, 0 outside every tier.
+func prizeFor(prizes []repository.ChallengePrize, rank int) int {
+	for _, prize := range prizes {
+		if rank >= prize.RankFrom && rank <= prize.RankTo {
+			return prize.Points
+		}
+	}
+
+	return 0
+}
+
+// normalizeNames lowercases topics and labels, which GitHub matches
+// regardless of case, and drops blanks and duplicates.
+func normalizeNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+
+	return normalized
+}
+
+func mapChallenge(challenge repository.Challenge) Challenge {
+	mapped := Challenge{
+		Id:          challenge.Id,
+		Slug:        challenge.Slug,
+		Name:        challenge.Name,
+		Description: challenge.Description,
+		StartsAt:    challenge.StartsAt,
+		EndsAt:      challenge.EndsAt,
+		Status:      challenge.Status,
+	}
+	if challenge.FinalizedAt.Valid {
+		mapped.FinalizedAt = &challenge.FinalizedAt.Time
+	}
+
+	return mapped
+}
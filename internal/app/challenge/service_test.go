@@ -0,0 +1,84 @@
+package challenge
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+func TestPrizeFor(t *testing.T) {
+	prizes := []repository.ChallengePrize{
+		{RankFrom: 1, RankTo: 1, Points: 500},
+		{RankFrom: 2, RankTo: 3, Points: 200},
+		{RankFrom: 5, RankTo: 10, Points: 50},
+	}
+
+	tests := []struct {
+		name string
+		rank int
+		want int
+	}{
+		{name: "single rank tier", rank: 1, want: 500},
+		{name: "start of a tier", rank: 2, want: 200},
+		{name: "end of a tier", rank: 3, want: 200},
+		{name: "gap between tiers", rank: 4, want: 0},
+		{name: "inside a tier", rank: 7, want: 50},
+		{name: "past the last tier", rank: 11, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prizeFor(prizes, tt.rank); got != tt.want {
+				t.Errorf("prizeFor(%d) = %d, want %d", tt.rank, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no prizes", func(t *testing.T) {
+		if got := prizeFor(nil, 1); got != 0 {
+			t.Errorf("prizeFor(1) = %d, want 0", got)
+		}
+	})
+}
+
+func TestSortPrizes(t *testing.T) {
+	tests := []struct {
+		name   string
+		prizes []Prize
+		want   []Prize
+		wantOk bool
+	}{
+		{name: "no prizes", prizes: nil, want: []Prize{}, wantOk: true},
+		{
+			name:   "sorted by rank",
+			prizes: []Prize{{RankFrom: 4, RankTo: 10, Points: 50}, {RankFrom: 1, RankTo: 1, Points: 500}, {RankFrom: 2, RankTo: 3, Points: 200}},
+			want:   []Prize{{RankFrom: 1, RankTo: 1, Points: 500}, {RankFrom: 2, RankTo: 3, Points: 200}, {RankFrom: 4, RankTo: 10, Points: 50}},
+			wantOk: true,
+		},
+		{
+			name:   "gaps between tiers",
+			prizes: []Prize{{RankFrom: 1, RankTo: 1, Points: 500}, {RankFrom: 5, RankTo: 5, Points: 50}},
+			want:   []Prize{{RankFrom: 1, RankTo: 1, Points: 500}, {RankFrom: 5, RankTo: 5, Points: 50}},
+			wantOk: true,
+		},
+		{name: "overlapping tiers", prizes: []Prize{{RankFrom: 1, RankTo: 3, Points: 500}, {RankFrom: 3, RankTo: 5, Points: 50}}},
+		{name: "tier inside another", prizes: []Prize{{RankFrom: 1, RankTo: 10, Points: 50}, {RankFrom: 2, RankTo: 2, Points: 500}}},
+		{name: "same start", prizes: []Prize{{RankFrom: 1, RankTo: 1, Points: 500}, {RankFrom: 1, RankTo: 2, Points: 50}}},
+		{name: "rank below 1", prizes: []Prize{{RankFrom: 0, RankTo: 1, Points: 500}}},
+		{name: "reversed range", prizes: []Prize{{RankFrom: 3, RankTo: 1, Points: 500}}},
+		{name: "no points", prizes: []Prize{{RankFrom: 1, RankTo: 1, Points: 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sortPrizes(tt.prizes)
+			if ok != tt.wantOk {
+				t.Fatalf("sortPrizes() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && !slices.Equal(got, tt.want) {
+				t.Errorf("sortPrizes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package contribution
+
+import "time"
+
+// contribution types, kept in sync with the contribution_type check
+// constraints on contributions and contribution_score
+const (
+	CommitPushed      = "Commit"
+	PullRequestOpened = "PullRequestOpened"
+	PullRequestMerged = "PullRequestMerged"
+	PullRequestReview = "PullRequestReview"
+	IssueOpened       = "IssueOpened"
+	IssueComment      = "IssueComment"
+)
+
+var ContributionTypes = []string{CommitPushed, PullRequestOpened, PullRequestMerged, PullRequestReview, IssueOpened, IssueComment}
+
+// contribution statuses, as derived by the contribution_scores view. A
+// voided contribution is worth no points.
+const (
+	StatusActive = "active"
+	StatusVoided = "voided"
+)
+
+type Contribution struct {
+	Id                  int       `json:"id"`
+	UserId              int       `json:"user_id"`
+	RepositoryId        int       `json:"repository_id"`
+	ContributionScoreId int       `json:"contribution_score_id"`
+	ContributionType    string    `json:"contribution_type"`
+	BalanceChange       int       `json:"balance_change"`
+	ContributedAt       time.Time `json:"contributed_at"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	ExternalId          string    `json:"external_id"`
+	BaseScore           int       `json:"base_score"`
+	Multiplier          float64   `json:"multiplier"`
+	Bonus               int       `json:"bonus"`
+	Status              string    `json:"status"`
+}
+
+// CreateContributionRequest describes a contribution to record. LinesChanged,
+// Title and Url are only known for merged pull requests and are passed on
+// with the recorded event rather than stored.
+type CreateContributionRequest struct {
+	UserId           int
+	RepositoryId     int
+	ContributionType string
+	ContributedAt    time.Time
+	ExternalId       string
+	DeliveryId       string
+	LinesChanged     int
+	Title            string
+	Url              string
+	Labels           []string
+}
+
+// feed sorts
+const (
+	SortNewest       = "newest"
+	SortOldest       = "oldest"
+	SortHighestScore = "highest_score"
+)
+
+var Sorts = []string{SortNewest, SortOldest, SortHighestScore}
+
+// ListContributionsRequest filters a contribution feed. Zero values disable
+// a filter. UserId is only honoured on the admin feed.
+type ListContributionsRequest struct {
+	UserId           int
+	ContributionType string
+	RepositoryId     int
+	From             time.Time
+	To               time.Time
+	Language         string
+	Sort             string
+	Cursor           string
+	Limit            int
+}
+
+type Feed struct {
+	Items      []FeedItem `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+type FeedItem struct {
+	Id               int            `json:"id"`
+	UserId           int            `json:"user_id"`
+	ContributionType string         `json:"contribution_type"`
+	ContributedAt    time.Time      `json:"contributed_at"`
+	ExternalId       string         `json:"external_id"`
+	Status           string         `json:"status"`
+	Repository       FeedRepository `json:"repository"`
+	Score            ScoreBreakdown `json:"score"`
+}
+
+type FeedRepository struct {
+	Id        int    `json:"id"`
+	Name      string `json:"name"`
+	OwnerName string `json:"owner_name"`
+	Language  string `json:"language"`
+}
+
+// ScoreBreakdown explains how a contribution's points were computed:
+// Total = BaseScore * Multiplier + Bonus.
+type ScoreBreakdown struct {
+	BaseScore  int     `json:"base_score"`
+	Multiplier float64 `json:"multiplier"`
+	Bonus      int     `json:"bonus"`
+	Total      int     `json:"total"`
+}
+
+// feedCursor is the sort key of the last item on a page, sent to clients
+// base64 encoded.
+type feedCursor struct {
+	Id            int       `json:"id"`
+	ContributedAt time.Time `json:"contributed_at"`
+	BalanceChange int       `json:"balance_change"`
+}
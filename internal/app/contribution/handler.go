@@ -0,0 +1,145 @@
+package contribution
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	contributionService Service
+}
+
+type Handler interface {
+	ListMyContributions(w http.ResponseWriter, r *http.Request)
+	ListUserContributions(w http.ResponseWriter, r *http.Request)
+	ListAllContributions(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(contributionService Service) Handler {
+	return &handler{
+		contributionService: contributionService,
+	}
+}
+
+func (h *handler) ListMyContributions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	listRequest, err := parseListContributionsRequest(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	feed, err := h.contributionService.ListMyContributions(ctx, listRequest)
+	if err != nil {
+		slog.Error("failed to list contributions", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "contributions fetched successfully", feed)
+}
+
+func (h *handler) ListUserContributions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	listRequest, err := parseListContributionsRequest(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	feed, err := h.contributionService.ListUserContributions(ctx, r.PathValue("github_username"), listRequest)
+	if err != nil {
+		slog.Error("failed to list user contributions", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "contributions fetched successfully", feed)
+}
+
+func (h *handler) ListAllContributions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	listRequest, err := parseListContributionsRequest(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if userIdParam := r.URL.Query().Get("user_id"); userIdParam != "" {
+		listRequest.UserId, err = strconv.Atoi(userIdParam)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+	}
+
+	feed, err := h.contributionService.ListAllContributions(ctx, listRequest)
+	if err != nil {
+		slog.Error("failed to list all contributions", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "contributions fetched successfully", feed)
+}
+
+// parseListContributionsRequest reads the feed filters shared by every
+// contribution listing.
+func parseListContributionsRequest(r *http.Request) (ListContributionsRequest, error) {
+	query := r.URL.Query()
+
+	listRequest := ListContributionsRequest{
+		ContributionType: query.Get("contribution_type"),
+		Language:         query.Get("language"),
+		Sort:             query.Get("sort"),
+		Cursor:           query.Get("cursor"),
+	}
+
+	if listRequest.ContributionType != "" && !slices.Contains(ContributionTypes, listRequest.ContributionType) {
+		return ListContributionsRequest{}, apperrors.ErrInvalidQueryParams
+	}
+
+	if listRequest.Sort == "" {
+		listRequest.Sort = SortNewest
+	}
+	if !slices.Contains(Sorts, listRequest.Sort) {
+		return ListContributionsRequest{}, apperrors.ErrInvalidQueryParams
+	}
+
+	var err error
+	if repositoryIdParam := query.Get("repository_id"); repositoryIdParam != "" {
+		listRequest.RepositoryId, err = strconv.Atoi(repositoryIdParam)
+		if err != nil {
+			return ListContributionsRequest{}, apperrors.ErrInvalidQueryParams
+		}
+	}
+
+	listRequest.From, err = request.ParseTime(r, "from")
+	if err != nil {
+		return ListContributionsRequest{}, err
+	}
+
+	listRequest.To, err = request.ParseTime(r, "to")
+	if err != nil {
+		return ListContributionsRequest{}, err
+	}
+
+	listRequest.Limit, err = request.ParseLimit(r)
+	if err != nil {
+		return ListContributionsRequest{}, err
+	}
+
+	return listRequest, nil
+}
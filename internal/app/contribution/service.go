@@ -0,0 +1,262 @@
+package contribution
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/user"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	contributionRepository      repository.ContributionRepository
+	contributionScoreRepository repository.ContributionScoreRepository
+	privacySettingRepository    repository.PrivacySettingRepository
+	userService                 user.Service
+	eventBus                    events.Bus
+}
+
+type Service interface {
+	CreateContribution(ctx context.Context, tx *sqlx.Tx, contributionInfo CreateContributionRequest) (Contribution, error)
+	GetContributionsByUserId(ctx context.Context, userId int) ([]Contribution, error)
+	ListMyContributions(ctx context.Context, request ListContributionsRequest) (Feed, error)
+	ListUserContributions(ctx context.Context, githubUsername string, request ListContributionsRequest) (Feed, error)
+	ListAllContributions(ctx context.Context, request ListContributionsRequest) (Feed, error)
+}
+
+func NewService(contributionRepository repository.ContributionRepository, contributionScoreRepository repository.ContributionScoreRepository, privacySettingRepository repository.PrivacySettingRepository, userService user.Service, eventBus events.Bus) Service {
+	return &service{
+		contributionRepository:      contributionRepository,
+		contributionScoreRepository: contributionScoreRepository,
+		privacySettingRepository:    privacySettingRepository,
+		userService:                 userService,
+		eventBus:                    eventBus,
+	}
+}
+
+// CreateContribution scores the contribution with the currently configured
+// score for its type, records it and publishes that it was recorded, inside
+// the caller's transaction. Integrations get the contribution itself as
+// contribution.scored, the internal event also carries the pull request or
+// issue title and link.
+func (s *service) CreateContribution(ctx context.Context, tx *sqlx.Tx, contributionInfo CreateContributionRequest) (Contribution, error) {
+	score, err := s.contributionScoreRepository.GetContributionScoreByType(ctx, tx, contributionInfo.ContributionType)
+	if err != nil {
+		slog.Error("failed to get contribution score", "contribution_type", contributionInfo.ContributionType, "error", err)
+		return Contribution{}, err
+	}
+
+	contribution, err := s.contributionRepository.CreateContribution(ctx, tx, repository.Contribution{
+		UserId:              contributionInfo.UserId,
+		RepositoryId:        contributionInfo.RepositoryId,
+		ContributionScoreId: score.Id,
+		ContributionType:    contributionInfo.ContributionType,
+		BalanceChange:       score.Score,
+		ContributedAt:       contributionInfo.ContributedAt,
+		ExternalId:          sql.NullString{String: contributionInfo.ExternalId, Valid: contributionInfo.ExternalId != ""},
+		DeliveryId:          sql.NullString{String: contributionInfo.DeliveryId, Valid: contributionInfo.DeliveryId != ""},
+		Labels:              contributionInfo.Labels,
+		BaseScore:           score.Score,
+		Multiplier:          1,
+	})
+	if err != nil {
+		return Contribution{}, err
+	}
+
+	created := mapContribution(contribution)
+	err = s.eventBus.Publish(ctx, tx, events.New(events.ContributionRecorded, created.UserId, events.ContributionRecordedData{
+		ContributionId:   created.Id,
+		UserId:           created.UserId,
+		RepositoryId:     created.RepositoryId,
+		ContributionType: created.ContributionType,
+		Points:           created.BalanceChange,
+		ContributedAt:    created.ContributedAt,
+		LinesChanged:     contributionInfo.LinesChanged,
+		Title:            contributionInfo.Title,
+		Url:              contributionInfo.Url,
+	}))
+	if err != nil {
+		return Contribution{}, err
+	}
+
+	err = s.eventBus.Publish(ctx, tx, events.New(events.ContributionScored, created.UserId, created))
+	if err != nil {
+		return Contribution{}, err
+	}
+
+	return created, nil
+}
+
+func (s *service) GetContributionsByUserId(ctx context.Context, userId int) ([]Contribution, error) {
+	userContributions, err := s.contributionRepository.GetContributionsByUserId(ctx, nil, userId)
+	if err != nil {
+		slog.Error("failed to get user contributions", "error", err)
+		return nil, err
+	}
+
+	contributions := make([]Contribution, 0, len(userContributions))
+	for _, contribution := range userContributions {
+		contributions = append(contributions, mapContribution(contribution))
+	}
+
+	return contributions, nil
+}
+
+func (s *service) ListMyContributions(ctx context.Context, request ListContributionsRequest) (Feed, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Feed{}, apperrors.ErrInternalServer
+	}
+
+	request.UserId = userId
+	return s.listContributions(ctx, request)
+}
+
+// ListUserContributions is the public feed of a user and is refused when the
+// user hides their activity.
+func (s *service) ListUserContributions(ctx context.Context, githubUsername string, request ListContributionsRequest) (Feed, error) {
+	userInfo, err := s.userService.GetUserByGithubUsername(ctx, githubUsername)
+	if err != nil {
+		return Feed{}, err
+	}
+	if userInfo.IsBlocked {
+		return Feed{}, apperrors.ErrUserNotFound
+	}
+
+	setting, err := s.privacySettingRepository.GetPrivacySettingByUserId(ctx, nil, userInfo.Id)
+	if err != nil && !errors.Is(err, apperrors.ErrPrivacySettingNotFound) {
+		return Feed{}, err
+	}
+	if setting.HideActivity {
+		return Feed{}, apperrors.ErrActivityHidden
+	}
+
+	request.UserId = userInfo.Id
+	return s.listContributions(ctx, request)
+}
+
+func (s *service) ListAllContributions(ctx context.Context, request ListContributionsRequest) (Feed, error) {
+	return s.listContributions(ctx, request)
+}
+
+func (s *service) listContributions(ctx context.Context, request ListContributionsRequest) (Feed, error) {
+	filter := repository.ContributionFilter{
+		UserId:           request.UserId,
+		ContributionType: request.ContributionType,
+		RepositoryId:     request.RepositoryId,
+		From:             sql.NullTime{Time: request.From, Valid: !request.From.IsZero()},
+		To:               sql.NullTime{Time: request.To, Valid: !request.To.IsZero()},
+		Language:         request.Language,
+		Sort:             request.Sort,
+		Limit:            request.Limit,
+	}
+
+	if request.Cursor != "" {
+		cursor, err := decodeCursor(request.Cursor)
+		if err != nil {
+			return Feed{}, err
+		}
+		filter.CursorId = cursor.Id
+		filter.CursorContributedAt = cursor.ContributedAt
+		filter.CursorBalanceChange = cursor.BalanceChange
+	}
+
+	items, err := s.contributionRepository.ListContributions(ctx, nil, filter)
+	if err != nil {
+		slog.Error("failed to list contributions", "error", err)
+		return Feed{}, err
+	}
+
+	feed := Feed{Items: make([]FeedItem, 0, len(items))}
+	for _, item := range items {
+		feed.Items = append(feed.Items, FeedItem{
+			Id:               item.Id,
+			UserId:           item.UserId,
+			ContributionType: item.ContributionType,
+			ContributedAt:    item.ContributedAt,
+			ExternalId:       item.ExternalId.String,
+			Status:           item.Status,
+			Repository: FeedRepository{
+				Id:        item.RepositoryId,
+				Name:      item.RepoName,
+				OwnerName: item.OwnerName,
+				Language:  item.Language,
+			},
+			Score: ScoreBreakdown{
+				BaseScore:  item.BaseScore,
+				Multiplier: item.Multiplier,
+				Bonus:      item.Bonus,
+				Total:      item.BalanceChange,
+			},
+		})
+	}
+
+	// a full page means there may be more
+	if len(items) == request.Limit && len(items) > 0 {
+		last := items[len(items)-1]
+		feed.NextCursor, err = encodeCursor(feedCursor{
+			Id:            last.Id,
+			ContributedAt: last.ContributedAt,
+			BalanceChange: last.BalanceChange,
+		})
+		if err != nil {
+			return Feed{}, err
+		}
+	}
+
+	return feed, nil
+}
+
+func encodeCursor(cursor feedCursor) (string, error) {
+	cursorBytes, err := json.Marshal(cursor)
+	if err != nil {
+		slog.Error("failed to encode feed cursor", "error", err)
+		return "", apperrors.ErrInternalServer
+	}
+
+	return base64.RawURLEncoding.EncodeToString(cursorBytes), nil
+}
+
+func decodeCursor(encoded string) (feedCursor, error) {
+	cursorBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return feedCursor{}, apperrors.ErrInvalidCursor
+	}
+
+	var cursor feedCursor
+	err = json.Unmarshal(cursorBytes, &cursor)
+	if err != nil || cursor.Id <= 0 {
+		return feedCursor{}, apperrors.ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+func mapContribution(contribution repository.Contribution) Contribution {
+	return Contribution{
+		Id:                  contribution.Id,
+		UserId:              contribution.UserId,
+		RepositoryId:        contribution.RepositoryId,
+		ContributionScoreId: contribution.ContributionScoreId,
+		ContributionType:    contribution.ContributionType,
+		BalanceChange:       contribution.BalanceChange,
+		ContributedAt:       contribution.ContributedAt,
+		CreatedAt:           contribution.CreatedAt,
+		UpdatedAt:           contribution.UpdatedAt,
+		ExternalId:          contribution.ExternalId.String,
+		BaseScore:           contribution.BaseScore,
+		Multiplier:          contribution.Multiplier,
+		Bonus:               contribution.Bonus,
+		Status:              contribution.Status,
+	}
+}
@@ -1,35 +1,296 @@
 package app
 
 import (
+	"context"
+	"time"
+
 	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/account"
 	"github.com/joshsoftware/code-curiosity-2025/internal/app/auth"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/badge"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/challenge"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/dispute"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/fraud"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/githubtoken"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/integration"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/job"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/judging"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/language"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/leaderboard"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/notification"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/profile"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/redemption"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/repo"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/role"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/scheduler"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/sponsor"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/streak"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/summary"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/team"
 	"github.com/joshsoftware/code-curiosity-2025/internal/app/user"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/webhook"
 	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/envelope"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/github"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/mailer"
 	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
 )
 
+const githubETagCacheSize = 10000
+
 type Dependencies struct {
-	AuthService auth.Service
-	UserService user.Service
-	AuthHandler auth.Handler
-	UserHandler user.Handler
-	AppCfg      config.AppConfig
+	AuthService             auth.Service
+	UserService             user.Service
+	GithubTokenService      githubtoken.Service
+	RepoService             repo.Service
+	ContributionService     contribution.Service
+	WebhookService          webhook.Service
+	JobService              job.Service
+	EventBus                events.Bus
+	LeaderboardService      leaderboard.Service
+	SummaryService          summary.Service
+	SchedulerService        scheduler.Service
+	AccountService          account.Service
+	ProfileService          profile.Service
+	NotificationService     notification.Service
+	DisputeService          dispute.Service
+	FraudService            fraud.Service
+	LanguageService         language.Service
+	SponsorService          sponsor.Service
+	RedemptionService       redemption.Service
+	RoleService             role.Service
+	IntegrationService      integration.Service
+	AuthHandler             auth.Handler
+	UserHandler             user.Handler
+	ContributionHandler     contribution.Handler
+	WebhookHandler          webhook.Handler
+	JobHandler              job.Handler
+	SchedulerHandler        scheduler.Handler
+	AccountHandler          account.Handler
+	ProfileHandler          profile.Handler
+	DisputeHandler          dispute.Handler
+	FraudHandler            fraud.Handler
+	RepoHandler             repo.Handler
+	LanguageHandler         language.Handler
+	SponsorHandler          sponsor.Handler
+	RedemptionHandler       redemption.Handler
+	NotificationHandler     notification.Handler
+	IntegrationHandler      integration.Handler
+	AdminIntegrationHandler integration.Handler
+	StreakHandler           streak.Handler
+	TeamHandler             team.Handler
+	ChallengeHandler        challenge.Handler
+	JudgingHandler          judging.Handler
+	RoleHandler             role.Handler
+	AppCfg                  config.AppConfig
 }
 
-func InitDependencies(db *sqlx.DB, appCfg config.AppConfig) Dependencies {
+func InitDependencies(db *sqlx.DB, appCfg config.AppConfig) (Dependencies, error) {
 	userRepository := repository.NewUserRepository(db)
+	githubTokenRepository := repository.NewGithubTokenRepository(db)
+	repoRepository := repository.NewRepoRepository(db)
+	languageRepository := repository.NewLanguageRepository(db)
+	sponsorRepository := repository.NewSponsorRepository(db)
+	sponsorInvitationRepository := repository.NewSponsorInvitationRepository(db)
+	redemptionRepository := repository.NewRedemptionRepository(db)
+	roleRepository := repository.NewRoleRepository(db)
+	integrationRepository := repository.NewIntegrationRepository(db)
+	contributionRepository := repository.NewContributionRepository(db)
+	contributionScoreRepository := repository.NewContributionScoreRepository(db)
+	webhookDeliveryRepository := repository.NewWebhookDeliveryRepository(db)
+	jobRepository := repository.NewJobRepository(db)
+	scheduledTaskRunRepository := repository.NewScheduledTaskRunRepository(db)
+	leaderboardRepository := repository.NewLeaderboardRepository(db)
+	summaryRepository := repository.NewSummaryRepository(db)
+	transactionRepository := repository.NewTransactionRepository(db)
+	badgeRepository := repository.NewBadgeRepository(db)
+	profileRepository := repository.NewProfileRepository(db)
+	privacySettingRepository := repository.NewPrivacySettingRepository(db)
+	contributionDisputeRepository := repository.NewContributionDisputeRepository(db)
+	contributionAdjustmentRepository := repository.NewContributionAdjustmentRepository(db)
+	notificationRepository := repository.NewNotificationRepository(db)
+	contributionFlagRepository := repository.NewContributionFlagRepository(db)
+	riskScoreRepository := repository.NewRiskScoreRepository(db)
+	eventOutboxRepository := repository.NewEventOutboxRepository(db)
+	streakRepository := repository.NewStreakRepository(db)
+	teamRepository := repository.NewTeamRepository(db)
+	challengeRepository := repository.NewChallengeRepository(db)
+	judgingRepository := repository.NewJudgingRepository(db)
+
+	githubETagStore := github.NewMemoryETagStore(githubETagCacheSize)
+	githubTrackers := github.NewTrackers()
+	eventBus := events.NewBus(eventOutboxRepository, appCfg)
+
+	tokenEnvelope, err := envelope.New(appCfg)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	notificationMailer, err := mailer.New(appCfg)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	jobService := job.NewService(jobRepository, appCfg)
+	integrationService := integration.NewService(integrationRepository, jobService, tokenEnvelope, appCfg)
+	userService := user.NewService(userRepository, eventBus)
+	githubTokenService := githubtoken.NewService(githubTokenRepository, tokenEnvelope, githubETagStore, githubTrackers, appCfg)
+	authService := auth.NewService(userService, githubTokenService, githubETagStore, appCfg)
+	repoService := repo.NewService(repoRepository, languageRepository, githubETagStore, githubTrackers, appCfg)
+	contributionService := contribution.NewService(contributionRepository, contributionScoreRepository, privacySettingRepository, userService, eventBus)
+	notificationService := notification.NewService(notificationRepository, userRepository, jobService, notificationMailer, appCfg)
+	disputeService := dispute.NewService(contributionDisputeRepository, contributionAdjustmentRepository, contributionRepository, transactionRepository, summaryRepository, userRepository, notificationService, eventBus)
+	fraudService := fraud.NewService(contributionFlagRepository, riskScoreRepository, userRepository, contributionRepository, disputeService, notificationService, fraud.DefaultRules(contributionRepository, githubTokenService, appCfg), appCfg)
+	webhookService := webhook.NewService(webhookDeliveryRepository, userService, repoService, contributionService, fraudService, jobService, appCfg)
+
+	leaderboardService := leaderboard.NewService(leaderboardRepository, eventBus)
+	languageService := language.NewService(languageRepository)
+	sponsorService := sponsor.NewService(sponsorRepository, sponsorInvitationRepository, userRepository, roleRepository, appCfg)
+	redemptionService := redemption.NewService(redemptionRepository, sponsorRepository, userRepository, transactionRepository, notificationService, eventBus, appCfg)
+	roleService := role.NewService(roleRepository, userRepository)
+	summaryService := summary.NewService(summaryRepository, userRepository, notificationService, eventBus)
+	teamService := team.NewService(teamRepository)
+	accountService := account.NewService(userRepository, githubTokenRepository, leaderboardRepository, transactionRepository, badgeRepository, summaryRepository, userService, contributionService, teamService, jobService, appCfg)
+	badgeService := badge.NewService(badgeRepository, notificationService, eventBus)
+	streakService := streak.NewService(streakRepository, userRepository, transactionRepository, privacySettingRepository, eventBus, appCfg)
+	challengeService := challenge.NewService(challengeRepository, userRepository, transactionRepository, notificationService, eventBus, appCfg)
+	judgingService := judging.NewService(judgingRepository, disputeService, appCfg)
+	profileService := profile.NewService(profileRepository, privacySettingRepository, badgeRepository, userService, streakService)
+	schedulerService := scheduler.NewService(scheduledTaskRunRepository, repository.NewAdvisoryLock(db, repository.SchedulerLeaderLockKey), func(key int64) repository.AdvisoryLock {
+		return repository.NewAdvisoryLock(db, key)
+	}, appCfg)
+
+	jobService.RegisterHandler(webhook.ProcessGithubDeliveryJob, job.HandlerFor(webhookService.ProcessGithubDelivery))
+	jobService.RegisterHandler(account.AnonymizeUserJob, job.HandlerFor(accountService.AnonymizeUser))
+	jobService.RegisterHandler(notification.SendEmailJob, job.HandlerFor(notificationService.SendEmail))
+	jobService.RegisterHandler(integration.DeliverEventJob, job.HandlerFor(integrationService.DeliverEvent))
+
+	eventBus.SubscribeAsync(events.ContributionRecorded, badge.EventSubscriber, events.HandlerFor(badgeService.HandleContributionRecorded))
+	eventBus.SubscribeAsync(events.ContributionRecorded, streak.EventSubscriber, events.HandlerFor(streakService.HandleContributionRecorded))
+	eventBus.SubscribeAsync(events.ContributionAdjusted, streak.EventSubscriber, events.HandlerFor(streakService.HandleContributionAdjusted))
+	eventBus.SubscribeAsync(events.ContributionRecorded, judging.EventSubscriber, events.HandlerFor(judgingService.HandleContributionRecorded))
+	for _, eventType := range events.Types {
+		eventBus.SubscribeAsync(eventType, integration.EventSubscriber, integrationService.HandleEvent)
+	}
 
-	userService := user.NewService(userRepository)
-	authService := auth.NewService(userService, appCfg)
+	err = schedulerService.RegisterTask(scheduler.LeaderboardRefreshTask, func(ctx context.Context, scheduledFor time.Time) error {
+		return leaderboardService.RefreshLeaderboard(ctx)
+	})
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	// month close runs just after a month ends and closes the month before
+	// the one it is scheduled in, so a manual run closes the last ended month
+	err = schedulerService.RegisterTask(scheduler.MonthCloseTask, func(ctx context.Context, scheduledFor time.Time) error {
+		return summaryService.CloseMonth(ctx, summary.PreviousMonth(scheduledFor))
+	})
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	err = schedulerService.RegisterTask(scheduler.RepositorySyncTask, func(ctx context.Context, scheduledFor time.Time) error {
+		_, err := repoService.SyncRepositories(ctx)
+		return err
+	})
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	err = schedulerService.RegisterTask(scheduler.RedemptionFundingTask, func(ctx context.Context, scheduledFor time.Time) error {
+		_, err := redemptionService.FundQueuedRedemptions(ctx)
+		return err
+	})
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	// the digest covers the week ending at the scheduled run
+	err = schedulerService.RegisterTask(scheduler.NotificationDigestTask, func(ctx context.Context, scheduledFor time.Time) error {
+		_, err := notificationService.SendWeeklyDigest(ctx, scheduledFor)
+		return err
+	})
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	err = schedulerService.RegisterTask(scheduler.ChallengeFinalizeTask, func(ctx context.Context, scheduledFor time.Time) error {
+		_, err := challengeService.FinalizeEndedChallenges(ctx)
+		return err
+	})
+	if err != nil {
+		return Dependencies{}, err
+	}
 
 	authHandler := auth.NewHandler(authService, appCfg)
 	userHandler := user.NewHandler(userService)
+	contributionHandler := contribution.NewHandler(contributionService)
+	webhookHandler := webhook.NewHandler(webhookService)
+	jobHandler := job.NewHandler(jobService)
+	schedulerHandler := scheduler.NewHandler(schedulerService)
+	accountHandler := account.NewHandler(accountService)
+	profileHandler := profile.NewHandler(profileService)
+	disputeHandler := dispute.NewHandler(disputeService)
+	fraudHandler := fraud.NewHandler(fraudService)
+	repoHandler := repo.NewHandler(repoService)
+	languageHandler := language.NewHandler(languageService)
+	sponsorHandler := sponsor.NewHandler(sponsorService)
+	redemptionHandler := redemption.NewHandler(redemptionService)
+	notificationHandler := notification.NewHandler(notificationService)
+	integrationHandler := integration.NewHandler(integrationService)
+	adminIntegrationHandler := integration.NewAdminHandler(integrationService)
+	streakHandler := streak.NewHandler(streakService)
+	teamHandler := team.NewHandler(teamService)
+	challengeHandler := challenge.NewHandler(challengeService)
+	judgingHandler := judging.NewHandler(judgingService)
+	roleHandler := role.NewHandler(roleService)
 
 	return Dependencies{
-		AuthService: authService,
-		UserService: userService,
-		AuthHandler: authHandler,
-		UserHandler: userHandler,
-		AppCfg:      appCfg,
-	}
+		AuthService:             authService,
+		UserService:             userService,
+		GithubTokenService:      githubTokenService,
+		RepoService:             repoService,
+		ContributionService:     contributionService,
+		WebhookService:          webhookService,
+		JobService:              jobService,
+		EventBus:                eventBus,
+		LeaderboardService:      leaderboardService,
+		SummaryService:          summaryService,
+		SchedulerService:        schedulerService,
+		AccountService:          accountService,
+		ProfileService:          profileService,
+		NotificationService:     notificationService,
+		DisputeService:          disputeService,
+		FraudService:            fraudService,
+		LanguageService:         languageService,
+		SponsorService:          sponsorService,
+		RedemptionService:       redemptionService,
+		RoleService:             roleService,
+		IntegrationService:      integrationService,
+		AuthHandler:             authHandler,
+		UserHandler:             userHandler,
+		ContributionHandler:     contributionHandler,
+		WebhookHandler:          webhookHandler,
+		JobHandler:              jobHandler,
+		SchedulerHandler:        schedulerHandler,
+		AccountHandler:          accountHandler,
+		ProfileHandler:          profileHandler,
+		DisputeHandler:          disputeHandler,
+		FraudHandler:            fraudHandler,
+		RepoHandler:             repoHandler,
+		LanguageHandler:         languageHandler,
+		SponsorHandler:          sponsorHandler,
+		RedemptionHandler:       redemptionHandler,
+		NotificationHandler:     notificationHandler,
+		IntegrationHandler:      integrationHandler,
+		AdminIntegrationHandler: adminIntegrationHandler,
+		StreakHandler:           streakHandler,
+		TeamHandler:             teamHandler,
+		ChallengeHandler:        challengeHandler,
+		JudgingHandler:          judgingHandler,
+		RoleHandler:             roleHandler,
+		AppCfg:                  appCfg,
+	}, nil
 }
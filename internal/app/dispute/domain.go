@@ -0,0 +1,74 @@
+package dispute
+
+import "time"
+
+// Dispute statuses. A dispute stays open until an admin voids or re-scores
+// the contribution, or rejects the dispute.
+const (
+	StatusOpen     = "open"
+	StatusVoided   = "voided"
+	StatusRescored = "rescored"
+	StatusRejected = "rejected"
+)
+
+var Statuses = []string{StatusOpen, StatusVoided, StatusRescored, StatusRejected}
+
+// Resolutions accepted when resolving a dispute
+const (
+	ResolutionVoid    = "void"
+	ResolutionRescore = "rescore"
+	ResolutionReject  = "reject"
+)
+
+// Adjustment actions recorded in contribution_adjustments. Judge adjustments
+// replace the automated score with the one a judges panel agreed on.
+const (
+	ActionVoid    = "void"
+	ActionRescore = "rescore"
+	ActionJudge   = "judge"
+)
+
+type Dispute struct {
+	Id             int        `json:"id"`
+	ContributionId int        `json:"contribution_id"`
+	UserId         int        `json:"user_id"`
+	Reason         string     `json:"reason"`
+	Status         string     `json:"status"`
+	ResolutionNote *string    `json:"resolution_note"`
+	ResolvedBy     *int64     `json:"resolved_by"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type Adjustment struct {
+	Id                    int       `json:"id"`
+	ContributionId        int       `json:"contribution_id"`
+	DisputeId             *int64    `json:"dispute_id"`
+	AdminId               int       `json:"admin_id"`
+	Action                string    `json:"action"`
+	PreviousBalanceChange int       `json:"previous_balance_change"`
+	NewBalanceChange      int       `json:"new_balance_change"`
+	Reason                string    `json:"reason"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+type FlagContributionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ResolveDisputeRequest needs Score only for the rescore resolution.
+type ResolveDisputeRequest struct {
+	Resolution string `json:"resolution"`
+	Reason     string `json:"reason"`
+	Score      *int   `json:"score"`
+}
+
+type VoidContributionRequest struct {
+	Reason string `json:"reason"`
+}
+
+type RescoreContributionRequest struct {
+	Reason string `json:"reason"`
+	Score  *int   `json:"score"`
+}
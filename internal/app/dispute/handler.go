@@ -0,0 +1,162 @@
+package dispute
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	disputeService Service
+}
+
+type Handler interface {
+	FlagContribution(w http.ResponseWriter, r *http.Request)
+	ListDisputes(w http.ResponseWriter, r *http.Request)
+	ResolveDispute(w http.ResponseWriter, r *http.Request)
+	VoidContribution(w http.ResponseWriter, r *http.Request)
+	RescoreContribution(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(disputeService Service) Handler {
+	return &handler{
+		disputeService: disputeService,
+	}
+}
+
+func (h *handler) FlagContribution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	contributionId, err := strconv.Atoi(r.PathValue("contributionId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody FlagContributionRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	dispute, err := h.disputeService.FlagContribution(ctx, contributionId, requestBody)
+	if err != nil {
+		slog.Error("failed to flag contribution", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "contribution flagged for review", dispute)
+}
+
+func (h *handler) ListDisputes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	disputes, err := h.disputeService.ListDisputes(ctx, r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		slog.Error("failed to list disputes", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "disputes fetched successfully", disputes)
+}
+
+func (h *handler) ResolveDispute(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	disputeId, err := strconv.Atoi(r.PathValue("disputeId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody ResolveDisputeRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	dispute, err := h.disputeService.ResolveDispute(ctx, disputeId, requestBody)
+	if err != nil {
+		slog.Error("failed to resolve dispute", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "dispute resolved", dispute)
+}
+
+func (h *handler) VoidContribution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	contributionId, err := strconv.Atoi(r.PathValue("contributionId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody VoidContributionRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	adjustment, err := h.disputeService.VoidContribution(ctx, contributionId, requestBody)
+	if err != nil {
+		slog.Error("failed to void contribution", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "contribution voided", adjustment)
+}
+
+func (h *handler) RescoreContribution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	contributionId, err := strconv.Atoi(r.PathValue("contributionId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody RescoreContributionRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	adjustment, err := h.disputeService.RescoreContribution(ctx, contributionId, requestBody)
+	if err != nil {
+		slog.Error("failed to rescore contribution", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "contribution re-scored", adjustment)
+}
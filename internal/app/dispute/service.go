@@ -0,0 +1,438 @@
+package dispute
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/notification"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/summary"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	disputeRepository      repository.ContributionDisputeRepository
+	adjustmentRepository   repository.ContributionAdjustmentRepository
+	contributionRepository repository.ContributionRepository
+	transactionRepository  repository.TransactionRepository
+	summaryRepository      repository.SummaryRepository
+	userRepository         repository.UserRepository
+	notificationService    notification.Service
+	eventBus               events.Bus
+}
+
+type Service interface {
+	FlagContribution(ctx context.Context, contributionId int, request FlagContributionRequest) (Dispute, error)
+	ListDisputes(ctx context.Context, status string, limit int, offset int) ([]Dispute, error)
+	ResolveDispute(ctx context.Context, disputeId int, request ResolveDisputeRequest) (Dispute, error)
+	VoidContribution(ctx context.Context, contributionId int, request VoidContributionRequest) (Adjustment, error)
+	RescoreContribution(ctx context.Context, contributionId int, request RescoreContributionRequest) (Adjustment, error)
+	ApplyJudgedScore(ctx context.Context, tx *sqlx.Tx, judgeId int, contributionId int, score int, reason string) (Adjustment, error)
+}
+
+func NewService(disputeRepository repository.ContributionDisputeRepository, adjustmentRepository repository.ContributionAdjustmentRepository, contributionRepository repository.ContributionRepository, transactionRepository repository.TransactionRepository, summaryRepository repository.SummaryRepository, userRepository repository.UserRepository, notificationService notification.Service, eventBus events.Bus) Service {
+	return &service{
+		disputeRepository:      disputeRepository,
+		adjustmentRepository:   adjustmentRepository,
+		contributionRepository: contributionRepository,
+		transactionRepository:  transactionRepository,
+		summaryRepository:      summaryRepository,
+		userRepository:         userRepository,
+		notificationService:    notificationService,
+		eventBus:               eventBus,
+	}
+}
+
+// FlagContribution opens a dispute on one of the caller's own contributions.
+// Contributions of other users are reported as not found.
+func (s *service) FlagContribution(ctx context.Context, contributionId int, request FlagContributionRequest) (created Dispute, err error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Dispute{}, apperrors.ErrInternalServer
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return Dispute{}, apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.disputeRepository.BeginTx(ctx)
+	if err != nil {
+		return Dispute{}, err
+	}
+	defer func() {
+		txErr := s.disputeRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	contributionInfo, err := s.contributionRepository.GetContributionByIdForUpdate(ctx, tx, contributionId)
+	if err != nil {
+		return Dispute{}, err
+	}
+
+	if contributionInfo.UserId != userId {
+		return Dispute{}, apperrors.ErrContributionNotFound
+	}
+
+	if contributionInfo.Status == contribution.StatusVoided {
+		return Dispute{}, apperrors.ErrContributionVoided
+	}
+
+	dispute, err := s.disputeRepository.CreateDispute(ctx, tx, repository.ContributionDispute{
+		ContributionId: contributionId,
+		UserId:         userId,
+		Reason:         reason,
+	})
+	if err != nil {
+		return Dispute{}, err
+	}
+
+	return newDispute(dispute), nil
+}
+
+func (s *service) ListDisputes(ctx context.Context, status string, limit int, offset int) ([]Dispute, error) {
+	disputes, err := s.disputeRepository.ListDisputes(ctx, nil, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Dispute, 0, len(disputes))
+	for _, dispute := range disputes {
+		result = append(result, newDispute(dispute))
+	}
+
+	return result, nil
+}
+
+func (s *service) ResolveDispute(ctx context.Context, disputeId int, request ResolveDisputeRequest) (resolved Dispute, err error) {
+	adminId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Dispute{}, apperrors.ErrInternalServer
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return Dispute{}, apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.disputeRepository.BeginTx(ctx)
+	if err != nil {
+		return Dispute{}, err
+	}
+	defer func() {
+		txErr := s.disputeRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	dispute, err := s.disputeRepository.GetDisputeByIdForUpdate(ctx, tx, disputeId)
+	if err != nil {
+		return Dispute{}, err
+	}
+
+	if dispute.Status != StatusOpen {
+		return Dispute{}, apperrors.ErrDisputeNotOpen
+	}
+
+	disputeRef := sql.NullInt64{Int64: int64(dispute.Id), Valid: true}
+
+	switch request.Resolution {
+	case ResolutionVoid:
+		_, err = s.adjust(ctx, tx, adminId, dispute.ContributionId, disputeRef, ActionVoid, 0, reason)
+		if err != nil {
+			return Dispute{}, err
+		}
+		dispute.Status = StatusVoided
+
+	case ResolutionRescore:
+		if request.Score == nil {
+			return Dispute{}, apperrors.ErrInvalidRequestBody
+		}
+		if *request.Score < 0 {
+			return Dispute{}, apperrors.ErrInvalidContributionScore
+		}
+		_, err = s.adjust(ctx, tx, adminId, dispute.ContributionId, disputeRef, ActionRescore, *request.Score, reason)
+		if err != nil {
+			return Dispute{}, err
+		}
+		dispute.Status = StatusRescored
+
+	case ResolutionReject:
+		err = s.notificationService.Notify(ctx, tx, dispute.UserId, notification.TypeContributionDisputeRejected, map[string]any{
+			"contribution_id": dispute.ContributionId,
+			"dispute_id":      dispute.Id,
+			"reason":          reason,
+		})
+		if err != nil {
+			return Dispute{}, err
+		}
+		dispute.Status = StatusRejected
+
+	default:
+		return Dispute{}, apperrors.ErrInvalidRequestBody
+	}
+
+	err = s.disputeRepository.ResolveDispute(ctx, tx, dispute.Id, dispute.Status, adminId, reason)
+	if err != nil {
+		return Dispute{}, err
+	}
+
+	now := time.Now()
+	dispute.ResolutionNote = sql.NullString{String: reason, Valid: true}
+	dispute.ResolvedBy = sql.NullInt64{Int64: int64(adminId), Valid: true}
+	dispute.ResolvedAt = sql.NullTime{Time: now, Valid: true}
+	dispute.UpdatedAt = now
+
+	return newDispute(dispute), nil
+}
+
+func (s *service) VoidContribution(ctx context.Context, contributionId int, request VoidContributionRequest) (Adjustment, error) {
+	return s.adjustDirectly(ctx, contributionId, ActionVoid, 0, request.Reason, StatusVoided)
+}
+
+func (s *service) RescoreContribution(ctx context.Context, contributionId int, request RescoreContributionRequest) (Adjustment, error) {
+	if request.Score == nil {
+		return Adjustment{}, apperrors.ErrInvalidRequestBody
+	}
+	if *request.Score < 0 {
+		return Adjustment{}, apperrors.ErrInvalidContributionScore
+	}
+
+	return s.adjustDirectly(ctx, contributionId, ActionRescore, *request.Score, request.Reason, StatusRescored)
+}
+
+// ApplyJudgedScore replaces the balance of a contribution with the score a
+// judges panel settled on, inside the caller's transaction. judgeId is the
+// judge whose score completed the panel, or the admin who closed it early.
+// Disputes left open on the contribution are closed as rescored.
+func (s *service) ApplyJudgedScore(ctx context.Context, tx *sqlx.Tx, judgeId int, contributionId int, score int, reason string) (Adjustment, error) {
+	adjustment, err := s.adjust(ctx, tx, judgeId, contributionId, sql.NullInt64{}, ActionJudge, score, reason)
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	err = s.disputeRepository.ResolveOpenDisputesForContribution(ctx, tx, contributionId, StatusRescored, judgeId, reason)
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	return adjustment, nil
+}
+
+// adjustDirectly applies an admin adjustment made outside of a dispute and
+// closes any dispute left open on the contribution with disputeStatus.
+func (s *service) adjustDirectly(ctx context.Context, contributionId int, action string, newBalanceChange int, reason string, disputeStatus string) (adjustment Adjustment, err error) {
+	adminId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Adjustment{}, apperrors.ErrInternalServer
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return Adjustment{}, apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.disputeRepository.BeginTx(ctx)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	defer func() {
+		txErr := s.disputeRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	adjustment, err = s.adjust(ctx, tx, adminId, contributionId, sql.NullInt64{}, action, newBalanceChange, reason)
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	err = s.disputeRepository.ResolveOpenDisputesForContribution(ctx, tx, contributionId, disputeStatus, adminId, reason)
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	return adjustment, nil
+}
+
+// adjust changes the balance of a contribution by recording an adjustment.
+// History is never edited: the contribution keeps the balance it was scored
+// with and the latest adjustment decides the balance in effect. When the
+// month close already paid the contribution into the wallet, the difference
+// is booked as a compensating transaction and carried into the wallet and
+// the month summary; points the user has already spent cannot be taken
+// back. Otherwise the month close credits the new balance when it runs. The
+// leaderboard picks the new wallet balance up on its next refresh.
+func (s *service) adjust(ctx context.Context, tx *sqlx.Tx, adminId int, contributionId int, disputeId sql.NullInt64, action string, newBalanceChange int, reason string) (Adjustment, error) {
+	contributionInfo, err := s.contributionRepository.GetContributionByIdForUpdate(ctx, tx, contributionId)
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	if contributionInfo.Status == contribution.StatusVoided {
+		return Adjustment{}, apperrors.ErrContributionVoided
+	}
+
+	adjustment, err := s.adjustmentRepository.CreateContributionAdjustment(ctx, tx, repository.ContributionAdjustment{
+		ContributionId:        contributionId,
+		DisputeId:             disputeId,
+		AdminId:               adminId,
+		Action:                action,
+		PreviousBalanceChange: contributionInfo.BalanceChange,
+		NewBalanceChange:      newBalanceChange,
+		Reason:                reason,
+	})
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	delta := newBalanceChange - contributionInfo.BalanceChange
+
+	credited, err := s.transactionRepository.IsContributionCredited(ctx, tx, contributionId)
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	if credited && delta != 0 {
+		_, err = s.transactionRepository.CreateTransaction(ctx, tx, repository.Transaction{
+			UserId:            contributionInfo.UserId,
+			ContributionId:    sql.NullInt64{Int64: int64(contributionId), Valid: true},
+			IsRedeemed:        false,
+			IsGained:          delta > 0,
+			TransactedBalance: abs(delta),
+			TransactedAt:      time.Now(),
+		})
+		if err != nil {
+			return Adjustment{}, err
+		}
+
+		if delta > 0 {
+			err = s.userRepository.IncrementUserBalance(ctx, tx, contributionInfo.UserId, delta)
+		} else {
+			err = s.userRepository.DebitUserBalance(ctx, tx, contributionInfo.UserId, -delta)
+			if errors.Is(err, apperrors.ErrInsufficientBalance) {
+				err = apperrors.ErrAdjustmentExceedsBalance
+			}
+		}
+		if err != nil {
+			return Adjustment{}, err
+		}
+
+		balanceReason := events.BalanceReasonDisputeAdjusted
+		if action == ActionJudge {
+			balanceReason = events.BalanceReasonJudged
+		}
+
+		err = s.eventBus.Publish(ctx, tx, events.New(events.BalanceChanged, contributionInfo.UserId, events.BalanceChangedData{
+			UserId: contributionInfo.UserId,
+			Delta:  delta,
+			Reason: balanceReason,
+		}))
+		if err != nil {
+			return Adjustment{}, err
+		}
+
+		monthYear := summary.MonthYear(contributionInfo.ContributedAt.UTC())
+
+		err = s.summaryRepository.AdjustSummaryNetBalance(ctx, tx, contributionInfo.UserId, monthYear, delta)
+		if err != nil {
+			return Adjustment{}, err
+		}
+
+		err = s.summaryRepository.RankMonth(ctx, tx, monthYear)
+		if err != nil {
+			return Adjustment{}, err
+		}
+	}
+
+	err = s.eventBus.Publish(ctx, tx, events.New(events.ContributionAdjusted, contributionInfo.UserId, events.ContributionAdjustedData{
+		ContributionId: contributionId,
+		UserId:         contributionInfo.UserId,
+		ContributedAt:  contributionInfo.ContributedAt,
+		PointsDelta:    delta,
+		Voided:         action == ActionVoid,
+	}))
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	notificationType := notification.TypeContributionRescored
+	if action == ActionVoid {
+		notificationType = notification.TypeContributionVoided
+	}
+
+	err = s.notificationService.Notify(ctx, tx, contributionInfo.UserId, notificationType, map[string]any{
+		"contribution_id":         contributionId,
+		"previous_balance_change": contributionInfo.BalanceChange,
+		"new_balance_change":      newBalanceChange,
+		"reason":                  reason,
+	})
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	return newAdjustment(adjustment), nil
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func newDispute(dispute repository.ContributionDispute) Dispute {
+	converted := Dispute{
+		Id:             dispute.Id,
+		ContributionId: dispute.ContributionId,
+		UserId:         dispute.UserId,
+		Reason:         dispute.Reason,
+		Status:         dispute.Status,
+		CreatedAt:      dispute.CreatedAt,
+		UpdatedAt:      dispute.UpdatedAt,
+	}
+	if dispute.ResolutionNote.Valid {
+		converted.ResolutionNote = &dispute.ResolutionNote.String
+	}
+	if dispute.ResolvedBy.Valid {
+		converted.ResolvedBy = &dispute.ResolvedBy.Int64
+	}
+	if dispute.ResolvedAt.Valid {
+		converted.ResolvedAt = &dispute.ResolvedAt.Time
+	}
+
+	return converted
+}
+
+func newAdjustment(adjustment repository.ContributionAdjustment) Adjustment {
+	converted := Adjustment{
+		Id:                    adjustment.Id,
+		ContributionId:        adjustment.ContributionId,
+		AdminId:               adjustment.AdminId,
+		Action:                adjustment.Action,
+		PreviousBalanceChange: adjustment.PreviousBalanceChange,
+		NewBalanceChange:      adjustment.NewBalanceChange,
+		Reason:                adjustment.Reason,
+		CreatedAt:             adjustment.CreatedAt,
+	}
+	if adjustment.DisputeId.Valid {
+		converted.DisputeId = &adjustment.DisputeId.Int64
+	}
+
+	return converted
+}
@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+// event types services publish
+const (
+	UserCreated          = "user.created"
+	ContributionRecorded = "contribution.recorded"
+	ContributionScored   = "contribution.scored"
+	ContributionAdjusted = "contribution.adjusted"
+	BalanceChanged       = "balance.changed"
+	BadgeAwarded         = "badge.awarded"
+	GoalCompleted        = "goal.completed"
+	RedemptionFulfilled  = "redemption.fulfilled"
+	LeaderboardRefreshed = "leaderboard.refreshed"
+)
+
+// Types are the event types webhook endpoints can subscribe to. Their
+// payloads are a public contract, so ContributionRecorded, which carries
+// pull request and issue titles, stays internal.
+var Types = []string{UserCreated, ContributionScored, BalanceChanged, BadgeAwarded, GoalCompleted, RedemptionFulfilled, LeaderboardRefreshed}
+
+// outbox statuses, kept in sync with the event_outbox_status_check constraint
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusProcessed = "processed"
+	OutboxStatusFailed    = "failed"
+)
+
+var OutboxStatuses = []string{OutboxStatusPending, OutboxStatusProcessed, OutboxStatusFailed}
+
+// reasons a BalanceChanged event is published for
+const (
+	BalanceReasonMonthClose       = "month_close"
+	BalanceReasonDisputeAdjusted  = "dispute_adjusted"
+	BalanceReasonRedemption       = "redemption"
+	BalanceReasonRedemptionRefund = "redemption_refund"
+	BalanceReasonStreakFreeze     = "streak_freeze"
+	BalanceReasonChallengePrize   = "challenge_prize"
+	BalanceReasonJudged           = "judged"
+)
+
+// Event is something that happened. UserId is the user the event is about,
+// or zero for events that concern everyone. Data holds the typed payload
+// for sync subscribers and the stored JSON for async ones.
+type Event struct {
+	Id         string
+	Type       string
+	UserId     int
+	OccurredAt time.Time
+	Data       any
+}
+
+type UserCreatedData struct {
+	UserId         int    `json:"user_id"`
+	GithubUsername string `json:"github_username"`
+}
+
+type ContributionRecordedData struct {
+	ContributionId   int       `json:"contribution_id"`
+	UserId           int       `json:"user_id"`
+	RepositoryId     int       `json:"repository_id"`
+	ContributionType string    `json:"contribution_type"`
+	Points           int       `json:"points"`
+	ContributedAt    time.Time `json:"contributed_at"`
+	LinesChanged     int       `json:"lines_changed,omitempty"`
+	Title            string    `json:"title,omitempty"`
+	Url              string    `json:"url,omitempty"`
+}
+
+// ContributionAdjustedData carries the change a void or rescore made to a
+// recorded contribution.
+type ContributionAdjustedData struct {
+	ContributionId int       `json:"contribution_id"`
+	UserId         int       `json:"user_id"`
+	ContributedAt  time.Time `json:"contributed_at"`
+	PointsDelta    int       `json:"points_delta"`
+	Voided         bool      `json:"voided"`
+}
+
+// BalanceChangedData carries the signed change to the user's balance.
+type BalanceChangedData struct {
+	UserId int    `json:"user_id"`
+	Delta  int    `json:"delta"`
+	Reason string `json:"reason"`
+}
+
+type BadgeAwardedData struct {
+	UserId    int       `json:"user_id"`
+	BadgeType string    `json:"badge_type"`
+	EarnedAt  time.Time `json:"earned_at"`
+}
+
+// GoalCompletedData is published when month close finds the user met every
+// target of their active goal. MonthYear is formatted as YYYYMM.
+type GoalCompletedData struct {
+	UserId    int    `json:"user_id"`
+	Level     string `json:"level"`
+	MonthYear int    `json:"month_year"`
+}
+
+// Handler reacts to an event. Sync handlers run inside the publisher's
+// transaction and returning an error rolls the publisher's change back.
+// Async handlers run inside the dispatcher's transaction and returning an
+// error retries the event later.
+type Handler func(ctx context.Context, tx *sqlx.Tx, event Event) error
+
+// HandlerFor adapts a function taking the typed payload into a Handler, so
+// the same function serves as a sync or an async subscriber.
+func HandlerFor[T any](fn func(ctx context.Context, tx *sqlx.Tx, event Event, data T) error) Handler {
+	return func(ctx context.Context, tx *sqlx.Tx, event Event) error {
+		data, ok := event.Data.(T)
+		if !ok {
+			raw, isRaw := event.Data.(json.RawMessage)
+			if !isRaw {
+				slog.Error("unexpected event payload", "event_type", event.Type, "event_id", event.Id)
+				return apperrors.ErrInternalServer
+			}
+
+			err := json.Unmarshal(raw, &data)
+			if err != nil {
+				slog.Error("failed to unmarshal event payload", "event_type", event.Type, "event_id", event.Id, "error", err)
+				return apperrors.ErrInternalServer
+			}
+		}
+
+		return fn(ctx, tx, event, data)
+	}
+}
@@ -0,0 +1,281 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+const (
+	pollInterval   = 2 * time.Second
+	handlerTimeout = 5 * time.Minute
+
+	baseRetryDelay = 10 * time.Second
+	maxRetryDelay  = time.Hour
+
+	// processed events older than the retention are deleted this often,
+	// this many per statement
+	pruneInterval  = time.Hour
+	pruneBatchSize = 1000
+)
+
+type bus struct {
+	eventOutboxRepository repository.EventOutboxRepository
+	maxAttempts           int
+	retention             time.Duration
+	prunedAt              time.Time
+
+	mu          sync.RWMutex
+	handlers    map[string][]Handler
+	async       map[string][]string
+	asyncByName map[string]Handler
+}
+
+type Bus interface {
+	Subscribe(eventType string, handler Handler)
+	SubscribeAsync(eventType string, subscriber string, handler Handler)
+	Publish(ctx context.Context, tx *sqlx.Tx, event Event) error
+	Run(ctx context.Context)
+}
+
+func NewBus(eventOutboxRepository repository.EventOutboxRepository, appCfg config.AppConfig) Bus {
+	return &bus{
+		eventOutboxRepository: eventOutboxRepository,
+		maxAttempts:           appCfg.Events.MaxAttempts,
+		retention:             appCfg.Events.Retention,
+		handlers:              make(map[string][]Handler),
+		async:                 make(map[string][]string),
+		asyncByName:           make(map[string]Handler),
+	}
+}
+
+// New builds an event of the given type with a fresh id.
+func New(eventType string, userId int, data any) Event {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return Event{
+		Id:         hex.EncodeToString(id),
+		Type:       eventType,
+		UserId:     userId,
+		OccurredAt: time.Now(),
+		Data:       data,
+	}
+}
+
+// Subscribe registers a handler run synchronously by Publish.
+func (b *bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// SubscribeAsync registers a handler run by the dispatcher after the
+// publisher commits. The subscriber name identifies its outbox rows, so it
+// must stay stable across releases and be unique per event type.
+func (b *bus) SubscribeAsync(eventType string, subscriber string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.async[eventType] = append(b.async[eventType], subscriber)
+	b.asyncByName[asyncKey(eventType, subscriber)] = handler
+}
+
+// Publish runs the event's sync handlers in subscription order, stopping at
+// the first error, then writes one outbox row per async subscriber. Pass the
+// caller's transaction so both commit or roll back with the change the
+// event describes.
+func (b *bus) Publish(ctx context.Context, tx *sqlx.Tx, event Event) error {
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	subscribers := b.async[event.Type]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		err := handler(ctx, tx, event)
+		if err != nil {
+			slog.Error("event handler failed", "event_type", event.Type, "event_id", event.Id, "error", err)
+			return err
+		}
+	}
+
+	if len(subscribers) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event.Data)
+	if err != nil {
+		slog.Error("failed to marshal event payload", "event_type", event.Type, "event_id", event.Id, "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	for _, subscriber := range subscribers {
+		err = b.eventOutboxRepository.CreateOutboxEvent(ctx, tx, repository.OutboxEvent{
+			EventId:    event.Id,
+			EventType:  event.Type,
+			Subscriber: subscriber,
+			UserId:     sql.NullInt64{Int64: int64(event.UserId), Valid: event.UserId != 0},
+			Payload:    payload,
+			OccurredAt: event.OccurredAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run dispatches outbox events to their async subscribers until ctx is
+// cancelled. Each event is handled in the transaction that claimed it, so
+// whatever the subscriber writes commits together with marking it
+// processed. While idle it deletes processed events past the retention;
+// failed ones are kept for inspection.
+func (b *bus) Run(ctx context.Context) {
+	slog.Info("event dispatcher started")
+
+	for ctx.Err() == nil {
+		err := b.dispatchNext(context.WithoutCancel(ctx))
+		if err == nil {
+			continue
+		}
+
+		if errors.Is(err, apperrors.ErrOutboxEventNotFound) {
+			b.pruneProcessed(ctx)
+		} else {
+			slog.Error("failed to dispatch outbox event", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(pollInterval):
+		}
+	}
+
+	slog.Info("event dispatcher stopped")
+}
+
+func (b *bus) dispatchNext(ctx context.Context) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, handlerTimeout)
+	defer cancel()
+
+	tx, err := b.eventOutboxRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	outboxEvent, err := b.eventOutboxRepository.ClaimNextOutboxEvent(ctx, tx, b.subscribers())
+	if err != nil {
+		_ = b.eventOutboxRepository.HandleTransaction(ctx, tx, err)
+		return err
+	}
+
+	handlerErr := b.invoke(ctx, tx, outboxEvent)
+	if handlerErr == nil {
+		err = b.eventOutboxRepository.MarkOutboxEventProcessed(ctx, tx, outboxEvent.Id)
+		txErr := b.eventOutboxRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			return txErr
+		}
+		return err
+	}
+
+	// the subscriber's writes are rolled back before the failure is recorded
+	err = b.eventOutboxRepository.HandleTransaction(ctx, tx, handlerErr)
+	if err != nil {
+		return err
+	}
+
+	attempts := outboxEvent.Attempts + 1
+	status := OutboxStatusPending
+	if attempts >= b.maxAttempts {
+		status = OutboxStatusFailed
+	}
+
+	slog.Error("event subscriber failed", "event_id", outboxEvent.EventId, "event_type", outboxEvent.EventType, "subscriber", outboxEvent.Subscriber, "attempt", attempts, "status", status, "error", handlerErr)
+	return b.eventOutboxRepository.MarkOutboxEventFailed(ctx, nil, outboxEvent.Id, status, handlerErr.Error(), time.Now().Add(retryDelay(attempts)))
+}
+
+func (b *bus) pruneProcessed(ctx context.Context) {
+	if b.retention <= 0 || time.Since(b.prunedAt) < pruneInterval {
+		return
+	}
+	b.prunedAt = time.Now()
+
+	before := b.prunedAt.Add(-b.retention)
+	var total int64
+	for {
+		deleted, err := b.eventOutboxRepository.DeleteProcessedOutboxEvents(ctx, nil, before, pruneBatchSize)
+		if err != nil {
+			return
+		}
+		total += deleted
+		if deleted < pruneBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		slog.Info("pruned processed outbox events", "count", total)
+	}
+}
+
+func (b *bus) invoke(ctx context.Context, tx *sqlx.Tx, outboxEvent repository.OutboxEvent) (err error) {
+	b.mu.RLock()
+	handler, ok := b.asyncByName[asyncKey(outboxEvent.EventType, outboxEvent.Subscriber)]
+	b.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no subscriber %q for event type %q", outboxEvent.Subscriber, outboxEvent.EventType)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("event subscriber panicked: %v", recovered)
+		}
+	}()
+
+	return handler(ctx, tx, Event{
+		Id:         outboxEvent.EventId,
+		Type:       outboxEvent.EventType,
+		UserId:     int(outboxEvent.UserId.Int64),
+		OccurredAt: outboxEvent.OccurredAt,
+		Data:       json.RawMessage(outboxEvent.Payload),
+	})
+}
+
+func (b *bus) subscribers() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	names := []string{}
+	for _, subscribers := range b.async {
+		names = append(names, subscribers...)
+	}
+	return names
+}
+
+func asyncKey(eventType string, subscriber string) string {
+	return eventType + "/" + subscriber
+}
+
+// retryDelay doubles with every failed attempt up to maxRetryDelay.
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay << min(attempts, 16)
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
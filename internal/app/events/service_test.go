@@ -0,0 +1,28 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first retry", attempts: 1, want: 20 * time.Second},
+		{name: "second retry", attempts: 2, want: 40 * time.Second},
+		{name: "eighth retry", attempts: 8, want: 2560 * time.Second},
+		{name: "capped", attempts: 9, want: time.Hour},
+		{name: "shift bounded", attempts: 1000, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(tt.attempts); got != tt.want {
+				t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package fraud
+
+import (
+	"context"
+	"time"
+)
+
+// What a rule does with a suspicious contribution, kept in sync with the
+// contribution_flags_action_check constraint. Flagged contributions are
+// recorded and queued for review, discarded ones are never recorded.
+const (
+	ActionFlag    = "flag"
+	ActionDiscard = "discard"
+)
+
+var Actions = []string{ActionFlag, ActionDiscard}
+
+// flag statuses, kept in sync with the contribution_flags_status_check
+// constraint. Discards need no review and start out confirmed.
+const (
+	FlagStatusPending   = "pending"
+	FlagStatusConfirmed = "confirmed"
+	FlagStatusDismissed = "dismissed"
+)
+
+var FlagStatuses = []string{FlagStatusPending, FlagStatusConfirmed, FlagStatusDismissed}
+
+// review decisions
+const (
+	DecisionConfirm = "confirm"
+	DecisionDismiss = "dismiss"
+)
+
+// rules and the risk points a finding adds to the user's score
+const (
+	RuleLowSignalRepository   = "low_signal_repository"
+	RuleSelfMergedPullRequest = "self_merged_pull_request"
+	RuleWhitespaceOnlyCommit  = "whitespace_only_commit"
+	RuleBurstRate             = "burst_rate"
+	RuleDuplicateCommit       = "duplicate_commit"
+	RuleSpamIssue             = "spam_issue"
+
+	lowSignalRepositoryPoints   = 1
+	selfMergedPullRequestPoints = 2
+	whitespaceOnlyCommitPoints  = 5
+	burstRatePoints             = 10
+	duplicateCommitPoints       = 5
+	spamIssuePoints             = 25
+)
+
+// Candidate is a contribution about to be recorded, with the context the
+// rules look at.
+type Candidate struct {
+	UserId             int
+	RepositoryId       int
+	RepositoryFullName string
+	RepositoryStars    int
+	ContributionType   string
+	ExternalId         string
+	DeliveryId         string
+	ContributedAt      time.Time
+	CommitSha          string
+	AuthorGithubId     int
+	MergedByGithubId   int
+}
+
+type Finding struct {
+	Rule       string
+	Action     string
+	Reason     string
+	RiskPoints int
+}
+
+// Rule is one anomaly check of the ingestion pipeline. Evaluate reports a
+// finding only when the candidate looks suspicious.
+type Rule interface {
+	Name() string
+	Evaluate(ctx context.Context, candidate Candidate) (Finding, bool, error)
+}
+
+type Assessment struct {
+	Findings []Finding
+}
+
+// Discarded reports whether any rule discards the contribution.
+func (a Assessment) Discarded() bool {
+	for _, finding := range a.Findings {
+		if finding.Action == ActionDiscard {
+			return true
+		}
+	}
+	return false
+}
+
+type Flag struct {
+	Id             int64      `json:"id"`
+	UserId         int        `json:"user_id"`
+	ContributionId *int64     `json:"contribution_id"`
+	ExternalId     string     `json:"external_id"`
+	Rule           string     `json:"rule"`
+	Action         string     `json:"action"`
+	Reason         string     `json:"reason"`
+	RiskPoints     int        `json:"risk_points"`
+	Status         string     `json:"status"`
+	ReviewNote     *string    `json:"review_note"`
+	ReviewedBy     *int64     `json:"reviewed_by"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type ListFlagsRequest struct {
+	Status string
+	UserId int
+	Limit  int
+	Offset int
+}
+
+type ReviewFlagRequest struct {
+	Decision string `json:"decision"`
+	Note     string `json:"note"`
+}
+
+type RiskScore struct {
+	UserId         int       `json:"user_id"`
+	GithubUsername string    `json:"github_username"`
+	IsBlocked      bool      `json:"is_blocked"`
+	Score          int       `json:"score"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,146 @@
+package fraud
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	fraudService Service
+}
+
+type Handler interface {
+	ListFlags(w http.ResponseWriter, r *http.Request)
+	ReviewFlag(w http.ResponseWriter, r *http.Request)
+	ListRiskScores(w http.ResponseWriter, r *http.Request)
+	UnblockUser(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(fraudService Service) Handler {
+	return &handler{
+		fraudService: fraudService,
+	}
+}
+
+func (h *handler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	listRequest := ListFlagsRequest{
+		Status: r.URL.Query().Get("status"),
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	if listRequest.Status != "" && !slices.Contains(FlagStatuses, listRequest.Status) {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	if userIdParam := r.URL.Query().Get("user_id"); userIdParam != "" {
+		listRequest.UserId, err = strconv.Atoi(userIdParam)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+	}
+
+	flags, err := h.fraudService.ListFlags(ctx, listRequest)
+	if err != nil {
+		slog.Error("failed to list contribution flags", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "contribution flags fetched successfully", flags)
+}
+
+func (h *handler) ReviewFlag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	flagId, err := strconv.ParseInt(r.PathValue("flagId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody ReviewFlagRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	flag, err := h.fraudService.ReviewFlag(ctx, flagId, requestBody)
+	if err != nil {
+		slog.Error("failed to review contribution flag", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "contribution flag reviewed", flag)
+}
+
+func (h *handler) ListRiskScores(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	minScore := 0
+	if minScoreParam := r.URL.Query().Get("min_score"); minScoreParam != "" {
+		minScore, err = strconv.Atoi(minScoreParam)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+	}
+
+	scores, err := h.fraudService.ListRiskScores(ctx, minScore, limit, offset)
+	if err != nil {
+		slog.Error("failed to list risk scores", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "risk scores fetched successfully", scores)
+}
+
+func (h *handler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := strconv.Atoi(r.PathValue("userId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.fraudService.UnblockUser(ctx, userId)
+	if err != nil {
+		slog.Error("failed to unblock user", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "user unblocked", nil)
+}
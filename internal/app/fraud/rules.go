@@ -0,0 +1,218 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/githubtoken"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	ghclient "github.com/joshsoftware/code-curiosity-2025/internal/pkg/github"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+// DefaultRules returns the rules every contribution is screened with
+func DefaultRules(contributionRepository repository.ContributionRepository, githubTokenService githubtoken.Service, appCfg config.AppConfig) []Rule {
+	return []Rule{
+		&duplicateCommitRule{contributionRepository: contributionRepository},
+		&whitespaceOnlyCommitRule{githubTokenService: githubTokenService},
+		&lowSignalRepositoryRule{contributionRepository: contributionRepository},
+		&selfMergedPullRequestRule{},
+		&burstRateRule{contributionRepository: contributionRepository, limit: appCfg.Fraud.BurstLimit, window: appCfg.Fraud.BurstWindow},
+	}
+}
+
+// duplicateCommitRule discards a commit already counted in another
+// repository, which is what pushing the same commit to a fork looks like.
+type duplicateCommitRule struct {
+	contributionRepository repository.ContributionRepository
+}
+
+func (r *duplicateCommitRule) Name() string {
+	return RuleDuplicateCommit
+}
+
+func (r *duplicateCommitRule) Evaluate(ctx context.Context, candidate Candidate) (Finding, bool, error) {
+	if candidate.CommitSha == "" {
+		return Finding{}, false, nil
+	}
+
+	recorded, err := r.contributionRepository.IsCommitShaRecorded(ctx, nil, candidate.CommitSha, candidate.ExternalId)
+	if err != nil || !recorded {
+		return Finding{}, false, err
+	}
+
+	return Finding{
+		Rule:       RuleDuplicateCommit,
+		Action:     ActionDiscard,
+		Reason:     fmt.Sprintf("commit %s is already counted in another repository", candidate.CommitSha),
+		RiskPoints: duplicateCommitPoints,
+	}, true, nil
+}
+
+// whitespaceOnlyCommitRule discards commits whose diff only moves whitespace
+// around. The diff is fetched with the contributor's token, and the commit is
+// let through when it cannot be fetched.
+type whitespaceOnlyCommitRule struct {
+	githubTokenService githubtoken.Service
+}
+
+type githubCommit struct {
+	Files []struct {
+		Patch string `json:"patch"`
+	} `json:"files"`
+}
+
+func (r *whitespaceOnlyCommitRule) Name() string {
+	return RuleWhitespaceOnlyCommit
+}
+
+func (r *whitespaceOnlyCommitRule) Evaluate(ctx context.Context, candidate Candidate) (Finding, bool, error) {
+	if candidate.CommitSha == "" || candidate.RepositoryFullName == "" {
+		return Finding{}, false, nil
+	}
+
+	client, err := r.githubTokenService.NewUserClient(ctx, candidate.UserId)
+	if err != nil {
+		slog.Warn("skipping whitespace check without a github client", "user_id", candidate.UserId, "error", err)
+		return Finding{}, false, nil
+	}
+
+	var commit githubCommit
+	_, err = client.Get(ctx, fmt.Sprintf("%s/repos/%s/commits/%s", ghclient.APIBaseURL, candidate.RepositoryFullName, candidate.CommitSha), &commit)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return Finding{}, false, err
+		}
+		slog.Warn("skipping whitespace check, commit could not be fetched", "sha", candidate.CommitSha, "error", err)
+		return Finding{}, false, nil
+	}
+
+	if len(commit.Files) == 0 {
+		return Finding{}, false, nil
+	}
+
+	for _, file := range commit.Files {
+		// binary files and renames carry no patch
+		if file.Patch == "" || !whitespaceOnlyPatch(file.Patch) {
+			return Finding{}, false, nil
+		}
+	}
+
+	return Finding{
+		Rule:       RuleWhitespaceOnlyCommit,
+		Action:     ActionDiscard,
+		Reason:     fmt.Sprintf("commit %s only changes whitespace", candidate.CommitSha),
+		RiskPoints: whitespaceOnlyCommitPoints,
+	}, true, nil
+}
+
+// whitespaceOnlyPatch reports whether the removed and added lines of a
+// unified diff are the same once all whitespace is dropped.
+func whitespaceOnlyPatch(patch string) bool {
+	var removed, added strings.Builder
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+		case strings.HasPrefix(line, "-"):
+			removed.WriteString(stripWhitespace(line[1:]))
+		case strings.HasPrefix(line, "+"):
+			added.WriteString(stripWhitespace(line[1:]))
+		}
+	}
+
+	return removed.String() == added.String()
+}
+
+func stripWhitespace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// lowSignalRepositoryRule flags contributions to repositories nobody has
+// starred and no other registered user contributes to, the shape of a
+// throwaway repository created to farm points.
+type lowSignalRepositoryRule struct {
+	contributionRepository repository.ContributionRepository
+}
+
+func (r *lowSignalRepositoryRule) Name() string {
+	return RuleLowSignalRepository
+}
+
+func (r *lowSignalRepositoryRule) Evaluate(ctx context.Context, candidate Candidate) (Finding, bool, error) {
+	if candidate.RepositoryStars > 0 {
+		return Finding{}, false, nil
+	}
+
+	contributors, err := r.contributionRepository.CountOtherRepositoryContributors(ctx, nil, candidate.RepositoryId, candidate.UserId)
+	if err != nil || contributors > 0 {
+		return Finding{}, false, err
+	}
+
+	return Finding{
+		Rule:       RuleLowSignalRepository,
+		Action:     ActionFlag,
+		Reason:     fmt.Sprintf("repository %s has no stars and no other contributors", candidate.RepositoryFullName),
+		RiskPoints: lowSignalRepositoryPoints,
+	}, true, nil
+}
+
+// selfMergedPullRequestRule flags pull requests merged by their own author.
+type selfMergedPullRequestRule struct{}
+
+func (r *selfMergedPullRequestRule) Name() string {
+	return RuleSelfMergedPullRequest
+}
+
+func (r *selfMergedPullRequestRule) Evaluate(ctx context.Context, candidate Candidate) (Finding, bool, error) {
+	if candidate.ContributionType != contribution.PullRequestMerged || candidate.MergedByGithubId == 0 || candidate.MergedByGithubId != candidate.AuthorGithubId {
+		return Finding{}, false, nil
+	}
+
+	return Finding{
+		Rule:       RuleSelfMergedPullRequest,
+		Action:     ActionFlag,
+		Reason:     "pull request was merged by its author",
+		RiskPoints: selfMergedPullRequestPoints,
+	}, true, nil
+}
+
+// burstRateRule flags contributions made faster than anyone works by hand.
+// The commits of one push count as a single contribution.
+type burstRateRule struct {
+	contributionRepository repository.ContributionRepository
+	limit                  int
+	window                 time.Duration
+}
+
+func (r *burstRateRule) Name() string {
+	return RuleBurstRate
+}
+
+func (r *burstRateRule) Evaluate(ctx context.Context, candidate Candidate) (Finding, bool, error) {
+	if r.limit <= 0 || r.window <= 0 {
+		return Finding{}, false, nil
+	}
+
+	count, err := r.contributionRepository.CountUserContributionsBetween(ctx, nil, candidate.UserId, candidate.ContributedAt.Add(-r.window), candidate.ContributedAt, candidate.DeliveryId)
+	if err != nil || count < r.limit {
+		return Finding{}, false, err
+	}
+
+	return Finding{
+		Rule:       RuleBurstRate,
+		Action:     ActionFlag,
+		Reason:     fmt.Sprintf("%d contributions recorded within %s", count+1, r.window),
+		RiskPoints: burstRatePoints,
+	}, true, nil
+}
@@ -0,0 +1,196 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+// fakeContributionRepository answers the lookups the rules make. Any other
+// method panics through the nil embedded interface.
+type fakeContributionRepository struct {
+	repository.ContributionRepository
+	commitShaRecorded bool
+	otherContributors int
+	recentCount       int
+	err               error
+
+	from, to          time.Time
+	excludeDeliveryId string
+}
+
+func (f *fakeContributionRepository) IsCommitShaRecorded(ctx context.Context, tx *sqlx.Tx, commitSha string, externalId string) (bool, error) {
+	return f.commitShaRecorded, f.err
+}
+
+func (f *fakeContributionRepository) CountOtherRepositoryContributors(ctx context.Context, tx *sqlx.Tx, repositoryId int, userId int) (int, error) {
+	return f.otherContributors, f.err
+}
+
+func (f *fakeContributionRepository) CountUserContributionsBetween(ctx context.Context, tx *sqlx.Tx, userId int, from, to time.Time, excludeDeliveryId string) (int, error) {
+	f.from, f.to, f.excludeDeliveryId = from, to, excludeDeliveryId
+	return f.recentCount, f.err
+}
+
+var errLookupFailed = errors.New("lookup failed")
+
+func TestWhitespaceOnlyPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  bool
+	}{
+		{name: "reindented line", patch: "@@ -1,1 +1,1 @@\n-\tfoo()\n+    foo()", want: true},
+		{name: "trailing whitespace", patch: "@@ -1 +1 @@\n-foo() \n+foo()", want: true},
+		{name: "line split across lines", patch: "@@ -1 +1,2 @@\n-foo(a, b)\n+foo(a,\n+    b)", want: true},
+		{name: "blank lines added", patch: "@@ -1 +1,3 @@\n+\n+\n context", want: true},
+		{name: "changed identifier", patch: "@@ -1 +1 @@\n-foo()\n+bar()", want: false},
+		{name: "added code", patch: "@@ -1 +1,2 @@\n foo()\n+bar()", want: false},
+		{name: "removed code", patch: "@@ -1,2 +1 @@\n foo()\n-bar()", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := whitespaceOnlyPatch(tt.patch); got != tt.want {
+				t.Errorf("whitespaceOnlyPatch(%q) = %v, want %v", tt.patch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelfMergedPullRequestRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate Candidate
+		want      bool
+	}{
+		{name: "merged by author", candidate: Candidate{ContributionType: contribution.PullRequestMerged, AuthorGithubId: 7, MergedByGithubId: 7}, want: true},
+		{name: "merged by someone else", candidate: Candidate{ContributionType: contribution.PullRequestMerged, AuthorGithubId: 7, MergedByGithubId: 8}},
+		{name: "merger unknown", candidate: Candidate{ContributionType: contribution.PullRequestMerged, AuthorGithubId: 7}},
+		{name: "not a merged pull request", candidate: Candidate{ContributionType: contribution.PullRequestOpened, AuthorGithubId: 7, MergedByGithubId: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &selfMergedPullRequestRule{}
+			finding, found, err := rule.Evaluate(context.Background(), tt.candidate)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if found != tt.want {
+				t.Fatalf("Evaluate() found = %v, want %v", found, tt.want)
+			}
+			if found && (finding.Rule != RuleSelfMergedPullRequest || finding.Action != ActionFlag) {
+				t.Errorf("Evaluate() finding = %+v, want a %s flag", finding, RuleSelfMergedPullRequest)
+			}
+		})
+	}
+}
+
+func TestDuplicateCommitRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate Candidate
+		repo      fakeContributionRepository
+		want      bool
+		wantErr   bool
+	}{
+		{name: "commit recorded elsewhere", candidate: Candidate{CommitSha: "abc"}, repo: fakeContributionRepository{commitShaRecorded: true}, want: true},
+		{name: "new commit", candidate: Candidate{CommitSha: "abc"}},
+		{name: "not a commit", candidate: Candidate{}, repo: fakeContributionRepository{commitShaRecorded: true}},
+		{name: "lookup fails", candidate: Candidate{CommitSha: "abc"}, repo: fakeContributionRepository{err: errLookupFailed}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &duplicateCommitRule{contributionRepository: &tt.repo}
+			finding, found, err := rule.Evaluate(context.Background(), tt.candidate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if found != tt.want {
+				t.Fatalf("Evaluate() found = %v, want %v", found, tt.want)
+			}
+			if found && finding.Action != ActionDiscard {
+				t.Errorf("Evaluate() action = %q, want %q", finding.Action, ActionDiscard)
+			}
+		})
+	}
+}
+
+func TestLowSignalRepositoryRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate Candidate
+		repo      fakeContributionRepository
+		want      bool
+		wantErr   bool
+	}{
+		{name: "unstarred and no other contributors", candidate: Candidate{RepositoryStars: 0}, want: true},
+		{name: "starred", candidate: Candidate{RepositoryStars: 3}},
+		{name: "other contributors", candidate: Candidate{RepositoryStars: 0}, repo: fakeContributionRepository{otherContributors: 2}},
+		{name: "lookup fails", candidate: Candidate{RepositoryStars: 0}, repo: fakeContributionRepository{err: errLookupFailed}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &lowSignalRepositoryRule{contributionRepository: &tt.repo}
+			_, found, err := rule.Evaluate(context.Background(), tt.candidate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if found != tt.want {
+				t.Errorf("Evaluate() found = %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
+
+func TestBurstRateRule(t *testing.T) {
+	contributedAt := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		limit   int
+		window  time.Duration
+		repo    fakeContributionRepository
+		want    bool
+		wantErr bool
+	}{
+		{name: "at the limit", limit: 5, window: time.Minute, repo: fakeContributionRepository{recentCount: 5}, want: true},
+		{name: "below the limit", limit: 5, window: time.Minute, repo: fakeContributionRepository{recentCount: 4}},
+		{name: "disabled limit", limit: 0, window: time.Minute, repo: fakeContributionRepository{recentCount: 50}},
+		{name: "disabled window", limit: 5, window: 0, repo: fakeContributionRepository{recentCount: 50}},
+		{name: "lookup fails", limit: 5, window: time.Minute, repo: fakeContributionRepository{err: errLookupFailed}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &burstRateRule{contributionRepository: &tt.repo, limit: tt.limit, window: tt.window}
+			_, found, err := rule.Evaluate(context.Background(), Candidate{UserId: 1, ContributedAt: contributedAt, DeliveryId: "delivery"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if found != tt.want {
+				t.Fatalf("Evaluate() found = %v, want %v", found, tt.want)
+			}
+		})
+	}
+
+	t.Run("counts the window before the contribution outside its push", func(t *testing.T) {
+		repo := &fakeContributionRepository{}
+		rule := &burstRateRule{contributionRepository: repo, limit: 5, window: time.Hour}
+		_, _, err := rule.Evaluate(context.Background(), Candidate{UserId: 1, ContributedAt: contributedAt, DeliveryId: "delivery"})
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		if !repo.from.Equal(contributedAt.Add(-time.Hour)) || !repo.to.Equal(contributedAt) || repo.excludeDeliveryId != "delivery" {
+			t.Errorf("counted from %v to %v excluding %q, want from %v to %v excluding %q", repo.from, repo.to, repo.excludeDeliveryId, contributedAt.Add(-time.Hour), contributedAt, "delivery")
+		}
+	})
+}
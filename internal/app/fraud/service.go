@@ -0,0 +1,334 @@
+package fraud
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/dispute"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/notification"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	contributionFlagRepository repository.ContributionFlagRepository
+	riskScoreRepository        repository.RiskScoreRepository
+	userRepository             repository.UserRepository
+	contributionRepository     repository.ContributionRepository
+	disputeService             dispute.Service
+	notificationService        notification.Service
+	rules                      []Rule
+	appCfg                     config.AppConfig
+}
+
+type Service interface {
+	Screen(ctx context.Context, candidate Candidate) (Assessment, error)
+	RecordAssessment(ctx context.Context, tx *sqlx.Tx, candidate Candidate, contributionId int, assessment Assessment) error
+	ReportSpamIssue(ctx context.Context, externalId string) error
+	ListFlags(ctx context.Context, request ListFlagsRequest) ([]Flag, error)
+	ReviewFlag(ctx context.Context, flagId int64, request ReviewFlagRequest) (Flag, error)
+	ListRiskScores(ctx context.Context, minScore int, limit int, offset int) ([]RiskScore, error)
+	UnblockUser(ctx context.Context, userId int) error
+}
+
+func NewService(contributionFlagRepository repository.ContributionFlagRepository, riskScoreRepository repository.RiskScoreRepository, userRepository repository.UserRepository, contributionRepository repository.ContributionRepository, disputeService dispute.Service, notificationService notification.Service, rules []Rule, appCfg config.AppConfig) Service {
+	return &service{
+		contributionFlagRepository: contributionFlagRepository,
+		riskScoreRepository:        riskScoreRepository,
+		userRepository:             userRepository,
+		contributionRepository:     contributionRepository,
+		disputeService:             disputeService,
+		notificationService:        notificationService,
+		rules:                      rules,
+		appCfg:                     appCfg,
+	}
+}
+
+// Screen runs every rule against the candidate. Nothing is stored until
+// RecordAssessment is called.
+func (s *service) Screen(ctx context.Context, candidate Candidate) (Assessment, error) {
+	var assessment Assessment
+	for _, rule := range s.rules {
+		finding, found, err := rule.Evaluate(ctx, candidate)
+		if err != nil {
+			slog.Error("fraud rule failed", "rule", rule.Name(), "external_id", candidate.ExternalId, "error", err)
+			return Assessment{}, err
+		}
+		if found {
+			assessment.Findings = append(assessment.Findings, finding)
+		}
+	}
+
+	return assessment, nil
+}
+
+// RecordAssessment stores the findings, adds their risk points to the user's
+// score and blocks the user once the score reaches the threshold, inside the
+// caller's transaction. Pass a zero contributionId for a discarded
+// contribution.
+func (s *service) RecordAssessment(ctx context.Context, tx *sqlx.Tx, candidate Candidate, contributionId int, assessment Assessment) (err error) {
+	if len(assessment.Findings) == 0 {
+		return nil
+	}
+
+	points := 0
+	for _, finding := range assessment.Findings {
+		status := FlagStatusPending
+		if finding.Action == ActionDiscard {
+			status = FlagStatusConfirmed
+		}
+
+		_, err = s.contributionFlagRepository.CreateContributionFlag(ctx, tx, repository.ContributionFlag{
+			UserId:         candidate.UserId,
+			ContributionId: sql.NullInt64{Int64: int64(contributionId), Valid: contributionId != 0},
+			ExternalId:     candidate.ExternalId,
+			Rule:           finding.Rule,
+			Action:         finding.Action,
+			Reason:         finding.Reason,
+			RiskPoints:     finding.RiskPoints,
+			Status:         status,
+		})
+		if errors.Is(err, apperrors.ErrContributionFlagExists) {
+			err = nil
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		slog.Info("contribution flagged", "rule", finding.Rule, "action", finding.Action, "user_id", candidate.UserId, "external_id", candidate.ExternalId)
+		points += finding.RiskPoints
+	}
+
+	if points == 0 {
+		return nil
+	}
+
+	return s.addRisk(ctx, tx, candidate.UserId, points)
+}
+
+// ReportSpamIssue flags the contribution of an issue its maintainers closed
+// as spam. Issues of unregistered users are ignored.
+func (s *service) ReportSpamIssue(ctx context.Context, externalId string) (err error) {
+	contributionInfo, err := s.contributionRepository.GetContributionByExternalId(ctx, nil, externalId)
+	if errors.Is(err, apperrors.ErrContributionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	candidate := Candidate{
+		UserId:           contributionInfo.UserId,
+		RepositoryId:     contributionInfo.RepositoryId,
+		ContributionType: contributionInfo.ContributionType,
+		ExternalId:       externalId,
+		ContributedAt:    contributionInfo.ContributedAt,
+	}
+
+	tx, err := s.contributionFlagRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		txErr := s.contributionFlagRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	return s.RecordAssessment(ctx, tx, candidate, contributionInfo.Id, Assessment{Findings: []Finding{{
+		Rule:       RuleSpamIssue,
+		Action:     ActionFlag,
+		Reason:     "issue was closed as spam by the repository maintainers",
+		RiskPoints: spamIssuePoints,
+	}}})
+}
+
+func (s *service) ListFlags(ctx context.Context, request ListFlagsRequest) ([]Flag, error) {
+	flags, err := s.contributionFlagRepository.ListContributionFlags(ctx, nil, repository.ContributionFlagFilter(request))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Flag, 0, len(flags))
+	for _, flag := range flags {
+		result = append(result, newFlag(flag))
+	}
+
+	return result, nil
+}
+
+// ReviewFlag confirms or dismisses a pending flag. Confirming voids the
+// flagged contribution, dismissing takes its risk points back.
+func (s *service) ReviewFlag(ctx context.Context, flagId int64, request ReviewFlagRequest) (reviewed Flag, err error) {
+	adminId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Flag{}, apperrors.ErrInternalServer
+	}
+
+	note := strings.TrimSpace(request.Note)
+	if note == "" || (request.Decision != DecisionConfirm && request.Decision != DecisionDismiss) {
+		return Flag{}, apperrors.ErrInvalidRequestBody
+	}
+
+	flag, err := s.contributionFlagRepository.GetContributionFlagByIdForUpdate(ctx, nil, flagId)
+	if err != nil {
+		return Flag{}, err
+	}
+	if flag.Status != FlagStatusPending {
+		return Flag{}, apperrors.ErrContributionFlagReviewed
+	}
+
+	// voiding books its own compensating entries, so it runs first and a
+	// failed review can simply be repeated
+	if request.Decision == DecisionConfirm && flag.ContributionId.Valid {
+		_, err = s.disputeService.VoidContribution(ctx, int(flag.ContributionId.Int64), dispute.VoidContributionRequest{Reason: note})
+		if err != nil && !errors.Is(err, apperrors.ErrContributionVoided) {
+			return Flag{}, err
+		}
+	}
+
+	tx, err := s.contributionFlagRepository.BeginTx(ctx)
+	if err != nil {
+		return Flag{}, err
+	}
+	defer func() {
+		txErr := s.contributionFlagRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	flag, err = s.contributionFlagRepository.GetContributionFlagByIdForUpdate(ctx, tx, flagId)
+	if err != nil {
+		return Flag{}, err
+	}
+
+	status := FlagStatusConfirmed
+	if request.Decision == DecisionDismiss {
+		status = FlagStatusDismissed
+	}
+
+	err = s.contributionFlagRepository.ReviewContributionFlag(ctx, tx, flagId, status, adminId, note)
+	if err != nil {
+		return Flag{}, err
+	}
+
+	if status == FlagStatusDismissed && flag.RiskPoints > 0 {
+		_, err = s.riskScoreRepository.AddUserRiskScore(ctx, tx, flag.UserId, -flag.RiskPoints)
+		if err != nil {
+			return Flag{}, err
+		}
+	}
+
+	now := time.Now()
+	flag.Status = status
+	flag.ReviewNote = sql.NullString{String: note, Valid: true}
+	flag.ReviewedBy = sql.NullInt64{Int64: int64(adminId), Valid: true}
+	flag.ReviewedAt = sql.NullTime{Time: now, Valid: true}
+
+	return newFlag(flag), nil
+}
+
+func (s *service) ListRiskScores(ctx context.Context, minScore int, limit int, offset int) ([]RiskScore, error) {
+	scores, err := s.riskScoreRepository.ListUserRiskScores(ctx, nil, minScore, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RiskScore, 0, len(scores))
+	for _, score := range scores {
+		result = append(result, RiskScore(score))
+	}
+
+	return result, nil
+}
+
+// UnblockUser lifts an automatic block after review and starts the user's
+// risk score over.
+func (s *service) UnblockUser(ctx context.Context, userId int) (err error) {
+	tx, err := s.riskScoreRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		txErr := s.riskScoreRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	err = s.userRepository.SetUserBlocked(ctx, tx, userId, false)
+	if err != nil {
+		return err
+	}
+
+	return s.riskScoreRepository.ResetUserRiskScore(ctx, tx, userId)
+}
+
+func (s *service) addRisk(ctx context.Context, tx *sqlx.Tx, userId int, points int) error {
+	score, err := s.riskScoreRepository.AddUserRiskScore(ctx, tx, userId, points)
+	if err != nil {
+		return err
+	}
+
+	if s.appCfg.Fraud.BlockThreshold <= 0 || score < s.appCfg.Fraud.BlockThreshold {
+		return nil
+	}
+
+	userInfo, err := s.userRepository.GetUserById(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	if userInfo.IsBlocked {
+		return nil
+	}
+
+	err = s.userRepository.SetUserBlocked(ctx, tx, userId, true)
+	if err != nil {
+		return err
+	}
+
+	slog.Warn("user blocked pending fraud review", "user_id", userId, "risk_score", score)
+
+	return s.notificationService.Notify(ctx, tx, userId, notification.TypeAccountOnHold, map[string]any{
+		"risk_score": score,
+	})
+}
+
+func newFlag(flag repository.ContributionFlag) Flag {
+	converted := Flag{
+		Id:         flag.Id,
+		UserId:     flag.UserId,
+		ExternalId: flag.ExternalId,
+		Rule:       flag.Rule,
+		Action:     flag.Action,
+		Reason:     flag.Reason,
+		RiskPoints: flag.RiskPoints,
+		Status:     flag.Status,
+		CreatedAt:  flag.CreatedAt,
+	}
+	if flag.ContributionId.Valid {
+		converted.ContributionId = &flag.ContributionId.Int64
+	}
+	if flag.ReviewNote.Valid {
+		converted.ReviewNote = &flag.ReviewNote.String
+	}
+	if flag.ReviewedBy.Valid {
+		converted.ReviewedBy = &flag.ReviewedBy.Int64
+	}
+	if flag.ReviewedAt.Valid {
+		converted.ReviewedAt = &flag.ReviewedAt.Time
+	}
+
+	return converted
+}
@@ -0,0 +1,257 @@
+package githubtoken
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/envelope"
+	ghclient "github.com/joshsoftware/code-curiosity-2025/internal/pkg/github"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+const rotationBatchSize = 100
+
+type service struct {
+	githubTokenRepository repository.GithubTokenRepository
+	envelope              envelope.Envelope
+	etagStore             ghclient.ETagStore
+	trackers              *ghclient.Trackers
+	githubOAuth2          oauth2.Config
+}
+
+type Service interface {
+	SaveToken(ctx context.Context, userId int, token *oauth2.Token) error
+	GetToken(ctx context.Context, userId int) (*oauth2.Token, error)
+	InvalidateToken(ctx context.Context, userId int) error
+	NewUserClient(ctx context.Context, userId int) (ghclient.Client, error)
+	RotateKeys(ctx context.Context) (int, error)
+}
+
+func NewService(githubTokenRepository repository.GithubTokenRepository, envelope envelope.Envelope, etagStore ghclient.ETagStore, trackers *ghclient.Trackers, appCfg config.AppConfig) Service {
+	return &service{
+		githubTokenRepository: githubTokenRepository,
+		envelope:              envelope,
+		etagStore:             etagStore,
+		trackers:              trackers,
+		githubOAuth2: oauth2.Config{
+			ClientID:     appCfg.GithubOauth.ClientID,
+			ClientSecret: appCfg.GithubOauth.ClientSecret,
+			RedirectURL:  appCfg.GithubOauth.RedirectURL,
+			Endpoint:     github.Endpoint,
+		},
+	}
+}
+
+// SaveToken stores the token sealed under a fresh data key, replacing and
+// revalidating whatever was stored for the user before.
+func (s *service) SaveToken(ctx context.Context, userId int, token *oauth2.Token) error {
+	var refreshToken []byte
+	if token.RefreshToken != "" {
+		refreshToken = []byte(token.RefreshToken)
+	}
+
+	sealedKey, ciphertexts, err := s.envelope.Seal(envelope.UserData(userId), []byte(token.AccessToken), refreshToken)
+	if err != nil {
+		slog.Error("failed to encrypt github token", "error", err)
+		return err
+	}
+
+	err = s.githubTokenRepository.UpsertGithubToken(ctx, nil, repository.GithubToken{
+		UserId:       userId,
+		KeyId:        sealedKey.KeyID,
+		WrappedKey:   sealedKey.WrappedKey,
+		AccessToken:  ciphertexts[0],
+		RefreshToken: ciphertexts[1],
+		TokenType:    token.Type(),
+		ExpiresAt:    sql.NullTime{Time: token.Expiry, Valid: !token.Expiry.IsZero()},
+	})
+	if err != nil {
+		slog.Error("failed to save github token", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) GetToken(ctx context.Context, userId int) (*oauth2.Token, error) {
+	storedToken, err := s.githubTokenRepository.GetGithubTokenByUserId(ctx, nil, userId)
+	if err != nil {
+		slog.Error("failed to get github token", "user_id", userId, "error", err)
+		return nil, err
+	}
+
+	if !storedToken.IsValid {
+		return nil, apperrors.ErrGithubTokenInvalid
+	}
+
+	plaintexts, err := s.envelope.Open(
+		envelope.SealedKey{KeyID: storedToken.KeyId, WrappedKey: storedToken.WrappedKey},
+		envelope.UserData(userId),
+		storedToken.AccessToken,
+		storedToken.RefreshToken,
+	)
+	if err != nil {
+		slog.Error("failed to decrypt github token", "user_id", userId, "error", err)
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken:  string(plaintexts[0]),
+		RefreshToken: string(plaintexts[1]),
+		TokenType:    storedToken.TokenType,
+		Expiry:       storedToken.ExpiresAt.Time,
+	}, nil
+}
+
+func (s *service) InvalidateToken(ctx context.Context, userId int) error {
+	err := s.githubTokenRepository.MarkGithubTokenInvalid(ctx, nil, userId)
+	if err != nil {
+		slog.Error("failed to invalidate github token", "user_id", userId, "error", err)
+		return err
+	}
+
+	slog.Info("github token marked invalid", "user_id", userId)
+	return nil
+}
+
+// NewUserClient returns a GitHub client acting on the user's behalf. Expiring
+// tokens are refreshed and persisted transparently, and a 401 from GitHub
+// marks the stored token invalid until the user logs in again. Every client
+// of the user shares their rate limit state.
+func (s *service) NewUserClient(ctx context.Context, userId int) (ghclient.Client, error) {
+	token, err := s.GetToken(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenSource := &persistingTokenSource{
+		ctx:     ctx,
+		service: s,
+		userId:  userId,
+		current: token,
+		base:    s.githubOAuth2.TokenSource(ctx, token),
+	}
+
+	return &userClient{
+		Client:  ghclient.NewClient(tokenSource, s.etagStore, s.trackers.Get(ghclient.UserKey(userId))),
+		ctx:     ctx,
+		service: s,
+		userId:  userId,
+	}, nil
+}
+
+// RotateKeys re-encrypts every stored token under a fresh data key wrapped
+// with the active key-encryption key, and returns how many were rotated.
+func (s *service) RotateKeys(ctx context.Context) (int, error) {
+	rotated := 0
+	lastId := 0
+
+	for {
+		tokens, err := s.githubTokenRepository.GetGithubTokensAfterId(ctx, nil, lastId, rotationBatchSize)
+		if err != nil {
+			return rotated, err
+		}
+		if len(tokens) == 0 {
+			return rotated, nil
+		}
+
+		for _, storedToken := range tokens {
+			lastId = storedToken.Id
+
+			plaintexts, err := s.envelope.Open(
+				envelope.SealedKey{KeyID: storedToken.KeyId, WrappedKey: storedToken.WrappedKey},
+				envelope.UserData(storedToken.UserId),
+				storedToken.AccessToken,
+				storedToken.RefreshToken,
+			)
+			if err != nil {
+				slog.Error("failed to decrypt github token during rotation", "token_id", storedToken.Id, "error", err)
+				return rotated, err
+			}
+
+			sealedKey, ciphertexts, err := s.envelope.Seal(envelope.UserData(storedToken.UserId), plaintexts...)
+			if err != nil {
+				slog.Error("failed to encrypt github token during rotation", "token_id", storedToken.Id, "error", err)
+				return rotated, err
+			}
+
+			storedToken.KeyId = sealedKey.KeyID
+			storedToken.WrappedKey = sealedKey.WrappedKey
+			storedToken.AccessToken = ciphertexts[0]
+			storedToken.RefreshToken = ciphertexts[1]
+
+			err = s.githubTokenRepository.UpdateGithubTokenCiphertext(ctx, nil, storedToken)
+			if err != nil {
+				return rotated, err
+			}
+			rotated++
+		}
+	}
+}
+
+type persistingTokenSource struct {
+	ctx     context.Context
+	service *service
+	userId  int
+	current *oauth2.Token
+	base    oauth2.TokenSource
+}
+
+func (p *persistingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := p.base.Token()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			p.service.InvalidateToken(p.ctx, p.userId)
+		}
+		return nil, err
+	}
+
+	if token.AccessToken != p.current.AccessToken {
+		err = p.service.SaveToken(p.ctx, p.userId, token)
+		if err != nil {
+			slog.Error("failed to persist refreshed github token", "user_id", p.userId, "error", err)
+		}
+		p.current = token
+	}
+
+	return token, nil
+}
+
+type userClient struct {
+	ghclient.Client
+	ctx     context.Context
+	service *service
+	userId  int
+}
+
+func (c *userClient) Do(ctx context.Context, req *http.Request) (ghclient.Response, error) {
+	resp, err := c.Client.Do(ctx, req)
+	c.checkUnauthorized(err)
+	return resp, err
+}
+
+func (c *userClient) Get(ctx context.Context, url string, v any) (ghclient.Response, error) {
+	resp, err := c.Client.Get(ctx, url, v)
+	c.checkUnauthorized(err)
+	return resp, err
+}
+
+func (c *userClient) GetPaginated(ctx context.Context, url string, eachPage func(body []byte) error) error {
+	err := c.Client.GetPaginated(ctx, url, eachPage)
+	c.checkUnauthorized(err)
+	return err
+}
+
+func (c *userClient) checkUnauthorized(err error) {
+	if errors.Is(err, apperrors.ErrGithubUnauthorized) {
+		c.service.InvalidateToken(c.ctx, c.userId)
+	}
+}
@@ -0,0 +1,19 @@
+package goal
+
+import "time"
+
+// goal levels, kept in sync with the goal_level_check constraint
+const (
+	Beginner     = "Beginner"
+	Intermediate = "Intermediate"
+	Advanced     = "Advanced"
+)
+
+var Levels = []string{Beginner, Intermediate, Advanced}
+
+type Goal struct {
+	Id        int       `json:"id"`
+	Level     string    `json:"level"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package integration
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestAllowedAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		addr string
+		want bool
+	}{
+		{name: "public ipv4", addr: "140.82.112.3", want: true},
+		{name: "public ipv6", addr: "2606:4700::1111", want: true},
+		{name: "loopback", addr: "127.0.0.1"},
+		{name: "ipv6 loopback", addr: "::1"},
+		{name: "private", addr: "10.1.2.3"},
+		{name: "private 172", addr: "172.16.0.1"},
+		{name: "private 192", addr: "192.168.1.1"},
+		{name: "cloud metadata", addr: "169.254.169.254"},
+		{name: "carrier grade nat", addr: "100.100.100.200"},
+		{name: "unspecified", addr: "0.0.0.0"},
+		{name: "this network", addr: "0.1.2.3"},
+		{name: "multicast", addr: "224.0.0.1"},
+		{name: "broadcast", addr: "255.255.255.255"},
+		{name: "unique local ipv6", addr: "fd00:ec2::254"},
+		{name: "link local ipv6", addr: "fe80::1"},
+		{name: "ipv4 mapped loopback", addr: "::ffff:127.0.0.1"},
+		{name: "nat64 of private", addr: "64:ff9b::a00:1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowedAddress(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("allowedAddress(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DeliverEventJob = "deliver_integration_event"
+
+	// EventSubscriber names the webhook subscriber's rows in the event outbox
+	EventSubscriber = "webhooks"
+
+	// PingEventType is sent by the test-ping endpoint only
+	PingEventType = "ping"
+
+	// delivery statuses, kept in sync with the integration_deliveries_status_check constraint
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+
+	EventHeader     = "X-CodeCuriosity-Event"
+	DeliveryHeader  = "X-CodeCuriosity-Delivery"
+	TimestampHeader = "X-CodeCuriosity-Timestamp"
+	SignatureHeader = "X-CodeCuriosity-Signature"
+)
+
+var DeliveryStatuses = []string{DeliveryStatusPending, DeliveryStatusSucceeded, DeliveryStatusFailed}
+
+// Endpoint is a URL events are posted to. UserId is empty for endpoints
+// registered by admins, which receive events about every user.
+type Endpoint struct {
+	Id          int64     `json:"id"`
+	UserId      *int64    `json:"user_id"`
+	Url         string    `json:"url"`
+	Description string    `json:"description"`
+	EventTypes  []string  `json:"event_types"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreatedEndpoint carries the signing secret, which is only ever shown
+// when the endpoint is created.
+type CreatedEndpoint struct {
+	Endpoint
+	Secret string `json:"secret"`
+}
+
+type CreateEndpointRequest struct {
+	Url         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"`
+}
+
+type Delivery struct {
+	Id             int64           `json:"id"`
+	EndpointId     int64           `json:"endpoint_id"`
+	EventId        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int64          `json:"response_status"`
+	ResponseBody   string          `json:"response_body,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DurationMs     *int64          `json:"duration_ms"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// Payload is the JSON body posted to endpoints.
+type Payload struct {
+	Id        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type DeliverEventPayload struct {
+	DeliveryId int64 `json:"delivery_id"`
+}
@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+// handler serves either the logged in user's endpoints or, with global
+// set, the admin endpoints receiving events about every user.
+type handler struct {
+	integrationService Service
+	global             bool
+}
+
+type Handler interface {
+	CreateEndpoint(w http.ResponseWriter, r *http.Request)
+	ListEndpoints(w http.ResponseWriter, r *http.Request)
+	DeleteEndpoint(w http.ResponseWriter, r *http.Request)
+	ListDeliveries(w http.ResponseWriter, r *http.Request)
+	Ping(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(integrationService Service) Handler {
+	return &handler{
+		integrationService: integrationService,
+	}
+}
+
+func NewAdminHandler(integrationService Service) Handler {
+	return &handler{
+		integrationService: integrationService,
+		global:             true,
+	}
+}
+
+func (h *handler) CreateEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var createRequest CreateEndpointRequest
+	err := json.NewDecoder(r.Body).Decode(&createRequest)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	endpoint, err := h.integrationService.CreateEndpoint(ctx, h.global, createRequest)
+	if err != nil {
+		slog.Error("failed to create webhook endpoint", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "webhook endpoint created successfully", endpoint)
+}
+
+func (h *handler) ListEndpoints(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	endpoints, err := h.integrationService.ListEndpoints(ctx, h.global)
+	if err != nil {
+		slog.Error("failed to list webhook endpoints", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "webhook endpoints fetched successfully", endpoints)
+}
+
+func (h *handler) DeleteEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	endpointId, err := strconv.ParseInt(r.PathValue("endpointId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.integrationService.DeleteEndpoint(ctx, h.global, endpointId)
+	if err != nil {
+		slog.Error("failed to delete webhook endpoint", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "webhook endpoint deleted", nil)
+}
+
+func (h *handler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	endpointId, err := strconv.ParseInt(r.PathValue("endpointId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	deliveries, err := h.integrationService.ListDeliveries(ctx, h.global, endpointId, limit, offset)
+	if err != nil {
+		slog.Error("failed to list webhook deliveries", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "webhook deliveries fetched successfully", deliveries)
+}
+
+func (h *handler) Ping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	endpointId, err := strconv.ParseInt(r.PathValue("endpointId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	delivery, err := h.integrationService.Ping(ctx, h.global, endpointId)
+	if err != nil {
+		slog.Error("failed to ping webhook endpoint", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "webhook endpoint pinged", delivery)
+}
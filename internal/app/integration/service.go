@@ -0,0 +1,452 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/job"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/envelope"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+const (
+	secretSize = 32
+
+	// response bodies are kept in the delivery log up to this many bytes
+	responseBodyLimit = 1024
+)
+
+type service struct {
+	integrationRepository repository.IntegrationRepository
+	jobService            job.Service
+	envelope              envelope.Envelope
+	httpClient            *http.Client
+	appCfg                config.AppConfig
+}
+
+type Service interface {
+	CreateEndpoint(ctx context.Context, global bool, request CreateEndpointRequest) (CreatedEndpoint, error)
+	ListEndpoints(ctx context.Context, global bool) ([]Endpoint, error)
+	DeleteEndpoint(ctx context.Context, global bool, endpointId int64) error
+	ListDeliveries(ctx context.Context, global bool, endpointId int64, limit int, offset int) ([]Delivery, error)
+	Ping(ctx context.Context, global bool, endpointId int64) (Delivery, error)
+	HandleEvent(ctx context.Context, tx *sqlx.Tx, event events.Event) error
+	DeliverEvent(ctx context.Context, payload DeliverEventPayload) error
+}
+
+func NewService(integrationRepository repository.IntegrationRepository, jobService job.Service, envelope envelope.Envelope, appCfg config.AppConfig) Service {
+	return &service{
+		integrationRepository: integrationRepository,
+		jobService:            jobService,
+		envelope:              envelope,
+		httpClient:            newHTTPClient(appCfg.Integrations.RequestTimeout),
+		appCfg:                appCfg,
+	}
+}
+
+// CreateEndpoint registers an endpoint for the logged in user, or with
+// global set an admin endpoint receiving events about every user. The
+// returned secret signs every delivery to the endpoint.
+func (s *service) CreateEndpoint(ctx context.Context, global bool, request CreateEndpointRequest) (CreatedEndpoint, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return CreatedEndpoint{}, apperrors.ErrInternalServer
+	}
+
+	err := s.validUrl(request.Url)
+	if err != nil {
+		return CreatedEndpoint{}, err
+	}
+
+	if len(request.EventTypes) == 0 {
+		return CreatedEndpoint{}, apperrors.ErrInvalidRequestBody
+	}
+	for _, eventType := range request.EventTypes {
+		if !slices.Contains(events.Types, eventType) {
+			return CreatedEndpoint{}, apperrors.ErrInvalidRequestBody
+		}
+	}
+
+	secretBytes := make([]byte, secretSize)
+	_, err = rand.Read(secretBytes)
+	if err != nil {
+		slog.Error("failed to generate webhook secret", "error", err)
+		return CreatedEndpoint{}, apperrors.ErrInternalServer
+	}
+	secret := hex.EncodeToString(secretBytes)
+
+	sealedKey, ciphertexts, err := s.envelope.Seal(envelope.UserData(userId), []byte(secret))
+	if err != nil {
+		slog.Error("failed to encrypt webhook secret", "error", err)
+		return CreatedEndpoint{}, err
+	}
+
+	created, err := s.integrationRepository.CreateEndpoint(ctx, nil, repository.IntegrationEndpoint{
+		UserId:      owner(global, userId),
+		CreatedBy:   userId,
+		Url:         request.Url,
+		Description: strings.TrimSpace(request.Description),
+		EventTypes:  slices.Compact(slices.Sorted(slices.Values(request.EventTypes))),
+		KeyId:       sealedKey.KeyID,
+		WrappedKey:  sealedKey.WrappedKey,
+		Secret:      ciphertexts[0],
+	})
+	if err != nil {
+		return CreatedEndpoint{}, err
+	}
+
+	slog.Info("webhook endpoint created", "endpoint_id", created.Id, "user_id", userId, "global", global)
+	return CreatedEndpoint{Endpoint: newEndpoint(created), Secret: secret}, nil
+}
+
+func (s *service) ListEndpoints(ctx context.Context, global bool) ([]Endpoint, error) {
+	ownerId, err := ownerFromContext(ctx, global)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := s.integrationRepository.ListEndpoints(ctx, nil, ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		result = append(result, newEndpoint(endpoint))
+	}
+
+	return result, nil
+}
+
+func (s *service) DeleteEndpoint(ctx context.Context, global bool, endpointId int64) error {
+	ownerId, err := ownerFromContext(ctx, global)
+	if err != nil {
+		return err
+	}
+
+	err = s.integrationRepository.DeleteEndpoint(ctx, nil, endpointId, ownerId)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("webhook endpoint deleted", "endpoint_id", endpointId)
+	return nil
+}
+
+func (s *service) ListDeliveries(ctx context.Context, global bool, endpointId int64, limit int, offset int) ([]Delivery, error) {
+	ownerId, err := ownerFromContext(ctx, global)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.integrationRepository.GetEndpoint(ctx, nil, endpointId, ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.integrationRepository.ListDeliveries(ctx, nil, endpointId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, newDelivery(delivery, global))
+	}
+
+	return result, nil
+}
+
+// Ping sends a ping event to the endpoint right away and reports how the
+// endpoint answered. Pings are logged but never retried.
+func (s *service) Ping(ctx context.Context, global bool, endpointId int64) (Delivery, error) {
+	ownerId, err := ownerFromContext(ctx, global)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	endpoint, err := s.integrationRepository.GetEndpoint(ctx, nil, endpointId, ownerId)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	event := events.New(PingEventType, 0, map[string]any{"endpoint_id": endpoint.Id})
+	delivery, err := s.createDelivery(ctx, nil, endpoint, event)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	attempt := s.send(ctx, endpoint, delivery)
+	if attempt.Status == DeliveryStatusPending {
+		attempt.Status = DeliveryStatusFailed
+	}
+
+	delivery, err = s.integrationRepository.RecordDeliveryAttempt(ctx, nil, delivery.Id, attempt)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return newDelivery(delivery, global), nil
+}
+
+// HandleEvent is an async event bus subscriber. It logs a delivery to every
+// endpoint subscribed to the event and queues it for sending, inside the
+// dispatcher's transaction.
+func (s *service) HandleEvent(ctx context.Context, tx *sqlx.Tx, event events.Event) error {
+	userId := sql.NullInt64{Int64: int64(event.UserId), Valid: event.UserId != 0}
+
+	endpoints, err := s.integrationRepository.ListSubscribedEndpoints(ctx, tx, event.Type, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		delivery, err := s.createDelivery(ctx, tx, endpoint, event)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.jobService.Enqueue(ctx, tx, DeliverEventJob, DeliverEventPayload{DeliveryId: delivery.Id}, job.EnqueueOptions{
+			MaxAttempts: s.appCfg.Integrations.MaxAttempts,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeliverEvent is the job handler sending a queued delivery. Returning an
+// error leaves the job to be retried with backoff until the attempts run
+// out, at which point the delivery is marked failed.
+func (s *service) DeliverEvent(ctx context.Context, payload DeliverEventPayload) error {
+	delivery, err := s.integrationRepository.GetDeliveryById(ctx, nil, payload.DeliveryId)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrIntegrationDeliveryNotFound) {
+			slog.Warn("dropping delivery to deleted webhook endpoint", "delivery_id", payload.DeliveryId)
+			return nil
+		}
+		return err
+	}
+	if delivery.Status != DeliveryStatusPending {
+		return nil
+	}
+
+	endpoint, err := s.integrationRepository.GetEndpointById(ctx, nil, delivery.EndpointId)
+	if err != nil {
+		return err
+	}
+
+	var attempt repository.IntegrationDeliveryAttempt
+	if endpoint.IsActive {
+		attempt = s.send(ctx, endpoint, delivery)
+	} else {
+		attempt = repository.IntegrationDeliveryAttempt{
+			Status:    DeliveryStatusFailed,
+			LastError: sql.NullString{String: "endpoint is disabled", Valid: true},
+		}
+	}
+
+	if attempt.Status == DeliveryStatusPending && delivery.Attempts+1 >= s.appCfg.Integrations.MaxAttempts {
+		attempt.Status = DeliveryStatusFailed
+	}
+
+	_, err = s.integrationRepository.RecordDeliveryAttempt(ctx, nil, delivery.Id, attempt)
+	if err != nil {
+		return err
+	}
+
+	if attempt.Status != DeliveryStatusSucceeded && endpoint.IsActive {
+		return apperrors.ErrIntegrationDeliveryFailed
+	}
+
+	return nil
+}
+
+func (s *service) createDelivery(ctx context.Context, tx *sqlx.Tx, endpoint repository.IntegrationEndpoint, event events.Event) (repository.IntegrationDelivery, error) {
+	body, err := json.Marshal(Payload{
+		Id:        event.Id,
+		Type:      event.Type,
+		CreatedAt: event.OccurredAt,
+		Data:      event.Data,
+	})
+	if err != nil {
+		slog.Error("failed to marshal webhook payload", "event_type", event.Type, "error", err)
+		return repository.IntegrationDelivery{}, apperrors.ErrInternalServer
+	}
+
+	return s.integrationRepository.CreateDelivery(ctx, tx, repository.IntegrationDelivery{
+		EndpointId: endpoint.Id,
+		EventId:    event.Id,
+		EventType:  event.Type,
+		Payload:    body,
+	})
+}
+
+// send posts the delivery to the endpoint. The body is signed with the
+// endpoint secret as hex HMAC-SHA256 over "<timestamp>.<body>", so receivers
+// can reject both forged and replayed deliveries. A delivery that did not
+// get a 2xx answer stays pending.
+func (s *service) send(ctx context.Context, endpoint repository.IntegrationEndpoint, delivery repository.IntegrationDelivery) repository.IntegrationDeliveryAttempt {
+	attempt := repository.IntegrationDeliveryAttempt{Status: DeliveryStatusPending}
+
+	plaintexts, err := s.envelope.Open(envelope.SealedKey{KeyID: endpoint.KeyId, WrappedKey: endpoint.WrappedKey}, envelope.UserData(endpoint.CreatedBy), endpoint.Secret)
+	if err != nil {
+		slog.Error("failed to decrypt webhook secret", "endpoint_id", endpoint.Id, "error", err)
+		attempt.LastError = sql.NullString{String: err.Error(), Valid: true}
+		return attempt
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, plaintexts[0])
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.LastError = sql.NullString{String: err.Error(), Valid: true}
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.EventId)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	startedAt := time.Now()
+	resp, err := s.httpClient.Do(req)
+	attempt.DurationMs = sql.NullInt64{Int64: time.Since(startedAt).Milliseconds(), Valid: true}
+	if err != nil {
+		slog.Warn("webhook delivery failed", "endpoint_id", endpoint.Id, "delivery_id", delivery.Id, "error", err)
+		attempt.LastError = sql.NullString{String: err.Error(), Valid: true}
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+	attempt.ResponseStatus = sql.NullInt64{Int64: int64(resp.StatusCode), Valid: true}
+	attempt.ResponseBody = sql.NullString{String: string(responseBody), Valid: true}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.LastError = sql.NullString{String: fmt.Sprintf("endpoint answered with status %d", resp.StatusCode), Valid: true}
+		return attempt
+	}
+
+	attempt.Status = DeliveryStatusSucceeded
+	attempt.LastError = sql.NullString{}
+	attempt.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return attempt
+}
+
+// validUrl accepts absolute https URLs, and plain http outside production.
+// Hosts given as an address must be public, hostnames are checked once
+// resolved when delivering.
+func (s *service) validUrl(rawUrl string) error {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil || parsed.Host == "" {
+		return apperrors.ErrInvalidIntegrationUrl
+	}
+
+	if addr, err := netip.ParseAddr(parsed.Hostname()); err == nil && !allowedAddress(addr) {
+		return apperrors.ErrIntegrationAddressNotAllowed
+	}
+	if strings.EqualFold(parsed.Hostname(), "localhost") {
+		return apperrors.ErrIntegrationAddressNotAllowed
+	}
+
+	if parsed.Scheme == "https" || (parsed.Scheme == "http" && !s.appCfg.IsProduction) {
+		return nil
+	}
+
+	return apperrors.ErrInvalidIntegrationUrl
+}
+
+func owner(global bool, userId int) sql.NullInt64 {
+	if global {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(userId), Valid: true}
+}
+
+func ownerFromContext(ctx context.Context, global bool) (sql.NullInt64, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return sql.NullInt64{}, apperrors.ErrInternalServer
+	}
+
+	return owner(global, userId), nil
+}
+
+func newEndpoint(endpoint repository.IntegrationEndpoint) Endpoint {
+	converted := Endpoint{
+		Id:          endpoint.Id,
+		Url:         endpoint.Url,
+		Description: endpoint.Description,
+		EventTypes:  endpoint.EventTypes,
+		IsActive:    endpoint.IsActive,
+		CreatedAt:   endpoint.CreatedAt,
+		UpdatedAt:   endpoint.UpdatedAt,
+	}
+	if endpoint.UserId.Valid {
+		converted.UserId = &endpoint.UserId.Int64
+	}
+
+	return converted
+}
+
+// newDelivery leaves out the endpoint's response body unless global is set,
+// so users cannot read back what a server answered to deliveries.
+func newDelivery(delivery repository.IntegrationDelivery, global bool) Delivery {
+	converted := Delivery{
+		Id:         delivery.Id,
+		EndpointId: delivery.EndpointId,
+		EventId:    delivery.EventId,
+		EventType:  delivery.EventType,
+		Payload:    delivery.Payload,
+		Status:     delivery.Status,
+		Attempts:   delivery.Attempts,
+		LastError:  delivery.LastError.String,
+		CreatedAt:  delivery.CreatedAt,
+		UpdatedAt:  delivery.UpdatedAt,
+	}
+	if global {
+		converted.ResponseBody = delivery.ResponseBody.String
+	}
+	if delivery.ResponseStatus.Valid {
+		converted.ResponseStatus = &delivery.ResponseStatus.Int64
+	}
+	if delivery.DurationMs.Valid {
+		converted.DurationMs = &delivery.DurationMs.Int64
+	}
+	if delivery.DeliveredAt.Valid {
+		converted.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+
+	return converted
+}
@@ -0,0 +1,55 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusDead      = "dead"
+	StatusCancelled = "cancelled"
+
+	DefaultMaxAttempts = 5
+)
+
+type Job struct {
+	Id          int64           `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	UniqueKey   string          `json:"unique_key,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LockedBy    string          `json:"locked_by,omitempty"`
+	LastError   string          `json:"last_error,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// EnqueueOptions are all optional. A job with a UniqueKey is not enqueued
+// while another job with the same key is pending or running.
+type EnqueueOptions struct {
+	UniqueKey   string
+	RunAt       time.Time
+	MaxAttempts int
+}
+
+type JobHandler func(ctx context.Context, job Job) error
+
+// HandlerFor adapts a function taking a typed payload into a JobHandler.
+func HandlerFor[T any](fn func(ctx context.Context, payload T) error) JobHandler {
+	return func(ctx context.Context, job Job) error {
+		var payload T
+		err := json.Unmarshal(job.Payload, &payload)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, payload)
+	}
+}
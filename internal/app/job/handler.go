@@ -0,0 +1,87 @@
+package job
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	jobService Service
+}
+
+type Handler interface {
+	ListJobs(w http.ResponseWriter, r *http.Request)
+	RetryJob(w http.ResponseWriter, r *http.Request)
+	CancelJob(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(jobService Service) Handler {
+	return &handler{
+		jobService: jobService,
+	}
+}
+
+func (h *handler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	jobs, err := h.jobService.ListJobs(ctx, r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		slog.Error("failed to list jobs", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "jobs fetched successfully", jobs)
+}
+
+func (h *handler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobId, err := strconv.ParseInt(r.PathValue("jobId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.jobService.RetryJob(ctx, jobId)
+	if err != nil {
+		slog.Error("failed to retry job", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "job queued for retry", nil)
+}
+
+func (h *handler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobId, err := strconv.ParseInt(r.PathValue("jobId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.jobService.CancelJob(ctx, jobId)
+	if err != nil {
+		slog.Error("failed to cancel job", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "job cancelled", nil)
+}
@@ -0,0 +1,272 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+const (
+	pollInterval   = 2 * time.Second
+	jobTimeout     = 10 * time.Minute
+	staleLockAfter = 15 * time.Minute
+	drainTimeout   = 30 * time.Second
+
+	baseRetryDelay = 15 * time.Second
+	maxRetryDelay  = time.Hour
+)
+
+type service struct {
+	jobRepository repository.JobRepository
+	concurrency   int
+	workerId      string
+
+	mu       sync.RWMutex
+	handlers map[string]JobHandler
+}
+
+type Service interface {
+	Enqueue(ctx context.Context, tx *sqlx.Tx, jobType string, payload any, opts EnqueueOptions) (Job, error)
+	RegisterHandler(jobType string, handler JobHandler)
+	Run(ctx context.Context)
+	ListJobs(ctx context.Context, status string, limit int, offset int) ([]Job, error)
+	RetryJob(ctx context.Context, jobId int64) error
+	CancelJob(ctx context.Context, jobId int64) error
+}
+
+func NewService(jobRepository repository.JobRepository, appCfg config.AppConfig) Service {
+	hostname, _ := os.Hostname()
+
+	return &service{
+		jobRepository: jobRepository,
+		concurrency:   appCfg.Jobs.Concurrency,
+		workerId:      fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		handlers:      make(map[string]JobHandler),
+	}
+}
+
+// Enqueue stores a job. Pass the caller's transaction to make the job
+// visible only if the surrounding business change commits.
+func (s *service) Enqueue(ctx context.Context, tx *sqlx.Tx, jobType string, payload any, opts EnqueueOptions) (Job, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("failed to marshal job payload", "type", jobType, "error", err)
+		return Job{}, apperrors.ErrInternalServer
+	}
+
+	if opts.RunAt.IsZero() {
+		opts.RunAt = time.Now()
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+
+	job, err := s.jobRepository.EnqueueJob(ctx, tx, repository.Job{
+		Type:        jobType,
+		Payload:     payloadBytes,
+		UniqueKey:   sql.NullString{String: opts.UniqueKey, Valid: opts.UniqueKey != ""},
+		MaxAttempts: opts.MaxAttempts,
+		RunAt:       opts.RunAt,
+	})
+	if err != nil {
+		return Job{}, err
+	}
+
+	return mapJob(job), nil
+}
+
+func (s *service) RegisterHandler(jobType string, handler JobHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[jobType] = handler
+}
+
+// Run works jobs until ctx is cancelled, then stops claiming and gives
+// in-flight jobs up to drainTimeout to finish before cancelling them.
+func (s *service) Run(ctx context.Context) {
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	go func() {
+		<-ctx.Done()
+		timer := time.NewTimer(drainTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			slog.Warn("job drain timed out, cancelling in-flight jobs")
+			cancelJobs()
+		case <-jobCtx.Done():
+		}
+	}()
+
+	var workers sync.WaitGroup
+	for i := 0; i < s.concurrency; i++ {
+		workers.Add(1)
+		go func(worker int) {
+			defer workers.Done()
+			s.work(ctx, jobCtx, fmt.Sprintf("%s-%d", s.workerId, worker))
+		}(i)
+	}
+
+	slog.Info("job workers started", "concurrency", s.concurrency)
+	workers.Wait()
+	slog.Info("job workers drained")
+}
+
+func (s *service) work(ctx context.Context, jobCtx context.Context, workerId string) {
+	for ctx.Err() == nil {
+		job, err := s.jobRepository.ClaimNextJob(ctx, nil, workerId, s.registeredTypes(), staleLockAfter)
+		if err != nil {
+			if !errors.Is(err, apperrors.ErrJobNotFound) && ctx.Err() == nil {
+				slog.Error("failed to claim job", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		s.execute(jobCtx, mapJob(job))
+	}
+}
+
+func (s *service) execute(ctx context.Context, job Job) {
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := s.invoke(ctx, job)
+	if err == nil {
+		slog.Info("job succeeded", "job_id", job.Id, "type", job.Type, "duration", time.Since(start))
+		s.jobRepository.MarkJobSucceeded(context.WithoutCancel(ctx), nil, job.Id)
+		return
+	}
+
+	status := StatusPending
+	runAt := time.Now().Add(retryDelay(job.Attempts))
+	if job.Attempts >= job.MaxAttempts {
+		status = StatusDead
+	}
+
+	slog.Error("job failed", "job_id", job.Id, "type", job.Type, "attempt", job.Attempts, "status", status, "error", err)
+	s.jobRepository.MarkJobFailed(context.WithoutCancel(ctx), nil, job.Id, status, err.Error(), runAt)
+}
+
+func (s *service) invoke(ctx context.Context, job Job) (err error) {
+	s.mu.RLock()
+	handler, ok := s.handlers[job.Type]
+	s.mu.RUnlock()
+	if !ok {
+		return apperrors.ErrUnknownJobType
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+func (s *service) registeredTypes() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	types := make([]string, 0, len(s.handlers))
+	for jobType := range s.handlers {
+		types = append(types, jobType)
+	}
+	return types
+}
+
+func (s *service) ListJobs(ctx context.Context, status string, limit int, offset int) ([]Job, error) {
+	jobs, err := s.jobRepository.ListJobs(ctx, nil, status, limit, offset)
+	if err != nil {
+		slog.Error("failed to list jobs", "error", err)
+		return nil, err
+	}
+
+	result := make([]Job, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, mapJob(job))
+	}
+	return result, nil
+}
+
+func (s *service) RetryJob(ctx context.Context, jobId int64) error {
+	_, err := s.jobRepository.GetJobById(ctx, nil, jobId)
+	if err != nil {
+		return err
+	}
+
+	err = s.jobRepository.RetryJob(ctx, nil, jobId)
+	if err != nil {
+		slog.Error("failed to retry job", "job_id", jobId, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) CancelJob(ctx context.Context, jobId int64) error {
+	_, err := s.jobRepository.GetJobById(ctx, nil, jobId)
+	if err != nil {
+		return err
+	}
+
+	err = s.jobRepository.CancelJob(ctx, nil, jobId)
+	if err != nil {
+		slog.Error("failed to cancel job", "job_id", jobId, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// retryDelay grows exponentially with the attempt count, with jitter so
+// jobs that failed together do not retry together.
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay << min(attempts, 16)
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay/2 + time.Duration(rand.Int64N(int64(delay/2)+1))
+}
+
+func mapJob(job repository.Job) Job {
+	mapped := Job{
+		Id:          job.Id,
+		Type:        job.Type,
+		Payload:     job.Payload,
+		Status:      job.Status,
+		UniqueKey:   job.UniqueKey.String,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LockedBy:    job.LockedBy.String,
+		LastError:   job.LastError.String,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+	if job.CompletedAt.Valid {
+		mapped.CompletedAt = &job.CompletedAt.Time
+	}
+	return mapped
+}
@@ -0,0 +1,31 @@
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		ceiling  time.Duration
+	}{
+		{name: "first retry", attempts: 0, ceiling: 15 * time.Second},
+		{name: "second retry", attempts: 1, ceiling: 30 * time.Second},
+		{name: "fourth retry", attempts: 3, ceiling: 2 * time.Minute},
+		{name: "capped", attempts: 10, ceiling: time.Hour},
+		{name: "shift bounded", attempts: 1000, ceiling: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				delay := retryDelay(tt.attempts)
+				if delay < tt.ceiling/2 || delay > tt.ceiling {
+					t.Fatalf("retryDelay(%d) = %v, want between %v and %v", tt.attempts, delay, tt.ceiling/2, tt.ceiling)
+				}
+			}
+		})
+	}
+}
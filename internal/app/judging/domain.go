@@ -0,0 +1,77 @@
+package judging
+
+import "time"
+
+// EventSubscriber names the judging subscriber's rows in the event outbox.
+const EventSubscriber = "judging"
+
+// review statuses, kept in sync with the judging_reviews_status_check
+// constraint. A pending review is closed as finalized once its judged score
+// is applied, or as cancelled when the contribution was voided meanwhile.
+const (
+	StatusPending   = "pending"
+	StatusFinalized = "finalized"
+	StatusCancelled = "cancelled"
+)
+
+var Statuses = []string{StatusPending, StatusFinalized, StatusCancelled}
+
+// reasons a contribution is queued for judging, kept in sync with the
+// judging_reviews_reason_check constraint
+const (
+	ReasonSize               = "size"
+	ReasonFeaturedRepository = "featured_repository"
+)
+
+var Reasons = []string{ReasonSize, ReasonFeaturedRepository}
+
+// every rubric criterion is scored from minRubricScore to maxRubricScore
+const (
+	minRubricScore = 1
+	maxRubricScore = 5
+)
+
+type Review struct {
+	Id                 int64      `json:"id"`
+	ContributionId     int        `json:"contribution_id"`
+	UserId             int        `json:"user_id"`
+	GithubUsername     string     `json:"github_username"`
+	RepositoryId       int        `json:"repository_id"`
+	RepositoryFullName string     `json:"repository_full_name"`
+	Reason             string     `json:"reason"`
+	LinesChanged       int        `json:"lines_changed"`
+	Title              string     `json:"title"`
+	Url                string     `json:"url"`
+	AutomatedScore     int        `json:"automated_score"`
+	FinalScore         *int64     `json:"final_score"`
+	Status             string     `json:"status"`
+	AdjustmentId       *int64     `json:"adjustment_id"`
+	ScoreCount         int        `json:"score_count"`
+	FinalizedAt        *time.Time `json:"finalized_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// ReviewDetail is the audit view of a review with every judge's score.
+type ReviewDetail struct {
+	Review
+	Scores []Score `json:"scores"`
+}
+
+type Score struct {
+	JudgeId             int       `json:"judge_id"`
+	JudgeGithubUsername string    `json:"judge_github_username"`
+	Impact              int       `json:"impact"`
+	Quality             int       `json:"quality"`
+	Docs                int       `json:"docs"`
+	Comment             string    `json:"comment"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// SubmitScoreRequest scores a review on the rubric. Scoring a review again
+// replaces the judge's earlier score until the review is closed.
+type SubmitScoreRequest struct {
+	Impact  int    `json:"impact"`
+	Quality int    `json:"quality"`
+	Docs    int    `json:"docs"`
+	Comment string `json:"comment"`
+}
@@ -0,0 +1,168 @@
+package judging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	judgingService Service
+}
+
+type Handler interface {
+	GetMyQueue(w http.ResponseWriter, r *http.Request)
+	SubmitScore(w http.ResponseWriter, r *http.Request)
+	ListReviews(w http.ResponseWriter, r *http.Request)
+	GetReview(w http.ResponseWriter, r *http.Request)
+	FinalizeReview(w http.ResponseWriter, r *http.Request)
+	FeatureRepository(w http.ResponseWriter, r *http.Request)
+	UnfeatureRepository(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(judgingService Service) Handler {
+	return &handler{
+		judgingService: judgingService,
+	}
+}
+
+func (h *handler) GetMyQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	reviews, err := h.judgingService.GetMyQueue(ctx, limit, offset)
+	if err != nil {
+		slog.Error("failed to get judging queue", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "judging queue fetched successfully", reviews)
+}
+
+func (h *handler) SubmitScore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reviewId, err := strconv.ParseInt(r.PathValue("reviewId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody SubmitScoreRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	review, err := h.judgingService.SubmitScore(ctx, reviewId, requestBody)
+	if err != nil {
+		slog.Error("failed to submit judging score", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "score submitted successfully", review)
+}
+
+func (h *handler) ListReviews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	reviews, err := h.judgingService.ListReviews(ctx, r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		slog.Error("failed to list judging reviews", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "judging reviews fetched successfully", reviews)
+}
+
+func (h *handler) GetReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reviewId, err := strconv.ParseInt(r.PathValue("reviewId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	review, err := h.judgingService.GetReview(ctx, reviewId)
+	if err != nil {
+		slog.Error("failed to get judging review", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "judging review fetched successfully", review)
+}
+
+func (h *handler) FinalizeReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reviewId, err := strconv.ParseInt(r.PathValue("reviewId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	review, err := h.judgingService.FinalizeReview(ctx, reviewId)
+	if err != nil {
+		slog.Error("failed to finalize judging review", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "judging review finalized successfully", review)
+}
+
+func (h *handler) FeatureRepository(w http.ResponseWriter, r *http.Request) {
+	h.setRepositoryFeatured(w, r, true, "repository featured successfully")
+}
+
+func (h *handler) UnfeatureRepository(w http.ResponseWriter, r *http.Request) {
+	h.setRepositoryFeatured(w, r, false, "repository unfeatured successfully")
+}
+
+func (h *handler) setRepositoryFeatured(w http.ResponseWriter, r *http.Request, featured bool, message string) {
+	ctx := r.Context()
+
+	repositoryId, err := strconv.Atoi(r.PathValue("repositoryId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.judgingService.SetRepositoryFeatured(ctx, repositoryId, featured)
+	if err != nil {
+		slog.Error("failed to set repository featured", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, message, nil)
+}
@@ -0,0 +1,367 @@
+package judging
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/dispute"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	judgingRepository repository.JudgingRepository
+	disputeService    dispute.Service
+	appCfg            config.AppConfig
+}
+
+type Service interface {
+	HandleContributionRecorded(ctx context.Context, tx *sqlx.Tx, event events.Event, data events.ContributionRecordedData) error
+	GetMyQueue(ctx context.Context, limit int, offset int) ([]Review, error)
+	SubmitScore(ctx context.Context, reviewId int64, request SubmitScoreRequest) (Review, error)
+	ListReviews(ctx context.Context, status string, limit int, offset int) ([]Review, error)
+	GetReview(ctx context.Context, reviewId int64) (ReviewDetail, error)
+	FinalizeReview(ctx context.Context, reviewId int64) (Review, error)
+	SetRepositoryFeatured(ctx context.Context, repositoryId int, featured bool) error
+}
+
+func NewService(judgingRepository repository.JudgingRepository, disputeService dispute.Service, appCfg config.AppConfig) Service {
+	return &service{
+		judgingRepository: judgingRepository,
+		disputeService:    disputeService,
+		appCfg:            appCfg,
+	}
+}
+
+// HandleContributionRecorded subscribes to recorded contributions and
+// queues merged pull requests that are large enough, or were merged into a
+// featured repository, for the judges panel.
+func (s *service) HandleContributionRecorded(ctx context.Context, tx *sqlx.Tx, event events.Event, data events.ContributionRecordedData) error {
+	if data.ContributionType != contribution.PullRequestMerged {
+		return nil
+	}
+
+	reason := ""
+	if s.appCfg.Judging.LinesThreshold > 0 && data.LinesChanged >= s.appCfg.Judging.LinesThreshold {
+		reason = ReasonSize
+	} else {
+		featured, err := s.judgingRepository.IsRepositoryFeatured(ctx, tx, data.RepositoryId)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrRepoNotFound) {
+				return nil
+			}
+			return err
+		}
+		if featured {
+			reason = ReasonFeaturedRepository
+		}
+	}
+
+	if reason == "" {
+		return nil
+	}
+
+	return s.judgingRepository.CreateJudgingReview(ctx, tx, repository.JudgingReview{
+		ContributionId: data.ContributionId,
+		Reason:         reason,
+		LinesChanged:   data.LinesChanged,
+		Title:          data.Title,
+		Url:            data.Url,
+		AutomatedScore: data.Points,
+	})
+}
+
+// GetMyQueue tops the caller's queue up with the oldest reviews still short
+// of judges and lists the reviews they have yet to score.
+func (s *service) GetMyQueue(ctx context.Context, limit int, offset int) ([]Review, error) {
+	judgeId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return nil, apperrors.ErrInternalServer
+	}
+
+	open, err := s.judgingRepository.CountOpenJudgingAssignments(ctx, nil, judgeId)
+	if err != nil {
+		return nil, err
+	}
+
+	if open < s.appCfg.Judging.QueueSize {
+		err = s.judgingRepository.AssignJudgingReviews(ctx, nil, judgeId, s.appCfg.Judging.JudgesPerReview, s.appCfg.Judging.QueueSize-open)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reviews, err := s.judgingRepository.ListJudgeQueue(ctx, nil, judgeId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapReviews(reviews), nil
+}
+
+// SubmitScore records the caller's rubric score for a review assigned to
+// them. Reviews assigned to other judges are reported as not found. The
+// score that completes the panel settles the review.
+func (s *service) SubmitScore(ctx context.Context, reviewId int64, request SubmitScoreRequest) (scored Review, err error) {
+	judgeId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Review{}, apperrors.ErrInternalServer
+	}
+
+	for _, score := range []int{request.Impact, request.Quality, request.Docs} {
+		if score < minRubricScore || score > maxRubricScore {
+			return Review{}, apperrors.ErrInvalidRubricScore
+		}
+	}
+
+	tx, err := s.judgingRepository.BeginTx(ctx)
+	if err != nil {
+		return Review{}, err
+	}
+
+	defer func() {
+		txErr := s.judgingRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	review, err := s.judgingRepository.LockJudgingReview(ctx, tx, reviewId)
+	if err != nil {
+		return Review{}, err
+	}
+
+	assigned, err := s.judgingRepository.IsJudgeAssigned(ctx, tx, reviewId, judgeId)
+	if err != nil {
+		return Review{}, err
+	}
+
+	if !assigned {
+		return Review{}, apperrors.ErrJudgingReviewNotFound
+	}
+
+	if review.Status != StatusPending {
+		return Review{}, apperrors.ErrJudgingReviewClosed
+	}
+
+	err = s.judgingRepository.UpsertJudgingScore(ctx, tx, repository.JudgingScore{
+		ReviewId: reviewId,
+		JudgeId:  judgeId,
+		Impact:   request.Impact,
+		Quality:  request.Quality,
+		Docs:     request.Docs,
+		Comment:  strings.TrimSpace(request.Comment),
+	})
+	if err != nil {
+		return Review{}, err
+	}
+
+	scores, err := s.judgingRepository.ListJudgingScores(ctx, tx, reviewId)
+	if err != nil {
+		return Review{}, err
+	}
+
+	if len(scores) >= s.appCfg.Judging.JudgesPerReview {
+		err = s.closeReview(ctx, tx, review, scores, judgeId)
+		if err != nil {
+			return Review{}, err
+		}
+	}
+
+	review, err = s.judgingRepository.GetJudgingReview(ctx, tx, reviewId)
+	if err != nil {
+		return Review{}, err
+	}
+
+	return mapReview(review), nil
+}
+
+func (s *service) ListReviews(ctx context.Context, status string, limit int, offset int) ([]Review, error) {
+	if status != "" && !slices.Contains(Statuses, status) {
+		return nil, apperrors.ErrInvalidQueryParams
+	}
+
+	reviews, err := s.judgingRepository.ListJudgingReviews(ctx, nil, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapReviews(reviews), nil
+}
+
+func (s *service) GetReview(ctx context.Context, reviewId int64) (ReviewDetail, error) {
+	review, err := s.judgingRepository.GetJudgingReview(ctx, nil, reviewId)
+	if err != nil {
+		return ReviewDetail{}, err
+	}
+
+	scores, err := s.judgingRepository.ListJudgingScores(ctx, nil, reviewId)
+	if err != nil {
+		return ReviewDetail{}, err
+	}
+
+	detail := ReviewDetail{
+		Review: mapReview(review),
+		Scores: make([]Score, 0, len(scores)),
+	}
+	for _, score := range scores {
+		detail.Scores = append(detail.Scores, Score{
+			JudgeId:             score.JudgeId,
+			JudgeGithubUsername: score.JudgeGithubUsername,
+			Impact:              score.Impact,
+			Quality:             score.Quality,
+			Docs:                score.Docs,
+			Comment:             score.Comment,
+			UpdatedAt:           score.UpdatedAt,
+		})
+	}
+
+	return detail, nil
+}
+
+// FinalizeReview settles a review with the scores it has so far, for when
+// the panel cannot be completed.
+func (s *service) FinalizeReview(ctx context.Context, reviewId int64) (finalized Review, err error) {
+	adminId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Review{}, apperrors.ErrInternalServer
+	}
+
+	tx, err := s.judgingRepository.BeginTx(ctx)
+	if err != nil {
+		return Review{}, err
+	}
+
+	defer func() {
+		txErr := s.judgingRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	review, err := s.judgingRepository.LockJudgingReview(ctx, tx, reviewId)
+	if err != nil {
+		return Review{}, err
+	}
+
+	if review.Status != StatusPending {
+		return Review{}, apperrors.ErrJudgingReviewClosed
+	}
+
+	scores, err := s.judgingRepository.ListJudgingScores(ctx, tx, reviewId)
+	if err != nil {
+		return Review{}, err
+	}
+
+	if len(scores) == 0 {
+		return Review{}, apperrors.ErrJudgingReviewUnscored
+	}
+
+	err = s.closeReview(ctx, tx, review, scores, adminId)
+	if err != nil {
+		return Review{}, err
+	}
+
+	review, err = s.judgingRepository.GetJudgingReview(ctx, tx, reviewId)
+	if err != nil {
+		return Review{}, err
+	}
+
+	return mapReview(review), nil
+}
+
+func (s *service) SetRepositoryFeatured(ctx context.Context, repositoryId int, featured bool) error {
+	return s.judgingRepository.SetRepositoryFeatured(ctx, nil, repositoryId, featured)
+}
+
+// closeReview replaces the automated score with the average rubric total of
+// the judges, through a judge adjustment so the change is audited and
+// reaches the wallet like any other rescore. A contribution voided while it
+// waited is closed as cancelled instead.
+func (s *service) closeReview(ctx context.Context, tx *sqlx.Tx, review repository.JudgingReview, scores []repository.JudgingScore, actorId int) error {
+	finalScore, reason := judgedScore(scores, s.appCfg.Judging.PointsPerRubricPoint)
+
+	adjustment, err := s.disputeService.ApplyJudgedScore(ctx, tx, actorId, review.ContributionId, finalScore, reason)
+	if errors.Is(err, apperrors.ErrContributionVoided) {
+		return s.judgingRepository.CloseJudgingReview(ctx, tx, review.Id, StatusCancelled, sql.NullInt64{}, sql.NullInt64{})
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.judgingRepository.CloseJudgingReview(ctx, tx, review.Id, StatusFinalized,
+		sql.NullInt64{Int64: int64(finalScore), Valid: true},
+		sql.NullInt64{Int64: int64(adjustment.Id), Valid: true},
+	)
+}
+
+// judgedScore is the average rubric total of the scores times
+// pointsPerRubricPoint, rounded, and the adjustment reason listing the
+// average of each criterion. scores must not be empty.
+func judgedScore(scores []repository.JudgingScore, pointsPerRubricPoint int) (int, string) {
+	var impact, quality, docs int
+	for _, score := range scores {
+		impact += score.Impact
+		quality += score.Quality
+		docs += score.Docs
+	}
+
+	judges := float64(len(scores))
+	finalScore := int(math.Round(float64(impact+quality+docs) / judges * float64(pointsPerRubricPoint)))
+	reason := fmt.Sprintf("judged by a panel of %d: impact %.1f, quality %.1f, docs %.1f", len(scores), float64(impact)/judges, float64(quality)/judges, float64(docs)/judges)
+
+	return finalScore, reason
+}
+
+func mapReviews(reviews []repository.JudgingReview) []Review {
+	mapped := make([]Review, 0, len(reviews))
+	for _, review := range reviews {
+		mapped = append(mapped, mapReview(review))
+	}
+
+	return mapped
+}
+
+func mapReview(review repository.JudgingReview) Review {
+	mapped := Review{
+		Id:                 review.Id,
+		ContributionId:     review.ContributionId,
+		UserId:             review.UserId,
+		GithubUsername:     review.GithubUsername,
+		RepositoryId:       review.RepositoryId,
+		RepositoryFullName: review.RepositoryFullName,
+		Reason:             review.Reason,
+		LinesChanged:       review.LinesChanged,
+		Title:              review.Title,
+		Url:                review.Url,
+		AutomatedScore:     review.AutomatedScore,
+		Status:             review.Status,
+		ScoreCount:         review.ScoreCount,
+		CreatedAt:          review.CreatedAt,
+	}
+	if review.FinalScore.Valid {
+		mapped.FinalScore = &review.FinalScore.Int64
+	}
+	if review.AdjustmentId.Valid {
+		mapped.AdjustmentId = &review.AdjustmentId.Int64
+	}
+	if review.FinalizedAt.Valid {
+		mapped.FinalizedAt = &review.FinalizedAt.Time
+	}
+
+	return mapped
+}
@@ -0,0 +1,73 @@
+package judging
+
+import (
+	"testing"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+func TestJudgedScore(t *testing.T) {
+	tests := []struct {
+		name                 string
+		scores               []repository.JudgingScore
+		pointsPerRubricPoint int
+		wantScore            int
+		wantReason           string
+	}{
+		{
+			name:                 "single judge",
+			scores:               []repository.JudgingScore{{Impact: 5, Quality: 4, Docs: 3}},
+			pointsPerRubricPoint: 10,
+			wantScore:            120,
+			wantReason:           "judged by a panel of 1: impact 5.0, quality 4.0, docs 3.0",
+		},
+		{
+			name: "average of the panel",
+			scores: []repository.JudgingScore{
+				{Impact: 5, Quality: 5, Docs: 5},
+				{Impact: 3, Quality: 4, Docs: 2},
+				{Impact: 1, Quality: 2, Docs: 3},
+			},
+			pointsPerRubricPoint: 10,
+			wantScore:            100,
+			wantReason:           "judged by a panel of 3: impact 3.0, quality 3.7, docs 3.3",
+		},
+		{
+			name:                 "half rounds up",
+			scores:               []repository.JudgingScore{{Impact: 5, Quality: 5, Docs: 5}, {Impact: 4, Quality: 4, Docs: 4}},
+			pointsPerRubricPoint: 1,
+			wantScore:            14,
+			wantReason:           "judged by a panel of 2: impact 4.5, quality 4.5, docs 4.5",
+		},
+		{
+			name: "fraction rounds down",
+			scores: []repository.JudgingScore{
+				{Impact: 1, Quality: 1, Docs: 1},
+				{Impact: 1, Quality: 1, Docs: 1},
+				{Impact: 1, Quality: 1, Docs: 2},
+			},
+			pointsPerRubricPoint: 1,
+			wantScore:            3,
+			wantReason:           "judged by a panel of 3: impact 1.0, quality 1.0, docs 1.3",
+		},
+		{
+			name:                 "no points per rubric point",
+			scores:               []repository.JudgingScore{{Impact: 5, Quality: 5, Docs: 5}},
+			pointsPerRubricPoint: 0,
+			wantScore:            0,
+			wantReason:           "judged by a panel of 1: impact 5.0, quality 5.0, docs 5.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reason := judgedScore(tt.scores, tt.pointsPerRubricPoint)
+			if score != tt.wantScore {
+				t.Errorf("judgedScore() score = %d, want %d", score, tt.wantScore)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("judgedScore() reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
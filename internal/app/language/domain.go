@@ -0,0 +1,29 @@
+package language
+
+import "time"
+
+// StatsRequest bounds the contributions attributed to languages. Zero times
+// leave the window open.
+type StatsRequest struct {
+	From time.Time
+	To   time.Time
+}
+
+// Stat is a user's activity in one language. Repositories counts distinct
+// repositories, which language goals such as "contribute to 3 Rust
+// repositories this month" are measured against.
+type Stat struct {
+	Language      string `json:"language"`
+	Contributions int    `json:"contributions"`
+	Repositories  int    `json:"repositories"`
+	Points        int    `json:"points"`
+}
+
+type LeaderboardEntry struct {
+	UserId         int    `json:"user_id"`
+	GithubUsername string `json:"github_username"`
+	AvatarUrl      string `json:"avatar_url"`
+	Contributions  int    `json:"contributions"`
+	Points         int    `json:"points"`
+	Rank           int    `json:"rank"`
+}
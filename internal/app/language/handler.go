@@ -0,0 +1,85 @@
+package language
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	languageService Service
+}
+
+type Handler interface {
+	GetMyLanguages(w http.ResponseWriter, r *http.Request)
+	GetLanguageLeaderboard(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(languageService Service) Handler {
+	return &handler{
+		languageService: languageService,
+	}
+}
+
+func (h *handler) GetMyLanguages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	statsRequest, err := parseStatsRequest(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	stats, err := h.languageService.GetMyLanguages(ctx, statsRequest)
+	if err != nil {
+		slog.Error("failed to get user languages", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "languages fetched successfully", stats)
+}
+
+func (h *handler) GetLanguageLeaderboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	statsRequest, err := parseStatsRequest(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	entries, err := h.languageService.GetLanguageLeaderboard(ctx, r.PathValue("language"), statsRequest, limit, offset)
+	if err != nil {
+		slog.Error("failed to get language leaderboard", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "language leaderboard fetched successfully", entries)
+}
+
+func parseStatsRequest(r *http.Request) (StatsRequest, error) {
+	from, err := request.ParseTime(r, "from")
+	if err != nil {
+		return StatsRequest{}, err
+	}
+
+	to, err := request.ParseTime(r, "to")
+	if err != nil {
+		return StatsRequest{}, err
+	}
+
+	return StatsRequest{From: from, To: to}, nil
+}
@@ -0,0 +1,67 @@
+package language
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	languageRepository repository.LanguageRepository
+}
+
+type Service interface {
+	GetMyLanguages(ctx context.Context, statsRequest StatsRequest) ([]Stat, error)
+	GetLanguageLeaderboard(ctx context.Context, language string, statsRequest StatsRequest, limit int, offset int) ([]LeaderboardEntry, error)
+}
+
+func NewService(languageRepository repository.LanguageRepository) Service {
+	return &service{
+		languageRepository: languageRepository,
+	}
+}
+
+// GetMyLanguages attributes the logged in user's contributions to the
+// languages of the repositories they were made in.
+func (s *service) GetMyLanguages(ctx context.Context, statsRequest StatsRequest) ([]Stat, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return nil, apperrors.ErrInternalServer
+	}
+
+	stats, err := s.languageRepository.GetUserLanguageStats(ctx, nil, userId, nullTime(statsRequest.From), nullTime(statsRequest.To))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Stat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, Stat(stat))
+	}
+
+	return result, nil
+}
+
+func (s *service) GetLanguageLeaderboard(ctx context.Context, language string, statsRequest StatsRequest, limit int, offset int) ([]LeaderboardEntry, error) {
+	entries, err := s.languageRepository.GetLanguageLeaderboard(ctx, nil, language, nullTime(statsRequest.From), nullTime(statsRequest.To), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]LeaderboardEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, LeaderboardEntry(entry))
+	}
+
+	return result, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,76 @@
+package leaderboard
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/summary"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+// snapshots older than this are pruned on every refresh
+const snapshotRetention = 7 * 24 * time.Hour
+
+type service struct {
+	leaderboardRepository repository.LeaderboardRepository
+	eventBus              events.Bus
+}
+
+type Service interface {
+	RefreshLeaderboard(ctx context.Context) error
+}
+
+func NewService(leaderboardRepository repository.LeaderboardRepository, eventBus events.Bus) Service {
+	return &service{
+		leaderboardRepository: leaderboardRepository,
+		eventBus:              eventBus,
+	}
+}
+
+// RefreshLeaderboard materializes a new ranked snapshot of all active users,
+// and of all teams by the points earned for them this month.
+func (s *service) RefreshLeaderboard(ctx context.Context) (err error) {
+	refreshedAt := time.Now()
+
+	tx, err := s.leaderboardRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		txErr := s.leaderboardRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	err = s.leaderboardRepository.CreateLeaderboardSnapshot(ctx, tx, refreshedAt)
+	if err != nil {
+		slog.Error("failed to create leaderboard snapshot", "error", err)
+		return err
+	}
+
+	err = s.leaderboardRepository.DeleteLeaderboardSnapshotsBefore(ctx, tx, refreshedAt.Add(-snapshotRetention))
+	if err != nil {
+		slog.Error("failed to prune leaderboard snapshots", "error", err)
+		return err
+	}
+
+	monthStart := time.Date(refreshedAt.Year(), refreshedAt.Month(), 1, 0, 0, 0, 0, time.UTC)
+	err = s.leaderboardRepository.CreateTeamLeaderboardSnapshot(ctx, tx, refreshedAt, summary.MonthYear(monthStart), monthStart, monthStart.AddDate(0, 1, 0))
+	if err != nil {
+		slog.Error("failed to create team leaderboard snapshot", "error", err)
+		return err
+	}
+
+	err = s.leaderboardRepository.DeleteTeamLeaderboardSnapshotsBefore(ctx, tx, refreshedAt.Add(-snapshotRetention))
+	if err != nil {
+		slog.Error("failed to prune team leaderboard snapshots", "error", err)
+		return err
+	}
+
+	return s.eventBus.Publish(ctx, tx, events.New(events.LeaderboardRefreshed, 0, map[string]any{
+		"refreshed_at": refreshedAt,
+	}))
+}
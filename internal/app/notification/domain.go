@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	TypeContributionVoided          = "contribution_voided"
+	TypeContributionRescored        = "contribution_rescored"
+	TypeContributionDisputeRejected = "contribution_dispute_rejected"
+	TypeAccountOnHold               = "account_on_hold"
+	TypeRedemptionFulfilled         = "redemption_fulfilled"
+	TypeRedemptionRejected          = "redemption_rejected"
+	TypePointsEarned                = "points_earned"
+	TypeBadgeUnlocked               = "badge_unlocked"
+	TypeGoalAchieved                = "goal_achieved"
+	TypeWeeklyDigest                = "weekly_digest"
+	TypeChallengePrizeWon           = "challenge_prize_won"
+
+	SendEmailJob = "send_notification_email"
+)
+
+type Notification struct {
+	Id        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    *time.Time      `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type Inbox struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+}
+
+// Preference is the channels a user receives one notification type on.
+type Preference struct {
+	Type  string `json:"type"`
+	InApp bool   `json:"in_app"`
+	Email bool   `json:"email"`
+}
+
+type UpdatePreferencesRequest struct {
+	Preferences []Preference `json:"preferences"`
+}
+
+// SendEmailPayload is a rendered email waiting in the job queue. The
+// address is looked up when it is sent so changed or erased addresses are
+// respected.
+type SendEmailPayload struct {
+	UserId  int    `json:"user_id"`
+	Type    string `json:"type"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
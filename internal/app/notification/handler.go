@@ -0,0 +1,129 @@
+package notification
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	notificationService Service
+}
+
+type Handler interface {
+	ListMyNotifications(w http.ResponseWriter, r *http.Request)
+	MarkRead(w http.ResponseWriter, r *http.Request)
+	MarkAllRead(w http.ResponseWriter, r *http.Request)
+	GetMyPreferences(w http.ResponseWriter, r *http.Request)
+	UpdateMyPreferences(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(notificationService Service) Handler {
+	return &handler{
+		notificationService: notificationService,
+	}
+}
+
+func (h *handler) ListMyNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	unreadOnly := false
+	if unreadParam := r.URL.Query().Get("unread"); unreadParam != "" {
+		unreadOnly, err = strconv.ParseBool(unreadParam)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+	}
+
+	inbox, err := h.notificationService.ListMyNotifications(ctx, unreadOnly, limit, offset)
+	if err != nil {
+		slog.Error("failed to list notifications", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "notifications fetched successfully", inbox)
+}
+
+func (h *handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	notificationId, err := strconv.ParseInt(r.PathValue("notificationId"), 10, 64)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.notificationService.MarkRead(ctx, notificationId)
+	if err != nil {
+		slog.Error("failed to mark notification read", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "notification marked read", nil)
+}
+
+func (h *handler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	marked, err := h.notificationService.MarkAllRead(ctx)
+	if err != nil {
+		slog.Error("failed to mark notifications read", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "notifications marked read", map[string]int64{"marked": marked})
+}
+
+func (h *handler) GetMyPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	preferences, err := h.notificationService.GetMyPreferences(ctx)
+	if err != nil {
+		slog.Error("failed to get notification preferences", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "notification preferences fetched successfully", preferences)
+}
+
+func (h *handler) UpdateMyPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var updateRequest UpdatePreferencesRequest
+	err := json.NewDecoder(r.Body).Decode(&updateRequest)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	preferences, err := h.notificationService.UpdateMyPreferences(ctx, updateRequest)
+	if err != nil {
+		slog.Error("failed to update notification preferences", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "notification preferences updated successfully", preferences)
+}
@@ -0,0 +1,380 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/job"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/mailer"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	notificationRepository repository.NotificationRepository
+	userRepository         repository.UserRepository
+	jobService             job.Service
+	mailer                 mailer.Mailer
+	appCfg                 config.AppConfig
+}
+
+type Service interface {
+	Notify(ctx context.Context, tx *sqlx.Tx, userId int, notificationType string, data map[string]any) error
+	ListMyNotifications(ctx context.Context, unreadOnly bool, limit int, offset int) (Inbox, error)
+	MarkRead(ctx context.Context, notificationId int64) error
+	MarkAllRead(ctx context.Context) (int64, error)
+	GetMyPreferences(ctx context.Context) ([]Preference, error)
+	UpdateMyPreferences(ctx context.Context, request UpdatePreferencesRequest) ([]Preference, error)
+	SendEmail(ctx context.Context, payload SendEmailPayload) error
+	SendWeeklyDigest(ctx context.Context, weekEnd time.Time) (int, error)
+}
+
+func NewService(notificationRepository repository.NotificationRepository, userRepository repository.UserRepository, jobService job.Service, mailer mailer.Mailer, appCfg config.AppConfig) Service {
+	return &service{
+		notificationRepository: notificationRepository,
+		userRepository:         userRepository,
+		jobService:             jobService,
+		mailer:                 mailer,
+		appCfg:                 appCfg,
+	}
+}
+
+// Notify renders the notification type's template and delivers it on the
+// channels the user wants. Pass the caller's transaction so nothing is
+// delivered unless the change it describes commits. Emails are only queued
+// here, so a failing mail server never rolls back the caller.
+func (s *service) Notify(ctx context.Context, tx *sqlx.Tx, userId int, notificationType string, data map[string]any) error {
+	messageTemplate, ok := templates[notificationType]
+	if !ok {
+		slog.Error("no template for notification type", "type", notificationType)
+		return apperrors.ErrUnknownNotificationType
+	}
+
+	title, body, err := messageTemplate.render(data)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := s.preferences(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	preference := preferences[notificationType]
+
+	if preference.InApp {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			slog.Error("failed to marshal notification data", "error", err)
+			return apperrors.ErrInternalServer
+		}
+
+		_, err = s.notificationRepository.CreateNotification(ctx, tx, repository.Notification{
+			UserId: userId,
+			Type:   notificationType,
+			Title:  title,
+			Body:   body,
+			Data:   encoded,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if preference.Email {
+		err = s.queueEmail(ctx, tx, SendEmailPayload{
+			UserId:  userId,
+			Type:    notificationType,
+			Subject: title,
+			Body:    body,
+		}, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) ListMyNotifications(ctx context.Context, unreadOnly bool, limit int, offset int) (Inbox, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Inbox{}, apperrors.ErrInternalServer
+	}
+
+	notifications, err := s.notificationRepository.ListNotifications(ctx, nil, userId, unreadOnly, limit, offset)
+	if err != nil {
+		return Inbox{}, err
+	}
+
+	unread, err := s.notificationRepository.CountUnreadNotifications(ctx, nil, userId)
+	if err != nil {
+		return Inbox{}, err
+	}
+
+	inbox := Inbox{
+		Notifications: make([]Notification, 0, len(notifications)),
+		Unread:        unread,
+	}
+	for _, notification := range notifications {
+		inbox.Notifications = append(inbox.Notifications, newNotification(notification))
+	}
+
+	return inbox, nil
+}
+
+func (s *service) MarkRead(ctx context.Context, notificationId int64) error {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	return s.notificationRepository.MarkNotificationRead(ctx, nil, userId, notificationId)
+}
+
+func (s *service) MarkAllRead(ctx context.Context) (int64, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return s.notificationRepository.MarkAllNotificationsRead(ctx, nil, userId)
+}
+
+// GetMyPreferences returns the effective channels for every notification
+// type, with defaults filled in for types the user never changed.
+func (s *service) GetMyPreferences(ctx context.Context) ([]Preference, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return nil, apperrors.ErrInternalServer
+	}
+
+	preferences, err := s.preferences(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Preference, 0, len(Types))
+	for _, notificationType := range Types {
+		result = append(result, preferences[notificationType])
+	}
+
+	return result, nil
+}
+
+// UpdateMyPreferences saves the given types' channels and leaves the other
+// types as they were.
+func (s *service) UpdateMyPreferences(ctx context.Context, request UpdatePreferencesRequest) (updated []Preference, err error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return nil, apperrors.ErrInternalServer
+	}
+
+	for _, preference := range request.Preferences {
+		messageTemplate, ok := templates[preference.Type]
+		if !ok {
+			return nil, apperrors.ErrUnknownNotificationType
+		}
+		if preference.InApp && !messageTemplate.supportsInApp {
+			return nil, apperrors.ErrInvalidRequestBody
+		}
+	}
+
+	tx, err := s.notificationRepository.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		txErr := s.notificationRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	for _, preference := range request.Preferences {
+		err = s.notificationRepository.UpsertNotificationPreference(ctx, tx, repository.NotificationPreference{
+			UserId: userId,
+			Type:   preference.Type,
+			InApp:  preference.InApp,
+			Email:  preference.Email,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	preferences, err := s.preferences(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	updated = make([]Preference, 0, len(Types))
+	for _, notificationType := range Types {
+		updated = append(updated, preferences[notificationType])
+	}
+
+	return updated, nil
+}
+
+// SendEmail is the job handler delivering a queued email. Returning an
+// error leaves the job to be retried with backoff.
+func (s *service) SendEmail(ctx context.Context, payload SendEmailPayload) error {
+	user, err := s.userRepository.GetUserById(ctx, nil, payload.UserId)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			slog.Warn("dropping email for deleted user", "user_id", payload.UserId, "type", payload.Type)
+			return nil
+		}
+		return err
+	}
+
+	if user.Email == "" {
+		slog.Warn("dropping email for user without an address", "user_id", payload.UserId, "type", payload.Type)
+		return nil
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: payload.Subject,
+		Body:    payload.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("notification email sent", "user_id", payload.UserId, "type", payload.Type)
+	return nil
+}
+
+// SendWeeklyDigest queues a digest of the week ending at weekEnd for every
+// user with activity or unread notifications. Users already sent that
+// week's digest are skipped, so the digest can safely be run again.
+func (s *service) SendWeeklyDigest(ctx context.Context, weekEnd time.Time) (int, error) {
+	weekEnd = time.Date(weekEnd.Year(), weekEnd.Month(), weekEnd.Day(), 0, 0, 0, 0, time.UTC)
+	weekStart := weekEnd.AddDate(0, 0, -7)
+
+	recipients, err := s.notificationRepository.ListDigestRecipients(ctx, nil, TypeWeeklyDigest, weekStart, weekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, recipient := range recipients {
+		err = s.queueDigest(ctx, recipient, weekStart)
+		if errors.Is(err, apperrors.ErrNotificationDigestExists) {
+			continue
+		}
+		if err != nil {
+			slog.Error("failed to queue weekly digest", "user_id", recipient.UserId, "error", err)
+			return sent, err
+		}
+		sent++
+	}
+
+	slog.Info("weekly digests queued", "week_start", weekStart, "digests", sent)
+	return sent, nil
+}
+
+func (s *service) queueDigest(ctx context.Context, recipient repository.DigestRecipient, weekStart time.Time) (err error) {
+	title, body, err := templates[TypeWeeklyDigest].render(map[string]any{
+		"github_username":      recipient.GithubUsername,
+		"contributions":        recipient.Contributions,
+		"points":               recipient.Points,
+		"unread_notifications": recipient.UnreadNotifications,
+	})
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.notificationRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.notificationRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	err = s.notificationRepository.RecordDigest(ctx, tx, recipient.UserId, weekStart)
+	if err != nil {
+		return err
+	}
+
+	return s.queueEmail(ctx, tx, SendEmailPayload{
+		UserId:  recipient.UserId,
+		Type:    TypeWeeklyDigest,
+		Subject: title,
+		Body:    body,
+	}, fmt.Sprintf("%s:%d:%s", TypeWeeklyDigest, recipient.UserId, weekStart.Format(time.DateOnly)))
+}
+
+func (s *service) queueEmail(ctx context.Context, tx *sqlx.Tx, payload SendEmailPayload, uniqueKey string) error {
+	_, err := s.jobService.Enqueue(ctx, tx, SendEmailJob, payload, job.EnqueueOptions{
+		UniqueKey:   uniqueKey,
+		MaxAttempts: s.appCfg.Notifications.EmailMaxAttempts,
+	})
+
+	return err
+}
+
+// preferences resolves the user's channels for every notification type.
+func (s *service) preferences(ctx context.Context, tx *sqlx.Tx, userId int) (map[string]Preference, error) {
+	stored, err := s.notificationRepository.ListNotificationPreferences(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make(map[string]Preference, len(templates))
+	for notificationType, messageTemplate := range templates {
+		preferences[notificationType] = Preference{
+			Type:  notificationType,
+			InApp: messageTemplate.inApp,
+			Email: messageTemplate.email,
+		}
+	}
+
+	for _, preference := range stored {
+		messageTemplate, ok := templates[preference.Type]
+		if !ok {
+			continue
+		}
+		preferences[preference.Type] = Preference{
+			Type:  preference.Type,
+			InApp: preference.InApp && messageTemplate.supportsInApp,
+			Email: preference.Email,
+		}
+	}
+
+	return preferences, nil
+}
+
+func newNotification(notification repository.Notification) Notification {
+	converted := Notification{
+		Id:        notification.Id,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Data:      notification.Data,
+		CreatedAt: notification.CreatedAt,
+	}
+	if notification.ReadAt.Valid {
+		converted.ReadAt = &notification.ReadAt.Time
+	}
+
+	return converted
+}
@@ -0,0 +1,243 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/job"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/mailer"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+// fakeNotificationRepository keeps notifications, preferences and sent
+// digests in memory. Any other method panics through the nil embedded
+// interface.
+type fakeNotificationRepository struct {
+	repository.NotificationRepository
+	preferences []repository.NotificationPreference
+	recipients  []repository.DigestRecipient
+
+	notifications []repository.Notification
+	digests       map[string]bool
+}
+
+func (f *fakeNotificationRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return nil, nil
+}
+
+func (f *fakeNotificationRepository) HandleTransaction(ctx context.Context, tx *sqlx.Tx, incomingErr error) error {
+	return nil
+}
+
+func (f *fakeNotificationRepository) CreateNotification(ctx context.Context, tx *sqlx.Tx, notification repository.Notification) (repository.Notification, error) {
+	f.notifications = append(f.notifications, notification)
+	return notification, nil
+}
+
+func (f *fakeNotificationRepository) ListNotificationPreferences(ctx context.Context, tx *sqlx.Tx, userId int) ([]repository.NotificationPreference, error) {
+	return f.preferences, nil
+}
+
+func (f *fakeNotificationRepository) ListDigestRecipients(ctx context.Context, tx *sqlx.Tx, digestType string, weekStart time.Time, weekEnd time.Time) ([]repository.DigestRecipient, error) {
+	return f.recipients, nil
+}
+
+func (f *fakeNotificationRepository) RecordDigest(ctx context.Context, tx *sqlx.Tx, userId int, weekStart time.Time) error {
+	key := fmt.Sprintf("%d:%s", userId, weekStart.Format(time.DateOnly))
+	if f.digests[key] {
+		return apperrors.ErrNotificationDigestExists
+	}
+	f.digests[key] = true
+	return nil
+}
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	users map[int]repository.User
+}
+
+func (f *fakeUserRepository) GetUserById(ctx context.Context, tx *sqlx.Tx, userId int) (repository.User, error) {
+	user, ok := f.users[userId]
+	if !ok {
+		return repository.User{}, apperrors.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// fakeJobService holds queued emails until the test delivers them.
+type fakeJobService struct {
+	job.Service
+	queued []SendEmailPayload
+}
+
+func (f *fakeJobService) Enqueue(ctx context.Context, tx *sqlx.Tx, jobType string, payload any, opts job.EnqueueOptions) (job.Job, error) {
+	f.queued = append(f.queued, payload.(SendEmailPayload))
+	return job.Job{}, nil
+}
+
+// deliver runs the queued emails through SendEmail, as the job worker would.
+func (f *fakeJobService) deliver(t *testing.T, s Service) {
+	t.Helper()
+
+	for _, payload := range f.queued {
+		err := s.SendEmail(context.Background(), payload)
+		if err != nil {
+			t.Fatalf("SendEmail() error = %v", err)
+		}
+	}
+	f.queued = nil
+}
+
+func newTestService(notificationRepository *fakeNotificationRepository, users map[int]repository.User) (Service, *fakeJobService, *mailer.MemoryMailer) {
+	jobService := &fakeJobService{}
+	memoryMailer := mailer.NewMemoryMailer()
+	s := NewService(notificationRepository, &fakeUserRepository{users: users}, jobService, memoryMailer, config.AppConfig{})
+
+	return s, jobService, memoryMailer
+}
+
+func TestNotifyPreferences(t *testing.T) {
+	data := map[string]any{"contribution_id": 12, "previous_balance_change": 20, "new_balance_change": 5, "reason": "duplicate"}
+	users := map[int]repository.User{1: {Id: 1, Email: "octocat@example.com"}}
+
+	tests := []struct {
+		name             string
+		notificationType string
+		preferences      []repository.NotificationPreference
+		wantInApp        bool
+		wantEmail        bool
+	}{
+		{name: "defaults with email", notificationType: TypeContributionVoided, wantInApp: true, wantEmail: true},
+		{name: "defaults without email", notificationType: TypeContributionRescored, wantInApp: true},
+		{
+			name:             "email turned off",
+			notificationType: TypeContributionVoided,
+			preferences:      []repository.NotificationPreference{{UserId: 1, Type: TypeContributionVoided, InApp: true, Email: false}},
+			wantInApp:        true,
+		},
+		{
+			name:             "email turned on",
+			notificationType: TypeContributionRescored,
+			preferences:      []repository.NotificationPreference{{UserId: 1, Type: TypeContributionRescored, InApp: true, Email: true}},
+			wantInApp:        true,
+			wantEmail:        true,
+		},
+		{
+			name:             "everything turned off",
+			notificationType: TypeContributionVoided,
+			preferences:      []repository.NotificationPreference{{UserId: 1, Type: TypeContributionVoided}},
+		},
+		{
+			name:             "preference for another type",
+			notificationType: TypeContributionVoided,
+			preferences:      []repository.NotificationPreference{{UserId: 1, Type: TypeContributionRescored}},
+			wantInApp:        true,
+			wantEmail:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationRepository := &fakeNotificationRepository{preferences: tt.preferences}
+			s, jobService, memoryMailer := newTestService(notificationRepository, users)
+
+			err := s.Notify(context.Background(), nil, 1, tt.notificationType, data)
+			if err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			jobService.deliver(t, s)
+
+			if got := len(notificationRepository.notifications) == 1; got != tt.wantInApp {
+				t.Errorf("in-app notifications = %d, want in-app %v", len(notificationRepository.notifications), tt.wantInApp)
+			}
+
+			messages := memoryMailer.Messages()
+			if got := len(messages) == 1; got != tt.wantEmail {
+				t.Fatalf("emails sent = %d, want email %v", len(messages), tt.wantEmail)
+			}
+			if tt.wantEmail {
+				if messages[0].To != "octocat@example.com" || !strings.Contains(messages[0].Body, "Contribution #12") {
+					t.Errorf("email = %+v, want the rendered notification sent to the user", messages[0])
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyUnknownType(t *testing.T) {
+	s, _, _ := newTestService(&fakeNotificationRepository{}, nil)
+
+	err := s.Notify(context.Background(), nil, 1, "not_a_type", map[string]any{})
+	if !errors.Is(err, apperrors.ErrUnknownNotificationType) {
+		t.Errorf("Notify() error = %v, want %v", err, apperrors.ErrUnknownNotificationType)
+	}
+}
+
+func TestSendEmailDropsUndeliverable(t *testing.T) {
+	users := map[int]repository.User{1: {Id: 1}}
+	s, _, memoryMailer := newTestService(&fakeNotificationRepository{}, users)
+
+	for _, userId := range []int{1, 2} {
+		err := s.SendEmail(context.Background(), SendEmailPayload{UserId: userId, Type: TypeAccountOnHold, Subject: "Account on hold", Body: "body"})
+		if err != nil {
+			t.Errorf("SendEmail() for user %d error = %v, want the email dropped", userId, err)
+		}
+	}
+
+	if messages := memoryMailer.Messages(); len(messages) != 0 {
+		t.Errorf("emails sent = %+v, want none", messages)
+	}
+}
+
+func TestSendWeeklyDigest(t *testing.T) {
+	notificationRepository := &fakeNotificationRepository{
+		recipients: []repository.DigestRecipient{
+			{UserId: 1, GithubUsername: "octocat", Contributions: 4, Points: 60, UnreadNotifications: 2},
+			{UserId: 2, GithubUsername: "hubot", Contributions: 1, Points: 10},
+		},
+		digests: map[string]bool{},
+	}
+	users := map[int]repository.User{
+		1: {Id: 1, Email: "octocat@example.com"},
+		2: {Id: 2, Email: "hubot@example.com"},
+	}
+	s, jobService, memoryMailer := newTestService(notificationRepository, users)
+
+	weekEnd := time.Date(2025, time.May, 12, 6, 0, 0, 0, time.UTC)
+	sent, err := s.SendWeeklyDigest(context.Background(), weekEnd)
+	if err != nil {
+		t.Fatalf("SendWeeklyDigest() error = %v", err)
+	}
+	if sent != 2 {
+		t.Errorf("SendWeeklyDigest() = %d, want 2", sent)
+	}
+	jobService.deliver(t, s)
+
+	// a rerun later the same day finds both digests already sent
+	sent, err = s.SendWeeklyDigest(context.Background(), weekEnd.Add(12*time.Hour))
+	if err != nil {
+		t.Fatalf("SendWeeklyDigest() rerun error = %v", err)
+	}
+	if sent != 0 {
+		t.Errorf("SendWeeklyDigest() rerun = %d, want 0", sent)
+	}
+	jobService.deliver(t, s)
+
+	messages := memoryMailer.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("emails sent = %d, want 2", len(messages))
+	}
+	if messages[0].To != "octocat@example.com" || !strings.Contains(messages[0].Body, "Hi octocat,") || !strings.Contains(messages[0].Body, "Points earned: 60") {
+		t.Errorf("digest = %+v, want octocat's week", messages[0])
+	}
+	if len(notificationRepository.notifications) != 0 {
+		t.Errorf("in-app notifications = %d, want none for the digest", len(notificationRepository.notifications))
+	}
+}
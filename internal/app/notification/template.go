@@ -0,0 +1,135 @@
+package notification
+
+import (
+	"log/slog"
+	"strings"
+	"text/template"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+// messageTemplate renders one notification type. inApp and email are the
+// channels used until the user sets a preference for the type; a type
+// that does not support a channel is never sent on it.
+type messageTemplate struct {
+	title *template.Template
+	body  *template.Template
+
+	inApp         bool
+	email         bool
+	supportsInApp bool
+}
+
+var templates = map[string]messageTemplate{
+	TypeContributionVoided: newTemplate(
+		"Contribution voided",
+		"Contribution #{{.contribution_id}} was voided and its {{.previous_balance_change}} points removed: {{.reason}}",
+		true, true,
+	),
+	TypeContributionRescored: newTemplate(
+		"Contribution re-scored",
+		"Contribution #{{.contribution_id}} was re-scored from {{.previous_balance_change}} to {{.new_balance_change}} points: {{.reason}}",
+		true, false,
+	),
+	TypeContributionDisputeRejected: newTemplate(
+		"Dispute rejected",
+		"Your dispute on contribution #{{.contribution_id}} was reviewed and rejected: {{.reason}}",
+		true, true,
+	),
+	TypeAccountOnHold: newTemplate(
+		"Account on hold",
+		"Some of your recent contributions look unusual, so your account is on hold until an admin reviews it.",
+		true, true,
+	),
+	TypeRedemptionFulfilled: newTemplate(
+		"Redemption fulfilled",
+		"Your redemption of {{.points}} points for a {{.store}} gift card has been fulfilled",
+		true, true,
+	),
+	TypeRedemptionRejected: newTemplate(
+		"Redemption rejected",
+		"Your redemption of {{.points}} points was rejected and the points returned to your wallet: {{.note}}",
+		true, true,
+	),
+	TypePointsEarned: newTemplate(
+		"Points added to your wallet",
+		"{{.points}} points for your contributions in {{.month}} were added to your wallet",
+		true, false,
+	),
+	TypeBadgeUnlocked: newTemplate(
+		"Badge unlocked",
+		"You unlocked the {{.badge_type}} badge",
+		true, false,
+	),
+	TypeGoalAchieved: newTemplate(
+		"Goal achieved",
+		"You reached your {{.level}} goal for {{.month}}",
+		true, true,
+	),
+	TypeChallengePrizeWon: newTemplate(
+		"Challenge prize won",
+		"You finished #{{.rank}} in {{.challenge}} and {{.points}} points were added to your wallet",
+		true, true,
+	),
+	TypeWeeklyDigest: emailOnly(newTemplate(
+		"Your week on CodeCuriosity",
+		`Hi {{.github_username}},
+
+Here is your week on CodeCuriosity:
+
+  Contributions: {{.contributions}}
+  Points earned: {{.points}}
+  Unread notifications: {{.unread_notifications}}
+
+Keep contributing!`,
+		false, true,
+	)),
+}
+
+// Types lists the notification types users can set preferences for.
+var Types = []string{
+	TypeContributionVoided,
+	TypeContributionRescored,
+	TypeContributionDisputeRejected,
+	TypeAccountOnHold,
+	TypeRedemptionFulfilled,
+	TypeRedemptionRejected,
+	TypePointsEarned,
+	TypeBadgeUnlocked,
+	TypeGoalAchieved,
+	TypeChallengePrizeWon,
+	TypeWeeklyDigest,
+}
+
+func newTemplate(title string, body string, inApp bool, email bool) messageTemplate {
+	return messageTemplate{
+		title:         template.Must(template.New("title").Option("missingkey=error").Parse(title)),
+		body:          template.Must(template.New("body").Option("missingkey=error").Parse(body)),
+		inApp:         inApp,
+		email:         email,
+		supportsInApp: true,
+	}
+}
+
+func emailOnly(messageTemplate messageTemplate) messageTemplate {
+	messageTemplate.supportsInApp = false
+	return messageTemplate
+}
+
+func (t messageTemplate) render(data map[string]any) (string, string, error) {
+	var title, body strings.Builder
+
+	err := t.title.Execute(&title, data)
+	if err != nil {
+		slog.Error("failed to render notification title", "error", err)
+		return "", "", apperrors.ErrInternalServer
+	}
+
+	err = t.body.Execute(&body, data)
+	if err != nil {
+		slog.Error("failed to render notification body", "error", err)
+		return "", "", apperrors.ErrInternalServer
+	}
+
+	return title.String(), body.String(), nil
+}
@@ -0,0 +1,107 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+)
+
+// callerData is the data each notification type's caller passes to Notify.
+// Templates fail to render on a missing key, so a template and its caller
+// drifting apart shows up here instead of as a failed notification.
+var callerData = map[string]map[string]any{
+	TypeContributionVoided: {
+		"contribution_id":         12,
+		"previous_balance_change": 20,
+		"new_balance_change":      0,
+		"reason":                  "duplicate",
+	},
+	TypeContributionRescored: {
+		"contribution_id":         12,
+		"previous_balance_change": 20,
+		"new_balance_change":      5,
+		"reason":                  "trivial change",
+	},
+	TypeContributionDisputeRejected: {
+		"contribution_id": 12,
+		"dispute_id":      3,
+		"reason":          "scored correctly",
+	},
+	TypeAccountOnHold: {
+		"risk_score": 80,
+	},
+	TypeRedemptionFulfilled: {
+		"redemption_id": 7,
+		"points":        500,
+		"store":         "amazon",
+	},
+	TypeRedemptionRejected: {
+		"redemption_id": 7,
+		"points":        500,
+		"note":          "store unavailable",
+	},
+	TypePointsEarned: {
+		"points":     120,
+		"month":      "May 2025",
+		"month_year": 202505,
+	},
+	TypeBadgeUnlocked: {
+		"badge_type": "first_pr",
+	},
+	TypeGoalAchieved: {
+		"level":      "advanced",
+		"month":      "May 2025",
+		"month_year": 202505,
+	},
+	TypeChallengePrizeWon: {
+		"challenge":    "Docs Sprint",
+		"challenge_id": 4,
+		"rank":         1,
+		"points":       300,
+	},
+	TypeWeeklyDigest: {
+		"github_username":      "octocat",
+		"contributions":        4,
+		"points":               60,
+		"unread_notifications": 2,
+	},
+}
+
+func TestTemplatesRender(t *testing.T) {
+	for notificationType, messageTemplate := range templates {
+		t.Run(notificationType, func(t *testing.T) {
+			data, ok := callerData[notificationType]
+			if !ok {
+				t.Fatalf("no caller data for %s", notificationType)
+			}
+
+			title, body, err := messageTemplate.render(data)
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			if title == "" || body == "" {
+				t.Errorf("render() = %q, %q, want a title and body", title, body)
+			}
+			if strings.Contains(title+body, "<no value>") {
+				t.Errorf("render() = %q, %q, want every value filled in", title, body)
+			}
+		})
+	}
+}
+
+func TestTemplatesListedInTypes(t *testing.T) {
+	if len(Types) != len(templates) {
+		t.Errorf("Types lists %d types, want all %d templates", len(Types), len(templates))
+	}
+	for _, notificationType := range Types {
+		if _, ok := templates[notificationType]; !ok {
+			t.Errorf("Types lists %s without a template", notificationType)
+		}
+	}
+}
+
+func TestRenderMissingKey(t *testing.T) {
+	_, _, err := templates[TypeRedemptionFulfilled].render(map[string]any{"points": 500})
+	if err == nil {
+		t.Error("render() error = nil, want an error for the missing store")
+	}
+}
@@ -0,0 +1,48 @@
+package profile
+
+import "time"
+
+const topRepositoriesLimit = 5
+
+// PublicProfile is what anyone can see about a user. Balance and activity
+// fields are left out when the user hides them in their privacy settings.
+type PublicProfile struct {
+	GithubUsername  string          `json:"github_username"`
+	AvatarUrl       string          `json:"avatar_url"`
+	TotalPoints     *int            `json:"total_points,omitempty"`
+	Rank            *int            `json:"rank,omitempty"`
+	Badges          []Badge         `json:"badges"`
+	TopRepositories []TopRepository `json:"top_repositories,omitempty"`
+	Languages       []LanguageCount `json:"languages,omitempty"`
+	Streak          *int            `json:"streak,omitempty"`
+	MemberSince     time.Time       `json:"member_since"`
+}
+
+type Badge struct {
+	BadgeType string    `json:"badge_type"`
+	EarnedAt  time.Time `json:"earned_at"`
+}
+
+type TopRepository struct {
+	RepoName      string `json:"repo_name"`
+	OwnerName     string `json:"owner_name"`
+	RepoUrl       string `json:"repo_url"`
+	Language      string `json:"language"`
+	Contributions int    `json:"contributions"`
+}
+
+type LanguageCount struct {
+	Language      string `json:"language"`
+	Contributions int    `json:"contributions"`
+}
+
+type PrivacySettings struct {
+	HideBalance  bool `json:"hide_balance"`
+	HideActivity bool `json:"hide_activity"`
+}
+
+// UpdatePrivacySettingsRequest leaves settings that are not sent unchanged.
+type UpdatePrivacySettingsRequest struct {
+	HideBalance  *bool `json:"hide_balance"`
+	HideActivity *bool `json:"hide_activity"`
+}
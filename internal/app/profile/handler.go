@@ -0,0 +1,76 @@
+package profile
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	profileService Service
+}
+
+type Handler interface {
+	GetPublicProfile(w http.ResponseWriter, r *http.Request)
+	GetPrivacySettings(w http.ResponseWriter, r *http.Request)
+	UpdatePrivacySettings(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(profileService Service) Handler {
+	return &handler{
+		profileService: profileService,
+	}
+}
+
+func (h *handler) GetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	profile, err := h.profileService.GetPublicProfile(ctx, r.PathValue("github_username"))
+	if err != nil {
+		slog.Error("failed to get public profile", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "profile fetched successfully", profile)
+}
+
+func (h *handler) GetPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	settings, err := h.profileService.GetPrivacySettings(ctx)
+	if err != nil {
+		slog.Error("failed to get privacy settings", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "privacy settings fetched successfully", settings)
+}
+
+func (h *handler) UpdatePrivacySettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody UpdatePrivacySettingsRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	settings, err := h.profileService.UpdatePrivacySettings(ctx, requestBody)
+	if err != nil {
+		slog.Error("failed to update privacy settings", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "privacy settings updated successfully", settings)
+}
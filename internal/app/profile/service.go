@@ -0,0 +1,164 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/streak"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/user"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	profileRepository        repository.ProfileRepository
+	privacySettingRepository repository.PrivacySettingRepository
+	badgeRepository          repository.BadgeRepository
+	userService              user.Service
+	streakService            streak.Service
+}
+
+type Service interface {
+	GetPublicProfile(ctx context.Context, githubUsername string) (PublicProfile, error)
+	GetPrivacySettings(ctx context.Context) (PrivacySettings, error)
+	UpdatePrivacySettings(ctx context.Context, settings UpdatePrivacySettingsRequest) (PrivacySettings, error)
+}
+
+func NewService(profileRepository repository.ProfileRepository, privacySettingRepository repository.PrivacySettingRepository, badgeRepository repository.BadgeRepository, userService user.Service, streakService streak.Service) Service {
+	return &service{
+		profileRepository:        profileRepository,
+		privacySettingRepository: privacySettingRepository,
+		badgeRepository:          badgeRepository,
+		userService:              userService,
+		streakService:            streakService,
+	}
+}
+
+func (s *service) GetPublicProfile(ctx context.Context, githubUsername string) (PublicProfile, error) {
+	userInfo, err := s.userService.GetUserByGithubUsername(ctx, githubUsername)
+	if err != nil {
+		return PublicProfile{}, err
+	}
+
+	// blocked users are kept off every public listing
+	if userInfo.IsBlocked {
+		return PublicProfile{}, apperrors.ErrUserNotFound
+	}
+
+	settings, err := s.privacySettings(ctx, userInfo.Id)
+	if err != nil {
+		return PublicProfile{}, err
+	}
+
+	badges, err := s.badgeRepository.GetBadgesByUserId(ctx, nil, userInfo.Id)
+	if err != nil {
+		return PublicProfile{}, err
+	}
+
+	profile := PublicProfile{
+		GithubUsername: userInfo.GithubUsername,
+		AvatarUrl:      userInfo.AvatarUrl,
+		Badges:         make([]Badge, 0, len(badges)),
+		MemberSince:    userInfo.CreatedAt,
+	}
+
+	for _, badge := range badges {
+		profile.Badges = append(profile.Badges, Badge{BadgeType: badge.BadgeType, EarnedAt: badge.EarnedAt})
+	}
+
+	if !settings.HideBalance {
+		profile.TotalPoints = &userInfo.CurrentBalance
+
+		rank, err := s.profileRepository.GetUserRank(ctx, nil, userInfo.Id)
+		if err != nil && !errors.Is(err, apperrors.ErrUserNotRanked) {
+			return PublicProfile{}, err
+		}
+		if err == nil {
+			profile.Rank = &rank
+		}
+	}
+
+	if !settings.HideActivity {
+		repositories, err := s.profileRepository.GetTopRepositories(ctx, nil, userInfo.Id, topRepositoriesLimit)
+		if err != nil {
+			return PublicProfile{}, err
+		}
+		for _, repository := range repositories {
+			profile.TopRepositories = append(profile.TopRepositories, TopRepository(repository))
+		}
+
+		languages, err := s.profileRepository.GetLanguages(ctx, nil, userInfo.Id)
+		if err != nil {
+			return PublicProfile{}, err
+		}
+		for _, language := range languages {
+			profile.Languages = append(profile.Languages, LanguageCount(language))
+		}
+
+		currentStreak, err := s.streakService.GetCurrentStreak(ctx, userInfo.Id)
+		if err != nil {
+			return PublicProfile{}, err
+		}
+		profile.Streak = &currentStreak
+	}
+
+	return profile, nil
+}
+
+func (s *service) GetPrivacySettings(ctx context.Context) (PrivacySettings, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return PrivacySettings{}, apperrors.ErrInternalServer
+	}
+
+	return s.privacySettings(ctx, userId)
+}
+
+func (s *service) UpdatePrivacySettings(ctx context.Context, request UpdatePrivacySettingsRequest) (PrivacySettings, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return PrivacySettings{}, apperrors.ErrInternalServer
+	}
+
+	settings, err := s.privacySettings(ctx, userId)
+	if err != nil {
+		return PrivacySettings{}, err
+	}
+
+	if request.HideBalance != nil {
+		settings.HideBalance = *request.HideBalance
+	}
+	if request.HideActivity != nil {
+		settings.HideActivity = *request.HideActivity
+	}
+
+	updated, err := s.privacySettingRepository.UpsertPrivacySetting(ctx, nil, repository.PrivacySetting{
+		UserId:       userId,
+		HideBalance:  settings.HideBalance,
+		HideActivity: settings.HideActivity,
+	})
+	if err != nil {
+		slog.Error("failed to update privacy settings", "error", err)
+		return PrivacySettings{}, err
+	}
+
+	return PrivacySettings{HideBalance: updated.HideBalance, HideActivity: updated.HideActivity}, nil
+}
+
+// privacySettings falls back to everything visible for users who never
+// changed their settings.
+func (s *service) privacySettings(ctx context.Context, userId int) (PrivacySettings, error) {
+	setting, err := s.privacySettingRepository.GetPrivacySettingByUserId(ctx, nil, userId)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrPrivacySettingNotFound) {
+			return PrivacySettings{}, nil
+		}
+		return PrivacySettings{}, err
+	}
+
+	return PrivacySettings{HideBalance: setting.HideBalance, HideActivity: setting.HideActivity}, nil
+}
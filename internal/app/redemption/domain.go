@@ -0,0 +1,54 @@
+package redemption
+
+import "time"
+
+// redemption statuses, kept in sync with the redemptions_status_check
+// constraint. Queued redemptions wait for sponsor budget, pending ones have
+// it reserved and wait for an admin to hand out the reward.
+const (
+	StatusQueued    = "queued"
+	StatusPending   = "pending"
+	StatusFulfilled = "fulfilled"
+	StatusRejected  = "rejected"
+)
+
+var Statuses = []string{StatusQueued, StatusPending, StatusFulfilled, StatusRejected}
+
+// stores rewards are handed out for, kept in sync with the
+// redemptions_store_check constraint
+const (
+	StoreGithub = "github"
+	StoreAmazon = "amazon"
+	StoreOther  = "other"
+)
+
+var Stores = []string{StoreGithub, StoreAmazon, StoreOther}
+
+// review decisions
+const (
+	DecisionFulfill = "fulfill"
+	DecisionReject  = "reject"
+)
+
+type Redemption struct {
+	Id          int        `json:"id"`
+	UserId      int        `json:"user_id"`
+	SponsorId   *int64     `json:"sponsor_id"`
+	Points      int        `json:"points"`
+	AmountCents int64      `json:"amount_cents"`
+	Store       string     `json:"store"`
+	Status      string     `json:"status"`
+	Note        string     `json:"note"`
+	ReviewedAt  *time.Time `json:"reviewed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type CreateRedemptionRequest struct {
+	Points int    `json:"points"`
+	Store  string `json:"store"`
+}
+
+type ReviewRedemptionRequest struct {
+	Decision string `json:"decision"`
+	Note     string `json:"note"`
+}
@@ -0,0 +1,140 @@
+package redemption
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	redemptionService Service
+}
+
+type Handler interface {
+	RequestRedemption(w http.ResponseWriter, r *http.Request)
+	ListMyRedemptions(w http.ResponseWriter, r *http.Request)
+	ListRedemptions(w http.ResponseWriter, r *http.Request)
+	ListSponsorRedemptions(w http.ResponseWriter, r *http.Request)
+	ReviewRedemption(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(redemptionService Service) Handler {
+	return &handler{
+		redemptionService: redemptionService,
+	}
+}
+
+func (h *handler) RequestRedemption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody CreateRedemptionRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	redemption, err := h.redemptionService.RequestRedemption(ctx, requestBody)
+	if err != nil {
+		slog.Error("failed to request redemption", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "redemption requested successfully", redemption)
+}
+
+func (h *handler) ListMyRedemptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	redemptions, err := h.redemptionService.ListMyRedemptions(ctx, limit, offset)
+	if err != nil {
+		slog.Error("failed to list user redemptions", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "redemptions fetched successfully", redemptions)
+}
+
+func (h *handler) ListRedemptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	redemptions, err := h.redemptionService.ListRedemptions(ctx, r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		slog.Error("failed to list redemptions", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "redemptions fetched successfully", redemptions)
+}
+
+func (h *handler) ListSponsorRedemptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	redemptions, err := h.redemptionService.ListSponsorRedemptions(ctx, limit, offset)
+	if err != nil {
+		slog.Error("failed to list sponsor redemptions", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "redemptions fetched successfully", redemptions)
+}
+
+func (h *handler) ReviewRedemption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	redemptionId, err := strconv.Atoi(r.PathValue("redemptionId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody ReviewRedemptionRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	redemption, err := h.redemptionService.ReviewRedemption(ctx, redemptionId, requestBody)
+	if err != nil {
+		slog.Error("failed to review redemption", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "redemption reviewed successfully", redemption)
+}
@@ -0,0 +1,387 @@
+package redemption
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/notification"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/sponsor"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	redemptionRepository  repository.RedemptionRepository
+	sponsorRepository     repository.SponsorRepository
+	userRepository        repository.UserRepository
+	transactionRepository repository.TransactionRepository
+	notificationService   notification.Service
+	eventBus              events.Bus
+	appCfg                config.AppConfig
+}
+
+type Service interface {
+	RequestRedemption(ctx context.Context, request CreateRedemptionRequest) (Redemption, error)
+	ListMyRedemptions(ctx context.Context, limit int, offset int) ([]Redemption, error)
+	ListRedemptions(ctx context.Context, status string, limit int, offset int) ([]Redemption, error)
+	ListSponsorRedemptions(ctx context.Context, limit int, offset int) ([]Redemption, error)
+	ReviewRedemption(ctx context.Context, redemptionId int, request ReviewRedemptionRequest) (Redemption, error)
+	FundQueuedRedemptions(ctx context.Context) (int, error)
+}
+
+func NewService(redemptionRepository repository.RedemptionRepository, sponsorRepository repository.SponsorRepository, userRepository repository.UserRepository, transactionRepository repository.TransactionRepository, notificationService notification.Service, eventBus events.Bus, appCfg config.AppConfig) Service {
+	return &service{
+		redemptionRepository:  redemptionRepository,
+		sponsorRepository:     sponsorRepository,
+		userRepository:        userRepository,
+		transactionRepository: transactionRepository,
+		notificationService:   notificationService,
+		eventBus:              eventBus,
+		appCfg:                appCfg,
+	}
+}
+
+// RequestRedemption takes the points out of the user's wallet and reserves
+// sponsor budget for them. Without budget the redemption is queued or
+// refused, depending on the configuration.
+func (s *service) RequestRedemption(ctx context.Context, request CreateRedemptionRequest) (created Redemption, err error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Redemption{}, apperrors.ErrInternalServer
+	}
+
+	if request.Points < s.appCfg.Redemptions.MinimumPoints || request.Points <= 0 || !slices.Contains(Stores, request.Store) {
+		return Redemption{}, apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.redemptionRepository.BeginTx(ctx)
+	if err != nil {
+		return Redemption{}, err
+	}
+
+	defer func() {
+		txErr := s.redemptionRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	err = s.userRepository.DebitUserBalance(ctx, tx, userId, request.Points)
+	if err != nil {
+		return Redemption{}, err
+	}
+
+	// read once the debit holds the row lock, so a user blocked meanwhile
+	// cannot slip through
+	userInfo, err := s.userRepository.GetUserById(ctx, tx, userId)
+	if err != nil {
+		return Redemption{}, err
+	}
+	if userInfo.IsBlocked {
+		return Redemption{}, apperrors.ErrUserBlocked
+	}
+
+	err = s.eventBus.Publish(ctx, tx, events.New(events.BalanceChanged, userId, events.BalanceChangedData{
+		UserId: userId,
+		Delta:  -request.Points,
+		Reason: events.BalanceReasonRedemption,
+	}))
+	if err != nil {
+		return Redemption{}, err
+	}
+
+	err = s.sponsorRepository.LockSponsorBudgets(ctx, tx)
+	if err != nil {
+		return Redemption{}, err
+	}
+
+	redemption := repository.Redemption{
+		UserId: userId,
+		Points: request.Points,
+		Store:  request.Store,
+		Status: StatusPending,
+	}
+
+	fundingSponsor, err := s.sponsorRepository.FindFundingSponsor(ctx, tx, userId, request.Points)
+	switch {
+	case errors.Is(err, apperrors.ErrSponsorBudgetUnavailable) && s.appCfg.Redemptions.QueueWhenUnfunded:
+		redemption.Status = StatusQueued
+	case err != nil:
+		return Redemption{}, err
+	default:
+		redemption.SponsorId = sql.NullInt64{Int64: int64(fundingSponsor.Id), Valid: true}
+		redemption.AmountCents = sponsor.AmountCents(request.Points, fundingSponsor.PointsPerUsd)
+	}
+
+	redemption, err = s.redemptionRepository.CreateRedemption(ctx, tx, redemption)
+	if err != nil {
+		return Redemption{}, err
+	}
+
+	_, err = s.transactionRepository.CreateTransaction(ctx, tx, repository.Transaction{
+		UserId:            userId,
+		RedemptionId:      sql.NullInt64{Int64: int64(redemption.Id), Valid: true},
+		IsRedeemed:        true,
+		IsGained:          false,
+		TransactedBalance: request.Points,
+		TransactedAt:      time.Now(),
+	})
+	if err != nil {
+		return Redemption{}, err
+	}
+
+	slog.Info("redemption requested", "redemption_id", redemption.Id, "user_id", userId, "points", request.Points, "status", redemption.Status)
+	return newRedemption(redemption), nil
+}
+
+func (s *service) ListMyRedemptions(ctx context.Context, limit int, offset int) ([]Redemption, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return s.listRedemptions(ctx, repository.RedemptionFilter{UserId: userId, Limit: limit, Offset: offset})
+}
+
+func (s *service) ListRedemptions(ctx context.Context, status string, limit int, offset int) ([]Redemption, error) {
+	if status != "" && !slices.Contains(Statuses, status) {
+		return nil, apperrors.ErrInvalidQueryParams
+	}
+
+	return s.listRedemptions(ctx, repository.RedemptionFilter{Status: status, Limit: limit, Offset: offset})
+}
+
+// ListSponsorRedemptions returns the redemptions funded by the logged in
+// user's sponsor organization.
+func (s *service) ListSponsorRedemptions(ctx context.Context, limit int, offset int) ([]Redemption, error) {
+	session, ok := middleware.SessionFromContext(ctx)
+	if !ok {
+		slog.Error("error obtaining session from context")
+		return nil, apperrors.ErrInternalServer
+	}
+	if session.SponsorId == 0 {
+		return nil, apperrors.ErrAccessForbidden
+	}
+
+	return s.listRedemptions(ctx, repository.RedemptionFilter{SponsorId: session.SponsorId, Limit: limit, Offset: offset})
+}
+
+func (s *service) listRedemptions(ctx context.Context, filter repository.RedemptionFilter) ([]Redemption, error) {
+	redemptions, err := s.redemptionRepository.ListRedemptions(ctx, nil, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Redemption, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		result = append(result, newRedemption(redemption))
+	}
+
+	return result, nil
+}
+
+// ReviewRedemption fulfills a funded redemption, debiting the sponsor's
+// budget, or rejects a redemption and gives the points back.
+func (s *service) ReviewRedemption(ctx context.Context, redemptionId int, request ReviewRedemptionRequest) (reviewed Redemption, err error) {
+	adminId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Redemption{}, apperrors.ErrInternalServer
+	}
+
+	note := strings.TrimSpace(request.Note)
+	if request.Decision != DecisionFulfill && request.Decision != DecisionReject {
+		return Redemption{}, apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.redemptionRepository.BeginTx(ctx)
+	if err != nil {
+		return Redemption{}, err
+	}
+
+	defer func() {
+		txErr := s.redemptionRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	redemption, err := s.redemptionRepository.GetRedemptionByIdForUpdate(ctx, tx, redemptionId)
+	if err != nil {
+		return Redemption{}, err
+	}
+	if redemption.Status == StatusFulfilled || redemption.Status == StatusRejected {
+		return Redemption{}, apperrors.ErrRedemptionReviewed
+	}
+
+	switch request.Decision {
+	case DecisionFulfill:
+		if redemption.Status == StatusQueued {
+			return Redemption{}, apperrors.ErrRedemptionNotFunded
+		}
+		redemption.Status = StatusFulfilled
+
+		_, err = s.sponsorRepository.CreateSponsorBudgetEntry(ctx, tx, repository.SponsorBudgetEntry{
+			SponsorId:    int(redemption.SponsorId.Int64),
+			EntryType:    sponsor.EntryRedemption,
+			AmountCents:  -redemption.AmountCents,
+			RedemptionId: sql.NullInt64{Int64: int64(redemption.Id), Valid: true},
+			Note:         note,
+			CreatedBy:    sql.NullInt64{Int64: int64(adminId), Valid: true},
+		})
+		if err != nil {
+			return Redemption{}, err
+		}
+
+		err = s.notificationService.Notify(ctx, tx, redemption.UserId, notification.TypeRedemptionFulfilled, map[string]any{
+			"redemption_id": redemption.Id,
+			"points":        redemption.Points,
+			"store":         redemption.Store,
+		})
+		if err != nil {
+			return Redemption{}, err
+		}
+
+	case DecisionReject:
+		redemption.Status = StatusRejected
+
+		err = s.userRepository.IncrementUserBalance(ctx, tx, redemption.UserId, redemption.Points)
+		if err != nil {
+			return Redemption{}, err
+		}
+
+		err = s.eventBus.Publish(ctx, tx, events.New(events.BalanceChanged, redemption.UserId, events.BalanceChangedData{
+			UserId: redemption.UserId,
+			Delta:  redemption.Points,
+			Reason: events.BalanceReasonRedemptionRefund,
+		}))
+		if err != nil {
+			return Redemption{}, err
+		}
+
+		_, err = s.transactionRepository.CreateTransaction(ctx, tx, repository.Transaction{
+			UserId:            redemption.UserId,
+			RedemptionId:      sql.NullInt64{Int64: int64(redemption.Id), Valid: true},
+			IsRedeemed:        true,
+			IsGained:          true,
+			TransactedBalance: redemption.Points,
+			TransactedAt:      time.Now(),
+		})
+		if err != nil {
+			return Redemption{}, err
+		}
+
+		err = s.notificationService.Notify(ctx, tx, redemption.UserId, notification.TypeRedemptionRejected, map[string]any{
+			"redemption_id": redemption.Id,
+			"points":        redemption.Points,
+			"note":          note,
+		})
+		if err != nil {
+			return Redemption{}, err
+		}
+	}
+
+	err = s.redemptionRepository.ReviewRedemption(ctx, tx, redemption.Id, redemption.Status, adminId, note)
+	if err != nil {
+		return Redemption{}, err
+	}
+
+	redemption.Note = note
+	redemption.ReviewedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	reviewed = newRedemption(redemption)
+
+	if reviewed.Status == StatusFulfilled {
+		err = s.eventBus.Publish(ctx, tx, events.New(events.RedemptionFulfilled, reviewed.UserId, reviewed))
+		if err != nil {
+			return Redemption{}, err
+		}
+	}
+
+	slog.Info("redemption reviewed", "redemption_id", redemption.Id, "status", redemption.Status, "admin_id", adminId)
+	return reviewed, nil
+}
+
+// FundQueuedRedemptions reserves sponsor budget for queued redemptions,
+// oldest first, and returns how many were funded. Redemptions no sponsor
+// can fund yet stay queued.
+func (s *service) FundQueuedRedemptions(ctx context.Context) (int, error) {
+	redemptions, err := s.redemptionRepository.ListQueuedRedemptions(ctx, nil, s.appCfg.Redemptions.FundingBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	funded := 0
+	for _, redemption := range redemptions {
+		err := s.fundRedemption(ctx, redemption)
+		if errors.Is(err, apperrors.ErrSponsorBudgetUnavailable) || errors.Is(err, apperrors.ErrRedemptionReviewed) {
+			continue
+		}
+		if err != nil {
+			return funded, err
+		}
+		funded++
+	}
+
+	if funded > 0 {
+		slog.Info("queued redemptions funded", "count", funded)
+	}
+	return funded, nil
+}
+
+func (s *service) fundRedemption(ctx context.Context, redemption repository.Redemption) (err error) {
+	tx, err := s.redemptionRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.redemptionRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	err = s.sponsorRepository.LockSponsorBudgets(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	fundingSponsor, err := s.sponsorRepository.FindFundingSponsor(ctx, tx, redemption.UserId, redemption.Points)
+	if err != nil {
+		return err
+	}
+
+	// the redemption may have been rejected since it was listed
+	return s.redemptionRepository.FundRedemption(ctx, tx, redemption.Id, fundingSponsor.Id, sponsor.AmountCents(redemption.Points, fundingSponsor.PointsPerUsd))
+}
+
+func newRedemption(redemption repository.Redemption) Redemption {
+	result := Redemption{
+		Id:          redemption.Id,
+		UserId:      redemption.UserId,
+		Points:      redemption.Points,
+		AmountCents: redemption.AmountCents,
+		Store:       redemption.Store,
+		Status:      redemption.Status,
+		Note:        redemption.Note,
+		CreatedAt:   redemption.CreatedAt,
+	}
+	if redemption.SponsorId.Valid {
+		result.SponsorId = &redemption.SponsorId.Int64
+	}
+	if redemption.ReviewedAt.Valid {
+		result.ReviewedAt = &redemption.ReviewedAt.Time
+	}
+
+	return result
+}
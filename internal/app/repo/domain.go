@@ -0,0 +1,77 @@
+package repo
+
+import "time"
+
+// access rule values, kept in sync with the repository_access_rules_access_check
+// constraint
+const (
+	AccessAllow = "allow"
+	AccessDeny  = "deny"
+)
+
+var Accesses = []string{AccessAllow, AccessDeny}
+
+type Repo struct {
+	Id           int       `json:"id"`
+	GithubRepoId int       `json:"github_repo_id"`
+	RepoName     string    `json:"repo_name"`
+	Description  string    `json:"description"`
+	LanguagesUrl string    `json:"languages_url"`
+	RepoUrl      string    `json:"repo_url"`
+	OwnerName    string    `json:"owner_name"`
+	UpdateDate   time.Time `json:"update_date"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Language     string    `json:"language"`
+	Stars        int       `json:"stars"`
+	Topics       []string  `json:"topics"`
+	License      string    `json:"license"`
+	IsArchived   bool      `json:"is_archived"`
+}
+
+type RepoStats struct {
+	Repo
+	Contributors int `json:"contributors"`
+	TotalPoints  int `json:"total_points"`
+}
+
+// AccessRule allows or denies scoring for one repository, or for every
+// repository of an owner when RepoName is empty.
+type AccessRule struct {
+	Id        int       `json:"id"`
+	OwnerName string    `json:"owner_name"`
+	RepoName  string    `json:"repo_name,omitempty"`
+	Access    string    `json:"access"`
+	Reason    string    `json:"reason"`
+	CreatedBy int       `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SaveAccessRuleRequest struct {
+	OwnerName string `json:"owner_name"`
+	RepoName  string `json:"repo_name"`
+	Access    string `json:"access"`
+	Reason    string `json:"reason"`
+}
+
+// githubRepository is the part of GitHub's repository resource the sync
+// keeps.
+type githubRepository struct {
+	Id              int      `json:"id"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	HtmlUrl         string   `json:"html_url"`
+	LanguagesUrl    string   `json:"languages_url"`
+	Language        string   `json:"language"`
+	StargazersCount int      `json:"stargazers_count"`
+	Topics          []string `json:"topics"`
+	Archived        bool     `json:"archived"`
+	License         struct {
+		SpdxId string `json:"spdx_id"`
+	} `json:"license"`
+	Owner struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
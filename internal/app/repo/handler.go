@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	repoService Service
+}
+
+type Handler interface {
+	ListRepositories(w http.ResponseWriter, r *http.Request)
+	ListAccessRules(w http.ResponseWriter, r *http.Request)
+	SaveAccessRule(w http.ResponseWriter, r *http.Request)
+	DeleteAccessRule(w http.ResponseWriter, r *http.Request)
+	GetGithubMetrics(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(repoService Service) Handler {
+	return &handler{
+		repoService: repoService,
+	}
+}
+
+func (h *handler) ListRepositories(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	repos, err := h.repoService.ListRepositories(ctx, limit, offset)
+	if err != nil {
+		slog.Error("failed to list repositories", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "repositories fetched successfully", repos)
+}
+
+func (h *handler) ListAccessRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rules, err := h.repoService.ListAccessRules(ctx)
+	if err != nil {
+		slog.Error("failed to list repository access rules", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "repository access rules fetched successfully", rules)
+}
+
+func (h *handler) SaveAccessRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody SaveAccessRuleRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	rule, err := h.repoService.SaveAccessRule(ctx, requestBody)
+	if err != nil {
+		slog.Error("failed to save repository access rule", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "repository access rule saved", rule)
+}
+
+func (h *handler) DeleteAccessRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ruleId, err := strconv.Atoi(r.PathValue("ruleId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.repoService.DeleteAccessRule(ctx, ruleId)
+	if err != nil {
+		slog.Error("failed to delete repository access rule", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "repository access rule deleted", nil)
+}
+
+func (h *handler) GetGithubMetrics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	metrics := h.repoService.GithubMetrics(ctx)
+
+	response.WriteJson(w, http.StatusOK, "github metrics fetched successfully", metrics)
+}
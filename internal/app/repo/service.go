@@ -0,0 +1,349 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	ghclient "github.com/joshsoftware/code-curiosity-2025/internal/pkg/github"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+const getRepositoryByIdUrl = ghclient.APIBaseURL + "/repositories/%d"
+
+type service struct {
+	repoRepository     repository.RepoRepository
+	languageRepository repository.LanguageRepository
+	etagStore          ghclient.ETagStore
+	trackers           *ghclient.Trackers
+	appCfg             config.AppConfig
+
+	installationMu     sync.Mutex
+	installationClient ghclient.Client
+}
+
+type Service interface {
+	UpsertRepo(ctx context.Context, repoInfo Repo) (Repo, error)
+	IsScorable(ctx context.Context, repoInfo Repo) (bool, error)
+	ListRepositories(ctx context.Context, limit int, offset int) ([]RepoStats, error)
+	ListAccessRules(ctx context.Context) ([]AccessRule, error)
+	SaveAccessRule(ctx context.Context, request SaveAccessRuleRequest) (AccessRule, error)
+	DeleteAccessRule(ctx context.Context, ruleId int) error
+	SyncRepositories(ctx context.Context) (int, error)
+	GithubMetrics(ctx context.Context) map[string]ghclient.Metrics
+}
+
+func NewService(repoRepository repository.RepoRepository, languageRepository repository.LanguageRepository, etagStore ghclient.ETagStore, trackers *ghclient.Trackers, appCfg config.AppConfig) Service {
+	return &service{
+		repoRepository:     repoRepository,
+		languageRepository: languageRepository,
+		etagStore:          etagStore,
+		trackers:           trackers,
+		appCfg:             appCfg,
+	}
+}
+
+// UpsertRepo creates the repository on first sight and refreshes its
+// metadata afterwards, keyed by the GitHub repository id.
+func (s *service) UpsertRepo(ctx context.Context, repoInfo Repo) (Repo, error) {
+	existing, err := s.repoRepository.GetRepoByGithubRepoId(ctx, nil, repoInfo.GithubRepoId)
+	if err != nil {
+		if !errors.Is(err, apperrors.ErrRepoNotFound) {
+			slog.Error("failed to get repository", "error", err)
+			return Repo{}, err
+		}
+
+		created, err := s.repoRepository.CreateRepo(ctx, nil, repository.Repo(repoInfo))
+		if err != nil {
+			slog.Error("failed to create repository", "error", err)
+			return Repo{}, err
+		}
+
+		return Repo(created), nil
+	}
+
+	repoInfo.Id = existing.Id
+	updated, err := s.repoRepository.UpdateRepo(ctx, nil, repository.Repo(repoInfo))
+	if err != nil {
+		slog.Error("failed to update repository", "error", err)
+		return Repo{}, err
+	}
+
+	return Repo(updated), nil
+}
+
+// IsScorable reports whether contributions to the repository earn points.
+// Archived repositories never do. Otherwise the most specific access rule
+// decides, and repositories without one are scored unless only allow-listed
+// repositories are.
+func (s *service) IsScorable(ctx context.Context, repoInfo Repo) (bool, error) {
+	if repoInfo.IsArchived {
+		return false, nil
+	}
+
+	access, err := s.repoRepository.GetRepositoryAccess(ctx, nil, repoInfo.OwnerName, repoInfo.RepoName)
+	if errors.Is(err, apperrors.ErrRepositoryAccessRuleNotFound) {
+		return !s.appCfg.Repositories.AllowListOnly, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return access == AccessAllow, nil
+}
+
+func (s *service) ListRepositories(ctx context.Context, limit int, offset int) ([]RepoStats, error) {
+	repos, err := s.repoRepository.ListRepoStats(ctx, nil, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RepoStats, 0, len(repos))
+	for _, repo := range repos {
+		result = append(result, RepoStats{
+			Repo:         Repo(repo.Repo),
+			Contributors: repo.Contributors,
+			TotalPoints:  repo.TotalPoints,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *service) ListAccessRules(ctx context.Context) ([]AccessRule, error) {
+	rules, err := s.repoRepository.ListRepositoryAccessRules(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]AccessRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, mapAccessRule(rule))
+	}
+
+	return result, nil
+}
+
+// SaveAccessRule allows or denies a repository, or a whole owner when no
+// repository name is given, replacing any rule for the same target. Rules
+// are checked when contributions are ingested, so contributions recorded
+// before a deny rule keep their points.
+func (s *service) SaveAccessRule(ctx context.Context, request SaveAccessRuleRequest) (AccessRule, error) {
+	adminId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return AccessRule{}, apperrors.ErrInternalServer
+	}
+
+	ownerName := strings.TrimSpace(request.OwnerName)
+	repoName := strings.TrimSpace(request.RepoName)
+	if ownerName == "" || !slices.Contains(Accesses, request.Access) {
+		return AccessRule{}, apperrors.ErrInvalidRequestBody
+	}
+
+	rule, err := s.repoRepository.UpsertRepositoryAccessRule(ctx, nil, repository.RepositoryAccessRule{
+		OwnerName: ownerName,
+		RepoName:  sql.NullString{String: repoName, Valid: repoName != ""},
+		Access:    request.Access,
+		Reason:    strings.TrimSpace(request.Reason),
+		CreatedBy: adminId,
+	})
+	if err != nil {
+		return AccessRule{}, err
+	}
+
+	slog.Info("repository access rule saved", "owner_name", ownerName, "repo_name", repoName, "access", request.Access, "admin_id", adminId)
+	return mapAccessRule(rule), nil
+}
+
+func (s *service) DeleteAccessRule(ctx context.Context, ruleId int) error {
+	return s.repoRepository.DeleteRepositoryAccessRule(ctx, nil, ruleId)
+}
+
+// SyncRepositories refreshes the metadata and language breakdown of the
+// repositories synced longest ago through the GitHub App installation and
+// returns how many were updated. Repositories that GitHub no longer serves or
+// that fail to sync keep their last known metadata and move to the back of
+// the queue, so one bad repository cannot hold up the rest.
+func (s *service) SyncRepositories(ctx context.Context) (int, error) {
+	repos, err := s.repoRepository.ListReposDueForSync(ctx, nil, s.appCfg.Repositories.SyncBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(repos) == 0 {
+		return 0, nil
+	}
+
+	client, err := s.getInstallationClient()
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for _, repo := range repos {
+		var fetched githubRepository
+		_, err := client.Get(ctx, fmt.Sprintf(getRepositoryByIdUrl, repo.GithubRepoId), &fetched)
+		if errors.Is(err, apperrors.ErrGithubNotFound) {
+			slog.Warn("tracked repository not found on github", "github_repo_id", repo.GithubRepoId)
+			err = s.repoRepository.MarkRepoSynced(ctx, nil, repo.Id)
+			if err != nil {
+				return synced, err
+			}
+			continue
+		}
+		if syncAborted(ctx, err) {
+			return synced, err
+		}
+		if err != nil {
+			slog.Error("failed to fetch repository from github", "github_repo_id", repo.GithubRepoId, "error", err)
+			err = s.repoRepository.MarkRepoSynced(ctx, nil, repo.Id)
+			if err != nil {
+				return synced, err
+			}
+			continue
+		}
+
+		// a nil breakdown keeps the languages stored from the last sync
+		var languages []repository.RepositoryLanguage
+		var languageBytes map[string]int64
+		_, err = client.Get(ctx, fetched.LanguagesUrl, &languageBytes)
+		if syncAborted(ctx, err) {
+			return synced, err
+		}
+		if err != nil {
+			slog.Warn("keeping stored languages, breakdown could not be fetched", "github_repo_id", repo.GithubRepoId, "error", err)
+		} else {
+			languages = repositoryLanguages(languageBytes)
+		}
+
+		err = s.syncRepo(ctx, repository.Repo{
+			Id:           repo.Id,
+			RepoName:     fetched.Name,
+			Description:  fetched.Description,
+			LanguagesUrl: fetched.LanguagesUrl,
+			RepoUrl:      fetched.HtmlUrl,
+			OwnerName:    fetched.Owner.Login,
+			UpdateDate:   fetched.UpdatedAt,
+			Language:     fetched.Language,
+			Stars:        fetched.StargazersCount,
+			Topics:       fetched.Topics,
+			License:      fetched.License.SpdxId,
+			IsArchived:   fetched.Archived,
+		}, languages)
+		if err != nil {
+			slog.Error("failed to sync repository", "github_repo_id", repo.GithubRepoId, "error", err)
+			err = s.repoRepository.MarkRepoSynced(ctx, nil, repo.Id)
+			if err != nil {
+				return synced, err
+			}
+			continue
+		}
+		synced++
+	}
+
+	rateLimit := client.Metrics().RateLimit
+	slog.Info("repository metadata synced", "count", synced, "github_remaining", rateLimit.Remaining, "github_limit", rateLimit.Limit, "github_reset", rateLimit.Reset)
+	return synced, nil
+}
+
+// GithubMetrics reports the rate limit state and usage of every GitHub
+// credential the process has used, the installation's and each user's.
+func (s *service) GithubMetrics(ctx context.Context) map[string]ghclient.Metrics {
+	return s.trackers.Metrics()
+}
+
+// getInstallationClient builds the GitHub App installation client on first
+// use and keeps it for the process, so its token and rate limit state carry
+// over from one sync to the next.
+func (s *service) getInstallationClient() (ghclient.Client, error) {
+	s.installationMu.Lock()
+	defer s.installationMu.Unlock()
+
+	if s.installationClient != nil {
+		return s.installationClient, nil
+	}
+
+	tokenSource, err := ghclient.NewAppInstallationTokenSource(s.appCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.installationClient = ghclient.NewClient(tokenSource, s.etagStore, s.trackers.Get(ghclient.InstallationKey))
+	return s.installationClient, nil
+}
+
+func (s *service) syncRepo(ctx context.Context, repoInfo repository.Repo, languages []repository.RepositoryLanguage) (err error) {
+	tx, err := s.repoRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.repoRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	err = s.repoRepository.SyncRepo(ctx, tx, repoInfo)
+	if err != nil || languages == nil {
+		return err
+	}
+
+	return s.languageRepository.ReplaceRepositoryLanguages(ctx, tx, repoInfo.Id, languages)
+}
+
+// syncAborted reports whether err stops the whole sync rather than just the
+// repository being synced, since every later request would fail the same way.
+func syncAborted(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return ctx.Err() != nil || errors.Is(err, apperrors.ErrGithubRateLimited) || errors.Is(err, apperrors.ErrGithubUnauthorized)
+}
+
+// repositoryLanguages turns GitHub's bytes-per-language map into rows
+// carrying each language's share of the repository.
+func repositoryLanguages(languageBytes map[string]int64) []repository.RepositoryLanguage {
+	var total int64
+	for _, bytes := range languageBytes {
+		total += bytes
+	}
+
+	languages := make([]repository.RepositoryLanguage, 0, len(languageBytes))
+	if total == 0 {
+		return languages
+	}
+
+	for language, bytes := range languageBytes {
+		languages = append(languages, repository.RepositoryLanguage{
+			Language: language,
+			Bytes:    bytes,
+			Share:    float64(bytes) / float64(total),
+		})
+	}
+
+	return languages
+}
+
+func mapAccessRule(rule repository.RepositoryAccessRule) AccessRule {
+	return AccessRule{
+		Id:        rule.Id,
+		OwnerName: rule.OwnerName,
+		RepoName:  rule.RepoName.String,
+		Access:    rule.Access,
+		Reason:    rule.Reason,
+		CreatedBy: rule.CreatedBy,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+}
@@ -0,0 +1,17 @@
+package role
+
+import "time"
+
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// UserRole is a role granted explicitly to a user. GrantedBy is empty for
+// roles carried over from before roles existed.
+type UserRole struct {
+	Role      string    `json:"role"`
+	GrantedBy *int64    `json:"granted_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
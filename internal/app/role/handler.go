@@ -0,0 +1,101 @@
+package role
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	roleService Service
+}
+
+type Handler interface {
+	ListRoles(w http.ResponseWriter, r *http.Request)
+	ListUserRoles(w http.ResponseWriter, r *http.Request)
+	GrantRole(w http.ResponseWriter, r *http.Request)
+	RevokeRole(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(roleService Service) Handler {
+	return &handler{
+		roleService: roleService,
+	}
+}
+
+func (h *handler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	roles, err := h.roleService.ListRoles(ctx)
+	if err != nil {
+		slog.Error("failed to list roles", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "roles fetched successfully", roles)
+}
+
+func (h *handler) ListUserRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := strconv.Atoi(r.PathValue("userId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	userRoles, err := h.roleService.ListUserRoles(ctx, userId)
+	if err != nil {
+		slog.Error("failed to list user roles", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "user roles fetched successfully", userRoles)
+}
+
+func (h *handler) GrantRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := strconv.Atoi(r.PathValue("userId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.roleService.GrantRole(ctx, userId, r.PathValue("role"))
+	if err != nil {
+		slog.Error("failed to grant role", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "role granted successfully", nil)
+}
+
+func (h *handler) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := strconv.Atoi(r.PathValue("userId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.roleService.RevokeRole(ctx, userId, r.PathValue("role"))
+	if err != nil {
+		slog.Error("failed to revoke role", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "role revoked successfully", nil)
+}
@@ -0,0 +1,174 @@
+package role
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	roleRepository repository.RoleRepository
+	userRepository repository.UserRepository
+}
+
+type Service interface {
+	ListRoles(ctx context.Context) ([]Role, error)
+	ListUserRoles(ctx context.Context, userId int) ([]UserRole, error)
+	GrantRole(ctx context.Context, userId int, role string) error
+	RevokeRole(ctx context.Context, userId int, role string) error
+}
+
+func NewService(roleRepository repository.RoleRepository, userRepository repository.UserRepository) Service {
+	return &service{
+		roleRepository: roleRepository,
+		userRepository: userRepository,
+	}
+}
+
+func (s *service) ListRoles(ctx context.Context) ([]Role, error) {
+	roles, err := s.roleRepository.ListRoles(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Role, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, Role(role))
+	}
+
+	return result, nil
+}
+
+func (s *service) ListUserRoles(ctx context.Context, userId int) ([]UserRole, error) {
+	_, err := s.userRepository.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	userRoles, err := s.roleRepository.ListUserRoles(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]UserRole, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		result = append(result, newUserRole(userRole))
+	}
+
+	return result, nil
+}
+
+// GrantRole gives the user a role. It takes effect on the user's next
+// request since sessions load roles from the database.
+func (s *service) GrantRole(ctx context.Context, userId int, role string) (err error) {
+	adminId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	err = checkAssignable(role)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.roleRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.roleRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	_, err = s.roleRepository.GetRole(ctx, tx, role)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.userRepository.GetUserById(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+
+	err = s.roleRepository.GrantUserRole(ctx, tx, userId, role, sql.NullInt64{Int64: int64(adminId), Valid: true})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("role granted", "user_id", userId, "role", role, "granted_by", adminId)
+	return nil
+}
+
+// RevokeRole takes a role away from the user. The last remaining admin
+// cannot be demoted, so someone is always left to manage roles.
+func (s *service) RevokeRole(ctx context.Context, userId int, role string) (err error) {
+	err = checkAssignable(role)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.roleRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.roleRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	admins := 0
+	if role == middleware.RoleAdmin {
+		admins, err = s.roleRepository.CountUsersWithRole(ctx, tx, middleware.RoleAdmin)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.roleRepository.RevokeUserRole(ctx, tx, userId, role)
+	if err != nil {
+		return err
+	}
+
+	if role == middleware.RoleAdmin && admins <= 1 {
+		err = apperrors.ErrLastAdmin
+		return err
+	}
+
+	slog.Info("role revoked", "user_id", userId, "role", role)
+	return nil
+}
+
+// checkAssignable rejects roles that are not managed through grants: every
+// user holds the user role implicitly, and the sponsor role follows sponsor
+// membership.
+func checkAssignable(role string) error {
+	if role == middleware.RoleUser || role == middleware.RoleSponsor {
+		return apperrors.ErrRoleNotAssignable
+	}
+
+	return nil
+}
+
+func newUserRole(userRole repository.UserRole) UserRole {
+	var grantedBy *int64
+	if userRole.GrantedBy.Valid {
+		grantedBy = &userRole.GrantedBy.Int64
+	}
+
+	return UserRole{
+		Role:      userRole.Role,
+		GrantedBy: grantedBy,
+		CreatedAt: userRole.CreatedAt,
+	}
+}
@@ -16,9 +16,126 @@ func NewRouter(deps Dependencies) http.Handler {
 
 	router.HandleFunc("GET /api/v1/auth/github", deps.AuthHandler.GithubOAuthLoginUrl)
 	router.HandleFunc("GET /api/v1/auth/github/callback", deps.AuthHandler.GithubOAuthLoginCallback)
-	router.HandleFunc("GET /api/v1/auth/user", middleware.Authentication(deps.AuthHandler.GetLoggedInUser, deps.AppCfg))
+	router.HandleFunc("GET /api/v1/auth/user", middleware.Authentication(deps.AuthHandler.GetLoggedInUser, deps.AppCfg, deps.UserService))
 
-	router.HandleFunc("PATCH /api/v1/user/email", middleware.Authentication(deps.UserHandler.UpdateUserEmail, deps.AppCfg))
+	router.HandleFunc("PATCH /api/v1/user/email", middleware.Authentication(deps.UserHandler.UpdateUserEmail, deps.AppCfg, deps.UserService))
+	router.HandleFunc("PATCH /api/v1/user/timezone", middleware.Authentication(deps.UserHandler.UpdateUserTimezone, deps.AppCfg, deps.UserService))
+	router.HandleFunc("DELETE /api/v1/user", middleware.Authentication(deps.AccountHandler.DeleteAccount, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/export", middleware.Authentication(deps.AccountHandler.ExportData, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/privacy", middleware.Authentication(deps.ProfileHandler.GetPrivacySettings, deps.AppCfg, deps.UserService))
+	router.HandleFunc("PATCH /api/v1/user/privacy", middleware.Authentication(deps.ProfileHandler.UpdatePrivacySettings, deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/user/languages", middleware.Authentication(deps.LanguageHandler.GetMyLanguages, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/contributions", middleware.Authentication(deps.ContributionHandler.ListMyContributions, deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/user/contributions/{contributionId}/disputes", middleware.Authentication(deps.DisputeHandler.FlagContribution, deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("POST /api/v1/user/redemptions", middleware.Authentication(deps.RedemptionHandler.RequestRedemption, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/redemptions", middleware.Authentication(deps.RedemptionHandler.ListMyRedemptions, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/streak", middleware.Authentication(deps.StreakHandler.GetMyStreak, deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/user/streak/freezes", middleware.Authentication(deps.StreakHandler.PurchaseFreezeTokens, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/teams", middleware.Authentication(deps.TeamHandler.ListMyTeams, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/notifications", middleware.Authentication(deps.NotificationHandler.ListMyNotifications, deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/user/notifications/read-all", middleware.Authentication(deps.NotificationHandler.MarkAllRead, deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/user/notifications/{notificationId}/read", middleware.Authentication(deps.NotificationHandler.MarkRead, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/notification-preferences", middleware.Authentication(deps.NotificationHandler.GetMyPreferences, deps.AppCfg, deps.UserService))
+	router.HandleFunc("PUT /api/v1/user/notification-preferences", middleware.Authentication(deps.NotificationHandler.UpdateMyPreferences, deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/user/webhooks", middleware.Authentication(deps.IntegrationHandler.CreateEndpoint, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/webhooks", middleware.Authentication(deps.IntegrationHandler.ListEndpoints, deps.AppCfg, deps.UserService))
+	router.HandleFunc("DELETE /api/v1/user/webhooks/{endpointId}", middleware.Authentication(deps.IntegrationHandler.DeleteEndpoint, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/user/webhooks/{endpointId}/deliveries", middleware.Authentication(deps.IntegrationHandler.ListDeliveries, deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/user/webhooks/{endpointId}/ping", middleware.Authentication(deps.IntegrationHandler.Ping, deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("POST /api/v1/user/sponsor-invitations/accept", middleware.Authentication(deps.SponsorHandler.AcceptInvitation, deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/sponsor", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.GetMySponsor, middleware.PermissionSponsorPortalRead), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/sponsor/contributions", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.ListMyContributions, middleware.PermissionSponsorPortalRead), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/sponsor/redemptions", middleware.Authentication(middleware.RequirePermission(deps.RedemptionHandler.ListSponsorRedemptions, middleware.PermissionSponsorPortalRead), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/sponsor/top-contributors", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.ListMyTopContributors, middleware.PermissionSponsorPortalRead), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/users/{github_username}", deps.ProfileHandler.GetPublicProfile)
+	router.HandleFunc("GET /api/v1/users/{github_username}/contributions", deps.ContributionHandler.ListUserContributions)
+	router.HandleFunc("GET /api/v1/users/{github_username}/calendar", deps.StreakHandler.GetCalendar)
+
+	router.HandleFunc("GET /api/v1/repositories", deps.RepoHandler.ListRepositories)
+	router.HandleFunc("GET /api/v1/leaderboard/languages/{language}", deps.LanguageHandler.GetLanguageLeaderboard)
+	router.HandleFunc("GET /api/v1/leaderboard/teams", deps.TeamHandler.ListTeamStandings)
+	router.HandleFunc("GET /api/v1/sponsors", deps.SponsorHandler.ListActiveSponsors)
+
+	router.HandleFunc("GET /api/v1/challenges", deps.ChallengeHandler.ListChallenges)
+	router.HandleFunc("GET /api/v1/challenges/{slug}", deps.ChallengeHandler.GetChallenge)
+	router.HandleFunc("GET /api/v1/challenges/{slug}/leaderboard", deps.ChallengeHandler.GetLeaderboard)
+	router.HandleFunc("POST /api/v1/challenges/{slug}/join", middleware.Authentication(deps.ChallengeHandler.JoinChallenge, deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/challenges/{slug}/leave", middleware.Authentication(deps.ChallengeHandler.LeaveChallenge, deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/judging/queue", middleware.Authentication(middleware.RequirePermission(deps.JudgingHandler.GetMyQueue, middleware.PermissionJudgingReview), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/judging/reviews/{reviewId}/scores", middleware.Authentication(middleware.RequirePermission(deps.JudgingHandler.SubmitScore, middleware.PermissionJudgingReview), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("POST /api/v1/teams", middleware.Authentication(deps.TeamHandler.CreateTeam, deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/teams/join", middleware.Authentication(deps.TeamHandler.JoinTeam, deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/teams/{slug}", deps.TeamHandler.GetTeam)
+	router.HandleFunc("GET /api/v1/teams/{slug}/leaderboard", deps.TeamHandler.GetTeamLeaderboard)
+	router.HandleFunc("POST /api/v1/teams/{slug}/leave", middleware.Authentication(deps.TeamHandler.LeaveTeam, deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/teams/{slug}/invite-code", middleware.Authentication(deps.TeamHandler.RegenerateInviteCode, deps.AppCfg, deps.UserService))
+	router.HandleFunc("PUT /api/v1/teams/{slug}/goal", middleware.Authentication(deps.TeamHandler.SetGoal, deps.AppCfg, deps.UserService))
+	router.HandleFunc("PUT /api/v1/teams/{slug}/members/{userId}/role", middleware.Authentication(deps.TeamHandler.UpdateMemberRole, deps.AppCfg, deps.UserService))
+	router.HandleFunc("DELETE /api/v1/teams/{slug}/members/{userId}", middleware.Authentication(deps.TeamHandler.RemoveMember, deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("POST /api/v1/webhooks/github", deps.WebhookHandler.ReceiveGithubWebhook)
+	router.HandleFunc("POST /api/v1/webhooks/github/deliveries/{deliveryId}/replay", middleware.Authentication(middleware.RequirePermission(deps.WebhookHandler.ReplayGithubDelivery, middleware.PermissionJobsManage), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/admin/contributions", middleware.Authentication(middleware.RequirePermission(deps.ContributionHandler.ListAllContributions, middleware.PermissionContributionsRead), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/contributions/{contributionId}/void", middleware.Authentication(middleware.RequirePermission(deps.DisputeHandler.VoidContribution, middleware.PermissionScoresWrite), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/contributions/{contributionId}/rescore", middleware.Authentication(middleware.RequirePermission(deps.DisputeHandler.RescoreContribution, middleware.PermissionScoresWrite), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/admin/disputes", middleware.Authentication(middleware.RequirePermission(deps.DisputeHandler.ListDisputes, middleware.PermissionScoresWrite), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/disputes/{disputeId}/resolve", middleware.Authentication(middleware.RequirePermission(deps.DisputeHandler.ResolveDispute, middleware.PermissionScoresWrite), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/admin/repositories/rules", middleware.Authentication(middleware.RequirePermission(deps.RepoHandler.ListAccessRules, middleware.PermissionRepositoriesManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("PUT /api/v1/admin/repositories/rules", middleware.Authentication(middleware.RequirePermission(deps.RepoHandler.SaveAccessRule, middleware.PermissionRepositoriesManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("DELETE /api/v1/admin/repositories/rules/{ruleId}", middleware.Authentication(middleware.RequirePermission(deps.RepoHandler.DeleteAccessRule, middleware.PermissionRepositoriesManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/admin/github/metrics", middleware.Authentication(middleware.RequirePermission(deps.RepoHandler.GetGithubMetrics, middleware.PermissionRepositoriesManage), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/admin/sponsors", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.ListSponsors, middleware.PermissionSponsorsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/sponsors", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.CreateSponsor, middleware.PermissionSponsorsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("PUT /api/v1/admin/sponsors/{sponsorId}/earmarks", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.SetEarmarks, middleware.PermissionSponsorsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/admin/sponsors/{sponsorId}/budget", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.ListBudgetEntries, middleware.PermissionSponsorsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/sponsors/{sponsorId}/budget", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.RecordBudgetEntry, middleware.PermissionSponsorsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/admin/sponsors/{sponsorId}/invitations", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.ListInvitations, middleware.PermissionSponsorsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/sponsors/{sponsorId}/invitations", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.InviteMember, middleware.PermissionSponsorsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("DELETE /api/v1/admin/sponsors/{sponsorId}/members/{userId}", middleware.Authentication(middleware.RequirePermission(deps.SponsorHandler.RemoveMember, middleware.PermissionSponsorsManage), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/admin/redemptions", middleware.Authentication(middleware.RequirePermission(deps.RedemptionHandler.ListRedemptions, middleware.PermissionRedemptionsApprove), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/redemptions/{redemptionId}/review", middleware.Authentication(middleware.RequirePermission(deps.RedemptionHandler.ReviewRedemption, middleware.PermissionRedemptionsApprove), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/admin/fraud/flags", middleware.Authentication(middleware.RequirePermission(deps.FraudHandler.ListFlags, middleware.PermissionFraudReview), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/fraud/flags/{flagId}/review", middleware.Authentication(middleware.RequirePermission(deps.FraudHandler.ReviewFlag, middleware.PermissionFraudReview), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/admin/fraud/risk-scores", middleware.Authentication(middleware.RequirePermission(deps.FraudHandler.ListRiskScores, middleware.PermissionFraudReview), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/fraud/users/{userId}/unblock", middleware.Authentication(middleware.RequirePermission(deps.FraudHandler.UnblockUser, middleware.PermissionUsersBlock), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/admin/roles", middleware.Authentication(middleware.RequirePermission(deps.RoleHandler.ListRoles, middleware.PermissionRolesManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/admin/users/{userId}/roles", middleware.Authentication(middleware.RequirePermission(deps.RoleHandler.ListUserRoles, middleware.PermissionRolesManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("PUT /api/v1/admin/users/{userId}/roles/{role}", middleware.Authentication(middleware.RequirePermission(deps.RoleHandler.GrantRole, middleware.PermissionRolesManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("DELETE /api/v1/admin/users/{userId}/roles/{role}", middleware.Authentication(middleware.RequirePermission(deps.RoleHandler.RevokeRole, middleware.PermissionRolesManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/webhooks", middleware.Authentication(middleware.RequirePermission(deps.AdminIntegrationHandler.CreateEndpoint, middleware.PermissionIntegrationsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/admin/webhooks", middleware.Authentication(middleware.RequirePermission(deps.AdminIntegrationHandler.ListEndpoints, middleware.PermissionIntegrationsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("DELETE /api/v1/admin/webhooks/{endpointId}", middleware.Authentication(middleware.RequirePermission(deps.AdminIntegrationHandler.DeleteEndpoint, middleware.PermissionIntegrationsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/admin/webhooks/{endpointId}/deliveries", middleware.Authentication(middleware.RequirePermission(deps.AdminIntegrationHandler.ListDeliveries, middleware.PermissionIntegrationsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/webhooks/{endpointId}/ping", middleware.Authentication(middleware.RequirePermission(deps.AdminIntegrationHandler.Ping, middleware.PermissionIntegrationsManage), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("POST /api/v1/admin/challenges", middleware.Authentication(middleware.RequirePermission(deps.ChallengeHandler.CreateChallenge, middleware.PermissionChallengesManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/challenges/{slug}/cancel", middleware.Authentication(middleware.RequirePermission(deps.ChallengeHandler.CancelChallenge, middleware.PermissionChallengesManage), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/admin/judging/reviews", middleware.Authentication(middleware.RequirePermission(deps.JudgingHandler.ListReviews, middleware.PermissionScoresWrite), deps.AppCfg, deps.UserService))
+	router.HandleFunc("GET /api/v1/admin/judging/reviews/{reviewId}", middleware.Authentication(middleware.RequirePermission(deps.JudgingHandler.GetReview, middleware.PermissionScoresWrite), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/judging/reviews/{reviewId}/finalize", middleware.Authentication(middleware.RequirePermission(deps.JudgingHandler.FinalizeReview, middleware.PermissionScoresWrite), deps.AppCfg, deps.UserService))
+	router.HandleFunc("PUT /api/v1/admin/repositories/{repositoryId}/featured", middleware.Authentication(middleware.RequirePermission(deps.JudgingHandler.FeatureRepository, middleware.PermissionRepositoriesManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("DELETE /api/v1/admin/repositories/{repositoryId}/featured", middleware.Authentication(middleware.RequirePermission(deps.JudgingHandler.UnfeatureRepository, middleware.PermissionRepositoriesManage), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/admin/jobs", middleware.Authentication(middleware.RequirePermission(deps.JobHandler.ListJobs, middleware.PermissionJobsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/jobs/{jobId}/retry", middleware.Authentication(middleware.RequirePermission(deps.JobHandler.RetryJob, middleware.PermissionJobsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/jobs/{jobId}/cancel", middleware.Authentication(middleware.RequirePermission(deps.JobHandler.CancelJob, middleware.PermissionJobsManage), deps.AppCfg, deps.UserService))
+
+	router.HandleFunc("GET /api/v1/admin/scheduler/runs", middleware.Authentication(middleware.RequirePermission(deps.SchedulerHandler.ListTaskRuns, middleware.PermissionJobsManage), deps.AppCfg, deps.UserService))
+	router.HandleFunc("POST /api/v1/admin/scheduler/tasks/{task}/run", middleware.Authentication(middleware.RequirePermission(deps.SchedulerHandler.TriggerTask, middleware.PermissionJobsManage), deps.AppCfg, deps.UserService))
 
 	return middleware.CorsMiddleware(router, deps.AppCfg)
 }
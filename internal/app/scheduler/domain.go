@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	LeaderboardRefreshTask = "leaderboard_refresh"
+	MonthCloseTask         = "month_close"
+	RepositorySyncTask     = "repository_sync"
+	RedemptionFundingTask  = "redemption_funding"
+	NotificationDigestTask = "notification_digest"
+	ChallengeFinalizeTask  = "challenge_finalize"
+
+	TriggerSchedule = "schedule"
+	TriggerCatchUp  = "catch_up"
+	TriggerManual   = "manual"
+
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+
+	CatchUpSkip = "skip"
+	CatchUpOnce = "once"
+	CatchUpAll  = "all"
+)
+
+// TaskFunc runs one occurrence of a task. scheduledFor is the schedule slot
+// being run, which for catch-up runs lies in the past.
+type TaskFunc func(ctx context.Context, scheduledFor time.Time) error
+
+type TaskRun struct {
+	Id           int64      `json:"id"`
+	TaskName     string     `json:"task_name"`
+	ScheduledFor time.Time  `json:"scheduled_for"`
+	Trigger      string     `json:"trigger"`
+	Status       string     `json:"status"`
+	Error        string     `json:"error,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
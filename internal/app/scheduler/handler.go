@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	schedulerService Service
+}
+
+type Handler interface {
+	TriggerTask(w http.ResponseWriter, r *http.Request)
+	ListTaskRuns(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(schedulerService Service) Handler {
+	return &handler{
+		schedulerService: schedulerService,
+	}
+}
+
+func (h *handler) TriggerTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var scheduledFor time.Time
+	if value := r.URL.Query().Get("scheduled_for"); value != "" {
+		var err error
+		scheduledFor, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidScheduledFor.Error(), nil)
+			return
+		}
+	}
+
+	run, err := h.schedulerService.TriggerTask(ctx, r.PathValue("task"), scheduledFor)
+	if err != nil {
+		slog.Error("failed to trigger task", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusAccepted, "task triggered", run)
+}
+
+func (h *handler) ListTaskRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	runs, err := h.schedulerService.ListTaskRuns(ctx, r.URL.Query().Get("task"), limit, offset)
+	if err != nil {
+		slog.Error("failed to list task runs", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "task runs fetched successfully", runs)
+}
@@ -0,0 +1,380 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	leaderRetryInterval = 30 * time.Second
+	tickInterval        = 15 * time.Second
+	maxCatchUpRuns      = 100
+)
+
+type task struct {
+	name     string
+	fn       TaskFunc
+	schedule cron.Schedule
+	catchUp  string
+}
+
+type service struct {
+	scheduledTaskRunRepository repository.ScheduledTaskRunRepository
+	leaderLock                 repository.AdvisoryLock
+	newLock                    func(key int64) repository.AdvisoryLock
+	appCfg                     config.AppConfig
+
+	mu      sync.Mutex
+	tasks   map[string]*task
+	running map[string]bool
+	runs    sync.WaitGroup
+}
+
+type Service interface {
+	RegisterTask(name string, fn TaskFunc) error
+	Run(ctx context.Context)
+	TriggerTask(ctx context.Context, name string, scheduledFor time.Time) (TaskRun, error)
+	ListTaskRuns(ctx context.Context, name string, limit int, offset int) ([]TaskRun, error)
+}
+
+// NewService takes the lock used for leader election and newLock, which
+// builds the advisory locks held around each task run.
+func NewService(scheduledTaskRunRepository repository.ScheduledTaskRunRepository, leaderLock repository.AdvisoryLock, newLock func(key int64) repository.AdvisoryLock, appCfg config.AppConfig) Service {
+	return &service{
+		scheduledTaskRunRepository: scheduledTaskRunRepository,
+		leaderLock:                 leaderLock,
+		newLock:                    newLock,
+		appCfg:                     appCfg,
+		tasks:                      make(map[string]*task),
+		running:                    make(map[string]bool),
+	}
+}
+
+// RegisterTask makes a task available for manual triggering and, when it has
+// a cron expression under scheduler.tasks in the config, for scheduling.
+func (s *service) RegisterTask(name string, fn TaskFunc) error {
+	registered := &task{name: name, fn: fn, catchUp: CatchUpSkip}
+
+	taskCfg, ok := s.appCfg.Scheduler.Tasks[name]
+	if ok && taskCfg.Cron != "" {
+		schedule, err := cron.ParseStandard(taskCfg.Cron)
+		if err != nil {
+			slog.Error("invalid cron expression", "task", name, "cron", taskCfg.Cron, "error", err)
+			return apperrors.ErrInvalidSchedule
+		}
+		registered.schedule = schedule
+
+		switch taskCfg.CatchUp {
+		case "", CatchUpSkip:
+		case CatchUpOnce, CatchUpAll:
+			registered.catchUp = taskCfg.CatchUp
+		default:
+			slog.Error("invalid catch up policy", "task", name, "catch_up", taskCfg.CatchUp)
+			return apperrors.ErrInvalidSchedule
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[name] = registered
+	return nil
+}
+
+// Run competes for leadership through a Postgres advisory lock and, while
+// leader, runs scheduled tasks. It returns once ctx is cancelled and any
+// task runs it started have finished.
+func (s *service) Run(ctx context.Context) {
+	defer s.runs.Wait()
+
+	for {
+		acquired, err := s.leaderLock.TryLock(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("failed to attempt scheduler leadership", "error", err)
+		}
+
+		if acquired {
+			slog.Info("scheduler leadership acquired")
+			s.lead(ctx)
+			s.leaderLock.Unlock(context.WithoutCancel(ctx))
+			slog.Info("scheduler leadership released")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(leaderRetryInterval):
+		}
+	}
+}
+
+func (s *service) lead(ctx context.Context) {
+	now := time.Now().UTC()
+	nextRuns := make(map[string]time.Time)
+
+	for _, scheduled := range s.scheduledTasks() {
+		s.catchUp(ctx, scheduled, now)
+		nextRuns[scheduled.name] = scheduled.schedule.Next(now)
+	}
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !s.leaderLock.Held(ctx) {
+			slog.Warn("scheduler leadership lost")
+			return
+		}
+
+		now := time.Now().UTC()
+		for _, scheduled := range s.scheduledTasks() {
+			next, ok := nextRuns[scheduled.name]
+			if !ok {
+				next = scheduled.schedule.Next(now)
+				nextRuns[scheduled.name] = next
+			}
+			if now.Before(next) {
+				continue
+			}
+
+			s.start(ctx, scheduled, next, TriggerSchedule)
+			nextRuns[scheduled.name] = scheduled.schedule.Next(now)
+		}
+	}
+}
+
+// catchUp runs slots missed since the task's last recorded scheduled run,
+// according to the task's catch-up policy.
+func (s *service) catchUp(ctx context.Context, scheduled *task, now time.Time) {
+	if scheduled.catchUp == CatchUpSkip {
+		return
+	}
+
+	lastRun, err := s.scheduledTaskRunRepository.GetLastScheduledTaskRun(ctx, nil, scheduled.name)
+	if err != nil {
+		if !errors.Is(err, apperrors.ErrTaskRunNotFound) {
+			slog.Error("failed to get last task run", "task", scheduled.name, "error", err)
+		}
+		return
+	}
+
+	var missed []time.Time
+	for slot := scheduled.schedule.Next(lastRun.ScheduledFor); !slot.After(now); slot = scheduled.schedule.Next(slot) {
+		missed = append(missed, slot)
+		if len(missed) > maxCatchUpRuns {
+			missed = missed[1:]
+		}
+	}
+	if len(missed) == 0 {
+		return
+	}
+
+	if scheduled.catchUp == CatchUpOnce {
+		missed = missed[len(missed)-1:]
+	}
+
+	slog.Info("catching up missed task runs", "task", scheduled.name, "runs", len(missed))
+	for _, slot := range missed {
+		s.execute(ctx, scheduled, slot, TriggerCatchUp)
+	}
+}
+
+// start runs the task in the background unless a previous run of it is
+// still going.
+func (s *service) start(ctx context.Context, scheduled *task, scheduledFor time.Time, trigger string) {
+	s.mu.Lock()
+	if s.running[scheduled.name] {
+		s.mu.Unlock()
+		slog.Warn("skipping task run, previous run still in progress", "task", scheduled.name, "scheduled_for", scheduledFor)
+		return
+	}
+	s.running[scheduled.name] = true
+	s.mu.Unlock()
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, scheduled.name)
+			s.mu.Unlock()
+		}()
+
+		s.execute(ctx, scheduled, scheduledFor, trigger)
+	}()
+}
+
+func (s *service) execute(ctx context.Context, scheduled *task, scheduledFor time.Time, trigger string) {
+	lock, err := s.lockTask(ctx, scheduled.name)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTaskRunning) {
+			slog.Warn("skipping task run, another run is in progress", "task", scheduled.name, "scheduled_for", scheduledFor)
+		}
+		return
+	}
+	defer lock.Unlock(context.WithoutCancel(ctx))
+
+	run, err := s.scheduledTaskRunRepository.CreateScheduledTaskRun(ctx, nil, repository.ScheduledTaskRun{
+		TaskName:     scheduled.name,
+		ScheduledFor: scheduledFor,
+		Trigger:      trigger,
+	})
+	if err != nil {
+		if !errors.Is(err, apperrors.ErrTaskRunAlreadyRecorded) {
+			slog.Error("failed to record task run", "task", scheduled.name, "error", err)
+		}
+		return
+	}
+
+	s.finish(ctx, scheduled, run)
+}
+
+func (s *service) finish(ctx context.Context, scheduled *task, run repository.ScheduledTaskRun) {
+	// a run that has started is allowed to complete during shutdown
+	runCtx := context.WithoutCancel(ctx)
+
+	slog.Info("task run started", "task", scheduled.name, "scheduled_for", run.ScheduledFor, "trigger", run.Trigger)
+	err := invoke(runCtx, scheduled, run.ScheduledFor)
+
+	status, runErr := RunStatusSucceeded, ""
+	if err != nil {
+		status, runErr = RunStatusFailed, err.Error()
+		slog.Error("task run failed", "task", scheduled.name, "scheduled_for", run.ScheduledFor, "error", err)
+	} else {
+		slog.Info("task run succeeded", "task", scheduled.name, "scheduled_for", run.ScheduledFor)
+	}
+
+	s.scheduledTaskRunRepository.FinishScheduledTaskRun(runCtx, nil, run.Id, status, runErr)
+}
+
+// TriggerTask runs a task immediately on this replica, regardless of
+// leadership, as if it had been scheduled for scheduledFor, and returns the
+// recorded run. A zero scheduledFor means now; later times are rejected. It
+// fails with ErrTaskRunning while a run of the task is in progress on any
+// replica.
+func (s *service) TriggerTask(ctx context.Context, name string, scheduledFor time.Time) (TaskRun, error) {
+	s.mu.Lock()
+	registered, ok := s.tasks[name]
+	s.mu.Unlock()
+	if !ok {
+		return TaskRun{}, apperrors.ErrUnknownTask
+	}
+
+	now := time.Now().UTC()
+	if scheduledFor.IsZero() {
+		scheduledFor = now
+	}
+	if scheduledFor.After(now) {
+		return TaskRun{}, apperrors.ErrInvalidScheduledFor
+	}
+
+	lock, err := s.lockTask(ctx, name)
+	if err != nil {
+		return TaskRun{}, err
+	}
+
+	run, err := s.scheduledTaskRunRepository.CreateScheduledTaskRun(ctx, nil, repository.ScheduledTaskRun{
+		TaskName:     name,
+		ScheduledFor: scheduledFor.UTC(),
+		Trigger:      TriggerManual,
+	})
+	if err != nil {
+		lock.Unlock(context.WithoutCancel(ctx))
+		slog.Error("failed to record manual task run", "task", name, "error", err)
+		return TaskRun{}, err
+	}
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		runCtx := context.WithoutCancel(ctx)
+		defer lock.Unlock(runCtx)
+
+		s.finish(runCtx, registered, run)
+	}()
+
+	return mapTaskRun(run), nil
+}
+
+// lockTask takes the task's run lock, which keeps manual and scheduled runs
+// of the same task from overlapping, including across replicas.
+func (s *service) lockTask(ctx context.Context, name string) (repository.AdvisoryLock, error) {
+	lock := s.newLock(repository.SchedulerTaskLockKey(name))
+
+	acquired, err := lock.TryLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, apperrors.ErrTaskRunning
+	}
+
+	return lock, nil
+}
+
+func (s *service) ListTaskRuns(ctx context.Context, name string, limit int, offset int) ([]TaskRun, error) {
+	runs, err := s.scheduledTaskRunRepository.ListScheduledTaskRuns(ctx, nil, name, limit, offset)
+	if err != nil {
+		slog.Error("failed to list task runs", "error", err)
+		return nil, err
+	}
+
+	result := make([]TaskRun, 0, len(runs))
+	for _, run := range runs {
+		result = append(result, mapTaskRun(run))
+	}
+	return result, nil
+}
+
+func (s *service) scheduledTasks() []*task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var scheduled []*task
+	for _, registered := range s.tasks {
+		if registered.schedule != nil {
+			scheduled = append(scheduled, registered)
+		}
+	}
+	return scheduled
+}
+
+func invoke(ctx context.Context, scheduled *task, scheduledFor time.Time) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("task panicked: %v", recovered)
+		}
+	}()
+
+	return scheduled.fn(ctx, scheduledFor)
+}
+
+func mapTaskRun(run repository.ScheduledTaskRun) TaskRun {
+	mapped := TaskRun{
+		Id:           run.Id,
+		TaskName:     run.TaskName,
+		ScheduledFor: run.ScheduledFor,
+		Trigger:      run.Trigger,
+		Status:       run.Status,
+		Error:        run.Error.String,
+		StartedAt:    run.StartedAt,
+	}
+	if run.FinishedAt.Valid {
+		mapped.FinishedAt = &run.FinishedAt.Time
+	}
+	return mapped
+}
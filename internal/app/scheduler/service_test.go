@@ -0,0 +1,260 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+// fakeScheduledTaskRunRepository records task runs in memory. Any other
+// method panics through the nil embedded interface.
+type fakeScheduledTaskRunRepository struct {
+	repository.ScheduledTaskRunRepository
+	lastRun *repository.ScheduledTaskRun
+
+	mu      sync.Mutex
+	created []repository.ScheduledTaskRun
+}
+
+func (f *fakeScheduledTaskRunRepository) CreateScheduledTaskRun(ctx context.Context, tx *sqlx.Tx, run repository.ScheduledTaskRun) (repository.ScheduledTaskRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	run.Id = int64(len(f.created) + 1)
+	run.Status = RunStatusRunning
+	f.created = append(f.created, run)
+	return run, nil
+}
+
+func (f *fakeScheduledTaskRunRepository) FinishScheduledTaskRun(ctx context.Context, tx *sqlx.Tx, runId int64, status string, runErr string) error {
+	return nil
+}
+
+func (f *fakeScheduledTaskRunRepository) GetLastScheduledTaskRun(ctx context.Context, tx *sqlx.Tx, taskName string) (repository.ScheduledTaskRun, error) {
+	if f.lastRun == nil {
+		return repository.ScheduledTaskRun{}, apperrors.ErrTaskRunNotFound
+	}
+	return *f.lastRun, nil
+}
+
+func (f *fakeScheduledTaskRunRepository) triggers() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var triggers []string
+	for _, run := range f.created {
+		triggers = append(triggers, run.Trigger)
+	}
+	return triggers
+}
+
+// fakeLocks stands in for Postgres advisory locks shared by every replica.
+type fakeLocks struct {
+	mu   sync.Mutex
+	held map[int64]bool
+}
+
+func (f *fakeLocks) newLock(key int64) repository.AdvisoryLock {
+	return &fakeLock{locks: f, key: key}
+}
+
+type fakeLock struct {
+	locks *fakeLocks
+	key   int64
+}
+
+func (l *fakeLock) TryLock(ctx context.Context) (bool, error) {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+
+	if l.locks.held[l.key] {
+		return false, nil
+	}
+	l.locks.held[l.key] = true
+	return true, nil
+}
+
+func (l *fakeLock) Lock(ctx context.Context) error {
+	_, err := l.TryLock(ctx)
+	return err
+}
+
+func (l *fakeLock) Held(ctx context.Context) bool {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+
+	return l.locks.held[l.key]
+}
+
+func (l *fakeLock) Unlock(ctx context.Context) error {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+
+	delete(l.locks.held, l.key)
+	return nil
+}
+
+func newTestService(t *testing.T, runRepository *fakeScheduledTaskRunRepository, locks *fakeLocks, tasks map[string]config.ScheduledTask) *service {
+	t.Helper()
+
+	appCfg := config.AppConfig{Scheduler: config.Scheduler{Tasks: tasks}}
+	return NewService(runRepository, locks.newLock(repository.SchedulerLeaderLockKey), locks.newLock, appCfg).(*service)
+}
+
+func TestRegisterTask(t *testing.T) {
+	tests := []struct {
+		name    string
+		taskCfg config.ScheduledTask
+		wantErr bool
+	}{
+		{name: "scheduled", taskCfg: config.ScheduledTask{Cron: "0 * * * *", CatchUp: CatchUpAll}},
+		{name: "unscheduled", taskCfg: config.ScheduledTask{}},
+		{name: "invalid cron", taskCfg: config.ScheduledTask{Cron: "every hour"}, wantErr: true},
+		{name: "invalid catch up", taskCfg: config.ScheduledTask{Cron: "0 * * * *", CatchUp: "twice"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t, &fakeScheduledTaskRunRepository{}, &fakeLocks{held: map[int64]bool{}}, map[string]config.ScheduledTask{"task": tt.taskCfg})
+
+			err := s.RegisterTask("task", func(ctx context.Context, scheduledFor time.Time) error { return nil })
+			if tt.wantErr {
+				if !errors.Is(err, apperrors.ErrInvalidSchedule) {
+					t.Errorf("RegisterTask() error = %v, want %v", err, apperrors.ErrInvalidSchedule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RegisterTask() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	now := time.Date(2025, time.May, 6, 10, 30, 0, 0, time.UTC)
+	lastRun := &repository.ScheduledTaskRun{TaskName: "task", ScheduledFor: time.Date(2025, time.May, 6, 7, 0, 0, 0, time.UTC)}
+	hour := func(h int) time.Time { return time.Date(2025, time.May, 6, h, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		catchUp string
+		lastRun *repository.ScheduledTaskRun
+		want    []time.Time
+	}{
+		{name: "skip", catchUp: CatchUpSkip, lastRun: lastRun},
+		{name: "once runs the latest missed slot", catchUp: CatchUpOnce, lastRun: lastRun, want: []time.Time{hour(10)}},
+		{name: "all runs every missed slot in order", catchUp: CatchUpAll, lastRun: lastRun, want: []time.Time{hour(8), hour(9), hour(10)}},
+		{name: "never run before", catchUp: CatchUpAll},
+		{name: "nothing missed", catchUp: CatchUpAll, lastRun: &repository.ScheduledTaskRun{TaskName: "task", ScheduledFor: hour(10)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runRepository := &fakeScheduledTaskRunRepository{lastRun: tt.lastRun}
+			s := newTestService(t, runRepository, &fakeLocks{held: map[int64]bool{}}, map[string]config.ScheduledTask{
+				"task": {Cron: "0 * * * *", CatchUp: tt.catchUp},
+			})
+
+			var ran []time.Time
+			err := s.RegisterTask("task", func(ctx context.Context, scheduledFor time.Time) error {
+				ran = append(ran, scheduledFor)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("RegisterTask() error = %v", err)
+			}
+
+			s.catchUp(context.Background(), s.tasks["task"], now)
+
+			if !slices.EqualFunc(ran, tt.want, time.Time.Equal) {
+				t.Errorf("ran slots %v, want %v", ran, tt.want)
+			}
+			for _, trigger := range runRepository.triggers() {
+				if trigger != TriggerCatchUp {
+					t.Errorf("run trigger = %q, want %q", trigger, TriggerCatchUp)
+				}
+			}
+		})
+	}
+}
+
+func TestRunSkippedWhileLockHeld(t *testing.T) {
+	runRepository := &fakeScheduledTaskRunRepository{}
+	locks := &fakeLocks{held: map[int64]bool{}}
+	s := newTestService(t, runRepository, locks, map[string]config.ScheduledTask{
+		"task": {Cron: "0 * * * *"},
+	})
+
+	ran := 0
+	err := s.RegisterTask("task", func(ctx context.Context, scheduledFor time.Time) error {
+		ran++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterTask() error = %v", err)
+	}
+
+	// a run of the task is in progress on another replica
+	locks.held[repository.SchedulerTaskLockKey("task")] = true
+
+	s.execute(context.Background(), s.tasks["task"], time.Now().UTC(), TriggerSchedule)
+
+	_, err = s.TriggerTask(context.Background(), "task", time.Time{})
+	if !errors.Is(err, apperrors.ErrTaskRunning) {
+		t.Errorf("TriggerTask() error = %v, want %v", err, apperrors.ErrTaskRunning)
+	}
+
+	if ran != 0 || len(runRepository.triggers()) != 0 {
+		t.Errorf("task ran %d times and recorded %v, want no run while the lock is held", ran, runRepository.triggers())
+	}
+}
+
+func TestTriggerTaskHoldsLock(t *testing.T) {
+	runRepository := &fakeScheduledTaskRunRepository{}
+	locks := &fakeLocks{held: map[int64]bool{}}
+	s := newTestService(t, runRepository, locks, nil)
+
+	release := make(chan struct{})
+	err := s.RegisterTask("task", func(ctx context.Context, scheduledFor time.Time) error {
+		<-release
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterTask() error = %v", err)
+	}
+
+	run, err := s.TriggerTask(context.Background(), "task", time.Time{})
+	if err != nil {
+		t.Fatalf("TriggerTask() error = %v", err)
+	}
+	if run.Trigger != TriggerManual {
+		t.Errorf("TriggerTask() trigger = %q, want %q", run.Trigger, TriggerManual)
+	}
+
+	// the first run is still going, so a second trigger is refused
+	_, err = s.TriggerTask(context.Background(), "task", time.Time{})
+	if !errors.Is(err, apperrors.ErrTaskRunning) {
+		t.Errorf("second TriggerTask() error = %v, want %v", err, apperrors.ErrTaskRunning)
+	}
+
+	close(release)
+	s.runs.Wait()
+
+	if locks.held[repository.SchedulerTaskLockKey("task")] {
+		t.Error("task lock still held after the run finished")
+	}
+
+	_, err = s.TriggerTask(context.Background(), "task", time.Now().Add(time.Hour))
+	if !errors.Is(err, apperrors.ErrInvalidScheduledFor) {
+		t.Errorf("TriggerTask() in the future error = %v, want %v", err, apperrors.ErrInvalidScheduledFor)
+	}
+}
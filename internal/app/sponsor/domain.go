@@ -0,0 +1,108 @@
+package sponsor
+
+import "time"
+
+// budget entry types, kept in sync with the
+// sponsor_budget_entries_entry_type_check constraint. Pledges add budget,
+// fulfilled redemptions spend it and adjustments correct it either way.
+const (
+	EntryPledge     = "pledge"
+	EntryRedemption = "redemption"
+	EntryAdjustment = "adjustment"
+)
+
+var EntryTypes = []string{EntryPledge, EntryRedemption, EntryAdjustment}
+
+// Sponsor is a sponsor organization with its budget. Amounts are in USD
+// cents, and AvailableCents is what is left once funded redemptions are
+// paid.
+type Sponsor struct {
+	Id              int       `json:"id"`
+	Name            string    `json:"name"`
+	WebsiteUrl      string    `json:"website_url"`
+	PointsPerUsd    int       `json:"points_per_usd"`
+	IsActive        bool      `json:"is_active"`
+	EarmarkedOwners []string  `json:"earmarked_owners"`
+	PledgedCents    int64     `json:"pledged_cents"`
+	SpentCents      int64     `json:"spent_cents"`
+	ReservedCents   int64     `json:"reserved_cents"`
+	AvailableCents  int64     `json:"available_cents"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type BudgetEntry struct {
+	Id           int       `json:"id"`
+	SponsorId    int       `json:"sponsor_id"`
+	EntryType    string    `json:"entry_type"`
+	AmountCents  int64     `json:"amount_cents"`
+	RedemptionId *int64    `json:"redemption_id"`
+	Note         string    `json:"note"`
+	CreatedBy    *int64    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type CreateSponsorRequest struct {
+	Name            string   `json:"name"`
+	WebsiteUrl      string   `json:"website_url"`
+	PointsPerUsd    int      `json:"points_per_usd"`
+	EarmarkedOwners []string `json:"earmarked_owners"`
+}
+
+// RecordBudgetEntryRequest adds a pledge or an adjustment to a sponsor's
+// budget. Pledges must be positive.
+type RecordBudgetEntryRequest struct {
+	EntryType   string `json:"entry_type"`
+	AmountCents int64  `json:"amount_cents"`
+	Note        string `json:"note"`
+}
+
+type SetEarmarksRequest struct {
+	OwnerNames []string `json:"owner_names"`
+}
+
+// AmountCents converts points to the USD cents a sponsor pays for them,
+// rounding down.
+func AmountCents(points int, pointsPerUsd int) int64 {
+	return int64(points) * 100 / int64(pointsPerUsd)
+}
+
+// Invitation asks a GitHub user to join a sponsor organization. Token is
+// only returned when the invitation is created.
+type Invitation struct {
+	Id             int        `json:"id"`
+	SponsorId      int        `json:"sponsor_id"`
+	GithubUsername string     `json:"github_username"`
+	Token          string     `json:"token,omitempty"`
+	InvitedBy      int        `json:"invited_by"`
+	AcceptedBy     *int64     `json:"accepted_by"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type InviteMemberRequest struct {
+	GithubUsername string `json:"github_username"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+// Contribution is a contribution to one of the sponsor's repositories, as
+// shown in the sponsor portal.
+type Contribution struct {
+	Id               int       `json:"id"`
+	GithubUsername   string    `json:"github_username"`
+	RepositoryName   string    `json:"repository_name"`
+	ContributionType string    `json:"contribution_type"`
+	BalanceChange    int       `json:"balance_change"`
+	ContributedAt    time.Time `json:"contributed_at"`
+}
+
+type Contributor struct {
+	UserId         int    `json:"user_id"`
+	GithubUsername string `json:"github_username"`
+	AvatarUrl      string `json:"avatar_url"`
+	Contributions  int    `json:"contributions"`
+	Points         int    `json:"points"`
+}
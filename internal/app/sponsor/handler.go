@@ -0,0 +1,332 @@
+package sponsor
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	sponsorService Service
+}
+
+type Handler interface {
+	ListActiveSponsors(w http.ResponseWriter, r *http.Request)
+	ListSponsors(w http.ResponseWriter, r *http.Request)
+	CreateSponsor(w http.ResponseWriter, r *http.Request)
+	SetEarmarks(w http.ResponseWriter, r *http.Request)
+	RecordBudgetEntry(w http.ResponseWriter, r *http.Request)
+	ListBudgetEntries(w http.ResponseWriter, r *http.Request)
+	InviteMember(w http.ResponseWriter, r *http.Request)
+	ListInvitations(w http.ResponseWriter, r *http.Request)
+	AcceptInvitation(w http.ResponseWriter, r *http.Request)
+	RemoveMember(w http.ResponseWriter, r *http.Request)
+	GetMySponsor(w http.ResponseWriter, r *http.Request)
+	ListMyContributions(w http.ResponseWriter, r *http.Request)
+	ListMyTopContributors(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(sponsorService Service) Handler {
+	return &handler{
+		sponsorService: sponsorService,
+	}
+}
+
+func (h *handler) ListActiveSponsors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sponsors, err := h.sponsorService.ListActiveSponsors(ctx)
+	if err != nil {
+		slog.Error("failed to list active sponsors", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "sponsors fetched successfully", sponsors)
+}
+
+func (h *handler) ListSponsors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sponsors, err := h.sponsorService.ListSponsors(ctx)
+	if err != nil {
+		slog.Error("failed to list sponsors", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "sponsors fetched successfully", sponsors)
+}
+
+func (h *handler) CreateSponsor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody CreateSponsorRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	sponsor, err := h.sponsorService.CreateSponsor(ctx, requestBody)
+	if err != nil {
+		slog.Error("failed to create sponsor", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "sponsor created successfully", sponsor)
+}
+
+func (h *handler) SetEarmarks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sponsorId, err := strconv.Atoi(r.PathValue("sponsorId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody SetEarmarksRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	err = h.sponsorService.SetEarmarks(ctx, sponsorId, requestBody)
+	if err != nil {
+		slog.Error("failed to set sponsor earmarks", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "sponsor earmarks updated successfully", nil)
+}
+
+func (h *handler) RecordBudgetEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sponsorId, err := strconv.Atoi(r.PathValue("sponsorId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody RecordBudgetEntryRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	entry, err := h.sponsorService.RecordBudgetEntry(ctx, sponsorId, requestBody)
+	if err != nil {
+		slog.Error("failed to record sponsor budget entry", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "sponsor budget entry recorded successfully", entry)
+}
+
+func (h *handler) ListBudgetEntries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sponsorId, err := strconv.Atoi(r.PathValue("sponsorId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	entries, err := h.sponsorService.ListBudgetEntries(ctx, sponsorId, limit, offset)
+	if err != nil {
+		slog.Error("failed to list sponsor budget entries", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "sponsor budget entries fetched successfully", entries)
+}
+
+func (h *handler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sponsorId, err := strconv.Atoi(r.PathValue("sponsorId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody InviteMemberRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	invitation, err := h.sponsorService.InviteMember(ctx, sponsorId, requestBody)
+	if err != nil {
+		slog.Error("failed to invite sponsor member", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "sponsor invitation created successfully", invitation)
+}
+
+func (h *handler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sponsorId, err := strconv.Atoi(r.PathValue("sponsorId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	invitations, err := h.sponsorService.ListInvitations(ctx, sponsorId)
+	if err != nil {
+		slog.Error("failed to list sponsor invitations", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "sponsor invitations fetched successfully", invitations)
+}
+
+func (h *handler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody AcceptInvitationRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	sponsor, err := h.sponsorService.AcceptInvitation(ctx, requestBody)
+	if err != nil {
+		slog.Error("failed to accept sponsor invitation", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "sponsor invitation accepted successfully", sponsor)
+}
+
+func (h *handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sponsorId, err := strconv.Atoi(r.PathValue("sponsorId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	userId, err := strconv.Atoi(r.PathValue("userId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.sponsorService.RemoveMember(ctx, sponsorId, userId)
+	if err != nil {
+		slog.Error("failed to remove sponsor member", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "sponsor member removed successfully", nil)
+}
+
+func (h *handler) GetMySponsor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sponsor, err := h.sponsorService.GetMySponsor(ctx)
+	if err != nil {
+		slog.Error("failed to get sponsor", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "sponsor fetched successfully", sponsor)
+}
+
+func (h *handler) ListMyContributions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	contributions, err := h.sponsorService.ListMyContributions(ctx, limit, offset)
+	if err != nil {
+		slog.Error("failed to list sponsor contributions", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "contributions fetched successfully", contributions)
+}
+
+func (h *handler) ListMyTopContributors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	from, err := request.ParseTime(r, "from")
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	to, err := request.ParseTime(r, "to")
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	limit, err := request.ParseLimit(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	contributors, err := h.sponsorService.ListMyTopContributors(ctx, from, to, limit)
+	if err != nil {
+		slog.Error("failed to list sponsor top contributors", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "top contributors fetched successfully", contributors)
+}
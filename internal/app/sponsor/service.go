@@ -0,0 +1,501 @@
+package sponsor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	sponsorRepository           repository.SponsorRepository
+	sponsorInvitationRepository repository.SponsorInvitationRepository
+	userRepository              repository.UserRepository
+	roleRepository              repository.RoleRepository
+	appCfg                      config.AppConfig
+}
+
+type Service interface {
+	ListActiveSponsors(ctx context.Context) ([]Sponsor, error)
+	ListSponsors(ctx context.Context) ([]Sponsor, error)
+	CreateSponsor(ctx context.Context, request CreateSponsorRequest) (Sponsor, error)
+	SetEarmarks(ctx context.Context, sponsorId int, request SetEarmarksRequest) error
+	RecordBudgetEntry(ctx context.Context, sponsorId int, request RecordBudgetEntryRequest) (BudgetEntry, error)
+	ListBudgetEntries(ctx context.Context, sponsorId int, limit int, offset int) ([]BudgetEntry, error)
+	InviteMember(ctx context.Context, sponsorId int, request InviteMemberRequest) (Invitation, error)
+	ListInvitations(ctx context.Context, sponsorId int) ([]Invitation, error)
+	AcceptInvitation(ctx context.Context, request AcceptInvitationRequest) (Sponsor, error)
+	RemoveMember(ctx context.Context, sponsorId int, userId int) error
+	GetMySponsor(ctx context.Context) (Sponsor, error)
+	ListMyContributions(ctx context.Context, limit int, offset int) ([]Contribution, error)
+	ListMyTopContributors(ctx context.Context, from time.Time, to time.Time, limit int) ([]Contributor, error)
+}
+
+func NewService(sponsorRepository repository.SponsorRepository, sponsorInvitationRepository repository.SponsorInvitationRepository, userRepository repository.UserRepository, roleRepository repository.RoleRepository, appCfg config.AppConfig) Service {
+	return &service{
+		sponsorRepository:           sponsorRepository,
+		sponsorInvitationRepository: sponsorInvitationRepository,
+		userRepository:              userRepository,
+		roleRepository:              roleRepository,
+		appCfg:                      appCfg,
+	}
+}
+
+// ListActiveSponsors returns the sponsors currently funding redemptions,
+// shown publicly.
+func (s *service) ListActiveSponsors(ctx context.Context) ([]Sponsor, error) {
+	return s.listSponsors(ctx, true)
+}
+
+func (s *service) ListSponsors(ctx context.Context) ([]Sponsor, error) {
+	return s.listSponsors(ctx, false)
+}
+
+func (s *service) listSponsors(ctx context.Context, activeOnly bool) ([]Sponsor, error) {
+	budgets, err := s.sponsorRepository.ListSponsorBudgets(ctx, nil, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	sponsors := make([]Sponsor, 0, len(budgets))
+	for _, budget := range budgets {
+		sponsors = append(sponsors, newSponsor(budget))
+	}
+
+	return sponsors, nil
+}
+
+func (s *service) CreateSponsor(ctx context.Context, request CreateSponsorRequest) (created Sponsor, err error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" || request.PointsPerUsd <= 0 {
+		return Sponsor{}, apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.sponsorRepository.BeginTx(ctx)
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	defer func() {
+		txErr := s.sponsorRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	sponsor, err := s.sponsorRepository.CreateSponsor(ctx, tx, repository.Sponsor{
+		Name:         name,
+		WebsiteUrl:   strings.TrimSpace(request.WebsiteUrl),
+		PointsPerUsd: request.PointsPerUsd,
+	})
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	ownerNames := normalizeOwnerNames(request.EarmarkedOwners)
+	err = s.sponsorRepository.SetSponsorEarmarks(ctx, tx, sponsor.Id, ownerNames)
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	slog.Info("sponsor created", "sponsor_id", sponsor.Id, "name", sponsor.Name)
+	return newSponsor(repository.SponsorBudget{Sponsor: sponsor, EarmarkedOwners: ownerNames}), nil
+}
+
+// SetEarmarks restricts the sponsor to funding points earned in
+// repositories of the given owners. An empty list lifts the restriction.
+func (s *service) SetEarmarks(ctx context.Context, sponsorId int, request SetEarmarksRequest) (err error) {
+	tx, err := s.sponsorRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.sponsorRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	_, err = s.sponsorRepository.GetSponsorById(ctx, tx, sponsorId)
+	if err != nil {
+		return err
+	}
+
+	return s.sponsorRepository.SetSponsorEarmarks(ctx, tx, sponsorId, normalizeOwnerNames(request.OwnerNames))
+}
+
+func (s *service) RecordBudgetEntry(ctx context.Context, sponsorId int, request RecordBudgetEntryRequest) (BudgetEntry, error) {
+	adminId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return BudgetEntry{}, apperrors.ErrInternalServer
+	}
+
+	switch request.EntryType {
+	case EntryPledge:
+		if request.AmountCents <= 0 {
+			return BudgetEntry{}, apperrors.ErrInvalidRequestBody
+		}
+	case EntryAdjustment:
+		if request.AmountCents == 0 {
+			return BudgetEntry{}, apperrors.ErrInvalidRequestBody
+		}
+	default:
+		return BudgetEntry{}, apperrors.ErrInvalidRequestBody
+	}
+
+	_, err := s.sponsorRepository.GetSponsorById(ctx, nil, sponsorId)
+	if err != nil {
+		return BudgetEntry{}, err
+	}
+
+	entry, err := s.sponsorRepository.CreateSponsorBudgetEntry(ctx, nil, repository.SponsorBudgetEntry{
+		SponsorId:   sponsorId,
+		EntryType:   request.EntryType,
+		AmountCents: request.AmountCents,
+		Note:        strings.TrimSpace(request.Note),
+		CreatedBy:   sql.NullInt64{Int64: int64(adminId), Valid: true},
+	})
+	if err != nil {
+		return BudgetEntry{}, err
+	}
+
+	slog.Info("sponsor budget entry recorded", "sponsor_id", sponsorId, "entry_type", request.EntryType, "amount_cents", request.AmountCents, "admin_id", adminId)
+	return newBudgetEntry(entry), nil
+}
+
+func (s *service) ListBudgetEntries(ctx context.Context, sponsorId int, limit int, offset int) ([]BudgetEntry, error) {
+	_, err := s.sponsorRepository.GetSponsorById(ctx, nil, sponsorId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.sponsorRepository.ListSponsorBudgetEntries(ctx, nil, sponsorId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BudgetEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, newBudgetEntry(entry))
+	}
+
+	return result, nil
+}
+
+// InviteMember creates an invitation for the GitHub user to join the
+// sponsor organization. The returned token is not stored and cannot be
+// fetched again.
+func (s *service) InviteMember(ctx context.Context, sponsorId int, request InviteMemberRequest) (Invitation, error) {
+	adminId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Invitation{}, apperrors.ErrInternalServer
+	}
+
+	githubUsername := strings.TrimSpace(request.GithubUsername)
+	if githubUsername == "" {
+		return Invitation{}, apperrors.ErrInvalidRequestBody
+	}
+
+	_, err := s.sponsorRepository.GetSponsorById(ctx, nil, sponsorId)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	token, tokenHash, err := newInvitationToken()
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	invitation, err := s.sponsorInvitationRepository.CreateSponsorInvitation(ctx, nil, repository.SponsorInvitation{
+		SponsorId:      sponsorId,
+		GithubUsername: githubUsername,
+		TokenHash:      tokenHash,
+		InvitedBy:      adminId,
+		ExpiresAt:      time.Now().Add(s.appCfg.Sponsors.InvitationTTL),
+	})
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	slog.Info("sponsor member invited", "sponsor_id", sponsorId, "github_username", githubUsername, "admin_id", adminId)
+
+	result := newInvitation(invitation)
+	result.Token = token
+	return result, nil
+}
+
+func (s *service) ListInvitations(ctx context.Context, sponsorId int) ([]Invitation, error) {
+	invitations, err := s.sponsorInvitationRepository.ListSponsorInvitations(ctx, nil, sponsorId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Invitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		result = append(result, newInvitation(invitation))
+	}
+
+	return result, nil
+}
+
+// AcceptInvitation makes the logged in user a member of the inviting
+// sponsor organization. The invitation must have been sent to the user's
+// GitHub username.
+func (s *service) AcceptInvitation(ctx context.Context, request AcceptInvitationRequest) (accepted Sponsor, err error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Sponsor{}, apperrors.ErrInternalServer
+	}
+
+	if request.Token == "" {
+		return Sponsor{}, apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.sponsorInvitationRepository.BeginTx(ctx)
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	defer func() {
+		txErr := s.sponsorInvitationRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	invitation, err := s.sponsorInvitationRepository.GetSponsorInvitationByTokenHashForUpdate(ctx, tx, hashInvitationToken(request.Token))
+	if err != nil {
+		return Sponsor{}, err
+	}
+	if invitation.AcceptedAt.Valid || time.Now().After(invitation.ExpiresAt) {
+		return Sponsor{}, apperrors.ErrSponsorInvitationUsed
+	}
+
+	user, err := s.userRepository.GetUserById(ctx, tx, userId)
+	if err != nil {
+		return Sponsor{}, err
+	}
+	if !strings.EqualFold(user.GithubUsername, invitation.GithubUsername) {
+		return Sponsor{}, apperrors.ErrSponsorInvitationMismatch
+	}
+
+	err = s.sponsorInvitationRepository.AcceptSponsorInvitation(ctx, tx, invitation.Id, userId)
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	err = s.userRepository.SetUserSponsor(ctx, tx, userId, invitation.SponsorId)
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	err = s.roleRepository.GrantUserRole(ctx, tx, userId, middleware.RoleSponsor, sql.NullInt64{Int64: int64(invitation.InvitedBy), Valid: true})
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	budget, err := s.sponsorRepository.GetSponsorBudget(ctx, tx, invitation.SponsorId)
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	slog.Info("sponsor invitation accepted", "sponsor_id", invitation.SponsorId, "user_id", userId)
+	return newSponsor(budget), nil
+}
+
+// RemoveMember detaches the user from the sponsor organization and takes
+// away the sponsor role that came with the membership.
+func (s *service) RemoveMember(ctx context.Context, sponsorId int, userId int) (err error) {
+	tx, err := s.userRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.userRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	err = s.userRepository.RemoveUserFromSponsor(ctx, tx, userId, sponsorId)
+	if err != nil {
+		return err
+	}
+
+	err = s.roleRepository.RevokeUserRole(ctx, tx, userId, middleware.RoleSponsor)
+	if err != nil && !errors.Is(err, apperrors.ErrUserRoleNotFound) {
+		return err
+	}
+
+	slog.Info("sponsor member removed", "sponsor_id", sponsorId, "user_id", userId)
+	return nil
+}
+
+func (s *service) GetMySponsor(ctx context.Context) (Sponsor, error) {
+	sponsorId, err := sessionSponsorId(ctx)
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	budget, err := s.sponsorRepository.GetSponsorBudget(ctx, nil, sponsorId)
+	if err != nil {
+		return Sponsor{}, err
+	}
+
+	return newSponsor(budget), nil
+}
+
+func (s *service) ListMyContributions(ctx context.Context, limit int, offset int) ([]Contribution, error) {
+	sponsorId, err := sessionSponsorId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contributions, err := s.sponsorRepository.ListSponsorContributions(ctx, nil, sponsorId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Contribution, 0, len(contributions))
+	for _, contribution := range contributions {
+		result = append(result, Contribution(contribution))
+	}
+
+	return result, nil
+}
+
+func (s *service) ListMyTopContributors(ctx context.Context, from time.Time, to time.Time, limit int) ([]Contributor, error) {
+	sponsorId, err := sessionSponsorId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contributors, err := s.sponsorRepository.ListSponsorTopContributors(ctx, nil, sponsorId,
+		sql.NullTime{Time: from, Valid: !from.IsZero()},
+		sql.NullTime{Time: to, Valid: !to.IsZero()},
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Contributor, 0, len(contributors))
+	for _, contributor := range contributors {
+		result = append(result, Contributor(contributor))
+	}
+
+	return result, nil
+}
+
+// sessionSponsorId returns the sponsor organization the logged in user is a
+// member of.
+func sessionSponsorId(ctx context.Context) (int, error) {
+	session, ok := middleware.SessionFromContext(ctx)
+	if !ok {
+		slog.Error("error obtaining session from context")
+		return 0, apperrors.ErrInternalServer
+	}
+	if session.SponsorId == 0 {
+		return 0, apperrors.ErrAccessForbidden
+	}
+
+	return session.SponsorId, nil
+}
+
+func newInvitationToken() (string, string, error) {
+	raw := make([]byte, 32)
+	_, err := rand.Read(raw)
+	if err != nil {
+		slog.Error("failed to generate invitation token", "error", err)
+		return "", "", apperrors.ErrInternalServer
+	}
+
+	token := hex.EncodeToString(raw)
+	return token, hashInvitationToken(token), nil
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeOwnerNames(ownerNames []string) []string {
+	normalized := make([]string, 0, len(ownerNames))
+	for _, ownerName := range ownerNames {
+		ownerName = strings.ToLower(strings.TrimSpace(ownerName))
+		if ownerName != "" {
+			normalized = append(normalized, ownerName)
+		}
+	}
+
+	return normalized
+}
+
+func newSponsor(budget repository.SponsorBudget) Sponsor {
+	return Sponsor{
+		Id:              budget.Id,
+		Name:            budget.Name,
+		WebsiteUrl:      budget.WebsiteUrl,
+		PointsPerUsd:    budget.PointsPerUsd,
+		IsActive:        budget.IsActive,
+		EarmarkedOwners: budget.EarmarkedOwners,
+		PledgedCents:    budget.PledgedCents,
+		SpentCents:      budget.SpentCents,
+		ReservedCents:   budget.ReservedCents,
+		AvailableCents:  budget.BalanceCents - budget.ReservedCents,
+		CreatedAt:       budget.CreatedAt,
+	}
+}
+
+func newBudgetEntry(entry repository.SponsorBudgetEntry) BudgetEntry {
+	budgetEntry := BudgetEntry{
+		Id:          entry.Id,
+		SponsorId:   entry.SponsorId,
+		EntryType:   entry.EntryType,
+		AmountCents: entry.AmountCents,
+		Note:        entry.Note,
+		CreatedAt:   entry.CreatedAt,
+	}
+	if entry.RedemptionId.Valid {
+		budgetEntry.RedemptionId = &entry.RedemptionId.Int64
+	}
+	if entry.CreatedBy.Valid {
+		budgetEntry.CreatedBy = &entry.CreatedBy.Int64
+	}
+
+	return budgetEntry
+}
+
+func newInvitation(invitation repository.SponsorInvitation) Invitation {
+	result := Invitation{
+		Id:             invitation.Id,
+		SponsorId:      invitation.SponsorId,
+		GithubUsername: invitation.GithubUsername,
+		InvitedBy:      invitation.InvitedBy,
+		ExpiresAt:      invitation.ExpiresAt,
+		CreatedAt:      invitation.CreatedAt,
+	}
+	if invitation.AcceptedBy.Valid {
+		result.AcceptedBy = &invitation.AcceptedBy.Int64
+	}
+	if invitation.AcceptedAt.Valid {
+		result.AcceptedAt = &invitation.AcceptedAt.Time
+	}
+
+	return result
+}
@@ -0,0 +1,37 @@
+package streak
+
+// EventSubscriber names the streak subscriber's rows in the event outbox.
+const EventSubscriber = "streaks"
+
+type Day struct {
+	Date          string `json:"date"`
+	Contributions int    `json:"contributions"`
+	Points        int    `json:"points"`
+}
+
+type Streak struct {
+	CurrentStreak  int    `json:"current_streak"`
+	LongestStreak  int    `json:"longest_streak"`
+	LastActiveDate string `json:"last_active_date,omitempty"`
+}
+
+// Calendar is a user's activity for one year, bucketed into days in the
+// user's timezone. FrozenDates are missed days covered by freeze tokens.
+type Calendar struct {
+	Year        int      `json:"year"`
+	Timezone    string   `json:"timezone"`
+	Days        []Day    `json:"days"`
+	FrozenDates []string `json:"frozen_dates"`
+	Streak      Streak   `json:"streak"`
+}
+
+type MyStreak struct {
+	Streak
+	FreezeTokens     int `json:"freeze_tokens"`
+	MaxFreezeTokens  int `json:"max_freeze_tokens"`
+	FreezeTokenPrice int `json:"freeze_token_price"`
+}
+
+type PurchaseFreezeTokensRequest struct {
+	Quantity int `json:"quantity"`
+}
@@ -0,0 +1,87 @@
+package streak
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	streakService Service
+}
+
+type Handler interface {
+	GetCalendar(w http.ResponseWriter, r *http.Request)
+	GetMyStreak(w http.ResponseWriter, r *http.Request)
+	PurchaseFreezeTokens(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(streakService Service) Handler {
+	return &handler{
+		streakService: streakService,
+	}
+}
+
+func (h *handler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	year := 0
+	if yearParam := r.URL.Query().Get("year"); yearParam != "" {
+		var err error
+		year, err = strconv.Atoi(yearParam)
+		if err != nil {
+			response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+			return
+		}
+	}
+
+	calendar, err := h.streakService.GetCalendar(ctx, r.PathValue("github_username"), year)
+	if err != nil {
+		slog.Error("failed to get activity calendar", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "activity calendar fetched successfully", calendar)
+}
+
+func (h *handler) GetMyStreak(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	streak, err := h.streakService.GetMyStreak(ctx)
+	if err != nil {
+		slog.Error("failed to get streak", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "streak fetched successfully", streak)
+}
+
+func (h *handler) PurchaseFreezeTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody PurchaseFreezeTokensRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	streak, err := h.streakService.PurchaseFreezeTokens(ctx, requestBody)
+	if err != nil {
+		slog.Error("failed to purchase streak freeze tokens", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "streak freeze tokens purchased", streak)
+}
@@ -0,0 +1,403 @@
+package streak
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+const minCalendarYear = 2000
+
+type service struct {
+	streakRepository         repository.StreakRepository
+	userRepository           repository.UserRepository
+	transactionRepository    repository.TransactionRepository
+	privacySettingRepository repository.PrivacySettingRepository
+	eventBus                 events.Bus
+	appCfg                   config.AppConfig
+}
+
+type Service interface {
+	HandleContributionRecorded(ctx context.Context, tx *sqlx.Tx, event events.Event, data events.ContributionRecordedData) error
+	HandleContributionAdjusted(ctx context.Context, tx *sqlx.Tx, event events.Event, data events.ContributionAdjustedData) error
+	GetCurrentStreak(ctx context.Context, userId int) (int, error)
+	GetCalendar(ctx context.Context, githubUsername string, year int) (Calendar, error)
+	GetMyStreak(ctx context.Context) (MyStreak, error)
+	PurchaseFreezeTokens(ctx context.Context, request PurchaseFreezeTokensRequest) (MyStreak, error)
+}
+
+func NewService(streakRepository repository.StreakRepository, userRepository repository.UserRepository, transactionRepository repository.TransactionRepository, privacySettingRepository repository.PrivacySettingRepository, eventBus events.Bus, appCfg config.AppConfig) Service {
+	return &service{
+		streakRepository:         streakRepository,
+		userRepository:           userRepository,
+		transactionRepository:    transactionRepository,
+		privacySettingRepository: privacySettingRepository,
+		eventBus:                 eventBus,
+		appCfg:                   appCfg,
+	}
+}
+
+// HandleContributionRecorded subscribes to recorded contributions. It adds
+// the contribution to its day in the user's timezone and brings the streak
+// up to date. A gap before the new day is covered with freeze tokens when
+// the user holds enough of them for every missed day.
+func (s *service) HandleContributionRecorded(ctx context.Context, tx *sqlx.Tx, event events.Event, data events.ContributionRecordedData) error {
+	userInfo, err := s.userRepository.GetUserById(ctx, tx, data.UserId)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	day := localDate(data.ContributedAt, userInfo.Timezone)
+
+	err = s.streakRepository.RecordDailyActivity(ctx, tx, data.UserId, day, 1, data.Points)
+	if err != nil {
+		return err
+	}
+
+	streak, err := s.streakRepository.LockStreak(ctx, tx, data.UserId)
+	if err != nil {
+		return err
+	}
+
+	if streak.LastActiveDate.Valid && day.After(streak.LastActiveDate.Time) {
+		missed := daysBetween(streak.LastActiveDate.Time, day) - 1
+		if missed > 0 && missed <= streak.FreezeTokens {
+			freezeDates := make([]time.Time, 0, missed)
+			for i := 1; i <= missed; i++ {
+				freezeDates = append(freezeDates, streak.LastActiveDate.Time.AddDate(0, 0, i))
+			}
+
+			err = s.streakRepository.CreateStreakFreezes(ctx, tx, data.UserId, freezeDates)
+			if err != nil {
+				return err
+			}
+			streak.FreezeTokens -= missed
+		}
+	}
+
+	if !streak.LastActiveDate.Valid || day.After(streak.LastActiveDate.Time) {
+		streak.LastActiveDate = sql.NullTime{Time: day, Valid: true}
+	}
+
+	days, err := s.streakRepository.ListStreakDays(ctx, tx, data.UserId, time.Time{}, streak.LastActiveDate.Time)
+	if err != nil {
+		return err
+	}
+
+	current, longest := streakRuns(days)
+	streak.CurrentStreak = current
+	streak.LongestStreak = max(streak.LongestStreak, longest)
+
+	return s.streakRepository.UpdateStreak(ctx, tx, streak)
+}
+
+// HandleContributionAdjusted subscribes to voided and rescored
+// contributions. It takes them out of their day and works the streak out
+// again, since a voided contribution may have been all that kept a day
+// active. Freeze tokens spent on the way are not refunded.
+func (s *service) HandleContributionAdjusted(ctx context.Context, tx *sqlx.Tx, event events.Event, data events.ContributionAdjustedData) error {
+	userInfo, err := s.userRepository.GetUserById(ctx, tx, data.UserId)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	contributions := 0
+	if data.Voided {
+		contributions = -1
+	}
+
+	day := localDate(data.ContributedAt, userInfo.Timezone)
+	err = s.streakRepository.RecordDailyActivity(ctx, tx, data.UserId, day, contributions, data.PointsDelta)
+	if err != nil || !data.Voided {
+		return err
+	}
+
+	streak, err := s.streakRepository.LockStreak(ctx, tx, data.UserId)
+	if err != nil || !streak.LastActiveDate.Valid {
+		return err
+	}
+
+	days, err := s.streakRepository.ListStreakDays(ctx, tx, data.UserId, time.Time{}, streak.LastActiveDate.Time)
+	if err != nil {
+		return err
+	}
+
+	// freezes only bridge gaps, so trailing ones lead nowhere once the day
+	// after them is gone
+	for len(days) > 0 && days[len(days)-1].Frozen {
+		days = days[:len(days)-1]
+	}
+
+	streak.LastActiveDate = sql.NullTime{}
+	if len(days) > 0 {
+		streak.LastActiveDate = sql.NullTime{Time: days[len(days)-1].Date, Valid: true}
+	}
+	streak.CurrentStreak, streak.LongestStreak = streakRuns(days)
+
+	return s.streakRepository.UpdateStreak(ctx, tx, streak)
+}
+
+// GetCurrentStreak is the user's current streak as of today in their
+// timezone.
+func (s *service) GetCurrentStreak(ctx context.Context, userId int) (int, error) {
+	userInfo, err := s.userRepository.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	streak, err := s.streakRepository.GetStreak(ctx, nil, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return mapStreak(streak, localDate(time.Now(), userInfo.Timezone)).CurrentStreak, nil
+}
+
+// GetCalendar is the public activity calendar of a user for a year and is
+// refused when the user hides their activity.
+func (s *service) GetCalendar(ctx context.Context, githubUsername string, year int) (Calendar, error) {
+	userInfo, err := s.userRepository.GetUserByGithubUsername(ctx, nil, githubUsername)
+	if err != nil {
+		return Calendar{}, err
+	}
+	if userInfo.IsBlocked {
+		return Calendar{}, apperrors.ErrUserNotFound
+	}
+
+	setting, err := s.privacySettingRepository.GetPrivacySettingByUserId(ctx, nil, userInfo.Id)
+	if err != nil && !errors.Is(err, apperrors.ErrPrivacySettingNotFound) {
+		return Calendar{}, err
+	}
+	if setting.HideActivity {
+		return Calendar{}, apperrors.ErrActivityHidden
+	}
+
+	today := localDate(time.Now(), userInfo.Timezone)
+	if year == 0 {
+		year = today.Year()
+	}
+	if year < minCalendarYear || year > today.Year()+1 {
+		return Calendar{}, apperrors.ErrInvalidYear
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	activity, err := s.streakRepository.ListDailyActivity(ctx, nil, userInfo.Id, from, to)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	streakDays, err := s.streakRepository.ListStreakDays(ctx, nil, userInfo.Id, from, to)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	streak, err := s.streakRepository.GetStreak(ctx, nil, userInfo.Id)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	calendar := Calendar{
+		Year:        year,
+		Timezone:    userInfo.Timezone,
+		Days:        make([]Day, 0, len(activity)),
+		FrozenDates: []string{},
+		Streak:      mapStreak(streak, today),
+	}
+	for _, day := range activity {
+		calendar.Days = append(calendar.Days, Day{
+			Date:          day.ActivityDate.Format(time.DateOnly),
+			Contributions: day.Contributions,
+			Points:        day.Points,
+		})
+	}
+	for _, day := range streakDays {
+		if day.Frozen {
+			calendar.FrozenDates = append(calendar.FrozenDates, day.Date.Format(time.DateOnly))
+		}
+	}
+
+	return calendar, nil
+}
+
+func (s *service) GetMyStreak(ctx context.Context) (MyStreak, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return MyStreak{}, apperrors.ErrInternalServer
+	}
+
+	userInfo, err := s.userRepository.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return MyStreak{}, err
+	}
+
+	streak, err := s.streakRepository.GetStreak(ctx, nil, userId)
+	if err != nil {
+		return MyStreak{}, err
+	}
+
+	return s.mapMyStreak(streak, localDate(time.Now(), userInfo.Timezone)), nil
+}
+
+// PurchaseFreezeTokens buys freeze tokens with wallet points. Each token
+// later covers one missed day so the streak survives it.
+func (s *service) PurchaseFreezeTokens(ctx context.Context, request PurchaseFreezeTokensRequest) (purchased MyStreak, err error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return MyStreak{}, apperrors.ErrInternalServer
+	}
+
+	if request.Quantity <= 0 {
+		return MyStreak{}, apperrors.ErrInvalidRequestBody
+	}
+
+	userInfo, err := s.userRepository.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return MyStreak{}, err
+	}
+
+	tx, err := s.streakRepository.BeginTx(ctx)
+	if err != nil {
+		return MyStreak{}, err
+	}
+
+	defer func() {
+		txErr := s.streakRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	streak, err := s.streakRepository.LockStreak(ctx, tx, userId)
+	if err != nil {
+		return MyStreak{}, err
+	}
+
+	if streak.FreezeTokens+request.Quantity > s.appCfg.Streaks.MaxFreezeTokens {
+		return MyStreak{}, apperrors.ErrFreezeTokenLimit
+	}
+
+	cost := request.Quantity * s.appCfg.Streaks.FreezeTokenPrice
+
+	err = s.userRepository.DebitUserBalance(ctx, tx, userId, cost)
+	if err != nil {
+		return MyStreak{}, err
+	}
+
+	_, err = s.transactionRepository.CreateTransaction(ctx, tx, repository.Transaction{
+		UserId:            userId,
+		IsRedeemed:        true,
+		IsGained:          false,
+		TransactedBalance: cost,
+		TransactedAt:      time.Now(),
+	})
+	if err != nil {
+		return MyStreak{}, err
+	}
+
+	err = s.eventBus.Publish(ctx, tx, events.New(events.BalanceChanged, userId, events.BalanceChangedData{
+		UserId: userId,
+		Delta:  -cost,
+		Reason: events.BalanceReasonStreakFreeze,
+	}))
+	if err != nil {
+		return MyStreak{}, err
+	}
+
+	streak.FreezeTokens += request.Quantity
+	err = s.streakRepository.UpdateStreak(ctx, tx, streak)
+	if err != nil {
+		return MyStreak{}, err
+	}
+
+	slog.Info("streak freeze tokens purchased", "user_id", userId, "quantity", request.Quantity, "points", cost)
+
+	return s.mapMyStreak(streak, localDate(time.Now(), userInfo.Timezone)), nil
+}
+
+func (s *service) mapMyStreak(streak repository.Streak, today time.Time) MyStreak {
+	return MyStreak{
+		Streak:           mapStreak(streak, today),
+		FreezeTokens:     streak.FreezeTokens,
+		MaxFreezeTokens:  s.appCfg.Streaks.MaxFreezeTokens,
+		FreezeTokenPrice: s.appCfg.Streaks.FreezeTokenPrice,
+	}
+}
+
+// mapStreak reports the stored streak as broken once the user has missed
+// more days since their last activity than their freeze tokens can cover.
+func mapStreak(streak repository.Streak, today time.Time) Streak {
+	mapped := Streak{
+		CurrentStreak: streak.CurrentStreak,
+		LongestStreak: streak.LongestStreak,
+	}
+
+	if !streak.LastActiveDate.Valid {
+		return mapped
+	}
+
+	mapped.LastActiveDate = streak.LastActiveDate.Time.Format(time.DateOnly)
+	if daysBetween(streak.LastActiveDate.Time, today)-1 > streak.FreezeTokens {
+		mapped.CurrentStreak = 0
+	}
+
+	return mapped
+}
+
+// streakRuns walks the days in date order and returns the number of active
+// days in the run ending at the last day and in the longest run. Frozen days
+// keep a run going without adding to it.
+func streakRuns(days []repository.StreakDay) (current int, longest int) {
+	var previous time.Time
+	for i, day := range days {
+		if i == 0 || daysBetween(previous, day.Date) != 1 {
+			current = 0
+		}
+		if !day.Frozen {
+			current++
+		}
+		longest = max(longest, current)
+		previous = day.Date
+	}
+
+	return current, longest
+}
+
+// localDate is the calendar day t falls on in timezone, as midnight UTC so
+// it compares with DATE columns.
+func localDate(t time.Time, timezone string) time.Time {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	year, month, day := t.In(location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from time.Time, to time.Time) int {
+	fromYear, fromMonth, fromDay := from.Date()
+	toYear, toMonth, toDay := to.Date()
+
+	start := time.Date(fromYear, fromMonth, fromDay, 0, 0, 0, 0, time.UTC)
+	end := time.Date(toYear, toMonth, toDay, 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours() / 24)
+}
@@ -0,0 +1,73 @@
+package streak
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+func date(day int) time.Time {
+	return time.Date(2025, time.June, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestStreakRuns(t *testing.T) {
+	active := func(day int) repository.StreakDay { return repository.StreakDay{Date: date(day)} }
+	frozen := func(day int) repository.StreakDay { return repository.StreakDay{Date: date(day), Frozen: true} }
+
+	tests := []struct {
+		name        string
+		days        []repository.StreakDay
+		wantCurrent int
+		wantLongest int
+	}{
+		{name: "no days"},
+		{name: "single day", days: []repository.StreakDay{active(1)}, wantCurrent: 1, wantLongest: 1},
+		{name: "consecutive days", days: []repository.StreakDay{active(1), active(2), active(3)}, wantCurrent: 3, wantLongest: 3},
+		{name: "gap resets the run", days: []repository.StreakDay{active(1), active(2), active(3), active(5)}, wantCurrent: 1, wantLongest: 3},
+		{name: "latest run is longest", days: []repository.StreakDay{active(1), active(3), active(4)}, wantCurrent: 2, wantLongest: 2},
+		{name: "frozen day bridges without counting", days: []repository.StreakDay{active(1), frozen(2), active(3)}, wantCurrent: 2, wantLongest: 2},
+		{name: "frozen days alone count nothing", days: []repository.StreakDay{frozen(1), frozen(2)}},
+		{name: "gap after a frozen day", days: []repository.StreakDay{active(1), frozen(2), active(4)}, wantCurrent: 1, wantLongest: 1},
+		{name: "across a month end", days: []repository.StreakDay{{Date: date(30)}, {Date: time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)}}, wantCurrent: 2, wantLongest: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := streakRuns(tt.days)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("streakRuns() = (%d, %d), want (%d, %d)", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestMapStreak(t *testing.T) {
+	tests := []struct {
+		name           string
+		streak         repository.Streak
+		today          time.Time
+		wantCurrent    int
+		wantLastActive string
+	}{
+		{name: "never active", streak: repository.Streak{}, today: date(10)},
+		{name: "active today", streak: repository.Streak{CurrentStreak: 4, LastActiveDate: sql.NullTime{Time: date(10), Valid: true}}, today: date(10), wantCurrent: 4, wantLastActive: "2025-06-10"},
+		{name: "active yesterday", streak: repository.Streak{CurrentStreak: 4, LastActiveDate: sql.NullTime{Time: date(9), Valid: true}}, today: date(10), wantCurrent: 4, wantLastActive: "2025-06-09"},
+		{name: "missed a day", streak: repository.Streak{CurrentStreak: 4, LastActiveDate: sql.NullTime{Time: date(8), Valid: true}}, today: date(10), wantLastActive: "2025-06-08"},
+		{name: "missed days covered by tokens", streak: repository.Streak{CurrentStreak: 4, FreezeTokens: 2, LastActiveDate: sql.NullTime{Time: date(7), Valid: true}}, today: date(10), wantCurrent: 4, wantLastActive: "2025-06-07"},
+		{name: "missed more days than tokens", streak: repository.Streak{CurrentStreak: 4, FreezeTokens: 1, LastActiveDate: sql.NullTime{Time: date(7), Valid: true}}, today: date(10), wantLastActive: "2025-06-07"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapStreak(tt.streak, tt.today)
+			if got.CurrentStreak != tt.wantCurrent || got.LastActiveDate != tt.wantLastActive {
+				t.Errorf("mapStreak() = (%d, %q), want (%d, %q)", got.CurrentStreak, got.LastActiveDate, tt.wantCurrent, tt.wantLastActive)
+			}
+			if got.LongestStreak != tt.streak.LongestStreak {
+				t.Errorf("mapStreak() longest = %d, want %d", got.LongestStreak, tt.streak.LongestStreak)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package summary
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/notification"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	summaryRepository   repository.SummaryRepository
+	userRepository      repository.UserRepository
+	notificationService notification.Service
+	eventBus            events.Bus
+}
+
+type Service interface {
+	CloseMonth(ctx context.Context, month time.Time) error
+}
+
+func NewService(summaryRepository repository.SummaryRepository, userRepository repository.UserRepository, notificationService notification.Service, eventBus events.Bus) Service {
+	return &service{
+		summaryRepository:   summaryRepository,
+		userRepository:      userRepository,
+		notificationService: notificationService,
+		eventBus:            eventBus,
+	}
+}
+
+// CloseMonth credits each user's contributions for the month containing
+// month to their wallet and writes their monthly summary. Only months that
+// have ended can be closed, since users summarized for a month are skipped
+// from then on. A user that fails to close is logged and skipped, so a
+// partially failed close can simply be run again. Blocked users are left out until an admin unblocks them and the
+// month is closed again. Users whose contributions met their active goal are
+// told they achieved it.
+func (s *service) CloseMonth(ctx context.Context, month time.Time) error {
+	monthStart := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	monthYear := MonthYear(monthStart)
+
+	if monthEnd.After(time.Now()) {
+		return apperrors.ErrMonthNotEnded
+	}
+
+	userIds, err := s.summaryRepository.GetUserIdsPendingMonthClose(ctx, nil, monthYear, monthStart, monthEnd)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, userId := range userIds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = s.closeUserMonth(ctx, userId, monthYear, monthStart, monthEnd)
+		if err != nil {
+			slog.Error("failed to close month for user", "user_id", userId, "month_year", monthYear, "error", err)
+			failed++
+		}
+	}
+
+	err = s.summaryRepository.RankMonth(ctx, nil, monthYear)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("month closed", "month_year", monthYear, "users", len(userIds), "failed", failed)
+	return nil
+}
+
+func (s *service) closeUserMonth(ctx context.Context, userId int, monthYear int, monthStart time.Time, monthEnd time.Time) (err error) {
+	tx, err := s.summaryRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		txErr := s.summaryRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	netBalance, lastContributionId, err := s.summaryRepository.CreditMonthContributions(ctx, tx, userId, monthStart, monthEnd)
+	if err != nil {
+		return err
+	}
+
+	// everything was voided since the user was listed, a summary needs a
+	// credited contribution to point to
+	if lastContributionId == 0 {
+		slog.Info("nothing to credit for user", "user_id", userId, "month_year", monthYear)
+		return nil
+	}
+
+	err = s.userRepository.IncrementUserBalance(ctx, tx, userId, netBalance)
+	if err != nil {
+		return err
+	}
+
+	if netBalance > 0 {
+		err = s.notificationService.Notify(ctx, tx, userId, notification.TypePointsEarned, map[string]any{
+			"points":     netBalance,
+			"month":      monthStart.Format("January 2006"),
+			"month_year": monthYear,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	level, achieved, err := s.summaryRepository.GetAchievedGoalLevel(ctx, tx, userId, monthStart, monthEnd)
+	if err != nil {
+		return err
+	}
+	if achieved {
+		err = s.notificationService.Notify(ctx, tx, userId, notification.TypeGoalAchieved, map[string]any{
+			"level":      level,
+			"month":      monthStart.Format("January 2006"),
+			"month_year": monthYear,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.eventBus.Publish(ctx, tx, events.New(events.GoalCompleted, userId, events.GoalCompletedData{
+			UserId:    userId,
+			Level:     level,
+			MonthYear: monthYear,
+		}))
+		if err != nil {
+			return err
+		}
+	}
+
+	if netBalance != 0 {
+		err = s.eventBus.Publish(ctx, tx, events.New(events.BalanceChanged, userId, events.BalanceChangedData{
+			UserId: userId,
+			Delta:  netBalance,
+			Reason: events.BalanceReasonMonthClose,
+		}))
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.summaryRepository.CreateSummary(ctx, tx, userId, monthYear, netBalance, lastContributionId, monthStart, monthEnd)
+}
+
+// PreviousMonth is the start of the month before the one containing t.
+func PreviousMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+}
+
+// MonthYear encodes a month as the YYYYMM integer stored in summary.month_year.
+func MonthYear(t time.Time) int {
+	return t.Year()*100 + int(t.Month())
+}
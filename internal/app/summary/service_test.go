@@ -0,0 +1,127 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/notification"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type credit struct {
+	netBalance         int
+	lastContributionId int
+	err                error
+}
+
+// fakeSummaryRepository closes months from canned credits. Any other method
+// panics through the nil embedded interface.
+type fakeSummaryRepository struct {
+	repository.SummaryRepository
+	pendingUserIds []int
+	credits        map[int]credit
+
+	summarized []int
+	ranked     bool
+}
+
+func (f *fakeSummaryRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return nil, nil
+}
+
+func (f *fakeSummaryRepository) HandleTransaction(ctx context.Context, tx *sqlx.Tx, incomingErr error) error {
+	return nil
+}
+
+func (f *fakeSummaryRepository) GetUserIdsPendingMonthClose(ctx context.Context, tx *sqlx.Tx, monthYear int, monthStart time.Time, monthEnd time.Time) ([]int, error) {
+	return f.pendingUserIds, nil
+}
+
+func (f *fakeSummaryRepository) CreditMonthContributions(ctx context.Context, tx *sqlx.Tx, userId int, monthStart time.Time, monthEnd time.Time) (int, int, error) {
+	credited := f.credits[userId]
+	return credited.netBalance, credited.lastContributionId, credited.err
+}
+
+func (f *fakeSummaryRepository) GetAchievedGoalLevel(ctx context.Context, tx *sqlx.Tx, userId int, monthStart time.Time, monthEnd time.Time) (string, bool, error) {
+	return "", false, nil
+}
+
+func (f *fakeSummaryRepository) CreateSummary(ctx context.Context, tx *sqlx.Tx, userId int, monthYear int, netBalance int, lastContributionId int, monthStart time.Time, monthEnd time.Time) error {
+	f.summarized = append(f.summarized, userId)
+	return nil
+}
+
+func (f *fakeSummaryRepository) RankMonth(ctx context.Context, tx *sqlx.Tx, monthYear int) error {
+	f.ranked = true
+	return nil
+}
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	credited map[int]int
+}
+
+func (f *fakeUserRepository) IncrementUserBalance(ctx context.Context, tx *sqlx.Tx, userId int, amount int) error {
+	f.credited[userId] += amount
+	return nil
+}
+
+type fakeNotificationService struct {
+	notification.Service
+}
+
+func (f fakeNotificationService) Notify(ctx context.Context, tx *sqlx.Tx, userId int, notificationType string, data map[string]any) error {
+	return nil
+}
+
+type fakeBus struct {
+	events.Bus
+}
+
+func (f fakeBus) Publish(ctx context.Context, tx *sqlx.Tx, event events.Event) error {
+	return nil
+}
+
+func TestCloseMonth(t *testing.T) {
+	summaryRepository := &fakeSummaryRepository{
+		pendingUserIds: []int{1, 2, 3},
+		credits: map[int]credit{
+			// every contribution was voided after the user was listed
+			1: {},
+			2: {err: errors.New("credit failed")},
+			3: {netBalance: 30, lastContributionId: 9},
+		},
+	}
+	userRepository := &fakeUserRepository{credited: map[int]int{}}
+	s := NewService(summaryRepository, userRepository, fakeNotificationService{}, fakeBus{})
+
+	err := s.CloseMonth(context.Background(), time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CloseMonth() error = %v", err)
+	}
+
+	if !slices.Equal(summaryRepository.summarized, []int{3}) {
+		t.Errorf("summarized users = %v, want [3]", summaryRepository.summarized)
+	}
+	if len(userRepository.credited) != 1 || userRepository.credited[3] != 30 {
+		t.Errorf("credited balances = %v, want map[3:30]", userRepository.credited)
+	}
+	if !summaryRepository.ranked {
+		t.Error("month was not ranked")
+	}
+}
+
+func TestCloseMonthNotEnded(t *testing.T) {
+	s := NewService(&fakeSummaryRepository{}, &fakeUserRepository{}, fakeNotificationService{}, fakeBus{})
+
+	err := s.CloseMonth(context.Background(), time.Now())
+	if !errors.Is(err, apperrors.ErrMonthNotEnded) {
+		t.Errorf("CloseMonth() error = %v, want %v", err, apperrors.ErrMonthNotEnded)
+	}
+}
@@ -0,0 +1,106 @@
+package team
+
+import "time"
+
+// team kinds, kept in sync with the teams_kind_check constraint
+const (
+	KindTeam         = "team"
+	KindOrganization = "organization"
+)
+
+var Kinds = []string{KindTeam, KindOrganization}
+
+// membership roles, kept in sync with the team_members_role_check constraint
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+var Roles = []string{RoleOwner, RoleAdmin, RoleMember}
+
+type Team struct {
+	Id          int64     `json:"id"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Kind        string    `json:"kind"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TeamDetail is the public view of a team. Goal is the team's goal for the
+// current month, if one is set.
+type TeamDetail struct {
+	Team
+	MemberCount int   `json:"member_count"`
+	MonthPoints int   `json:"month_points"`
+	Goal        *Goal `json:"goal"`
+}
+
+// MyTeam is a team the logged in user belongs to. The invite code is only
+// shown to owners and admins.
+type MyTeam struct {
+	Team
+	Role       string    `json:"role"`
+	InviteCode string    `json:"invite_code,omitempty"`
+	JoinedAt   time.Time `json:"joined_at"`
+}
+
+type Goal struct {
+	Month        string `json:"month"`
+	TargetPoints int    `json:"target_points"`
+	Points       int    `json:"points"`
+	Achieved     bool   `json:"achieved"`
+}
+
+type InviteCode struct {
+	InviteCode string `json:"invite_code"`
+}
+
+type MemberStanding struct {
+	Rank           int    `json:"rank"`
+	UserId         int    `json:"user_id"`
+	GithubUsername string `json:"github_username"`
+	AvatarUrl      string `json:"avatar_url"`
+	Role           string `json:"role"`
+	IsMember       bool   `json:"is_member"`
+	Contributions  int    `json:"contributions"`
+	Points         int    `json:"points"`
+}
+
+type Leaderboard struct {
+	Month   string           `json:"month"`
+	Points  int              `json:"points"`
+	Members []MemberStanding `json:"members"`
+}
+
+type Standing struct {
+	Rank        int       `json:"rank"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	Month       string    `json:"month"`
+	Points      int       `json:"points"`
+	MemberCount int       `json:"member_count"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+}
+
+type CreateTeamRequest struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Kind        string `json:"kind"`
+}
+
+type JoinTeamRequest struct {
+	InviteCode string `json:"invite_code"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type SetGoalRequest struct {
+	Month        string `json:"month"`
+	TargetPoints int    `json:"target_points"`
+}
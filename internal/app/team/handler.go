@@ -0,0 +1,240 @@
+package team
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/request"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	teamService Service
+}
+
+type Handler interface {
+	CreateTeam(w http.ResponseWriter, r *http.Request)
+	GetTeam(w http.ResponseWriter, r *http.Request)
+	GetTeamLeaderboard(w http.ResponseWriter, r *http.Request)
+	ListTeamStandings(w http.ResponseWriter, r *http.Request)
+	ListMyTeams(w http.ResponseWriter, r *http.Request)
+	JoinTeam(w http.ResponseWriter, r *http.Request)
+	LeaveTeam(w http.ResponseWriter, r *http.Request)
+	RegenerateInviteCode(w http.ResponseWriter, r *http.Request)
+	UpdateMemberRole(w http.ResponseWriter, r *http.Request)
+	RemoveMember(w http.ResponseWriter, r *http.Request)
+	SetGoal(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(teamService Service) Handler {
+	return &handler{
+		teamService: teamService,
+	}
+}
+
+func (h *handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody CreateTeamRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	team, err := h.teamService.CreateTeam(ctx, requestBody)
+	if err != nil {
+		slog.Error("failed to create team", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "team created successfully", team)
+}
+
+func (h *handler) GetTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	team, err := h.teamService.GetTeam(ctx, r.PathValue("slug"))
+	if err != nil {
+		slog.Error("failed to get team", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "team fetched successfully", team)
+}
+
+func (h *handler) GetTeamLeaderboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	leaderboard, err := h.teamService.GetTeamLeaderboard(ctx, r.PathValue("slug"), r.URL.Query().Get("month"))
+	if err != nil {
+		slog.Error("failed to get team leaderboard", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "team leaderboard fetched successfully", leaderboard)
+}
+
+func (h *handler) ListTeamStandings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, offset, err := request.ParsePagination(r)
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	standings, err := h.teamService.ListTeamStandings(ctx, r.URL.Query().Get("kind"), limit, offset)
+	if err != nil {
+		slog.Error("failed to list team standings", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "team standings fetched successfully", standings)
+}
+
+func (h *handler) ListMyTeams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teams, err := h.teamService.ListMyTeams(ctx)
+	if err != nil {
+		slog.Error("failed to list user teams", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "teams fetched successfully", teams)
+}
+
+func (h *handler) JoinTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody JoinTeamRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	team, err := h.teamService.JoinTeam(ctx, requestBody)
+	if err != nil {
+		slog.Error("failed to join team", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusCreated, "joined team successfully", team)
+}
+
+func (h *handler) LeaveTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := h.teamService.LeaveTeam(ctx, r.PathValue("slug"))
+	if err != nil {
+		slog.Error("failed to leave team", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "left team successfully", nil)
+}
+
+func (h *handler) RegenerateInviteCode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	inviteCode, err := h.teamService.RegenerateInviteCode(ctx, r.PathValue("slug"))
+	if err != nil {
+		slog.Error("failed to regenerate team invite code", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "invite code regenerated successfully", inviteCode)
+}
+
+func (h *handler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := strconv.Atoi(r.PathValue("userId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	var requestBody UpdateMemberRoleRequest
+	err = json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	err = h.teamService.UpdateMemberRole(ctx, r.PathValue("slug"), userId, requestBody)
+	if err != nil {
+		slog.Error("failed to update team member role", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "team member role updated successfully", nil)
+}
+
+func (h *handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := strconv.Atoi(r.PathValue("userId"))
+	if err != nil {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidQueryParams.Error(), nil)
+		return
+	}
+
+	err = h.teamService.RemoveMember(ctx, r.PathValue("slug"), userId)
+	if err != nil {
+		slog.Error("failed to remove team member", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "team member removed successfully", nil)
+}
+
+func (h *handler) SetGoal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody SetGoalRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	goal, err := h.teamService.SetGoal(ctx, r.PathValue("slug"), requestBody)
+	if err != nil {
+		slog.Error("failed to set team goal", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "team goal set successfully", goal)
+}
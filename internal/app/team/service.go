@@ -0,0 +1,697 @@
+package team
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/summary"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+const monthLayout = "2006-01"
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,48}[a-z0-9]$`)
+
+type service struct {
+	teamRepository repository.TeamRepository
+}
+
+type Service interface {
+	CreateTeam(ctx context.Context, request CreateTeamRequest) (MyTeam, error)
+	GetTeam(ctx context.Context, slug string) (TeamDetail, error)
+	GetTeamLeaderboard(ctx context.Context, slug string, month string) (Leaderboard, error)
+	ListTeamStandings(ctx context.Context, kind string, limit int, offset int) ([]Standing, error)
+	ListMyTeams(ctx context.Context) ([]MyTeam, error)
+	JoinTeam(ctx context.Context, request JoinTeamRequest) (MyTeam, error)
+	LeaveTeam(ctx context.Context, slug string) error
+	RegenerateInviteCode(ctx context.Context, slug string) (InviteCode, error)
+	UpdateMemberRole(ctx context.Context, slug string, userId int, request UpdateMemberRoleRequest) error
+	RemoveMember(ctx context.Context, slug string, userId int) error
+	SetGoal(ctx context.Context, slug string, request SetGoalRequest) (Goal, error)
+	RemoveUser(ctx context.Context, tx *sqlx.Tx, userId int) error
+}
+
+func NewService(teamRepository repository.TeamRepository) Service {
+	return &service{
+		teamRepository: teamRepository,
+	}
+}
+
+// CreateTeam creates a team with the caller as its owner. Organizations
+// can only be created by users allowed to manage teams.
+func (s *service) CreateTeam(ctx context.Context, request CreateTeamRequest) (created MyTeam, err error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return MyTeam{}, apperrors.ErrInternalServer
+	}
+
+	slug := strings.ToLower(strings.TrimSpace(request.Slug))
+	name := strings.TrimSpace(request.Name)
+	if !slugPattern.MatchString(slug) || name == "" {
+		return MyTeam{}, apperrors.ErrInvalidRequestBody
+	}
+
+	kind := request.Kind
+	if kind == "" {
+		kind = KindTeam
+	}
+	if !slices.Contains(Kinds, kind) {
+		return MyTeam{}, apperrors.ErrInvalidRequestBody
+	}
+	if kind == KindOrganization && !canManageTeams(ctx) {
+		return MyTeam{}, apperrors.ErrAccessForbidden
+	}
+
+	inviteCode, err := newInviteCode()
+	if err != nil {
+		return MyTeam{}, err
+	}
+
+	tx, err := s.teamRepository.BeginTx(ctx)
+	if err != nil {
+		return MyTeam{}, err
+	}
+
+	defer func() {
+		txErr := s.teamRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	team, err := s.teamRepository.CreateTeam(ctx, tx, repository.Team{
+		Slug:        slug,
+		Name:        name,
+		Description: strings.TrimSpace(request.Description),
+		Kind:        kind,
+		InviteCode:  inviteCode,
+		CreatedBy:   userId,
+	})
+	if err != nil {
+		return MyTeam{}, err
+	}
+
+	member, err := s.teamRepository.AddTeamMember(ctx, tx, team.Id, userId, RoleOwner)
+	if err != nil {
+		return MyTeam{}, err
+	}
+
+	slog.Info("team created", "team_id", team.Id, "slug", team.Slug, "kind", team.Kind, "user_id", userId)
+
+	return mapMyTeam(repository.UserTeam{Team: team, Role: member.Role, JoinedAt: member.JoinedAt}), nil
+}
+
+// GetTeam is the public view of a team with its points and goal progress
+// for the current month.
+func (s *service) GetTeam(ctx context.Context, slug string) (TeamDetail, error) {
+	team, err := s.teamRepository.GetTeamBySlug(ctx, nil, slug)
+	if err != nil {
+		return TeamDetail{}, err
+	}
+
+	memberCount, err := s.teamRepository.CountTeamMembers(ctx, nil, team.Id)
+	if err != nil {
+		return TeamDetail{}, err
+	}
+
+	monthStart := currentMonthStart()
+	points, err := s.teamRepository.GetTeamPoints(ctx, nil, team.Id, monthStart, monthStart.AddDate(0, 1, 0))
+	if err != nil {
+		return TeamDetail{}, err
+	}
+
+	detail := TeamDetail{
+		Team:        mapTeam(team),
+		MemberCount: memberCount,
+		MonthPoints: points,
+	}
+
+	goal, err := s.teamRepository.GetTeamGoal(ctx, nil, team.Id, summary.MonthYear(monthStart))
+	if err != nil && !errors.Is(err, apperrors.ErrTeamGoalNotFound) {
+		return TeamDetail{}, err
+	}
+	if err == nil {
+		mapped := mapGoal(goal, points)
+		detail.Goal = &mapped
+	}
+
+	return detail, nil
+}
+
+// GetTeamLeaderboard ranks the members' contributions to the team for a
+// month, the current one by default. Members who left during the month
+// keep what they earned while they belonged to the team.
+func (s *service) GetTeamLeaderboard(ctx context.Context, slug string, month string) (Leaderboard, error) {
+	monthStart, err := parseMonth(month)
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	team, err := s.teamRepository.GetTeamBySlug(ctx, nil, slug)
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	standings, err := s.teamRepository.ListTeamMemberStandings(ctx, nil, team.Id, monthStart, monthStart.AddDate(0, 1, 0))
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	leaderboard := Leaderboard{
+		Month:   monthStart.Format(monthLayout),
+		Members: make([]MemberStanding, 0, len(standings)),
+	}
+	for _, standing := range standings {
+		leaderboard.Points += standing.Points
+		leaderboard.Members = append(leaderboard.Members, MemberStanding{
+			Rank:           standing.Rank,
+			UserId:         standing.UserId,
+			GithubUsername: standing.GithubUsername,
+			AvatarUrl:      standing.AvatarUrl,
+			Role:           standing.Role,
+			IsMember:       standing.IsMember,
+			Contributions:  standing.Contributions,
+			Points:         standing.Points,
+		})
+	}
+
+	return leaderboard, nil
+}
+
+// ListTeamStandings reads the latest team leaderboard snapshot. Teams and
+// organizations are ranked separately.
+func (s *service) ListTeamStandings(ctx context.Context, kind string, limit int, offset int) ([]Standing, error) {
+	if kind != "" && !slices.Contains(Kinds, kind) {
+		return nil, apperrors.ErrInvalidQueryParams
+	}
+
+	teamStandings, err := s.teamRepository.ListTeamStandings(ctx, nil, kind, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	standings := make([]Standing, 0, len(teamStandings))
+	for _, standing := range teamStandings {
+		standings = append(standings, Standing{
+			Rank:        standing.Rank,
+			Slug:        standing.Slug,
+			Name:        standing.Name,
+			Kind:        standing.Kind,
+			Month:       fmt.Sprintf("%04d-%02d", standing.MonthYear/100, standing.MonthYear%100),
+			Points:      standing.Points,
+			MemberCount: standing.MemberCount,
+			RefreshedAt: standing.RefreshedAt,
+		})
+	}
+
+	return standings, nil
+}
+
+func (s *service) ListMyTeams(ctx context.Context) ([]MyTeam, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return nil, apperrors.ErrInternalServer
+	}
+
+	userTeams, err := s.teamRepository.ListUserTeams(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]MyTeam, 0, len(userTeams))
+	for _, userTeam := range userTeams {
+		teams = append(teams, mapMyTeam(userTeam))
+	}
+
+	return teams, nil
+}
+
+func (s *service) JoinTeam(ctx context.Context, request JoinTeamRequest) (MyTeam, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return MyTeam{}, apperrors.ErrInternalServer
+	}
+
+	inviteCode := strings.TrimSpace(request.InviteCode)
+	if inviteCode == "" {
+		return MyTeam{}, apperrors.ErrInvalidRequestBody
+	}
+
+	team, err := s.teamRepository.GetTeamByInviteCode(ctx, nil, inviteCode)
+	if err != nil {
+		return MyTeam{}, err
+	}
+
+	member, err := s.teamRepository.AddTeamMember(ctx, nil, team.Id, userId, RoleMember)
+	if err != nil {
+		return MyTeam{}, err
+	}
+
+	slog.Info("user joined team", "team_id", team.Id, "user_id", userId)
+
+	return mapMyTeam(repository.UserTeam{Team: team, Role: member.Role, JoinedAt: member.JoinedAt}), nil
+}
+
+// LeaveTeam ends the caller's membership. The owner has to hand ownership
+// over first unless they are the last member, in which case the invite code
+// is replaced so nobody can join the empty team.
+func (s *service) LeaveTeam(ctx context.Context, slug string) (err error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	tx, err := s.teamRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.teamRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	team, err := s.teamRepository.GetTeamBySlug(ctx, tx, slug)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.teamRepository.GetTeamMember(ctx, tx, team.Id, userId)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == RoleOwner {
+		memberCount, err := s.teamRepository.CountTeamMembers(ctx, tx, team.Id)
+		if err != nil {
+			return err
+		}
+		if memberCount > 1 {
+			return apperrors.ErrTeamOwnerCannotLeave
+		}
+	}
+
+	err = s.endMembership(ctx, tx, member)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("user left team", "team_id", team.Id, "user_id", userId)
+
+	return nil
+}
+
+// RegenerateInviteCode replaces the team's invite code so the old one stops
+// working. Owners and admins can do this.
+func (s *service) RegenerateInviteCode(ctx context.Context, slug string) (InviteCode, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return InviteCode{}, apperrors.ErrInternalServer
+	}
+
+	team, err := s.teamRepository.GetTeamBySlug(ctx, nil, slug)
+	if err != nil {
+		return InviteCode{}, err
+	}
+
+	role, err := s.callerRole(ctx, nil, team.Id, userId)
+	if err != nil {
+		return InviteCode{}, err
+	}
+	if role != RoleOwner && role != RoleAdmin {
+		return InviteCode{}, apperrors.ErrAccessForbidden
+	}
+
+	inviteCode, err := newInviteCode()
+	if err != nil {
+		return InviteCode{}, err
+	}
+
+	err = s.teamRepository.UpdateTeamInviteCode(ctx, nil, team.Id, inviteCode)
+	if err != nil {
+		return InviteCode{}, err
+	}
+
+	slog.Info("team invite code regenerated", "team_id", team.Id, "user_id", userId)
+
+	return InviteCode{InviteCode: inviteCode}, nil
+}
+
+// UpdateMemberRole changes a member's role and is reserved for the owner.
+// Making someone else the owner transfers ownership and turns the previous
+// owner into an admin.
+func (s *service) UpdateMemberRole(ctx context.Context, slug string, userId int, request UpdateMemberRoleRequest) (err error) {
+	callerId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	if !slices.Contains(Roles, request.Role) {
+		return apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.teamRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.teamRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	team, err := s.teamRepository.GetTeamBySlug(ctx, tx, slug)
+	if err != nil {
+		return err
+	}
+
+	role, err := s.callerRole(ctx, tx, team.Id, callerId)
+	if err != nil {
+		return err
+	}
+	if role != RoleOwner {
+		return apperrors.ErrAccessForbidden
+	}
+
+	member, err := s.teamRepository.GetTeamMember(ctx, tx, team.Id, userId)
+	if err != nil {
+		return err
+	}
+	if member.Role == request.Role {
+		return nil
+	}
+	if member.Role == RoleOwner {
+		// ownership only moves by making another member the owner
+		return apperrors.ErrTeamOwnerCannotLeave
+	}
+
+	if request.Role == RoleOwner {
+		owner, err := s.currentOwner(ctx, tx, team.Id, callerId)
+		if err != nil {
+			return err
+		}
+
+		err = s.teamRepository.UpdateTeamMemberRole(ctx, tx, owner.Id, RoleAdmin)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.teamRepository.UpdateTeamMemberRole(ctx, tx, member.Id, request.Role)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("team member role updated", "team_id", team.Id, "user_id", userId, "role", request.Role, "updated_by", callerId)
+
+	return nil
+}
+
+// RemoveMember ends another member's membership. Owners can remove anyone
+// but themselves, admins only plain members.
+func (s *service) RemoveMember(ctx context.Context, slug string, userId int) (err error) {
+	callerId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	tx, err := s.teamRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		txErr := s.teamRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	team, err := s.teamRepository.GetTeamBySlug(ctx, tx, slug)
+	if err != nil {
+		return err
+	}
+
+	role, err := s.callerRole(ctx, tx, team.Id, callerId)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.teamRepository.GetTeamMember(ctx, tx, team.Id, userId)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case member.Role == RoleOwner:
+		return apperrors.ErrTeamOwnerCannotLeave
+	case role == RoleOwner:
+	case role == RoleAdmin && member.Role == RoleMember:
+	default:
+		return apperrors.ErrAccessForbidden
+	}
+
+	err = s.teamRepository.EndTeamMembership(ctx, tx, member.Id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	slog.Info("team member removed", "team_id", team.Id, "user_id", userId, "removed_by", callerId)
+
+	return nil
+}
+
+// SetGoal sets the points target for a month, the current one by default.
+// Owners and admins can do this.
+func (s *service) SetGoal(ctx context.Context, slug string, request SetGoalRequest) (Goal, error) {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return Goal{}, apperrors.ErrInternalServer
+	}
+
+	if request.TargetPoints <= 0 {
+		return Goal{}, apperrors.ErrInvalidRequestBody
+	}
+
+	monthStart, err := parseMonth(request.Month)
+	if err != nil {
+		return Goal{}, err
+	}
+	if monthStart.Before(currentMonthStart()) {
+		return Goal{}, apperrors.ErrInvalidMonth
+	}
+
+	team, err := s.teamRepository.GetTeamBySlug(ctx, nil, slug)
+	if err != nil {
+		return Goal{}, err
+	}
+
+	role, err := s.callerRole(ctx, nil, team.Id, userId)
+	if err != nil {
+		return Goal{}, err
+	}
+	if role != RoleOwner && role != RoleAdmin {
+		return Goal{}, apperrors.ErrAccessForbidden
+	}
+
+	goal, err := s.teamRepository.UpsertTeamGoal(ctx, nil, repository.TeamGoal{
+		TeamId:       team.Id,
+		MonthYear:    summary.MonthYear(monthStart),
+		TargetPoints: request.TargetPoints,
+		SetBy:        userId,
+	})
+	if err != nil {
+		return Goal{}, err
+	}
+
+	points, err := s.teamRepository.GetTeamPoints(ctx, nil, team.Id, monthStart, monthStart.AddDate(0, 1, 0))
+	if err != nil {
+		return Goal{}, err
+	}
+
+	slog.Info("team goal set", "team_id", team.Id, "month_year", goal.MonthYear, "target_points", goal.TargetPoints, "user_id", userId)
+
+	return mapGoal(goal, points), nil
+}
+
+// RemoveUser ends every membership of a deleted account inside the
+// caller's transaction. Teams they owned pass to the next member in line.
+func (s *service) RemoveUser(ctx context.Context, tx *sqlx.Tx, userId int) error {
+	teams, err := s.teamRepository.ListUserTeams(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, team := range teams {
+		member, err := s.teamRepository.GetTeamMember(ctx, tx, team.Id, userId)
+		if err != nil {
+			return err
+		}
+
+		err = s.endMembership(ctx, tx, member)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// endMembership ends the membership and keeps the team in a usable state:
+// a departing owner is replaced by the next member in line, and a team
+// left without members gets a fresh invite code nobody knows.
+func (s *service) endMembership(ctx context.Context, tx *sqlx.Tx, member repository.TeamMember) error {
+	err := s.teamRepository.EndTeamMembership(ctx, tx, member.Id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	successor, err := s.teamRepository.GetTeamSuccessor(ctx, tx, member.TeamId)
+	if errors.Is(err, apperrors.ErrTeamMemberNotFound) {
+		inviteCode, err := newInviteCode()
+		if err != nil {
+			return err
+		}
+		return s.teamRepository.UpdateTeamInviteCode(ctx, tx, member.TeamId, inviteCode)
+	}
+	if err != nil {
+		return err
+	}
+
+	if member.Role != RoleOwner {
+		return nil
+	}
+
+	err = s.teamRepository.UpdateTeamMemberRole(ctx, tx, successor.Id, RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("team ownership passed on", "team_id", member.TeamId, "user_id", successor.UserId, "previous_owner_id", member.UserId)
+
+	return nil
+}
+
+// callerRole is the caller's role in the team. Users allowed to manage teams
+// act as owners of every team.
+func (s *service) callerRole(ctx context.Context, tx *sqlx.Tx, teamId int64, userId int) (string, error) {
+	if canManageTeams(ctx) {
+		return RoleOwner, nil
+	}
+
+	member, err := s.teamRepository.GetTeamMember(ctx, tx, teamId, userId)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrTeamMemberNotFound) {
+			return "", apperrors.ErrAccessForbidden
+		}
+		return "", err
+	}
+
+	return member.Role, nil
+}
+
+// currentOwner is the owner's membership. When a team manager transfers
+// ownership of a team they do not belong to, the owner is looked up among
+// the members rather than taken from the caller.
+func (s *service) currentOwner(ctx context.Context, tx *sqlx.Tx, teamId int64, callerId int) (repository.TeamMember, error) {
+	member, err := s.teamRepository.GetTeamMember(ctx, tx, teamId, callerId)
+	if err == nil && member.Role == RoleOwner {
+		return member, nil
+	}
+	if err != nil && !errors.Is(err, apperrors.ErrTeamMemberNotFound) {
+		return repository.TeamMember{}, err
+	}
+
+	return s.teamRepository.GetTeamOwner(ctx, tx, teamId)
+}
+
+func canManageTeams(ctx context.Context) bool {
+	session, ok := middleware.SessionFromContext(ctx)
+	return ok && session.HasPermission(middleware.PermissionTeamsManage)
+}
+
+func newInviteCode() (string, error) {
+	raw := make([]byte, 12)
+	_, err := rand.Read(raw)
+	if err != nil {
+		slog.Error("failed to generate team invite code", "error", err)
+		return "", apperrors.ErrInternalServer
+	}
+
+	return hex.EncodeToString(raw), nil
+}
+
+// parseMonth reads a YYYY-MM month, defaulting to the current UTC month.
+func parseMonth(month string) (time.Time, error) {
+	if month == "" {
+		return currentMonthStart(), nil
+	}
+
+	monthStart, err := time.Parse(monthLayout, month)
+	if err != nil {
+		return time.Time{}, apperrors.ErrInvalidMonth
+	}
+
+	return monthStart, nil
+}
+
+func currentMonthStart() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func mapTeam(team repository.Team) Team {
+	return Team{
+		Id:          team.Id,
+		Slug:        team.Slug,
+		Name:        team.Name,
+		Description: team.Description,
+		Kind:        team.Kind,
+		CreatedAt:   team.CreatedAt,
+	}
+}
+
+func mapMyTeam(userTeam repository.UserTeam) MyTeam {
+	team := MyTeam{
+		Team:     mapTeam(userTeam.Team),
+		Role:     userTeam.Role,
+		JoinedAt: userTeam.JoinedAt,
+	}
+	if userTeam.Role == RoleOwner || userTeam.Role == RoleAdmin {
+		team.InviteCode = userTeam.InviteCode
+	}
+
+	return team
+}
+
+func mapGoal(goal repository.TeamGoal, points int) Goal {
+	return Goal{
+		Month:        fmt.Sprintf("%04d-%02d", goal.MonthYear/100, goal.MonthYear%100),
+		TargetPoints: goal.TargetPoints,
+		Points:       points,
+		Achieved:     points >= goal.TargetPoints,
+	}
+}
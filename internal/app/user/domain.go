@@ -13,11 +13,11 @@ type User struct {
 	AvatarUrl           string        `json:"avatar_url"`
 	CurrentBalance      int           `json:"current_balance"`
 	CurrentActiveGoalId sql.NullInt64 `json:"current_active_goal_id"`
-	IsBlocked           bool          `json:"is_blocked"`
-	IsAdmin             bool          `json:"is_admin"`
-	Password            string        `json:"password"`
-	IsDeleted           bool          `json:"is_deleted"`
-	DeletedAt           sql.NullTime  `json:"deleted_at"`
+	IsBlocked           bool          `json:"-"`
+	Password            string        `json:"-"`
+	IsDeleted           bool          `json:"-"`
+	DeletedAt           sql.NullTime  `json:"-"`
+	Timezone            string        `json:"timezone"`
 	CreatedAt           time.Time        `json:"created_at"`
 	UpdatedAt           time.Time        `json:"updated_at"`
 }
@@ -27,9 +27,12 @@ type CreateUserRequestBody struct {
 	GithubUsername string `json:"github_id"`
 	AvatarUrl      string `json:"avatar_url"`
 	Email          string `json:"email"`
-	IsAdmin        bool   `json:"is_admin"`
 }
 
 type Email struct {
 	Email string `json:"email"`
 }
+
+type Timezone struct {
+	Timezone string `json:"timezone"`
+}
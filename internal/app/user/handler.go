@@ -15,6 +15,7 @@ type handler struct {
 
 type Handler interface {
 	UpdateUserEmail(w http.ResponseWriter, r *http.Request)
+	UpdateUserTimezone(w http.ResponseWriter, r *http.Request)
 }
 
 func NewHandler(userService Service) Handler {
@@ -44,3 +45,25 @@ func (h *handler) UpdateUserEmail(w http.ResponseWriter, r *http.Request) {
 
 	response.WriteJson(w, http.StatusOK, "email updated successfully", nil)
 }
+
+func (h *handler) UpdateUserTimezone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestBody Timezone
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		slog.Error(apperrors.ErrFailedMarshal.Error(), "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	err = h.userService.UpdateUserTimezone(ctx, requestBody.Timezone)
+	if err != nil {
+		slog.Error("failed to update user timezone", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusOK, "timezone updated successfully", nil)
+}
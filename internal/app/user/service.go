@@ -3,7 +3,9 @@ package user
 import (
 	"context"
 	"log/slog"
+	"time"
 
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
 	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
 	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/middleware"
 	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
@@ -11,18 +13,24 @@ import (
 
 type service struct {
 	userRepository repository.UserRepository
+	eventBus       events.Bus
 }
 
 type Service interface {
 	GetUserById(ctx context.Context, userId int) (User, error)
 	GetUserByGithubId(ctx context.Context, githubId int) (User, error)
+	GetUserByGithubIdIncludingDeleted(ctx context.Context, githubId int) (User, error)
+	GetUserByGithubUsername(ctx context.Context, githubUsername string) (User, error)
 	CreateUser(ctx context.Context, userInfo CreateUserRequestBody) (User, error)
 	UpdateUserEmail(ctx context.Context, email string) error
+	UpdateUserTimezone(ctx context.Context, timezone string) error
+	ValidateSession(ctx context.Context, userId int, issuedAt time.Time) (middleware.Session, error)
 }
 
-func NewService(userRepository repository.UserRepository) Service {
+func NewService(userRepository repository.UserRepository, eventBus events.Bus) Service {
 	return &service{
 		userRepository: userRepository,
+		eventBus:       eventBus,
 	}
 }
 
@@ -47,13 +55,53 @@ func (s *service) GetUserByGithubId(ctx context.Context, githubId int) (User, er
 	return User(userInfo), nil
 }
 
-func (s *service) CreateUser(ctx context.Context, userInfo CreateUserRequestBody) (User, error) {
-	user, err := s.userRepository.CreateUser(ctx, nil, repository.CreateUserRequestBody(userInfo))
+func (s *service) GetUserByGithubIdIncludingDeleted(ctx context.Context, githubId int) (User, error) {
+	userInfo, err := s.userRepository.GetUserByGithubIdIncludingDeleted(ctx, nil, githubId)
+	if err != nil {
+		return User{}, err
+	}
+
+	return User(userInfo), nil
+}
+
+func (s *service) GetUserByGithubUsername(ctx context.Context, githubUsername string) (User, error) {
+	userInfo, err := s.userRepository.GetUserByGithubUsername(ctx, nil, githubUsername)
+	if err != nil {
+		slog.Error("failed to get user by github username", "error", err)
+		return User{}, err
+	}
+
+	return User(userInfo), nil
+}
+
+// CreateUser stores the user and publishes that it was created.
+func (s *service) CreateUser(ctx context.Context, userInfo CreateUserRequestBody) (created User, err error) {
+	tx, err := s.userRepository.BeginTx(ctx)
+	if err != nil {
+		return User{}, err
+	}
+
+	defer func() {
+		txErr := s.userRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	user, err := s.userRepository.CreateUser(ctx, tx, repository.CreateUserRequestBody(userInfo))
 	if err != nil {
 		slog.Error("failed to create user", "error", err)
 		return User{}, apperrors.ErrUserCreationFailed
 	}
 
+	err = s.eventBus.Publish(ctx, tx, events.New(events.UserCreated, user.Id, events.UserCreatedData{
+		UserId:         user.Id,
+		GithubUsername: user.GithubUsername,
+	}))
+	if err != nil {
+		return User{}, err
+	}
+
 	return User(user), nil
 }
 
@@ -74,3 +122,39 @@ func (s *service) UpdateUserEmail(ctx context.Context, email string) error {
 
 	return nil
 }
+
+// UpdateUserTimezone sets the IANA timezone the user's activity calendar is
+// bucketed in. Days already recorded keep the timezone they were recorded in.
+func (s *service) UpdateUserTimezone(ctx context.Context, timezone string) error {
+	userId, ok := ctx.Value(middleware.UserIdKey).(int)
+	if !ok {
+		slog.Error("error obtaining user id from context")
+		return apperrors.ErrInternalServer
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" || timezone == "Local" {
+		return apperrors.ErrInvalidTimezone
+	}
+
+	err = s.userRepository.UpdateUserTimezone(ctx, nil, userId, location.String())
+	if err != nil {
+		slog.Error("failed to update user timezone", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) ValidateSession(ctx context.Context, userId int, issuedAt time.Time) (middleware.Session, error) {
+	userSession, err := s.userRepository.GetUserSession(ctx, nil, userId, issuedAt)
+	if err != nil {
+		return middleware.Session{}, err
+	}
+
+	return middleware.Session{
+		Roles:       userSession.Roles,
+		Permissions: userSession.Permissions,
+		SponsorId:   int(userSession.SponsorId.Int64),
+	}, nil
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	GithubEventHeader     = "X-GitHub-Event"
+	GithubDeliveryHeader  = "X-GitHub-Delivery"
+	GithubSignatureHeader = "X-Hub-Signature-256"
+	GithubSignaturePrefix = "sha256="
+
+	// GitHub caps webhook payloads at 25MB
+	MaxPayloadBytes = 25 << 20
+
+	PushEvent              = "push"
+	PullRequestEvent       = "pull_request"
+	IssuesEvent            = "issues"
+	IssueCommentEvent      = "issue_comment"
+	PullRequestReviewEvent = "pull_request_review"
+
+	ProcessGithubDeliveryJob = "github_webhook.process_delivery"
+)
+
+type ProcessGithubDeliveryPayload struct {
+	DeliveryId string `json:"delivery_id"`
+}
+
+type githubUser struct {
+	Id    int    `json:"id"`
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+// isBot reports whether the account is a GitHub App or other bot, whose
+// activity is never scored.
+func (u githubUser) isBot() bool {
+	return u.Type == "Bot" || isBotLogin(u.Login)
+}
+
+// push payloads only carry the commit author's login, bots are told apart
+// by the suffix GitHub gives their logins
+func isBotLogin(login string) bool {
+	return strings.HasSuffix(login, "[bot]")
+}
+
+type githubRepository struct {
+	Id              int      `json:"id"`
+	Name            string   `json:"name"`
+	FullName        string   `json:"full_name"`
+	Description     string   `json:"description"`
+	HtmlUrl         string   `json:"html_url"`
+	LanguagesUrl    string   `json:"languages_url"`
+	Language        string   `json:"language"`
+	StargazersCount int      `json:"stargazers_count"`
+	Topics          []string `json:"topics"`
+	Archived        bool     `json:"archived"`
+	License         struct {
+		SpdxId string `json:"spdx_id"`
+	} `json:"license"`
+	Owner     githubUser `json:"owner"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type eventHeader struct {
+	Action     string           `json:"action"`
+	Repository githubRepository `json:"repository"`
+	Sender     githubUser       `json:"sender"`
+}
+
+type pushPayload struct {
+	Commits []struct {
+		Id       string `json:"id"`
+		Distinct bool   `json:"distinct"`
+		Author   struct {
+			Username string `json:"username"`
+		} `json:"author"`
+	} `json:"commits"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type pullRequest struct {
+	Id        int           `json:"id"`
+	Title     string        `json:"title"`
+	HtmlUrl   string        `json:"html_url"`
+	User      githubUser    `json:"user"`
+	Draft     bool          `json:"draft"`
+	Merged    bool          `json:"merged"`
+	MergedBy  githubUser    `json:"merged_by"`
+	Additions int           `json:"additions"`
+	Deletions int           `json:"deletions"`
+	Labels    []githubLabel `json:"labels"`
+	CreatedAt time.Time     `json:"created_at"`
+	MergedAt  time.Time     `json:"merged_at"`
+}
+
+type pullRequestPayload struct {
+	PullRequest pullRequest `json:"pull_request"`
+}
+
+type issuesPayload struct {
+	Issue struct {
+		Id        int           `json:"id"`
+		User      githubUser    `json:"user"`
+		CreatedAt time.Time     `json:"created_at"`
+		Labels    []githubLabel `json:"labels"`
+	} `json:"issue"`
+}
+
+type issueCommentPayload struct {
+	Comment struct {
+		Id        int        `json:"id"`
+		User      githubUser `json:"user"`
+		CreatedAt time.Time  `json:"created_at"`
+	} `json:"comment"`
+}
+
+type pullRequestReviewPayload struct {
+	Review struct {
+		Id          int        `json:"id"`
+		User        githubUser `json:"user"`
+		SubmittedAt time.Time  `json:"submitted_at"`
+	} `json:"review"`
+	PullRequest pullRequest `json:"pull_request"`
+}
+
+// eventContribution is a contribution extracted from a payload before it has
+// been matched to a registered user. Push commits only carry the author's
+// login, every other event carries the GitHub user id. LinesChanged, Title
+// and Url are only set for merged pull requests, Labels for pull requests
+// and issues.
+type eventContribution struct {
+	GithubId         int
+	GithubUsername   string
+	ContributionType string
+	ContributedAt    time.Time
+	ExternalId       string
+	CommitSha        string
+	MergedByGithubId int
+	LinesChanged     int
+	Title            string
+	Url              string
+	Labels           []string
+}
+
+// labels maintainers put on issues closed as spam. Triage labels such as
+// "invalid" are left out, they do not mean the issue was spam.
+var spamLabels = []string{"spam"}
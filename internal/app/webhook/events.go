@@ -0,0 +1,191 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+)
+
+// extractContributions converts a webhook payload into the contributions it
+// represents. Events and actions we do not score yield no contributions.
+// Commits are dated receivedAt, when they were pushed: their author date can
+// be weeks old on rebased or long-lived branches and fall in a month that
+// was already closed. Bots are never scored, and draft pull requests only
+// count as opened once they are ready for review.
+func extractContributions(event string, action string, payload []byte, receivedAt time.Time) ([]eventContribution, error) {
+	switch event {
+	case PushEvent:
+		var push pushPayload
+		if err := json.Unmarshal(payload, &push); err != nil {
+			return nil, err
+		}
+
+		var header eventHeader
+		if err := json.Unmarshal(payload, &header); err != nil {
+			return nil, err
+		}
+
+		var contributions []eventContribution
+		for _, commit := range push.Commits {
+			// the same commit pushed to another branch is not distinct
+			if !commit.Distinct || commit.Author.Username == "" || isBotLogin(commit.Author.Username) {
+				continue
+			}
+			contributions = append(contributions, eventContribution{
+				GithubUsername:   commit.Author.Username,
+				ContributionType: contribution.CommitPushed,
+				ContributedAt:    receivedAt,
+				ExternalId:       fmt.Sprintf("commit:%d:%s", header.Repository.Id, commit.Id),
+				CommitSha:        commit.Id,
+			})
+		}
+		return contributions, nil
+
+	case PullRequestEvent:
+		var pr pullRequestPayload
+		if err := json.Unmarshal(payload, &pr); err != nil {
+			return nil, err
+		}
+		if pr.PullRequest.User.isBot() {
+			return nil, nil
+		}
+
+		switch {
+		case action == "opened" && !pr.PullRequest.Draft:
+			return []eventContribution{{
+				GithubId:         pr.PullRequest.User.Id,
+				ContributionType: contribution.PullRequestOpened,
+				ContributedAt:    pr.PullRequest.CreatedAt,
+				ExternalId:       fmt.Sprintf("pull_request:%d:opened", pr.PullRequest.Id),
+				Labels:           labelNames(pr.PullRequest.Labels),
+			}}, nil
+		case action == "ready_for_review":
+			// the same external id as when opened, so a pull request
+			// turned back into a draft and readied again counts once
+			return []eventContribution{{
+				GithubId:         pr.PullRequest.User.Id,
+				ContributionType: contribution.PullRequestOpened,
+				ContributedAt:    receivedAt,
+				ExternalId:       fmt.Sprintf("pull_request:%d:opened", pr.PullRequest.Id),
+				Labels:           labelNames(pr.PullRequest.Labels),
+			}}, nil
+		case action == "closed" && pr.PullRequest.Merged:
+			return []eventContribution{{
+				GithubId:         pr.PullRequest.User.Id,
+				ContributionType: contribution.PullRequestMerged,
+				ContributedAt:    pr.PullRequest.MergedAt,
+				ExternalId:       fmt.Sprintf("pull_request:%d:merged", pr.PullRequest.Id),
+				MergedByGithubId: pr.PullRequest.MergedBy.Id,
+				LinesChanged:     pr.PullRequest.Additions + pr.PullRequest.Deletions,
+				Title:            pr.PullRequest.Title,
+				Url:              pr.PullRequest.HtmlUrl,
+				Labels:           labelNames(pr.PullRequest.Labels),
+			}}, nil
+		}
+		return nil, nil
+
+	case IssuesEvent:
+		if action != "opened" {
+			return nil, nil
+		}
+
+		var issue issuesPayload
+		if err := json.Unmarshal(payload, &issue); err != nil {
+			return nil, err
+		}
+		if issue.Issue.User.isBot() {
+			return nil, nil
+		}
+
+		return []eventContribution{{
+			GithubId:         issue.Issue.User.Id,
+			ContributionType: contribution.IssueOpened,
+			ContributedAt:    issue.Issue.CreatedAt,
+			ExternalId:       fmt.Sprintf("issue:%d:opened", issue.Issue.Id),
+			Labels:           labelNames(issue.Issue.Labels),
+		}}, nil
+
+	case IssueCommentEvent:
+		if action != "created" {
+			return nil, nil
+		}
+
+		var comment issueCommentPayload
+		if err := json.Unmarshal(payload, &comment); err != nil {
+			return nil, err
+		}
+		if comment.Comment.User.isBot() {
+			return nil, nil
+		}
+
+		return []eventContribution{{
+			GithubId:         comment.Comment.User.Id,
+			ContributionType: contribution.IssueComment,
+			ContributedAt:    comment.Comment.CreatedAt,
+			ExternalId:       fmt.Sprintf("issue_comment:%d", comment.Comment.Id),
+		}}, nil
+
+	case PullRequestReviewEvent:
+		if action != "submitted" {
+			return nil, nil
+		}
+
+		var review pullRequestReviewPayload
+		if err := json.Unmarshal(payload, &review); err != nil {
+			return nil, err
+		}
+
+		// reviewing your own pull request is not a contribution
+		if review.Review.User.isBot() || review.Review.User.Id == review.PullRequest.User.Id {
+			return nil, nil
+		}
+
+		return []eventContribution{{
+			GithubId:         review.Review.User.Id,
+			ContributionType: contribution.PullRequestReview,
+			ContributedAt:    review.Review.SubmittedAt,
+			ExternalId:       fmt.Sprintf("pull_request_review:%d", review.Review.Id),
+		}}, nil
+	}
+
+	return nil, nil
+}
+
+// extractSpamIssue returns the external id of the issue contribution when the
+// payload closes an issue labelled as spam.
+func extractSpamIssue(event string, action string, payload []byte) (string, error) {
+	if event != IssuesEvent || action != "closed" {
+		return "", nil
+	}
+
+	var issue issuesPayload
+	if err := json.Unmarshal(payload, &issue); err != nil {
+		return "", err
+	}
+
+	for _, label := range issue.Issue.Labels {
+		if slices.Contains(spamLabels, strings.ToLower(label.Name)) {
+			return fmt.Sprintf("issue:%d:opened", issue.Issue.Id), nil
+		}
+	}
+
+	return "", nil
+}
+
+// labelNames lowercases the label names so challenges match them regardless
+// of case.
+func labelNames(labels []githubLabel) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		name := strings.ToLower(strings.TrimSpace(label.Name))
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+)
+
+func TestExtractContributionsDatesCommitsWhenPushed(t *testing.T) {
+	// authored in a month that has already been closed, pushed today
+	payload := `{
+		"repository": {"id": 10},
+		"commits": [{"id": "abc", "distinct": true, "timestamp": "2025-03-02T10:00:00Z", "author": {"username": "octocat"}}]
+	}`
+	receivedAt := time.Date(2025, time.May, 6, 8, 30, 0, 0, time.UTC)
+
+	contributions, err := extractContributions(PushEvent, "", []byte(payload), receivedAt)
+	if err != nil {
+		t.Fatalf("extractContributions() error = %v", err)
+	}
+	if len(contributions) != 1 {
+		t.Fatalf("extractContributions() = %d contributions, want 1", len(contributions))
+	}
+	if !contributions[0].ContributedAt.Equal(receivedAt) {
+		t.Errorf("ContributedAt = %v, want the push time %v", contributions[0].ContributedAt, receivedAt)
+	}
+}
+
+func TestExtractContributions(t *testing.T) {
+	receivedAt := time.Date(2025, time.May, 6, 8, 30, 0, 0, time.UTC)
+	createdAt := time.Date(2025, time.May, 1, 9, 0, 0, 0, time.UTC)
+	mergedAt := time.Date(2025, time.May, 4, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		event   string
+		action  string
+		payload string
+		want    []eventContribution
+		wantErr bool
+	}{
+		{
+			name:   "push",
+			event:  PushEvent,
+			action: "",
+			payload: `{"repository": {"id": 10}, "commits": [
+				{"id": "abc", "distinct": true, "author": {"username": "octocat"}},
+				{"id": "def", "distinct": false, "author": {"username": "octocat"}},
+				{"id": "ghi", "distinct": true, "author": {"username": ""}},
+				{"id": "jkl", "distinct": true, "author": {"username": "dependabot[bot]"}}
+			]}`,
+			want: []eventContribution{{GithubUsername: "octocat", ContributionType: contribution.CommitPushed, ContributedAt: receivedAt, ExternalId: "commit:10:abc", CommitSha: "abc"}},
+		},
+		{
+			name:    "pull request opened",
+			event:   PullRequestEvent,
+			action:  "opened",
+			payload: `{"pull_request": {"id": 5, "user": {"id": 7}, "created_at": "2025-05-01T09:00:00Z", "labels": [{"name": "Hacktoberfest"}]}}`,
+			want:    []eventContribution{{GithubId: 7, ContributionType: contribution.PullRequestOpened, ContributedAt: createdAt, ExternalId: "pull_request:5:opened", Labels: []string{"hacktoberfest"}}},
+		},
+		{
+			name:    "draft pull request opened",
+			event:   PullRequestEvent,
+			action:  "opened",
+			payload: `{"pull_request": {"id": 5, "user": {"id": 7}, "draft": true, "created_at": "2025-05-01T09:00:00Z"}}`,
+		},
+		{
+			name:    "draft ready for review",
+			event:   PullRequestEvent,
+			action:  "ready_for_review",
+			payload: `{"pull_request": {"id": 5, "user": {"id": 7}, "created_at": "2025-05-01T09:00:00Z"}}`,
+			want:    []eventContribution{{GithubId: 7, ContributionType: contribution.PullRequestOpened, ContributedAt: receivedAt, ExternalId: "pull_request:5:opened", Labels: []string{}}},
+		},
+		{
+			name:    "pull request opened by a bot",
+			event:   PullRequestEvent,
+			action:  "opened",
+			payload: `{"pull_request": {"id": 5, "user": {"id": 8, "login": "renovate[bot]", "type": "Bot"}, "created_at": "2025-05-01T09:00:00Z"}}`,
+		},
+		{
+			name:    "pull request merged",
+			event:   PullRequestEvent,
+			action:  "closed",
+			payload: `{"pull_request": {"id": 5, "title": "Fix", "html_url": "https://github.com/o/r/pull/5", "user": {"id": 7}, "merged": true, "merged_by": {"id": 9}, "additions": 10, "deletions": 4, "merged_at": "2025-05-04T17:00:00Z"}}`,
+			want:    []eventContribution{{GithubId: 7, ContributionType: contribution.PullRequestMerged, ContributedAt: mergedAt, ExternalId: "pull_request:5:merged", MergedByGithubId: 9, LinesChanged: 14, Title: "Fix", Url: "https://github.com/o/r/pull/5", Labels: []string{}}},
+		},
+		{
+			name:    "pull request closed unmerged",
+			event:   PullRequestEvent,
+			action:  "closed",
+			payload: `{"pull_request": {"id": 5, "user": {"id": 7}, "merged": false}}`,
+		},
+		{
+			name:    "pull request edited",
+			event:   PullRequestEvent,
+			action:  "edited",
+			payload: `{"pull_request": {"id": 5, "user": {"id": 7}}}`,
+		},
+		{
+			name:    "issue opened",
+			event:   IssuesEvent,
+			action:  "opened",
+			payload: `{"issue": {"id": 3, "user": {"id": 7}, "created_at": "2025-05-01T09:00:00Z", "labels": [{"name": "Bug"}, {"name": "bug"}]}}`,
+			want:    []eventContribution{{GithubId: 7, ContributionType: contribution.IssueOpened, ContributedAt: createdAt, ExternalId: "issue:3:opened", Labels: []string{"bug"}}},
+		},
+		{
+			name:    "issue opened by a bot",
+			event:   IssuesEvent,
+			action:  "opened",
+			payload: `{"issue": {"id": 3, "user": {"id": 8, "type": "Bot"}, "created_at": "2025-05-01T09:00:00Z"}}`,
+		},
+		{
+			name:    "issue closed",
+			event:   IssuesEvent,
+			action:  "closed",
+			payload: `{"issue": {"id": 3, "user": {"id": 7}}}`,
+		},
+		{
+			name:    "issue comment",
+			event:   IssueCommentEvent,
+			action:  "created",
+			payload: `{"comment": {"id": 4, "user": {"id": 7}, "created_at": "2025-05-01T09:00:00Z"}}`,
+			want:    []eventContribution{{GithubId: 7, ContributionType: contribution.IssueComment, ContributedAt: createdAt, ExternalId: "issue_comment:4"}},
+		},
+		{
+			name:    "issue comment by a bot",
+			event:   IssueCommentEvent,
+			action:  "created",
+			payload: `{"comment": {"id": 4, "user": {"id": 8, "login": "codecov[bot]"}, "created_at": "2025-05-01T09:00:00Z"}}`,
+		},
+		{
+			name:    "issue comment edited",
+			event:   IssueCommentEvent,
+			action:  "edited",
+			payload: `{"comment": {"id": 4, "user": {"id": 7}}}`,
+		},
+		{
+			name:    "review",
+			event:   PullRequestReviewEvent,
+			action:  "submitted",
+			payload: `{"review": {"id": 6, "user": {"id": 7}, "submitted_at": "2025-05-01T09:00:00Z"}, "pull_request": {"id": 5, "user": {"id": 9}}}`,
+			want:    []eventContribution{{GithubId: 7, ContributionType: contribution.PullRequestReview, ContributedAt: createdAt, ExternalId: "pull_request_review:6"}},
+		},
+		{
+			name:    "review of own pull request",
+			event:   PullRequestReviewEvent,
+			action:  "submitted",
+			payload: `{"review": {"id": 6, "user": {"id": 7}}, "pull_request": {"id": 5, "user": {"id": 7}}}`,
+		},
+		{
+			name:    "review by a bot",
+			event:   PullRequestReviewEvent,
+			action:  "submitted",
+			payload: `{"review": {"id": 6, "user": {"id": 8, "type": "Bot"}}, "pull_request": {"id": 5, "user": {"id": 9}}}`,
+		},
+		{
+			name:    "unscored event",
+			event:   "star",
+			action:  "created",
+			payload: `{}`,
+		},
+		{
+			name:    "malformed payload",
+			event:   PullRequestEvent,
+			action:  "opened",
+			payload: `{"pull_request": [}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractContributions(tt.event, tt.action, []byte(tt.payload), receivedAt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractContributions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractContributions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractSpamIssue(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  string
+		payload string
+		want    string
+	}{
+		{name: "closed as spam", action: "closed", payload: `{"issue": {"id": 3, "labels": [{"name": "Spam"}]}}`, want: "issue:3:opened"},
+		{name: "closed as invalid", action: "closed", payload: `{"issue": {"id": 3, "labels": [{"name": "invalid"}]}}`},
+		{name: "closed without labels", action: "closed", payload: `{"issue": {"id": 3}}`},
+		{name: "labelled spam but still open", action: "labeled", payload: `{"issue": {"id": 3, "labels": [{"name": "spam"}]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractSpamIssue(IssuesEvent, tt.action, []byte(tt.payload))
+			if err != nil {
+				t.Fatalf("extractSpamIssue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("extractSpamIssue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/response"
+)
+
+type handler struct {
+	webhookService Service
+}
+
+type Handler interface {
+	ReceiveGithubWebhook(w http.ResponseWriter, r *http.Request)
+	ReplayGithubDelivery(w http.ResponseWriter, r *http.Request)
+}
+
+func NewHandler(webhookService Service) Handler {
+	return &handler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *handler) ReceiveGithubWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	event := r.Header.Get(GithubEventHeader)
+	deliveryId := r.Header.Get(GithubDeliveryHeader)
+	if event == "" || deliveryId == "" {
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxPayloadBytes))
+	if err != nil {
+		slog.Error("failed to read webhook payload", "error", err)
+		response.WriteJson(w, http.StatusBadRequest, apperrors.ErrInvalidRequestBody.Error(), nil)
+		return
+	}
+
+	err = h.webhookService.ReceiveGithubDelivery(ctx, deliveryId, event, r.Header.Get(GithubSignatureHeader), payload)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrWebhookDeliveryAlreadyReceived) {
+			response.WriteJson(w, http.StatusOK, "delivery already received", nil)
+			return
+		}
+		slog.Error("failed to receive github webhook", "delivery_id", deliveryId, "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusAccepted, "delivery accepted", nil)
+}
+
+func (h *handler) ReplayGithubDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deliveryId := r.PathValue("deliveryId")
+
+	err := h.webhookService.ReplayGithubDelivery(ctx, deliveryId)
+	if err != nil {
+		slog.Error("failed to replay github delivery", "error", err)
+		status, errorMessage := apperrors.MapError(err)
+		response.WriteJson(w, status, errorMessage, nil)
+		return
+	}
+
+	response.WriteJson(w, http.StatusAccepted, "delivery queued for replay", nil)
+}
@@ -0,0 +1,290 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/fraud"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/job"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/repo"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/user"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+type service struct {
+	webhookDeliveryRepository repository.WebhookDeliveryRepository
+	userService               user.Service
+	repoService               repo.Service
+	contributionService       contribution.Service
+	fraudService              fraud.Service
+	jobService                job.Service
+	appCfg                    config.AppConfig
+}
+
+type Service interface {
+	ReceiveGithubDelivery(ctx context.Context, deliveryId string, event string, signature string, payload []byte) error
+	ReplayGithubDelivery(ctx context.Context, deliveryId string) error
+	ProcessGithubDelivery(ctx context.Context, payload ProcessGithubDeliveryPayload) error
+}
+
+func NewService(webhookDeliveryRepository repository.WebhookDeliveryRepository, userService user.Service, repoService repo.Service, contributionService contribution.Service, fraudService fraud.Service, jobService job.Service, appCfg config.AppConfig) Service {
+	return &service{
+		webhookDeliveryRepository: webhookDeliveryRepository,
+		userService:               userService,
+		repoService:               repoService,
+		contributionService:       contributionService,
+		fraudService:              fraudService,
+		jobService:                jobService,
+		appCfg:                    appCfg,
+	}
+}
+
+// ReceiveGithubDelivery verifies and persists a delivery and queues it for
+// processing, so GitHub gets its response well within its 10s timeout.
+func (s *service) ReceiveGithubDelivery(ctx context.Context, deliveryId string, event string, signature string, payload []byte) (err error) {
+	if !s.validSignature(signature, payload) {
+		slog.Warn("rejected github webhook with invalid signature", "delivery_id", deliveryId)
+		return apperrors.ErrInvalidWebhookSignature
+	}
+
+	var header eventHeader
+	err = json.Unmarshal(payload, &header)
+	if err != nil {
+		slog.Error("failed to unmarshal webhook payload", "delivery_id", deliveryId, "error", err)
+		return apperrors.ErrInvalidRequestBody
+	}
+
+	tx, err := s.webhookDeliveryRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		txErr := s.webhookDeliveryRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	_, err = s.webhookDeliveryRepository.CreateWebhookDelivery(ctx, tx, repository.WebhookDelivery{
+		DeliveryId: deliveryId,
+		Event:      event,
+		Action:     header.Action,
+		Payload:    payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.enqueueDelivery(ctx, tx, deliveryId)
+	return err
+}
+
+func (s *service) ReplayGithubDelivery(ctx context.Context, deliveryId string) error {
+	err := s.webhookDeliveryRepository.ResetWebhookDelivery(ctx, nil, deliveryId)
+	if err != nil {
+		slog.Error("failed to replay webhook delivery", "delivery_id", deliveryId, "error", err)
+		return err
+	}
+
+	_, err = s.enqueueDelivery(ctx, nil, deliveryId)
+	if err != nil && !errors.Is(err, apperrors.ErrJobAlreadyQueued) {
+		return err
+	}
+
+	slog.Info("webhook delivery queued for replay", "delivery_id", deliveryId)
+	return nil
+}
+
+// ProcessGithubDelivery is the job handler converting a stored delivery into
+// contributions. Failures are recorded on the delivery and retried by the
+// job queue.
+func (s *service) ProcessGithubDelivery(ctx context.Context, payload ProcessGithubDeliveryPayload) error {
+	delivery, err := s.webhookDeliveryRepository.GetWebhookDeliveryByDeliveryId(ctx, nil, payload.DeliveryId)
+	if err != nil {
+		return err
+	}
+
+	err = s.webhookDeliveryRepository.MarkWebhookDeliveryProcessing(ctx, nil, delivery.Id)
+	if err != nil {
+		return err
+	}
+
+	err = s.processDelivery(ctx, delivery)
+	if err != nil {
+		s.webhookDeliveryRepository.MarkWebhookDeliveryFailed(ctx, nil, delivery.Id, err.Error())
+		return err
+	}
+
+	return s.webhookDeliveryRepository.MarkWebhookDeliveryProcessed(ctx, nil, delivery.Id)
+}
+
+func (s *service) enqueueDelivery(ctx context.Context, tx *sqlx.Tx, deliveryId string) (job.Job, error) {
+	return s.jobService.Enqueue(ctx, tx, ProcessGithubDeliveryJob, ProcessGithubDeliveryPayload{DeliveryId: deliveryId}, job.EnqueueOptions{
+		UniqueKey: "github_delivery:" + deliveryId,
+	})
+}
+
+func (s *service) processDelivery(ctx context.Context, delivery repository.WebhookDelivery) error {
+	spamIssueId, err := extractSpamIssue(delivery.Event, delivery.Action, delivery.Payload)
+	if err != nil {
+		return err
+	}
+	if spamIssueId != "" {
+		return s.fraudService.ReportSpamIssue(ctx, spamIssueId)
+	}
+
+	eventContributions, err := extractContributions(delivery.Event, delivery.Action, delivery.Payload, delivery.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if len(eventContributions) == 0 {
+		return nil
+	}
+
+	var header eventHeader
+	err = json.Unmarshal(delivery.Payload, &header)
+	if err != nil {
+		return err
+	}
+
+	var trackedRepo repo.Repo
+	for _, eventContribution := range eventContributions {
+		contributor, err := s.findContributor(ctx, eventContribution)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrUserNotFound) {
+				continue
+			}
+			return err
+		}
+
+		// only repositories registered users contribute to are tracked
+		if trackedRepo.Id == 0 {
+			trackedRepo, err = s.repoService.UpsertRepo(ctx, repo.Repo{
+				GithubRepoId: header.Repository.Id,
+				RepoName:     header.Repository.Name,
+				Description:  header.Repository.Description,
+				LanguagesUrl: header.Repository.LanguagesUrl,
+				RepoUrl:      header.Repository.HtmlUrl,
+				OwnerName:    header.Repository.Owner.Login,
+				UpdateDate:   header.Repository.UpdatedAt,
+				Language:     header.Repository.Language,
+				Stars:        header.Repository.StargazersCount,
+				Topics:       header.Repository.Topics,
+				License:      header.Repository.License.SpdxId,
+				IsArchived:   header.Repository.Archived,
+			})
+			if err != nil {
+				return err
+			}
+
+			scorable, err := s.repoService.IsScorable(ctx, trackedRepo)
+			if err != nil {
+				return err
+			}
+			if !scorable {
+				slog.Info("ignoring contributions to excluded repository", "repository", header.Repository.FullName, "delivery_id", delivery.DeliveryId)
+				return nil
+			}
+		}
+
+		candidate := fraud.Candidate{
+			UserId:             contributor.Id,
+			RepositoryId:       trackedRepo.Id,
+			RepositoryFullName: header.Repository.FullName,
+			RepositoryStars:    header.Repository.StargazersCount,
+			ContributionType:   eventContribution.ContributionType,
+			ExternalId:         eventContribution.ExternalId,
+			DeliveryId:         delivery.DeliveryId,
+			ContributedAt:      eventContribution.ContributedAt,
+			CommitSha:          eventContribution.CommitSha,
+			AuthorGithubId:     contributor.GithubId,
+			MergedByGithubId:   eventContribution.MergedByGithubId,
+		}
+
+		assessment, err := s.fraudService.Screen(ctx, candidate)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordContribution(ctx, candidate, assessment, contribution.CreateContributionRequest{
+			UserId:           contributor.Id,
+			RepositoryId:     trackedRepo.Id,
+			ContributionType: eventContribution.ContributionType,
+			ContributedAt:    eventContribution.ContributedAt,
+			ExternalId:       eventContribution.ExternalId,
+			DeliveryId:       delivery.DeliveryId,
+			LinesChanged:     eventContribution.LinesChanged,
+			Title:            eventContribution.Title,
+			Url:              eventContribution.Url,
+			Labels:           eventContribution.Labels,
+		})
+		if errors.Is(err, apperrors.ErrContributionAlreadyRecorded) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordContribution records a screened contribution together with its
+// assessment, so a contribution is never kept without its findings. A
+// discarded contribution only records the assessment.
+func (s *service) recordContribution(ctx context.Context, candidate fraud.Candidate, assessment fraud.Assessment, contributionInfo contribution.CreateContributionRequest) (err error) {
+	tx, err := s.webhookDeliveryRepository.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		txErr := s.webhookDeliveryRepository.HandleTransaction(ctx, tx, err)
+		if txErr != nil {
+			err = txErr
+		}
+	}()
+
+	if assessment.Discarded() {
+		return s.fraudService.RecordAssessment(ctx, tx, candidate, 0, assessment)
+	}
+
+	created, err := s.contributionService.CreateContribution(ctx, tx, contributionInfo)
+	if err != nil {
+		return err
+	}
+
+	return s.fraudService.RecordAssessment(ctx, tx, candidate, created.Id, assessment)
+}
+
+func (s *service) findContributor(ctx context.Context, eventContribution eventContribution) (user.User, error) {
+	if eventContribution.GithubId != 0 {
+		return s.userService.GetUserByGithubId(ctx, eventContribution.GithubId)
+	}
+	return s.userService.GetUserByGithubUsername(ctx, eventContribution.GithubUsername)
+}
+
+func (s *service) validSignature(signature string, payload []byte) bool {
+	if !strings.HasPrefix(signature, GithubSignaturePrefix) {
+		return false
+	}
+
+	received, err := hex.DecodeString(strings.TrimPrefix(signature, GithubSignaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.appCfg.GithubWebhook.Secret))
+	mac.Write(payload)
+
+	return hmac.Equal(received, mac.Sum(nil))
+}
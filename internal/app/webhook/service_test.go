@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+)
+
+func TestValidSignature(t *testing.T) {
+	payload := []byte(`{"action":"opened"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(payload)
+	signed := GithubSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name      string
+		signature string
+		payload   []byte
+		want      bool
+	}{
+		{name: "valid", signature: signed, payload: payload, want: true},
+		{name: "missing", signature: "", payload: payload},
+		{name: "missing prefix", signature: hex.EncodeToString(mac.Sum(nil)), payload: payload},
+		{name: "sha1 signature", signature: "sha1=" + hex.EncodeToString(mac.Sum(nil)), payload: payload},
+		{name: "not hex", signature: GithubSignaturePrefix + "zz", payload: payload},
+		{name: "truncated", signature: signed[:len(signed)-2], payload: payload},
+		{name: "tampered payload", signature: signed, payload: []byte(`{"action":"closed"}`)},
+		{name: "other secret", signature: GithubSignaturePrefix + hex.EncodeToString(hmac.New(sha256.New, []byte("other")).Sum(nil)), payload: payload},
+	}
+
+	s := &service{appCfg: config.AppConfig{GithubWebhook: config.GithubWebhook{Secret: "secret"}}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.validSignature(tt.signature, tt.payload); got != tt.want {
+				t.Errorf("validSignature(%q) = %v, want %v", tt.signature, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
@@ -12,11 +13,12 @@ type HTTPServer struct {
 }
 
 type Database struct {
-	Host     string `yaml:"host" required:"true"`
-	Port     int    `yaml:"port" required:"true"`
-	User     string `yaml:"user" required:"true"`
-	Password string `yaml:"password" required:"true"`
-	Name     string `yaml:"name" required:"true"`
+	Host        string `yaml:"host" required:"true"`
+	Port        int    `yaml:"port" required:"true"`
+	User        string `yaml:"user" required:"true"`
+	Password    string `yaml:"password" required:"true"`
+	Name        string `yaml:"name" required:"true"`
+	AutoMigrate bool   `yaml:"auto_migrate"`
 }
 
 type GithubOauth struct {
@@ -25,13 +27,145 @@ type GithubOauth struct {
 	RedirectURL  string `yaml:"redirect_url" required:"true"`
 }
 
+type GithubApp struct {
+	AppID          int64  `yaml:"app_id"`
+	InstallationID int64  `yaml:"installation_id"`
+	PrivateKeyPath string `yaml:"private_key_path"`
+}
+
+type GithubWebhook struct {
+	Secret string `yaml:"secret" required:"true"`
+}
+
+type Encryption struct {
+	ActiveKeyID string            `yaml:"active_key_id"`
+	Keys        map[string]string `yaml:"keys"`
+}
+
+type Jobs struct {
+	Concurrency int `yaml:"concurrency" env-default:"4"`
+}
+
+type ScheduledTask struct {
+	Cron    string `yaml:"cron"`
+	CatchUp string `yaml:"catch_up" env-default:"skip"`
+}
+
+type Scheduler struct {
+	Tasks map[string]ScheduledTask `yaml:"tasks"`
+}
+
+// defaultScheduledTasks are used for tasks missing from scheduler.tasks. A
+// task listed with an empty cron is left unscheduled. Cron expressions are
+// evaluated in UTC.
+var defaultScheduledTasks = map[string]ScheduledTask{
+	// hourly, on the hour
+	"leaderboard_refresh": {Cron: "0 * * * *", CatchUp: "skip"},
+	// just after each month ends; every missed month is closed in order
+	"month_close": {Cron: "5 0 1 * *", CatchUp: "all"},
+}
+
+func (s *Scheduler) applyDefaults() {
+	if s.Tasks == nil {
+		s.Tasks = make(map[string]ScheduledTask, len(defaultScheduledTasks))
+	}
+
+	for name, task := range defaultScheduledTasks {
+		if _, ok := s.Tasks[name]; !ok {
+			s.Tasks[name] = task
+		}
+	}
+}
+
+type Accounts struct {
+	DeletionGracePeriod time.Duration `yaml:"deletion_grace_period" env-default:"720h"`
+}
+
+type Fraud struct {
+	BlockThreshold int           `yaml:"block_threshold" env-default:"100"`
+	BurstLimit     int           `yaml:"burst_limit" env-default:"30"`
+	BurstWindow    time.Duration `yaml:"burst_window" env-default:"1h"`
+}
+
+type Repositories struct {
+	AllowListOnly bool `yaml:"allow_list_only"`
+	SyncBatchSize int  `yaml:"sync_batch_size" env-default:"100"`
+}
+
+type Redemptions struct {
+	MinimumPoints     int  `yaml:"minimum_points" env-default:"100"`
+	QueueWhenUnfunded bool `yaml:"queue_when_unfunded" env-default:"true"`
+	FundingBatchSize  int  `yaml:"funding_batch_size" env-default:"100"`
+}
+
+type Sponsors struct {
+	InvitationTTL time.Duration `yaml:"invitation_ttl" env-default:"168h"`
+}
+
+type Mail struct {
+	Driver       string `yaml:"driver"`
+	From         string `yaml:"from" env-default:"CodeCuriosity <no-reply@codecuriosity.org>"`
+	SMTPHost     string `yaml:"smtp_host"`
+	SMTPPort     int    `yaml:"smtp_port" env-default:"587"`
+	SMTPUsername string `yaml:"smtp_username"`
+	SMTPPassword string `yaml:"smtp_password"`
+	FileDir      string `yaml:"file_dir" env-default:"tmp/mail"`
+}
+
+type Notifications struct {
+	EmailMaxAttempts int `yaml:"email_max_attempts" env-default:"8"`
+}
+
+type Integrations struct {
+	RequestTimeout time.Duration `yaml:"request_timeout" env-default:"10s"`
+	MaxAttempts    int           `yaml:"max_attempts" env-default:"8"`
+}
+
+type Events struct {
+	MaxAttempts int           `yaml:"max_attempts" env-default:"10"`
+	Retention   time.Duration `yaml:"retention" env-default:"168h"`
+}
+
+type Streaks struct {
+	FreezeTokenPrice int `yaml:"freeze_token_price" env-default:"50"`
+	MaxFreezeTokens  int `yaml:"max_freeze_tokens" env-default:"3"`
+}
+
+type Challenges struct {
+	FinalizeDelay time.Duration `yaml:"finalize_delay" env-default:"1h"`
+}
+
+type Judging struct {
+	LinesThreshold       int `yaml:"lines_threshold" env-default:"500"`
+	JudgesPerReview      int `yaml:"judges_per_review" env-default:"3"`
+	PointsPerRubricPoint int `yaml:"points_per_rubric_point" env-default:"10"`
+	QueueSize            int `yaml:"queue_size" env-default:"10"`
+}
+
 type AppConfig struct {
-	IsProduction bool        `yaml:"is_production"`
-	HTTPServer   HTTPServer  `yaml:"http_server"`
-	Database     Database    `yaml:"database"`
-	JWTSecret    string      `yaml:"jwt_secret"`
-	ClientURL    string      `yaml:"client_url"`
-	GithubOauth  GithubOauth `yaml:"github_oauth"`
+	IsProduction  bool          `yaml:"is_production"`
+	HTTPServer    HTTPServer    `yaml:"http_server"`
+	Database      Database      `yaml:"database"`
+	JWTSecret     string        `yaml:"jwt_secret"`
+	ClientURL     string        `yaml:"client_url"`
+	GithubOauth   GithubOauth   `yaml:"github_oauth"`
+	GithubApp     GithubApp     `yaml:"github_app"`
+	GithubWebhook GithubWebhook `yaml:"github_webhook"`
+	Encryption    Encryption    `yaml:"encryption"`
+	Jobs          Jobs          `yaml:"jobs"`
+	Scheduler     Scheduler     `yaml:"scheduler"`
+	Accounts      Accounts      `yaml:"accounts"`
+	Fraud         Fraud         `yaml:"fraud"`
+	Repositories  Repositories  `yaml:"repositories"`
+	Redemptions   Redemptions   `yaml:"redemptions"`
+	Sponsors      Sponsors      `yaml:"sponsors"`
+	Mail          Mail          `yaml:"mail"`
+	Notifications Notifications `yaml:"notifications"`
+	Integrations  Integrations  `yaml:"integrations"`
+	Events        Events        `yaml:"events"`
+	Streaks       Streaks       `yaml:"streaks"`
+	Challenges    Challenges    `yaml:"challenges"`
+	Judging       Judging       `yaml:"judging"`
 }
 
 func LoadAppConfig() (AppConfig, error) {
@@ -49,6 +183,7 @@ func LoadAppConfig() (AppConfig, error) {
 	if err := cleanenv.ReadConfig(appConfigPath, &appCfg); err != nil {
 		return AppConfig{}, apperrors.ErrFailedToLoadAppConfig
 	}
+	appCfg.Scheduler.applyDefaults()
 
 	return appCfg, nil
 }
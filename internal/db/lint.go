@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/badge"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/challenge"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/contribution"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/dispute"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/events"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/fraud"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/goal"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/integration"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/judging"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/redemption"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/repo"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/sponsor"
+	"github.com/joshsoftware/code-curiosity-2025/internal/app/team"
+)
+
+const (
+	// foreign keys whose columns are not the leading columns of any index
+	unindexedForeignKeysQuery = `
+	SELECT c.conrelid::regclass::text, c.conname
+	from pg_constraint c
+	where c.contype='f'
+	and c.connamespace='public'::regnamespace
+	and not exists (
+		SELECT 1 from pg_index i
+		where i.indrelid=c.conrelid
+		and array_to_string((i.indkey::int2[])[0:array_length(c.conkey, 1)-1], ',')=array_to_string(c.conkey, ',')
+	)
+	order by 1, 2`
+
+	getCheckConstraintQuery = "SELECT pg_get_constraintdef(oid) from pg_constraint where conname=$1 and contype='c'"
+)
+
+// enumConstraints maps check constraints to the Go constants they enforce
+var enumConstraints = map[string][]string{
+	"contributions_contribution_type_check":         contribution.ContributionTypes,
+	"contribution_score_contribution_type_check":    contribution.ContributionTypes,
+	"badges_badge_type_check":                       badge.BadgeTypes,
+	"goal_level_check":                              goal.Levels,
+	"contribution_disputes_status_check":            dispute.Statuses,
+	"contribution_flags_action_check":               fraud.Actions,
+	"contribution_flags_status_check":               fraud.FlagStatuses,
+	"repository_access_rules_access_check":          repo.Accesses,
+	"redemptions_status_check":                      redemption.Statuses,
+	"redemptions_store_check":                       redemption.Stores,
+	"sponsor_budget_entries_entry_type_check":       sponsor.EntryTypes,
+	"integration_deliveries_status_check":           integration.DeliveryStatuses,
+	"event_outbox_status_check":                     events.OutboxStatuses,
+	"teams_kind_check":                              team.Kinds,
+	"team_members_role_check":                       team.Roles,
+	"challenges_status_check":                       challenge.Statuses,
+	"challenge_multipliers_contribution_type_check": contribution.ContributionTypes,
+	"judging_reviews_status_check":                  judging.Statuses,
+	"judging_reviews_reason_check":                  judging.Reasons,
+}
+
+// LintSchema reports foreign keys without an index and check constraints
+// that drifted from the Go constants, one message per problem.
+func LintSchema(ctx context.Context, db *sqlx.DB) ([]string, error) {
+	var problems []string
+
+	rows, err := db.QueryContext(ctx, unindexedForeignKeysQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var table, constraint string
+		if err := rows.Scan(&table, &constraint); err != nil {
+			return nil, err
+		}
+		problems = append(problems, fmt.Sprintf("%s: foreign key %s has no index", table, constraint))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	constraints := make([]string, 0, len(enumConstraints))
+	for constraint := range enumConstraints {
+		constraints = append(constraints, constraint)
+	}
+	sort.Strings(constraints)
+
+	for _, constraint := range constraints {
+		var definition string
+		err := db.QueryRowContext(ctx, getCheckConstraintQuery, constraint).Scan(&definition)
+		if errors.Is(err, sql.ErrNoRows) {
+			problems = append(problems, fmt.Sprintf("check constraint %s is missing", constraint))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, checkConstraintProblems(constraint, definition, enumConstraints[constraint])...)
+	}
+
+	return problems, nil
+}
+
+// checkConstraintProblems lists the values a check constraint definition
+// does not allow.
+func checkConstraintProblems(constraint string, definition string, values []string) []string {
+	var problems []string
+	for _, value := range values {
+		if !strings.Contains(definition, "'"+value+"'") {
+			problems = append(problems, fmt.Sprintf("check constraint %s does not allow %q", constraint, value))
+		}
+	}
+
+	return problems
+}
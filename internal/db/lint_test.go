@@ -0,0 +1,42 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCheckConstraintProblems(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		values     []string
+		want       []string
+	}{
+		{
+			name:       "all values allowed",
+			definition: `CHECK (((level)::text = ANY ((ARRAY['Beginner'::character varying, 'Advanced'::character varying])::text[])))`,
+			values:     []string{"Beginner", "Advanced"},
+		},
+		{
+			name:       "value missing",
+			definition: `CHECK (((level)::text = ANY ((ARRAY['Beginner'::character varying])::text[])))`,
+			values:     []string{"Beginner", "Advanced"},
+			want:       []string{`check constraint goal_level_check does not allow "Advanced"`},
+		},
+		{
+			name:       "prefix of an allowed value",
+			definition: `CHECK (((level)::text = ANY ((ARRAY['Beginners'::character varying])::text[])))`,
+			values:     []string{"Beginner"},
+			want:       []string{`check constraint goal_level_check does not allow "Beginner"`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := checkConstraintProblems("goal_level_check", test.definition, test.values)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("checkConstraintProblems() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -1,134 +1,377 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
+	"sort"
 	"strconv"
+	"text/tabwriter"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/db/migrations"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+
+	usage = `usage: go run ./internal/db [flags] <command> [argument]
+
+commands:
+  up [steps]         apply all or the next <steps> pending migrations
+  down [steps]       revert all or the last <steps> applied migrations
+  goto <version>     migrate up or down to <version>
+  redo               revert and re-apply the current migration
+  force <version>    set the version without running migrations and clear the dirty flag
+  status             list applied and pending migrations with checksums
+  version            print the current version
+  lint               fail when a foreign key has no index or enum constraints drift from the code
+  create <name>      create a new pair of migration files
+
+flags:`
 )
 
 var (
-	// mainMigrationsDIR defines the directory where all migration files are located
+	// mainMigrationsDIR defines the directory new migration files are created in,
+	// all other commands read the migrations embedded in the binary
 	mainMigrationsDIR = "./internal/db/migrations"
 
-	// mainMigrationFilesPath defines path for migration files
-	mainMigrationFilesPath = "file://" + mainMigrationsDIR
+	errDirtyDatabase = errors.New("database is dirty, fix the failed migration and run 'force <version>'")
+	errLocked        = errors.New("another migration is already running")
 )
 
 // Migration used to define migrations
 type Migration struct {
 	m             *migrate.Migrate
 	directoryName string
-	filesPath     string
+	dryRun        bool
 }
 
-// InitMainDBMigrations used to initialize migrations
-func InitMainDBMigrations(config config.AppConfig) (migration Migration, er error) {
-	var dbConnection string = fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", config.Database.User, config.Database.Password, config.Database.Host, config.Database.Port, config.Database.Name)
+// MigrationFile describes one embedded migration version
+type MigrationFile struct {
+	Version  uint
+	Name     string
+	UpPath   string
+	DownPath string
+	Checksum string
+}
 
-	migration.directoryName = mainMigrationsDIR
-	migration.filesPath = mainMigrationFilesPath
+// migrationFlags holds the command line overrides for the app config
+type migrationFlags struct {
+	configPath string
+	dir        string
+	dryRun     bool
+	host       string
+	port       int
+	user       string
+	password   string
+	name       string
+}
 
-	migration.m, er = migrate.New(migration.filesPath, dbConnection)
-	// if err == migrate.ErrNoChange {
+// InitMainDBMigrations used to initialize migrations
+func InitMainDBMigrations(config config.AppConfig, directoryName string, dryRun bool) (migration Migration, err error) {
+	migration.directoryName = directoryName
+	migration.dryRun = dryRun
 
+	migration.m, err = migrations.New(config)
 	return
 }
 
-func (migration Migration) MigrationsUpAll(){
-	err := migration.m.Up()
-	if err != nil {
-		if err == migrate.ErrNoChange {
-			slog.Error("No new migrations to apply")
-			return
+// MigrationsUp used to apply all or the next steps pending migrations
+func (migration Migration) MigrationsUp(steps int) error {
+	if migration.dryRun {
+		pending, err := migration.pending()
+		if err != nil {
+			return err
 		}
-		slog.Error("An error occurred while making migrations up", "error", err)
-		return
+		if steps > 0 && steps < len(pending) {
+			pending = pending[:steps]
+		}
+		return printPlan(pending, "up")
 	}
-	slog.Info("Current migration version:", "version", migration.MigrationVersion())
-	slog.Info("Migration up completed")
+
+	var err error
+	if steps > 0 {
+		err = migration.m.Steps(steps)
+	} else {
+		err = migration.m.Up()
+	}
+
+	return migration.report(err, "Migration up completed", "No new migrations to apply")
 }
 
-func (migration Migration) MigrationsUpWithSteps(steps int){
-	if err := migration.m.Steps(steps); err != nil {
-		if err == migrate.ErrNoChange {
-			slog.Error("No new migrations to apply")
-			return
+// MigrationsDown used to revert all or the last steps applied migrations
+func (migration Migration) MigrationsDown(steps int) error {
+	if migration.dryRun {
+		applied, err := migration.applied()
+		if err != nil {
+			return err
 		}
+		reverse(applied)
+		if steps > 0 && steps < len(applied) {
+			applied = applied[:steps]
+		}
+		return printPlan(applied, "down")
+	}
 
-		slog.Error("An error occurred while making migrations up", "error", err)
-		return
-    }
+	var err error
+	if steps > 0 {
+		err = migration.m.Steps(-steps)
+	} else {
+		err = migration.m.Down()
+	}
 
-	slog.Info("Current migration version:", "version", migration.MigrationVersion())
-	slog.Info("Migration up completed")
+	return migration.report(err, "Migration down completed", "No migrations to revert")
 }
 
-// MigrationsUp used to make migrations up
-func (migration Migration) MigrationsUp(steps string) {
-	if len(steps) == 0 {
-		migration.MigrationsUpAll()
-	} else {
-		stepsCnt, err := strconv.Atoi(steps)
+// MigrationsGoto used to migrate up or down to the given version
+func (migration Migration) MigrationsGoto(version uint) error {
+	if migration.dryRun {
+		current, _, err := migration.version()
+		if err != nil {
+			return err
+		}
+
+		files, err := migration.files()
 		if err != nil {
-			slog.Error("Failed to parse steps argument to integer", "error", err)
-			return
+			return err
+		}
+
+		var plan []MigrationFile
+		if version >= current {
+			for _, file := range files {
+				if file.Version > current && file.Version <= version {
+					plan = append(plan, file)
+				}
+			}
+			return printPlan(plan, "up")
 		}
 
-		migration.MigrationsUpWithSteps(stepsCnt)
+		for _, file := range files {
+			if file.Version > version && file.Version <= current {
+				plan = append(plan, file)
+			}
+		}
+		reverse(plan)
+		return printPlan(plan, "down")
 	}
+
+	return migration.report(migration.m.Migrate(version), "Migration goto completed", "Already at requested version")
 }
 
-func (migration Migration) MigrationsDownAll() {
-	err := migration.m.Down()
+// MigrationsRedo used to revert and re-apply the current migration
+func (migration Migration) MigrationsRedo() error {
+	current, _, err := migration.version()
 	if err != nil {
-		if err == migrate.ErrNoChange {
-			slog.Info("No migrations to revert")
-			return
+		return err
+	}
+	if current == 0 {
+		return errors.New("no migration has been applied yet")
+	}
+
+	if migration.dryRun {
+		files, err := migration.files()
+		if err != nil {
+			return err
 		}
+		for _, file := range files {
+			if file.Version == current {
+				if err := printPlan([]MigrationFile{file}, "down"); err != nil {
+					return err
+				}
+				return printPlan([]MigrationFile{file}, "up")
+			}
+		}
+		return fmt.Errorf("migration file for version %d not found", current)
+	}
 
-		slog.Error("An error occurred while making migrations down", "error", err)
-		return
+	if err := migration.m.Steps(-1); err != nil {
+		return migration.report(err, "", "")
+	}
+
+	return migration.report(migration.m.Steps(1), "Migration redo completed", "")
+}
+
+// MigrationsForce used to set the version and clear the dirty flag without
+// running any migration
+func (migration Migration) MigrationsForce(version int) error {
+	if migration.dryRun {
+		fmt.Printf("-- would force version to %d and clear the dirty flag\n", version)
+		return nil
+	}
+
+	err := migration.m.Force(version)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Forced migration version", "version", version)
+	return nil
+}
+
+// MigrationsStatus used to print applied and pending migrations
+func (migration Migration) MigrationsStatus() error {
+	current, dirty, err := migration.version()
+	if err != nil {
+		return err
+	}
+
+	files, err := migration.files()
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tNAME\tSTATUS\tCHECKSUM")
+	for _, file := range files {
+		status := "pending"
+		if file.Version <= current {
+			status = "applied"
+		}
+		if file.Version == current && dirty {
+			status = "dirty"
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", file.Version, file.Name, status, file.Checksum)
 	}
-	slog.Info("Current migration version:", "version", migration.MigrationVersion())
-	slog.Info("Migration down completed")
+
+	return writer.Flush()
 }
 
-func (migration Migration) MigrationsDownWithSteps(steps int) {
-	if err := migration.m.Steps(-1 * steps); err != nil {
-		if err == migrate.ErrNoChange {
-			slog.Error("No migrations to revert")
-			return
+// report logs the outcome of a golang-migrate call and the resulting version
+func (migration Migration) report(err error, success string, noChange string) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		slog.Info(noChange)
+		return nil
+	}
+
+	var dirtyErr migrate.ErrDirty
+	if errors.As(err, &dirtyErr) {
+		slog.Error("Database is dirty", "version", dirtyErr.Version)
+		return errDirtyDatabase
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if success != "" {
+		slog.Info(success)
+	}
+	return migration.MigrationVersion()
+}
+
+// pending used to list migrations newer than the current version
+func (migration Migration) pending() ([]MigrationFile, error) {
+	current, dirty, err := migration.version()
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, errDirtyDatabase
+	}
+
+	files, err := migration.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []MigrationFile
+	for _, file := range files {
+		if file.Version > current {
+			pending = append(pending, file)
 		}
+	}
+	return pending, nil
+}
 
-		slog.Error("An error occurred while making migrations down", "error", err)
-		return
-    }
+// applied used to list migrations up to and including the current version
+func (migration Migration) applied() ([]MigrationFile, error) {
+	current, dirty, err := migration.version()
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, errDirtyDatabase
+	}
+
+	files, err := migration.files()
+	if err != nil {
+		return nil, err
+	}
 
-	slog.Info("Current migration version:", "version", migration.MigrationVersion())
-	slog.Info("Migration down completed")
+	var applied []MigrationFile
+	for _, file := range files {
+		if file.Version <= current {
+			applied = append(applied, file)
+		}
+	}
+	return applied, nil
 }
 
-// MigrationsDown used to make migrations down
-func (migration Migration) MigrationsDown(steps string) {
-	if len(steps) == 0 {
-		migration.MigrationsDownAll()
-	} else {
-		stepsCnt, err := strconv.Atoi(steps)
+// version returns 0 when no migration has been applied yet
+func (migration Migration) version() (uint, bool, error) {
+	version, dirty, err := migration.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// files used to read the embedded migration files, ordered by version
+func (migration Migration) files() ([]MigrationFile, error) {
+	entries, err := fs.ReadDir(migrations.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*MigrationFile)
+	for _, entry := range entries {
+		matches := migrations.FilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
 		if err != nil {
-			slog.Error("Failed to parse steps argument to integer", "error", err)
-			return
+			return nil, err
+		}
+
+		file, ok := byVersion[uint(version)]
+		if !ok {
+			file = &MigrationFile{Version: uint(version), Name: matches[2]}
+			byVersion[uint(version)] = file
 		}
 
-		migration.MigrationsDownWithSteps(stepsCnt)
+		path := entry.Name()
+		if matches[3] == "up" {
+			file.UpPath = path
+			file.Checksum, err = checksum(path)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			file.DownPath = path
+		}
+	}
+
+	files := make([]MigrationFile, 0, len(byVersion))
+	for _, file := range byVersion {
+		files = append(files, *file)
 	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Version < files[j].Version })
+
+	return files, nil
 }
 
 // CreateMigrationFile creates new migration files
@@ -177,7 +420,7 @@ func createFile(filename string) (err error) {
 
 // MigrationVersion prints the current migration version
 func (migration Migration) MigrationVersion() (err error) {
-	version, dirty, err := migration.m.Version()
+	version, dirty, err := migration.version()
 	if err != nil {
 		return
 	}
@@ -186,34 +429,247 @@ func (migration Migration) MigrationVersion() (err error) {
 	return
 }
 
-func main() {
-	// Setup config
+// printPlan used to print the SQL a dry run would execute
+func printPlan(files []MigrationFile, direction string) error {
+	if len(files) == 0 {
+		fmt.Println("-- nothing to run")
+		return nil
+	}
+
+	for _, file := range files {
+		path := file.UpPath
+		if direction == "down" {
+			path = file.DownPath
+		}
+		if path == "" {
+			return fmt.Errorf("missing %s migration file for version %d", direction, file.Version)
+		}
+
+		sql, err := fs.ReadFile(migrations.FS, path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("-- %d_%s (%s)\n%s\n\n", file.Version, file.Name, direction, sql)
+	}
+	return nil
+}
+
+func checksum(path string) (string, error) {
+	content, err := fs.ReadFile(migrations.FS, path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])[:12], nil
+}
+
+func reverse(files []MigrationFile) {
+	for i, j := 0, len(files)-1; i < j; i, j = i+1, j-1 {
+		files[i], files[j] = files[j], files[i]
+	}
+}
+
+// loadConfig used to read the app config and apply command line overrides
+func loadConfig(flags migrationFlags) (config.AppConfig, error) {
+	if flags.configPath != "" {
+		os.Setenv("CONFIG_PATH", flags.configPath)
+	}
+
 	cfg, err := config.LoadAppConfig()
 	if err != nil {
-		slog.Error("error loading app config", "error", err)
-		return
+		return config.AppConfig{}, err
 	}
 
-	if len(os.Args) < 2 {
-		slog.Error("Missing action argument. Use 'up' or 'down' or 'create'.")
-		os.Exit(1)
+	if flags.host != "" {
+		cfg.Database.Host = flags.host
+	}
+	if flags.port != 0 {
+		cfg.Database.Port = flags.port
+	}
+	if flags.user != "" {
+		cfg.Database.User = flags.user
+	}
+	if flags.password != "" {
+		cfg.Database.Password = flags.password
+	}
+	if flags.name != "" {
+		cfg.Database.Name = flags.name
+	}
+
+	return cfg, nil
+}
+
+func parseSteps(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
 	}
 
-	migration, err := InitMainDBMigrations(cfg)
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps <= 0 {
+		return 0, fmt.Errorf("steps must be a positive integer, got %q", args[0])
+	}
+	return steps, nil
+}
+
+func run() int {
+	var flags migrationFlags
+	flagSet := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintln(flagSet.Output(), usage)
+		flagSet.PrintDefaults()
+	}
+	flagSet.StringVar(&flags.configPath, "config", "", "path to the app config, overrides CONFIG_PATH")
+	flagSet.StringVar(&flags.dir, "dir", mainMigrationsDIR, "directory new migration files are created in")
+	flagSet.BoolVar(&flags.dryRun, "dry-run", false, "print the SQL that would run without executing it")
+	flagSet.StringVar(&flags.host, "db-host", "", "override database.host")
+	flagSet.IntVar(&flags.port, "db-port", 0, "override database.port")
+	flagSet.StringVar(&flags.user, "db-user", "", "override database.user")
+	flagSet.StringVar(&flags.password, "db-password", "", "override database.password")
+	flagSet.StringVar(&flags.name, "db-name", "", "override database.name")
+
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		return exitUsage
+	}
+
+	args := flagSet.Args()
+	if len(args) == 0 {
+		flagSet.Usage()
+		return exitUsage
+	}
+	action, args := args[0], args[1:]
+
+	if action == "create" {
+		if len(args) == 0 {
+			slog.Error("Missing migration name")
+			return exitUsage
+		}
+		err := Migration{directoryName: flags.dir}.CreateMigrationFile(args[0])
+		if err != nil {
+			slog.Error("Failed to create migration files", "error", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	// Setup config
+	cfg, err := loadConfig(flags)
 	if err != nil {
-		slog.Error("Error initializing migrations:", "Error", err.Error())
-		return
+		slog.Error("error loading app config", "error", err)
+		return exitError
+	}
+
+	migration, err := InitMainDBMigrations(cfg, flags.dir, flags.dryRun)
+	if err != nil {
+		slog.Error("Error initializing migrations", "error", err)
+		return exitError
+	}
+	defer migration.m.Close()
+
+	// status, version and dry runs only read, everything else must hold the
+	// migration lock so concurrent deploys cannot interleave
+	if action == "lint" {
+		return lint(cfg)
+	}
+
+	readOnly := flags.dryRun || action == "status" || action == "version"
+	if !readOnly {
+		db, err := config.InitDataStore(cfg)
+		if err != nil {
+			slog.Error("error initializing database", "error", err)
+			return exitError
+		}
+		defer db.Close()
+
+		ctx := context.Background()
+		lock := repository.NewAdvisoryLock(db, repository.MigrationLockKey)
+		acquired, err := lock.TryLock(ctx)
+		if err != nil {
+			slog.Error("Failed to acquire migration lock", "error", err)
+			return exitError
+		}
+		if !acquired {
+			slog.Error(errLocked.Error())
+			return exitError
+		}
+		defer lock.Unlock(ctx)
 	}
 
-	action := os.Args[1]
 	switch action {
 	case "up":
-		migration.MigrationsUp(os.Args[2])
+		steps, stepsErr := parseSteps(args)
+		if stepsErr != nil {
+			slog.Error(stepsErr.Error())
+			return exitUsage
+		}
+		err = migration.MigrationsUp(steps)
 	case "down":
-		migration.MigrationsDown(os.Args[2])
-	case "create":
-		migration.CreateMigrationFile(os.Args[2])
+		steps, stepsErr := parseSteps(args)
+		if stepsErr != nil {
+			slog.Error(stepsErr.Error())
+			return exitUsage
+		}
+		err = migration.MigrationsDown(steps)
+	case "goto", "force":
+		if len(args) == 0 {
+			slog.Error("Missing version argument")
+			return exitUsage
+		}
+		version, parseErr := strconv.ParseUint(args[0], 10, 64)
+		if parseErr != nil {
+			slog.Error("Version must be a non-negative integer", "version", args[0])
+			return exitUsage
+		}
+		if action == "goto" {
+			err = migration.MigrationsGoto(uint(version))
+		} else {
+			err = migration.MigrationsForce(int(version))
+		}
+	case "redo":
+		err = migration.MigrationsRedo()
+	case "status":
+		err = migration.MigrationsStatus()
+	case "version":
+		err = migration.MigrationVersion()
 	default:
-		slog.Info("Invalid action. Use 'up' or 'down' or 'create'.")
+		slog.Error("Invalid action", "action", action)
+		flagSet.Usage()
+		return exitUsage
+	}
+
+	if err != nil {
+		slog.Error("Migration failed", "action", action, "error", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func lint(cfg config.AppConfig) int {
+	db, err := config.InitDataStore(cfg)
+	if err != nil {
+		slog.Error("error initializing database", "error", err)
+		return exitError
+	}
+	defer db.Close()
+
+	problems, err := LintSchema(context.Background(), db)
+	if err != nil {
+		slog.Error("Schema lint failed", "error", err)
+		return exitError
 	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		slog.Error("Schema lint found problems", "count", len(problems))
+		return exitError
+	}
+
+	slog.Info("Schema lint passed")
+	return exitOK
+}
+
+func main() {
+	os.Exit(run())
 }
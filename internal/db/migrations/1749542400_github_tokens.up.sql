@@ -0,0 +1,17 @@
+CREATE TABLE "github_tokens"(
+    "id" SERIAL PRIMARY KEY,
+    "user_id" BIGINT NOT NULL UNIQUE,
+    "key_id" VARCHAR(255) NOT NULL,
+    "wrapped_key" BYTEA NOT NULL,
+    "access_token" BYTEA NOT NULL,
+    "refresh_token" BYTEA NULL,
+    "token_type" VARCHAR(255) NOT NULL,
+    "expires_at" TIMESTAMPTZ NULL,
+    "is_valid" BOOLEAN NOT NULL DEFAULT TRUE,
+    "invalidated_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE
+    "github_tokens" ADD CONSTRAINT "github_tokens_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
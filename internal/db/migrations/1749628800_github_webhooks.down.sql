@@ -0,0 +1,4 @@
+ALTER TABLE "repositories" DROP COLUMN IF EXISTS "language";
+ALTER TABLE "contributions" DROP CONSTRAINT IF EXISTS "contributions_external_id_unique";
+ALTER TABLE "contributions" DROP COLUMN IF EXISTS "external_id";
+DROP TABLE IF EXISTS "github_webhook_deliveries";
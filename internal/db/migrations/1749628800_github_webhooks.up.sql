@@ -0,0 +1,21 @@
+CREATE TABLE "github_webhook_deliveries"(
+    "id" SERIAL PRIMARY KEY,
+    "delivery_id" VARCHAR(255) NOT NULL UNIQUE,
+    "event" VARCHAR(255) NOT NULL,
+    "action" VARCHAR(255) NOT NULL DEFAULT '',
+    "payload" JSONB NOT NULL,
+    "status" VARCHAR(255) NOT NULL DEFAULT 'pending',
+    "attempts" INT NOT NULL DEFAULT 0,
+    "last_error" TEXT NULL,
+    "processed_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE
+    "contributions" ADD COLUMN "external_id" VARCHAR(255) NULL;
+ALTER TABLE
+    "contributions" ADD CONSTRAINT "contributions_external_id_unique" UNIQUE("external_id");
+
+ALTER TABLE
+    "repositories" ADD COLUMN "language" VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,19 @@
+CREATE TABLE "jobs"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "type" VARCHAR(255) NOT NULL,
+    "payload" JSONB NOT NULL DEFAULT '{}',
+    "status" VARCHAR(255) NOT NULL DEFAULT 'pending',
+    "unique_key" VARCHAR(255) NULL,
+    "attempts" INT NOT NULL DEFAULT 0,
+    "max_attempts" INT NOT NULL DEFAULT 5,
+    "run_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "locked_at" TIMESTAMPTZ NULL,
+    "locked_by" VARCHAR(255) NULL,
+    "last_error" TEXT NULL,
+    "completed_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "jobs_status_run_at_index" ON "jobs"("status", "run_at");
+CREATE UNIQUE INDEX "jobs_unique_key_active_index" ON "jobs"("unique_key") WHERE "status" IN ('pending', 'running');
@@ -0,0 +1,15 @@
+CREATE TABLE "scheduled_task_runs"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "task_name" VARCHAR(255) NOT NULL,
+    "scheduled_for" TIMESTAMPTZ NOT NULL,
+    "trigger" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(255) NOT NULL DEFAULT 'running',
+    "error" TEXT NULL,
+    "started_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "finished_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "scheduled_task_runs_task_name_scheduled_for_index" ON "scheduled_task_runs"("task_name", "scheduled_for") WHERE "trigger" <> 'manual';
+CREATE INDEX "scheduled_task_runs_task_name_started_at_index" ON "scheduled_task_runs"("task_name", "started_at");
@@ -0,0 +1,24 @@
+ALTER TABLE "goal" DROP CONSTRAINT IF EXISTS "goal_level_check";
+ALTER TABLE "badges" DROP CONSTRAINT IF EXISTS "badges_badge_type_check";
+ALTER TABLE "contribution_score" DROP CONSTRAINT IF EXISTS "contribution_score_contribution_type_check";
+ALTER TABLE "contributions" DROP CONSTRAINT IF EXISTS "contributions_contribution_type_check";
+
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_current_active_goal_id_foreign";
+
+ALTER TABLE "summary" DROP CONSTRAINT IF EXISTS "summary_user_id_month_year_unique";
+ALTER TABLE "repositories" DROP CONSTRAINT IF EXISTS "repositories_github_repo_id_unique";
+
+DROP INDEX IF EXISTS "users_current_active_goal_id_index";
+DROP INDEX IF EXISTS "goal_contribution_set_by_user_id_index";
+DROP INDEX IF EXISTS "goal_contribution_contribution_score_id_index";
+DROP INDEX IF EXISTS "goal_contribution_goal_id_index";
+DROP INDEX IF EXISTS "contribution_score_admin_id_index";
+DROP INDEX IF EXISTS "leaderboard_hourly_refreshed_at_index";
+DROP INDEX IF EXISTS "leaderboard_hourly_user_id_index";
+DROP INDEX IF EXISTS "badges_user_id_index";
+DROP INDEX IF EXISTS "summary_contribution_id_index";
+DROP INDEX IF EXISTS "transactions_contribution_id_index";
+DROP INDEX IF EXISTS "transactions_user_id_index";
+DROP INDEX IF EXISTS "contributions_contribution_score_id_index";
+DROP INDEX IF EXISTS "contributions_repository_id_index";
+DROP INDEX IF EXISTS "contributions_user_id_index";
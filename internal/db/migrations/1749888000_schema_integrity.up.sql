@@ -0,0 +1,89 @@
+CREATE INDEX "contributions_user_id_index" ON "contributions"("user_id");
+CREATE INDEX "contributions_repository_id_index" ON "contributions"("repository_id");
+CREATE INDEX "contributions_contribution_score_id_index" ON "contributions"("contribution_score_id");
+CREATE INDEX "transactions_user_id_index" ON "transactions"("user_id");
+CREATE INDEX "transactions_contribution_id_index" ON "transactions"("contribution_id");
+CREATE INDEX "summary_contribution_id_index" ON "summary"("contribution_id");
+CREATE INDEX "badges_user_id_index" ON "badges"("user_id");
+CREATE INDEX "leaderboard_hourly_user_id_index" ON "leaderboard_hourly"("user_id");
+CREATE INDEX "leaderboard_hourly_refreshed_at_index" ON "leaderboard_hourly"("refreshed_at");
+CREATE INDEX "contribution_score_admin_id_index" ON "contribution_score"("admin_id");
+CREATE INDEX "goal_contribution_goal_id_index" ON "goal_contribution"("goal_id");
+CREATE INDEX "goal_contribution_contribution_score_id_index" ON "goal_contribution"("contribution_score_id");
+CREATE INDEX "goal_contribution_set_by_user_id_index" ON "goal_contribution"("set_by_user_id");
+CREATE INDEX "users_current_active_goal_id_index" ON "users"("current_active_goal_id");
+
+-- repositories were looked up before being created, so concurrent webhooks
+-- could store one GitHub repository twice; keep the oldest row
+UPDATE "contributions" c SET "repository_id"=d."keep_id"
+FROM (
+    SELECT "id", min("id") OVER (PARTITION BY "github_repo_id") AS "keep_id" FROM "repositories"
+) d
+WHERE c."repository_id"=d."id" AND d."id"<>d."keep_id";
+DELETE FROM "repositories" r USING "repositories" k
+WHERE r."github_repo_id"=k."github_repo_id" AND r."id">k."id";
+
+-- a month closed twice for a user keeps its latest summary
+DELETE FROM "summary" s USING "summary" k
+WHERE s."user_id"=k."user_id" AND s."month_year"=k."month_year" AND s."id"<k."id";
+
+ALTER TABLE
+    "repositories" ADD CONSTRAINT "repositories_github_repo_id_unique" UNIQUE("github_repo_id");
+ALTER TABLE
+    "summary" ADD CONSTRAINT "summary_user_id_month_year_unique" UNIQUE("user_id", "month_year");
+
+UPDATE "users" SET "current_active_goal_id"=NULL
+WHERE "current_active_goal_id" IS NOT NULL AND NOT EXISTS (SELECT 1 FROM "goal" g WHERE g."id"="users"."current_active_goal_id");
+
+ALTER TABLE
+    "users" ADD CONSTRAINT "users_current_active_goal_id_foreign" FOREIGN KEY("current_active_goal_id") REFERENCES "goal"("id") ON DELETE SET NULL;
+
+-- enum values only differing in case are normalized to the spelling the code uses
+UPDATE "contributions" t SET "contribution_type"=v."name"
+FROM (VALUES ('Commit'), ('PullRequestOpened'), ('PullRequestMerged'), ('PullRequestReview'), ('IssueOpened'), ('IssueComment')) v("name")
+WHERE lower(t."contribution_type")=lower(v."name") AND t."contribution_type"<>v."name";
+UPDATE "contribution_score" t SET "contribution_type"=v."name"
+FROM (VALUES ('Commit'), ('PullRequestOpened'), ('PullRequestMerged'), ('PullRequestReview'), ('IssueOpened'), ('IssueComment')) v("name")
+WHERE lower(t."contribution_type")=lower(v."name") AND t."contribution_type"<>v."name";
+UPDATE "badges" t SET "badge_type"=v."name"
+FROM (VALUES ('FirstContribution'), ('BeginnerGoal'), ('IntermediateGoal'), ('AdvancedGoal')) v("name")
+WHERE lower(t."badge_type")=lower(v."name") AND t."badge_type"<>v."name";
+UPDATE "goal" t SET "level"=v."name"
+FROM (VALUES ('Beginner'), ('Intermediate'), ('Advanced')) v("name")
+WHERE lower(t."level")=lower(v."name") AND t."level"<>v."name";
+
+-- the checks apply to new rows right away; existing rows are validated only
+-- once nothing is left that the normalization above could not fix, so a
+-- stray value is reported instead of failing the migration
+ALTER TABLE
+    "contributions" ADD CONSTRAINT "contributions_contribution_type_check" CHECK("contribution_type" IN ('Commit', 'PullRequestOpened', 'PullRequestMerged', 'PullRequestReview', 'IssueOpened', 'IssueComment')) NOT VALID;
+ALTER TABLE
+    "contribution_score" ADD CONSTRAINT "contribution_score_contribution_type_check" CHECK("contribution_type" IN ('Commit', 'PullRequestOpened', 'PullRequestMerged', 'PullRequestReview', 'IssueOpened', 'IssueComment')) NOT VALID;
+ALTER TABLE
+    "badges" ADD CONSTRAINT "badges_badge_type_check" CHECK("badge_type" IN ('FirstContribution', 'BeginnerGoal', 'IntermediateGoal', 'AdvancedGoal')) NOT VALID;
+ALTER TABLE
+    "goal" ADD CONSTRAINT "goal_level_check" CHECK("level" IN ('Beginner', 'Intermediate', 'Advanced')) NOT VALID;
+
+DO $$
+DECLARE
+    c RECORD;
+    invalid BOOLEAN;
+BEGIN
+    FOR c IN SELECT * FROM (VALUES
+        ('contributions', 'contributions_contribution_type_check', 'contribution_type', ARRAY['Commit', 'PullRequestOpened', 'PullRequestMerged', 'PullRequestReview', 'IssueOpened', 'IssueComment']),
+        ('contribution_score', 'contribution_score_contribution_type_check', 'contribution_type', ARRAY['Commit', 'PullRequestOpened', 'PullRequestMerged', 'PullRequestReview', 'IssueOpened', 'IssueComment']),
+        ('badges', 'badges_badge_type_check', 'badge_type', ARRAY['FirstContribution', 'BeginnerGoal', 'IntermediateGoal', 'AdvancedGoal']),
+        ('goal', 'goal_level_check', 'level', ARRAY['Beginner', 'Intermediate', 'Advanced'])
+    ) AS t("table_name", "constraint_name", "column_name", "allowed")
+    LOOP
+        EXECUTE format('SELECT EXISTS (SELECT 1 FROM %I WHERE %I <> ALL($1))', c."table_name", c."column_name")
+        INTO invalid USING c."allowed";
+
+        IF invalid THEN
+            RAISE WARNING '% has % values outside %, fix them and run ALTER TABLE % VALIDATE CONSTRAINT %',
+                c."table_name", c."column_name", c."constraint_name", c."table_name", c."constraint_name";
+        ELSE
+            EXECUTE format('ALTER TABLE %I VALIDATE CONSTRAINT %I', c."table_name", c."constraint_name");
+        END IF;
+    END LOOP;
+END $$;
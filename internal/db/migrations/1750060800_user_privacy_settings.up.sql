@@ -0,0 +1,10 @@
+CREATE TABLE "user_privacy_settings"(
+    "user_id" BIGINT PRIMARY KEY,
+    "hide_balance" BOOLEAN NOT NULL DEFAULT FALSE,
+    "hide_activity" BOOLEAN NOT NULL DEFAULT FALSE,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE
+    "user_privacy_settings" ADD CONSTRAINT "user_privacy_settings_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "contributions_user_id_contributed_at_index";
+DROP INDEX IF EXISTS "contributions_contributed_at_id_index";
+
+ALTER TABLE "contributions" DROP COLUMN IF EXISTS "bonus";
+ALTER TABLE "contributions" DROP COLUMN IF EXISTS "multiplier";
+ALTER TABLE "contributions" DROP COLUMN IF EXISTS "base_score";
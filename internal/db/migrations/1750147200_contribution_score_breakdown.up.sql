@@ -0,0 +1,11 @@
+ALTER TABLE
+    "contributions" ADD COLUMN "base_score" BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE
+    "contributions" ADD COLUMN "multiplier" NUMERIC(6, 2) NOT NULL DEFAULT 1;
+ALTER TABLE
+    "contributions" ADD COLUMN "bonus" BIGINT NOT NULL DEFAULT 0;
+
+UPDATE "contributions" SET "base_score"="balance_change";
+
+CREATE INDEX "contributions_contributed_at_id_index" ON "contributions"("contributed_at", "id");
+CREATE INDEX "contributions_user_id_contributed_at_index" ON "contributions"("user_id", "contributed_at");
@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS "contribution_scores";
+
+DROP TABLE IF EXISTS "notifications";
+DROP TABLE IF EXISTS "contribution_adjustments";
+DROP TABLE IF EXISTS "contribution_disputes";
@@ -0,0 +1,82 @@
+CREATE TABLE "contribution_disputes"(
+    "id" SERIAL PRIMARY KEY,
+    "contribution_id" BIGINT NOT NULL,
+    "user_id" BIGINT NOT NULL,
+    "reason" TEXT NOT NULL,
+    "status" VARCHAR(255) NOT NULL DEFAULT 'open',
+    "resolution_note" TEXT NULL,
+    "resolved_by" BIGINT NULL,
+    "resolved_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "contribution_disputes_contribution_id_index" ON "contribution_disputes"("contribution_id");
+CREATE UNIQUE INDEX "contribution_disputes_contribution_id_open_index" ON "contribution_disputes"("contribution_id") WHERE "status"='open';
+CREATE INDEX "contribution_disputes_user_id_index" ON "contribution_disputes"("user_id");
+CREATE INDEX "contribution_disputes_resolved_by_index" ON "contribution_disputes"("resolved_by");
+CREATE INDEX "contribution_disputes_status_index" ON "contribution_disputes"("status");
+
+CREATE TABLE "contribution_adjustments"(
+    "id" SERIAL PRIMARY KEY,
+    "contribution_id" BIGINT NOT NULL,
+    "dispute_id" BIGINT NULL,
+    "admin_id" BIGINT NOT NULL,
+    "action" VARCHAR(255) NOT NULL,
+    "previous_balance_change" BIGINT NOT NULL,
+    "new_balance_change" BIGINT NOT NULL,
+    "reason" TEXT NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "contribution_adjustments_contribution_id_index" ON "contribution_adjustments"("contribution_id", "id");
+CREATE INDEX "contribution_adjustments_dispute_id_index" ON "contribution_adjustments"("dispute_id");
+CREATE INDEX "contribution_adjustments_admin_id_index" ON "contribution_adjustments"("admin_id");
+
+CREATE TABLE "notifications"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" BIGINT NOT NULL,
+    "type" VARCHAR(255) NOT NULL,
+    "title" VARCHAR(255) NOT NULL,
+    "body" TEXT NOT NULL,
+    "data" JSONB NOT NULL DEFAULT '{}',
+    "read_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "notifications_user_id_created_at_index" ON "notifications"("user_id", "created_at");
+
+ALTER TABLE
+    "contribution_disputes" ADD CONSTRAINT "contribution_disputes_contribution_id_foreign" FOREIGN KEY("contribution_id") REFERENCES "contributions"("id");
+ALTER TABLE
+    "contribution_disputes" ADD CONSTRAINT "contribution_disputes_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+ALTER TABLE
+    "contribution_disputes" ADD CONSTRAINT "contribution_disputes_resolved_by_foreign" FOREIGN KEY("resolved_by") REFERENCES "users"("id");
+ALTER TABLE
+    "contribution_disputes" ADD CONSTRAINT "contribution_disputes_status_check" CHECK("status" IN ('open', 'voided', 'rescored', 'rejected'));
+ALTER TABLE
+    "contribution_adjustments" ADD CONSTRAINT "contribution_adjustments_contribution_id_foreign" FOREIGN KEY("contribution_id") REFERENCES "contributions"("id");
+ALTER TABLE
+    "contribution_adjustments" ADD CONSTRAINT "contribution_adjustments_dispute_id_foreign" FOREIGN KEY("dispute_id") REFERENCES "contribution_disputes"("id");
+ALTER TABLE
+    "contribution_adjustments" ADD CONSTRAINT "contribution_adjustments_admin_id_foreign" FOREIGN KEY("admin_id") REFERENCES "users"("id");
+ALTER TABLE
+    "notifications" ADD CONSTRAINT "notifications_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+
+-- contributions are never edited once recorded; the score and status in
+-- effect come from the latest adjustment, and a voided contribution is never
+-- adjusted again
+CREATE VIEW "contribution_scores" AS
+SELECT
+    c."id" AS "contribution_id",
+    COALESCE(a."new_balance_change", c."balance_change") AS "balance_change",
+    CASE WHEN a."action"='void' THEN 'voided' ELSE 'active' END AS "status"
+FROM "contributions" c
+LEFT JOIN LATERAL (
+    SELECT "action", "new_balance_change" FROM "contribution_adjustments"
+    WHERE "contribution_id"=c."id"
+    ORDER BY "id" DESC
+    LIMIT 1
+) a ON TRUE;
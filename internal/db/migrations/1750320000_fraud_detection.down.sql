@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "contributions_user_id_contributed_at_index";
+ALTER TABLE "contributions" DROP COLUMN IF EXISTS "delivery_id";
+DROP INDEX IF EXISTS "contributions_commit_sha_index";
+DROP TABLE IF EXISTS "user_risk_scores";
+DROP TABLE IF EXISTS "contribution_flags";
@@ -0,0 +1,53 @@
+CREATE TABLE "contribution_flags"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" BIGINT NOT NULL,
+    "contribution_id" BIGINT NULL,
+    "external_id" VARCHAR(255) NOT NULL,
+    "rule" VARCHAR(255) NOT NULL,
+    "action" VARCHAR(255) NOT NULL,
+    "reason" TEXT NOT NULL,
+    "risk_points" INTEGER NOT NULL DEFAULT 0,
+    "status" VARCHAR(255) NOT NULL DEFAULT 'pending',
+    "review_note" TEXT NULL,
+    "reviewed_by" BIGINT NULL,
+    "reviewed_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "contribution_flags_external_id_rule_unique" ON "contribution_flags"("external_id", "rule");
+CREATE INDEX "contribution_flags_user_id_index" ON "contribution_flags"("user_id");
+CREATE INDEX "contribution_flags_contribution_id_index" ON "contribution_flags"("contribution_id");
+CREATE INDEX "contribution_flags_reviewed_by_index" ON "contribution_flags"("reviewed_by");
+CREATE INDEX "contribution_flags_status_index" ON "contribution_flags"("status");
+
+CREATE TABLE "user_risk_scores"(
+    "user_id" BIGINT PRIMARY KEY,
+    "score" INTEGER NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "user_risk_scores_score_index" ON "user_risk_scores"("score");
+
+-- commit external ids are commit:<github repo id>:<sha>; the same sha pushed
+-- to a fork is looked up through this index
+CREATE INDEX "contributions_commit_sha_index" ON "contributions"(split_part("external_id", ':', 3)) WHERE "external_id" LIKE 'commit:%';
+
+-- the burst rule counts the commits of one push as a single contribution
+ALTER TABLE
+    "contributions" ADD COLUMN "delivery_id" VARCHAR(255) NULL;
+CREATE INDEX "contributions_user_id_contributed_at_index" ON "contributions"("user_id", "contributed_at");
+
+ALTER TABLE
+    "contribution_flags" ADD CONSTRAINT "contribution_flags_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+ALTER TABLE
+    "contribution_flags" ADD CONSTRAINT "contribution_flags_contribution_id_foreign" FOREIGN KEY("contribution_id") REFERENCES "contributions"("id");
+ALTER TABLE
+    "contribution_flags" ADD CONSTRAINT "contribution_flags_reviewed_by_foreign" FOREIGN KEY("reviewed_by") REFERENCES "users"("id");
+ALTER TABLE
+    "contribution_flags" ADD CONSTRAINT "contribution_flags_action_check" CHECK("action" IN ('flag', 'discard'));
+ALTER TABLE
+    "contribution_flags" ADD CONSTRAINT "contribution_flags_status_check" CHECK("status" IN ('pending', 'confirmed', 'dismissed'));
+ALTER TABLE
+    "user_risk_scores" ADD CONSTRAINT "user_risk_scores_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
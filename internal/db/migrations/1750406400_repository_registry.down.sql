@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "repository_access_rules";
+
+DROP INDEX IF EXISTS "repositories_synced_at_index";
+ALTER TABLE "repositories" DROP COLUMN IF EXISTS "synced_at";
+ALTER TABLE "repositories" DROP COLUMN IF EXISTS "is_archived";
+ALTER TABLE "repositories" DROP COLUMN IF EXISTS "license";
+ALTER TABLE "repositories" DROP COLUMN IF EXISTS "topics";
+ALTER TABLE "repositories" DROP COLUMN IF EXISTS "stars";
+ALTER TABLE "repositories" ALTER COLUMN "description" TYPE VARCHAR(255) USING left("description", 255);
@@ -0,0 +1,34 @@
+ALTER TABLE
+    "repositories" ALTER COLUMN "description" TYPE TEXT;
+ALTER TABLE
+    "repositories" ADD COLUMN "stars" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE
+    "repositories" ADD COLUMN "topics" TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE
+    "repositories" ADD COLUMN "license" VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE
+    "repositories" ADD COLUMN "is_archived" BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE
+    "repositories" ADD COLUMN "synced_at" TIMESTAMPTZ NULL;
+
+CREATE INDEX "repositories_synced_at_index" ON "repositories"("synced_at" NULLS FIRST);
+
+-- a rule without repo_name covers every repository of the owner
+CREATE TABLE "repository_access_rules"(
+    "id" SERIAL PRIMARY KEY,
+    "owner_name" VARCHAR(255) NOT NULL,
+    "repo_name" VARCHAR(255) NULL,
+    "access" VARCHAR(255) NOT NULL,
+    "reason" TEXT NOT NULL DEFAULT '',
+    "created_by" BIGINT NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "repository_access_rules_target_unique" ON "repository_access_rules"(lower("owner_name"), lower(COALESCE("repo_name", '')));
+CREATE INDEX "repository_access_rules_created_by_index" ON "repository_access_rules"("created_by");
+
+ALTER TABLE
+    "repository_access_rules" ADD CONSTRAINT "repository_access_rules_created_by_foreign" FOREIGN KEY("created_by") REFERENCES "users"("id");
+ALTER TABLE
+    "repository_access_rules" ADD CONSTRAINT "repository_access_rules_access_check" CHECK("access" IN ('allow', 'deny'));
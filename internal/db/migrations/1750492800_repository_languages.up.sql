@@ -0,0 +1,14 @@
+CREATE TABLE "repository_languages"(
+    "repository_id" BIGINT NOT NULL,
+    "language" VARCHAR(255) NOT NULL,
+    "bytes" BIGINT NOT NULL,
+    "share" NUMERIC(5, 4) NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("repository_id", "language")
+);
+
+CREATE INDEX "repository_languages_language_index" ON "repository_languages"(lower("language"));
+
+ALTER TABLE
+    "repository_languages" ADD CONSTRAINT "repository_languages_repository_id_foreign" FOREIGN KEY("repository_id") REFERENCES "repositories"("id");
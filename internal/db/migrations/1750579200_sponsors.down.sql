@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS "transactions_redemption_id_index";
+ALTER TABLE "transactions" DROP CONSTRAINT IF EXISTS "transactions_redemption_id_foreign";
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "redemption_id";
+DELETE FROM "transactions" WHERE "contribution_id" IS NULL;
+ALTER TABLE "transactions" ALTER COLUMN "contribution_id" SET NOT NULL;
+
+DROP TABLE IF EXISTS "sponsor_budget_entries";
+DROP TABLE IF EXISTS "redemptions";
+DROP TABLE IF EXISTS "sponsor_earmarks";
+DROP TABLE IF EXISTS "sponsors";
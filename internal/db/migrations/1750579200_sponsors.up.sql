@@ -0,0 +1,91 @@
+CREATE TABLE "sponsors"(
+    "id" SERIAL PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    "website_url" VARCHAR(255) NOT NULL DEFAULT '',
+    "points_per_usd" BIGINT NOT NULL,
+    "is_active" BOOLEAN NOT NULL DEFAULT TRUE,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "sponsors_name_unique" ON "sponsors"(lower("name"));
+
+-- a sponsor with earmarks only funds points earned in repositories of the
+-- earmarked owners, owner names are stored lower case
+CREATE TABLE "sponsor_earmarks"(
+    "sponsor_id" BIGINT NOT NULL,
+    "owner_name" VARCHAR(255) NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("sponsor_id", "owner_name")
+);
+
+CREATE TABLE "redemptions"(
+    "id" SERIAL PRIMARY KEY,
+    "user_id" BIGINT NOT NULL,
+    "sponsor_id" BIGINT NULL,
+    "points" BIGINT NOT NULL,
+    "amount_cents" BIGINT NOT NULL DEFAULT 0,
+    "store" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(255) NOT NULL,
+    "note" TEXT NOT NULL DEFAULT '',
+    "reviewed_by" BIGINT NULL,
+    "reviewed_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "redemptions_user_id_index" ON "redemptions"("user_id");
+CREATE INDEX "redemptions_sponsor_id_status_index" ON "redemptions"("sponsor_id", "status");
+CREATE INDEX "redemptions_reviewed_by_index" ON "redemptions"("reviewed_by");
+CREATE INDEX "redemptions_status_id_index" ON "redemptions"("status", "id");
+
+-- the remaining budget of a sponsor is the sum of its entries, pledges are
+-- positive and fulfilled redemptions negative
+CREATE TABLE "sponsor_budget_entries"(
+    "id" SERIAL PRIMARY KEY,
+    "sponsor_id" BIGINT NOT NULL,
+    "entry_type" VARCHAR(255) NOT NULL,
+    "amount_cents" BIGINT NOT NULL,
+    "redemption_id" BIGINT NULL,
+    "note" TEXT NOT NULL DEFAULT '',
+    "created_by" BIGINT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "sponsor_budget_entries_sponsor_id_index" ON "sponsor_budget_entries"("sponsor_id");
+CREATE UNIQUE INDEX "sponsor_budget_entries_redemption_id_unique" ON "sponsor_budget_entries"("redemption_id");
+CREATE INDEX "sponsor_budget_entries_created_by_index" ON "sponsor_budget_entries"("created_by");
+
+-- redemptions debit points without a contribution behind them
+ALTER TABLE
+    "transactions" ALTER COLUMN "contribution_id" DROP NOT NULL;
+ALTER TABLE
+    "transactions" ADD COLUMN "redemption_id" BIGINT NULL;
+
+CREATE INDEX "transactions_redemption_id_index" ON "transactions"("redemption_id");
+
+ALTER TABLE
+    "sponsors" ADD CONSTRAINT "sponsors_points_per_usd_check" CHECK("points_per_usd" > 0);
+ALTER TABLE
+    "sponsor_earmarks" ADD CONSTRAINT "sponsor_earmarks_sponsor_id_foreign" FOREIGN KEY("sponsor_id") REFERENCES "sponsors"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "redemptions" ADD CONSTRAINT "redemptions_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+ALTER TABLE
+    "redemptions" ADD CONSTRAINT "redemptions_sponsor_id_foreign" FOREIGN KEY("sponsor_id") REFERENCES "sponsors"("id");
+ALTER TABLE
+    "redemptions" ADD CONSTRAINT "redemptions_reviewed_by_foreign" FOREIGN KEY("reviewed_by") REFERENCES "users"("id");
+ALTER TABLE
+    "redemptions" ADD CONSTRAINT "redemptions_store_check" CHECK("store" IN ('github', 'amazon', 'other'));
+ALTER TABLE
+    "redemptions" ADD CONSTRAINT "redemptions_status_check" CHECK("status" IN ('queued', 'pending', 'fulfilled', 'rejected'));
+ALTER TABLE
+    "sponsor_budget_entries" ADD CONSTRAINT "sponsor_budget_entries_sponsor_id_foreign" FOREIGN KEY("sponsor_id") REFERENCES "sponsors"("id");
+ALTER TABLE
+    "sponsor_budget_entries" ADD CONSTRAINT "sponsor_budget_entries_redemption_id_foreign" FOREIGN KEY("redemption_id") REFERENCES "redemptions"("id");
+ALTER TABLE
+    "sponsor_budget_entries" ADD CONSTRAINT "sponsor_budget_entries_created_by_foreign" FOREIGN KEY("created_by") REFERENCES "users"("id");
+ALTER TABLE
+    "sponsor_budget_entries" ADD CONSTRAINT "sponsor_budget_entries_entry_type_check" CHECK("entry_type" IN ('pledge', 'redemption', 'adjustment'));
+ALTER TABLE
+    "transactions" ADD CONSTRAINT "transactions_redemption_id_foreign" FOREIGN KEY("redemption_id") REFERENCES "redemptions"("id");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "sponsor_invitations";
+
+DROP INDEX IF EXISTS "users_sponsor_id_index";
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_sponsor_id_foreign";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "sponsor_id";
@@ -0,0 +1,34 @@
+-- members of a sponsor organization get read-only access to its portal
+ALTER TABLE
+    "users" ADD COLUMN "sponsor_id" BIGINT NULL;
+
+CREATE INDEX "users_sponsor_id_index" ON "users"("sponsor_id");
+
+-- only a hash of the invitation token is stored, the token itself is
+-- handed out once when the invitation is created
+CREATE TABLE "sponsor_invitations"(
+    "id" SERIAL PRIMARY KEY,
+    "sponsor_id" BIGINT NOT NULL,
+    "github_username" VARCHAR(255) NOT NULL,
+    "token_hash" VARCHAR(64) NOT NULL,
+    "invited_by" BIGINT NOT NULL,
+    "accepted_by" BIGINT NULL,
+    "accepted_at" TIMESTAMPTZ NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "sponsor_invitations_token_hash_unique" ON "sponsor_invitations"("token_hash");
+CREATE INDEX "sponsor_invitations_sponsor_id_index" ON "sponsor_invitations"("sponsor_id");
+CREATE INDEX "sponsor_invitations_invited_by_index" ON "sponsor_invitations"("invited_by");
+CREATE INDEX "sponsor_invitations_accepted_by_index" ON "sponsor_invitations"("accepted_by");
+
+ALTER TABLE
+    "users" ADD CONSTRAINT "users_sponsor_id_foreign" FOREIGN KEY("sponsor_id") REFERENCES "sponsors"("id");
+ALTER TABLE
+    "sponsor_invitations" ADD CONSTRAINT "sponsor_invitations_sponsor_id_foreign" FOREIGN KEY("sponsor_id") REFERENCES "sponsors"("id");
+ALTER TABLE
+    "sponsor_invitations" ADD CONSTRAINT "sponsor_invitations_invited_by_foreign" FOREIGN KEY("invited_by") REFERENCES "users"("id");
+ALTER TABLE
+    "sponsor_invitations" ADD CONSTRAINT "sponsor_invitations_accepted_by_foreign" FOREIGN KEY("accepted_by") REFERENCES "users"("id");
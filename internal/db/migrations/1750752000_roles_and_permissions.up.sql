@@ -0,0 +1,81 @@
+CREATE TABLE "roles"(
+    "name" VARCHAR(255) PRIMARY KEY,
+    "description" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "permissions"(
+    "name" VARCHAR(255) PRIMARY KEY,
+    "description" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "role_permissions"(
+    "role" VARCHAR(255) NOT NULL,
+    "permission" VARCHAR(255) NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("role", "permission")
+);
+
+CREATE INDEX "role_permissions_permission_index" ON "role_permissions"("permission");
+
+-- every signed in user holds the user role without a row here
+CREATE TABLE "user_roles"(
+    "user_id" BIGINT NOT NULL,
+    "role" VARCHAR(255) NOT NULL,
+    "granted_by" BIGINT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("user_id", "role")
+);
+
+CREATE INDEX "user_roles_role_index" ON "user_roles"("role");
+CREATE INDEX "user_roles_granted_by_index" ON "user_roles"("granted_by");
+
+ALTER TABLE
+    "role_permissions" ADD CONSTRAINT "role_permissions_role_foreign" FOREIGN KEY("role") REFERENCES "roles"("name") ON DELETE CASCADE;
+ALTER TABLE
+    "role_permissions" ADD CONSTRAINT "role_permissions_permission_foreign" FOREIGN KEY("permission") REFERENCES "permissions"("name") ON DELETE CASCADE;
+ALTER TABLE
+    "user_roles" ADD CONSTRAINT "user_roles_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+ALTER TABLE
+    "user_roles" ADD CONSTRAINT "user_roles_role_foreign" FOREIGN KEY("role") REFERENCES "roles"("name") ON DELETE CASCADE;
+ALTER TABLE
+    "user_roles" ADD CONSTRAINT "user_roles_granted_by_foreign" FOREIGN KEY("granted_by") REFERENCES "users"("id");
+
+INSERT INTO "roles" ("name", "description") VALUES
+    ('admin', 'Full access to the platform'),
+    ('moderator', 'Reviews contributions, disputes and fraud flags'),
+    ('sponsor', 'Member of a sponsor organization'),
+    ('judge', 'Judges challenge submissions'),
+    ('user', 'Every signed in user');
+
+INSERT INTO "permissions" ("name", "description") VALUES
+    ('contributions:read', 'List contributions of every user'),
+    ('scores:write', 'Void and rescore contributions and resolve disputes'),
+    ('fraud:review', 'Review fraud flags and risk scores'),
+    ('users:block', 'Block and unblock users'),
+    ('repositories:manage', 'Manage repository access rules'),
+    ('sponsors:manage', 'Manage sponsors, their budgets and members'),
+    ('redemptions:approve', 'Fulfill and reject redemptions'),
+    ('jobs:manage', 'Manage background jobs, scheduled tasks and webhook deliveries'),
+    ('roles:manage', 'Grant and revoke roles'),
+    ('sponsor_portal:read', 'Read the sponsor portal of the own sponsor organization');
+
+INSERT INTO "role_permissions" ("role", "permission")
+    SELECT 'admin', "name" FROM "permissions";
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+    ('moderator', 'contributions:read'),
+    ('moderator', 'scores:write'),
+    ('moderator', 'fraud:review'),
+    ('moderator', 'users:block'),
+    ('sponsor', 'sponsor_portal:read');
+
+INSERT INTO "user_roles" ("user_id", "role")
+    SELECT "id", 'admin' FROM "users" WHERE "is_admin";
+INSERT INTO "user_roles" ("user_id", "role")
+    SELECT "id", 'sponsor' FROM "users" WHERE "sponsor_id" IS NOT NULL;
+
+ALTER TABLE "users" DROP COLUMN "is_admin";
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "notifications_user_id_unread_index";
+DROP TABLE IF EXISTS "notification_digests";
+DROP TABLE IF EXISTS "notification_preferences";
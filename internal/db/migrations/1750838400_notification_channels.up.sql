@@ -0,0 +1,25 @@
+-- a missing row means the notification type's default channels apply
+CREATE TABLE "notification_preferences"(
+    "user_id" BIGINT NOT NULL,
+    "type" VARCHAR(255) NOT NULL,
+    "in_app" BOOLEAN NOT NULL,
+    "email" BOOLEAN NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("user_id", "type")
+);
+
+-- one row per user and digest week, so a re-run never mails a digest twice
+CREATE TABLE "notification_digests"(
+    "user_id" BIGINT NOT NULL,
+    "week_start" DATE NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("user_id", "week_start")
+);
+
+CREATE INDEX "notifications_user_id_unread_index" ON "notifications"("user_id") WHERE "read_at" IS NULL;
+
+ALTER TABLE
+    "notification_preferences" ADD CONSTRAINT "notification_preferences_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "notification_digests" ADD CONSTRAINT "notification_digests_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+DELETE FROM "role_permissions" WHERE "permission" = 'integrations:manage';
+DELETE FROM "permissions" WHERE "name" = 'integrations:manage';
+
+DROP TABLE IF EXISTS "integration_deliveries";
+DROP TABLE IF EXISTS "integration_endpoints";
@@ -0,0 +1,54 @@
+-- endpoints without a user_id are registered by admins and receive events
+-- about every user
+CREATE TABLE "integration_endpoints"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" BIGINT NULL,
+    "created_by" BIGINT NOT NULL,
+    "url" TEXT NOT NULL,
+    "description" TEXT NOT NULL DEFAULT '',
+    "event_types" TEXT[] NOT NULL,
+    "key_id" VARCHAR(255) NOT NULL,
+    "wrapped_key" BYTEA NOT NULL,
+    "secret" BYTEA NOT NULL,
+    "is_active" BOOLEAN NOT NULL DEFAULT TRUE,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "integration_endpoints_user_id_index" ON "integration_endpoints"("user_id");
+CREATE INDEX "integration_endpoints_created_by_index" ON "integration_endpoints"("created_by");
+CREATE INDEX "integration_endpoints_event_types_index" ON "integration_endpoints" USING GIN("event_types");
+
+CREATE TABLE "integration_deliveries"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "endpoint_id" BIGINT NOT NULL,
+    "event_id" VARCHAR(255) NOT NULL,
+    "event_type" VARCHAR(255) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "status" VARCHAR(255) NOT NULL DEFAULT 'pending',
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "response_status" INTEGER NULL,
+    "response_body" TEXT NULL,
+    "last_error" TEXT NULL,
+    "duration_ms" INTEGER NULL,
+    "delivered_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "integration_deliveries_endpoint_id_created_at_index" ON "integration_deliveries"("endpoint_id", "created_at");
+
+ALTER TABLE
+    "integration_endpoints" ADD CONSTRAINT "integration_endpoints_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+ALTER TABLE
+    "integration_endpoints" ADD CONSTRAINT "integration_endpoints_created_by_foreign" FOREIGN KEY("created_by") REFERENCES "users"("id");
+ALTER TABLE
+    "integration_deliveries" ADD CONSTRAINT "integration_deliveries_endpoint_id_foreign" FOREIGN KEY("endpoint_id") REFERENCES "integration_endpoints"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "integration_deliveries" ADD CONSTRAINT "integration_deliveries_status_check" CHECK("status" IN ('pending', 'succeeded', 'failed'));
+
+INSERT INTO "permissions" ("name", "description") VALUES
+    ('integrations:manage', 'Manage webhook endpoints receiving events about every user');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+    ('admin', 'integrations:manage');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "badges_user_id_badge_type_unique";
+
+DROP TABLE IF EXISTS "event_outbox";
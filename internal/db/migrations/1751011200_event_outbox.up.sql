@@ -0,0 +1,28 @@
+-- one row per event and async subscriber, written in the transaction that
+-- made the change the event describes
+CREATE TABLE "event_outbox"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "event_id" VARCHAR(255) NOT NULL,
+    "event_type" VARCHAR(255) NOT NULL,
+    "subscriber" VARCHAR(255) NOT NULL,
+    "user_id" BIGINT NULL,
+    "payload" JSONB NOT NULL,
+    "occurred_at" TIMESTAMPTZ NOT NULL,
+    "status" VARCHAR(255) NOT NULL DEFAULT 'pending',
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "available_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "last_error" TEXT NULL,
+    "processed_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "event_outbox_event_id_subscriber_unique" ON "event_outbox"("event_id", "subscriber");
+CREATE INDEX "event_outbox_pending_index" ON "event_outbox"("available_at", "id") WHERE "status" = 'pending';
+CREATE INDEX "event_outbox_processed_at_index" ON "event_outbox"("processed_at") WHERE "status" = 'processed';
+
+ALTER TABLE
+    "event_outbox" ADD CONSTRAINT "event_outbox_status_check" CHECK("status" IN ('pending', 'processed', 'failed'));
+
+-- badges are awarded once per user by the badge subscriber
+CREATE UNIQUE INDEX "badges_user_id_badge_type_unique" ON "badges"("user_id", "badge_type");
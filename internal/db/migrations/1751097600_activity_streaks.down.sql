@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "streak_freezes";
+DROP TABLE IF EXISTS "streaks";
+DROP TABLE IF EXISTS "daily_activity";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "timezone";
@@ -0,0 +1,60 @@
+-- days are bucketed in the user's timezone as contributions are recorded
+ALTER TABLE
+    "users" ADD COLUMN "timezone" VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+CREATE TABLE "daily_activity"(
+    "user_id" BIGINT NOT NULL,
+    "activity_date" DATE NOT NULL,
+    "contributions" INTEGER NOT NULL DEFAULT 0,
+    "points" BIGINT NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("user_id", "activity_date")
+);
+
+CREATE TABLE "streaks"(
+    "user_id" BIGINT PRIMARY KEY,
+    "current_streak" INTEGER NOT NULL DEFAULT 0,
+    "longest_streak" INTEGER NOT NULL DEFAULT 0,
+    "last_active_date" DATE NULL,
+    "freeze_tokens" INTEGER NOT NULL DEFAULT 0,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- days a freeze token was spent on to keep a streak going
+CREATE TABLE "streak_freezes"(
+    "user_id" BIGINT NOT NULL,
+    "freeze_date" DATE NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("user_id", "freeze_date")
+);
+
+ALTER TABLE
+    "daily_activity" ADD CONSTRAINT "daily_activity_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+ALTER TABLE
+    "streaks" ADD CONSTRAINT "streaks_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+ALTER TABLE
+    "streak_freezes" ADD CONSTRAINT "streak_freezes_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+
+-- every user starts out in UTC, so existing contributions are bucketed by
+-- their UTC day; voided contributions are not activity
+INSERT INTO "daily_activity"("user_id", "activity_date", "contributions", "points")
+SELECT c."user_id", (c."contributed_at" AT TIME ZONE 'UTC')::date, count(*), COALESCE(SUM(cs."balance_change"), 0)
+FROM "contributions" c
+JOIN "contribution_scores" cs ON cs."contribution_id"=c."id"
+WHERE cs."status"<>'voided'
+GROUP BY c."user_id", (c."contributed_at" AT TIME ZONE 'UTC')::date;
+
+-- consecutive active days share the same date minus their position
+INSERT INTO "streaks"("user_id", "current_streak", "longest_streak", "last_active_date")
+SELECT "user_id", (array_agg("length" ORDER BY "run_end" DESC))[1], max("length"), max("run_end")
+FROM (
+    SELECT "user_id", count(*) AS "length", max("activity_date") AS "run_end"
+    FROM (
+        SELECT "user_id", "activity_date", "activity_date" - (ROW_NUMBER() OVER (PARTITION BY "user_id" ORDER BY "activity_date"))::int AS "run"
+        FROM "daily_activity"
+    ) days
+    GROUP BY "user_id", "run"
+) runs
+GROUP BY "user_id";
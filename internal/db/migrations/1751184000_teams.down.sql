@@ -0,0 +1,7 @@
+DELETE FROM "role_permissions" WHERE "permission" = 'teams:manage';
+DELETE FROM "permissions" WHERE "name" = 'teams:manage';
+
+DROP TABLE IF EXISTS "team_leaderboard_hourly";
+DROP TABLE IF EXISTS "team_goals";
+DROP TABLE IF EXISTS "team_members";
+DROP TABLE IF EXISTS "teams";
@@ -0,0 +1,86 @@
+-- organizations are teams created by admins for a company, anyone can
+-- create a plain team
+CREATE TABLE "teams"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "slug" VARCHAR(64) NOT NULL,
+    "name" VARCHAR(255) NOT NULL,
+    "description" TEXT NOT NULL DEFAULT '',
+    "kind" VARCHAR(255) NOT NULL DEFAULT 'team',
+    "invite_code" VARCHAR(32) NOT NULL,
+    "created_by" BIGINT NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "teams_slug_unique" ON "teams"("slug");
+CREATE UNIQUE INDEX "teams_invite_code_unique" ON "teams"("invite_code");
+CREATE INDEX "teams_created_by_index" ON "teams"("created_by");
+
+-- memberships are kept after a member leaves: contributions count towards
+-- a team only when made between joined_at and left_at
+CREATE TABLE "team_members"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "team_id" BIGINT NOT NULL,
+    "user_id" BIGINT NOT NULL,
+    "role" VARCHAR(255) NOT NULL DEFAULT 'member',
+    "joined_at" TIMESTAMPTZ NOT NULL,
+    "left_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "team_members_team_id_user_id_active_unique" ON "team_members"("team_id", "user_id") WHERE "left_at" IS NULL;
+CREATE INDEX "team_members_team_id_index" ON "team_members"("team_id");
+CREATE INDEX "team_members_user_id_index" ON "team_members"("user_id");
+
+CREATE TABLE "team_goals"(
+    "team_id" BIGINT NOT NULL,
+    "month_year" BIGINT NOT NULL,
+    "target_points" BIGINT NOT NULL,
+    "set_by" BIGINT NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("team_id", "month_year")
+);
+
+CREATE INDEX "team_goals_set_by_index" ON "team_goals"("set_by");
+
+-- refreshed together with leaderboard_hourly, ranking teams by the points
+-- their members earned for them this month
+CREATE TABLE "team_leaderboard_hourly"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "team_id" BIGINT NOT NULL,
+    "month_year" BIGINT NOT NULL,
+    "points" BIGINT NOT NULL,
+    "member_count" INTEGER NOT NULL,
+    "rank" BIGINT NOT NULL,
+    "refreshed_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "team_leaderboard_hourly_team_id_index" ON "team_leaderboard_hourly"("team_id");
+CREATE INDEX "team_leaderboard_hourly_refreshed_at_index" ON "team_leaderboard_hourly"("refreshed_at");
+
+ALTER TABLE
+    "teams" ADD CONSTRAINT "teams_created_by_foreign" FOREIGN KEY("created_by") REFERENCES "users"("id");
+ALTER TABLE
+    "teams" ADD CONSTRAINT "teams_kind_check" CHECK("kind" IN ('team', 'organization'));
+ALTER TABLE
+    "team_members" ADD CONSTRAINT "team_members_team_id_foreign" FOREIGN KEY("team_id") REFERENCES "teams"("id");
+ALTER TABLE
+    "team_members" ADD CONSTRAINT "team_members_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+ALTER TABLE
+    "team_members" ADD CONSTRAINT "team_members_role_check" CHECK("role" IN ('owner', 'admin', 'member'));
+ALTER TABLE
+    "team_goals" ADD CONSTRAINT "team_goals_team_id_foreign" FOREIGN KEY("team_id") REFERENCES "teams"("id");
+ALTER TABLE
+    "team_goals" ADD CONSTRAINT "team_goals_set_by_foreign" FOREIGN KEY("set_by") REFERENCES "users"("id");
+ALTER TABLE
+    "team_leaderboard_hourly" ADD CONSTRAINT "team_leaderboard_hourly_team_id_foreign" FOREIGN KEY("team_id") REFERENCES "teams"("id");
+
+INSERT INTO "permissions" ("name", "description") VALUES
+    ('teams:manage', 'Create organizations and manage every team');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+    ('admin', 'teams:manage');
@@ -0,0 +1,11 @@
+DELETE FROM "role_permissions" WHERE "permission" = 'challenges:manage';
+DELETE FROM "permissions" WHERE "name" = 'challenges:manage';
+
+DROP TABLE IF EXISTS "challenge_participants";
+DROP TABLE IF EXISTS "challenge_prizes";
+DROP TABLE IF EXISTS "challenge_multipliers";
+DROP TABLE IF EXISTS "challenge_repositories";
+DROP TABLE IF EXISTS "challenges";
+
+ALTER TABLE
+    "contributions" DROP COLUMN IF EXISTS "labels";
@@ -0,0 +1,110 @@
+-- labels of the pull request or issue when the contribution was recorded,
+-- lowercased
+ALTER TABLE
+    "contributions" ADD COLUMN "labels" TEXT[] NOT NULL DEFAULT '{}';
+
+-- a challenge counts the contributions its participants make between
+-- starts_at and ends_at to the listed repositories, repositories tagged with
+-- one of the topics or pull requests and issues carrying one of the labels,
+-- or every contribution when none of them is set
+CREATE TABLE "challenges"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "slug" VARCHAR(64) NOT NULL,
+    "name" VARCHAR(255) NOT NULL,
+    "description" TEXT NOT NULL DEFAULT '',
+    "starts_at" TIMESTAMPTZ NOT NULL,
+    "ends_at" TIMESTAMPTZ NOT NULL,
+    "topics" TEXT[] NOT NULL DEFAULT '{}',
+    "labels" TEXT[] NOT NULL DEFAULT '{}',
+    "multiplier" NUMERIC(6, 2) NOT NULL DEFAULT 1,
+    "status" VARCHAR(255) NOT NULL DEFAULT 'open',
+    "finalized_at" TIMESTAMPTZ NULL,
+    "created_by" BIGINT NOT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "challenges_slug_unique" ON "challenges"("slug");
+CREATE INDEX "challenges_created_by_index" ON "challenges"("created_by");
+CREATE INDEX "challenges_open_ends_at_index" ON "challenges"("ends_at") WHERE "status" = 'open';
+
+CREATE TABLE "challenge_repositories"(
+    "challenge_id" BIGINT NOT NULL,
+    "repository_id" BIGINT NOT NULL,
+    PRIMARY KEY("challenge_id", "repository_id")
+);
+
+CREATE INDEX "challenge_repositories_repository_id_index" ON "challenge_repositories"("repository_id");
+
+-- overrides the challenge multiplier for one contribution type
+CREATE TABLE "challenge_multipliers"(
+    "challenge_id" BIGINT NOT NULL,
+    "contribution_type" VARCHAR(255) NOT NULL,
+    "multiplier" NUMERIC(6, 2) NOT NULL,
+    PRIMARY KEY("challenge_id", "contribution_type")
+);
+
+-- participants ranked rank_from to rank_to, inclusive, win points
+CREATE TABLE "challenge_prizes"(
+    "challenge_id" BIGINT NOT NULL,
+    "rank_from" INTEGER NOT NULL,
+    "rank_to" INTEGER NOT NULL,
+    "points" BIGINT NOT NULL,
+    PRIMARY KEY("challenge_id", "rank_from")
+);
+
+-- final_contributions, final_points, final_rank and prize_points are frozen when the challenge
+-- is finalized, transaction_id is the wallet credit for the prize
+CREATE TABLE "challenge_participants"(
+    "challenge_id" BIGINT NOT NULL,
+    "user_id" BIGINT NOT NULL,
+    "joined_at" TIMESTAMPTZ NOT NULL,
+    "final_contributions" BIGINT NULL,
+    "final_points" BIGINT NULL,
+    "final_rank" BIGINT NULL,
+    "prize_points" BIGINT NOT NULL DEFAULT 0,
+    "transaction_id" BIGINT NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("challenge_id", "user_id")
+);
+
+CREATE INDEX "challenge_participants_user_id_index" ON "challenge_participants"("user_id");
+CREATE INDEX "challenge_participants_transaction_id_index" ON "challenge_participants"("transaction_id");
+
+ALTER TABLE
+    "challenges" ADD CONSTRAINT "challenges_created_by_foreign" FOREIGN KEY("created_by") REFERENCES "users"("id");
+ALTER TABLE
+    "challenges" ADD CONSTRAINT "challenges_status_check" CHECK("status" IN ('open', 'finalized', 'cancelled'));
+ALTER TABLE
+    "challenges" ADD CONSTRAINT "challenges_window_check" CHECK("ends_at" > "starts_at");
+ALTER TABLE
+    "challenges" ADD CONSTRAINT "challenges_multiplier_check" CHECK("multiplier" > 0);
+ALTER TABLE
+    "challenge_repositories" ADD CONSTRAINT "challenge_repositories_challenge_id_foreign" FOREIGN KEY("challenge_id") REFERENCES "challenges"("id");
+ALTER TABLE
+    "challenge_repositories" ADD CONSTRAINT "challenge_repositories_repository_id_foreign" FOREIGN KEY("repository_id") REFERENCES "repositories"("id");
+ALTER TABLE
+    "challenge_multipliers" ADD CONSTRAINT "challenge_multipliers_challenge_id_foreign" FOREIGN KEY("challenge_id") REFERENCES "challenges"("id");
+ALTER TABLE
+    "challenge_multipliers" ADD CONSTRAINT "challenge_multipliers_contribution_type_check" CHECK("contribution_type" IN ('Commit', 'PullRequestOpened', 'PullRequestMerged', 'PullRequestReview', 'IssueOpened', 'IssueComment'));
+ALTER TABLE
+    "challenge_multipliers" ADD CONSTRAINT "challenge_multipliers_multiplier_check" CHECK("multiplier" >= 0);
+ALTER TABLE
+    "challenge_prizes" ADD CONSTRAINT "challenge_prizes_challenge_id_foreign" FOREIGN KEY("challenge_id") REFERENCES "challenges"("id");
+ALTER TABLE
+    "challenge_prizes" ADD CONSTRAINT "challenge_prizes_ranks_check" CHECK("rank_from" >= 1 AND "rank_to" >= "rank_from");
+ALTER TABLE
+    "challenge_prizes" ADD CONSTRAINT "challenge_prizes_points_check" CHECK("points" > 0);
+ALTER TABLE
+    "challenge_participants" ADD CONSTRAINT "challenge_participants_challenge_id_foreign" FOREIGN KEY("challenge_id") REFERENCES "challenges"("id");
+ALTER TABLE
+    "challenge_participants" ADD CONSTRAINT "challenge_participants_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id");
+ALTER TABLE
+    "challenge_participants" ADD CONSTRAINT "challenge_participants_transaction_id_foreign" FOREIGN KEY("transaction_id") REFERENCES "transactions"("id");
+
+INSERT INTO "permissions" ("name", "description") VALUES
+    ('challenges:manage', 'Create and cancel challenges');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+    ('admin', 'challenges:manage');
@@ -0,0 +1,10 @@
+DELETE FROM "role_permissions" WHERE "permission" = 'judging:review';
+DELETE FROM "permissions" WHERE "name" = 'judging:review';
+
+UPDATE "roles" SET "description" = 'Judges challenge submissions' WHERE "name" = 'judge';
+
+DROP TABLE IF EXISTS "judging_scores";
+DROP TABLE IF EXISTS "judging_assignments";
+DROP TABLE IF EXISTS "judging_reviews";
+
+ALTER TABLE "repositories" DROP COLUMN IF EXISTS "is_featured";
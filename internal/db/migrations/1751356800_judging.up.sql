@@ -0,0 +1,78 @@
+ALTER TABLE "repositories" ADD COLUMN "is_featured" BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- merged pull requests routed to judges because of their size or their
+-- repository. automated_score is the balance the contribution had when it
+-- was queued, final_score the judged balance that replaced it through
+-- adjustment_id
+CREATE TABLE "judging_reviews"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "contribution_id" BIGINT NOT NULL,
+    "reason" VARCHAR(255) NOT NULL,
+    "lines_changed" INTEGER NOT NULL DEFAULT 0,
+    "title" TEXT NOT NULL DEFAULT '',
+    "url" TEXT NOT NULL DEFAULT '',
+    "automated_score" BIGINT NOT NULL,
+    "final_score" BIGINT NULL,
+    "status" VARCHAR(255) NOT NULL DEFAULT 'pending',
+    "adjustment_id" BIGINT NULL,
+    "finalized_at" TIMESTAMPTZ NULL,
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "judging_reviews_contribution_id_unique" ON "judging_reviews"("contribution_id");
+CREATE INDEX "judging_reviews_adjustment_id_index" ON "judging_reviews"("adjustment_id");
+CREATE INDEX "judging_reviews_pending_index" ON "judging_reviews"("created_at") WHERE "status" = 'pending';
+
+-- judges take reviews from the pending queue until each review has enough
+CREATE TABLE "judging_assignments"(
+    "review_id" BIGINT NOT NULL,
+    "judge_id" BIGINT NOT NULL,
+    "assigned_at" TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY("review_id", "judge_id")
+);
+
+CREATE INDEX "judging_assignments_judge_id_index" ON "judging_assignments"("judge_id");
+
+-- one rubric score per judge, revisable until the review is finalized
+CREATE TABLE "judging_scores"(
+    "review_id" BIGINT NOT NULL,
+    "judge_id" BIGINT NOT NULL,
+    "impact" SMALLINT NOT NULL,
+    "quality" SMALLINT NOT NULL,
+    "docs" SMALLINT NOT NULL,
+    "comment" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("review_id", "judge_id")
+);
+
+CREATE INDEX "judging_scores_judge_id_index" ON "judging_scores"("judge_id");
+
+ALTER TABLE
+    "judging_reviews" ADD CONSTRAINT "judging_reviews_contribution_id_foreign" FOREIGN KEY("contribution_id") REFERENCES "contributions"("id");
+ALTER TABLE
+    "judging_reviews" ADD CONSTRAINT "judging_reviews_adjustment_id_foreign" FOREIGN KEY("adjustment_id") REFERENCES "contribution_adjustments"("id");
+ALTER TABLE
+    "judging_reviews" ADD CONSTRAINT "judging_reviews_status_check" CHECK("status" IN ('pending', 'finalized', 'cancelled'));
+ALTER TABLE
+    "judging_reviews" ADD CONSTRAINT "judging_reviews_reason_check" CHECK("reason" IN ('size', 'featured_repository'));
+ALTER TABLE
+    "judging_assignments" ADD CONSTRAINT "judging_assignments_review_id_foreign" FOREIGN KEY("review_id") REFERENCES "judging_reviews"("id");
+ALTER TABLE
+    "judging_assignments" ADD CONSTRAINT "judging_assignments_judge_id_foreign" FOREIGN KEY("judge_id") REFERENCES "users"("id");
+ALTER TABLE
+    "judging_scores" ADD CONSTRAINT "judging_scores_review_id_foreign" FOREIGN KEY("review_id") REFERENCES "judging_reviews"("id");
+ALTER TABLE
+    "judging_scores" ADD CONSTRAINT "judging_scores_judge_id_foreign" FOREIGN KEY("judge_id") REFERENCES "users"("id");
+ALTER TABLE
+    "judging_scores" ADD CONSTRAINT "judging_scores_rubric_check" CHECK("impact" BETWEEN 1 AND 5 AND "quality" BETWEEN 1 AND 5 AND "docs" BETWEEN 1 AND 5);
+
+UPDATE "roles" SET "description" = 'Scores contributions queued for judging' WHERE "name" = 'judge';
+
+INSERT INTO "permissions" ("name", "description") VALUES
+    ('judging:review', 'Take and score contributions from the judging queue');
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+    ('admin', 'judging:review'),
+    ('judge', 'judging:review');
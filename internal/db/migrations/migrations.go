@@ -0,0 +1,134 @@
+// Package migrations embeds the SQL migrations so the server and the
+// migration tool can run them without the source tree.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/joshsoftware/code-curiosity-2025/internal/repository"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+// FilePattern matches migration file names: <version>_<name>.<up|down>.sql
+var FilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// New returns a migrate instance reading the embedded migrations
+func New(appCfg config.AppConfig) (*migrate.Migrate, error) {
+	source, err := iofs.New(FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	dbConnection := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", appCfg.Database.User, appCfg.Database.Password, appCfg.Database.Host, appCfg.Database.Port, appCfg.Database.Name)
+
+	return migrate.NewWithSourceInstance("iofs", source, dbConnection)
+}
+
+// Latest returns the highest migration version embedded in the binary
+func Latest() (uint, error) {
+	entries, err := fs.ReadDir(FS, ".")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		matches := FilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+
+	return latest, nil
+}
+
+// Up applies pending migrations while holding the migration advisory lock,
+// so replicas starting together migrate once
+func Up(ctx context.Context, db *sqlx.DB, appCfg config.AppConfig) error {
+	lock := repository.NewAdvisoryLock(db, repository.MigrationLockKey)
+	err := lock.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock(ctx)
+
+	m, err := New(appCfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	err = m.Up()
+	if errors.Is(err, migrate.ErrNoChange) {
+		slog.Info("database schema is up to date")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	version, _, err := m.Version()
+	if err != nil {
+		return err
+	}
+
+	slog.Info("applied pending migrations", "version", version)
+	return nil
+}
+
+// CheckVersion returns ErrSchemaDirty or ErrSchemaBehind when the database
+// is not at the latest embedded migration
+func CheckVersion(appCfg config.AppConfig) error {
+	m, err := New(appCfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	latest, err := Latest()
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		version = 0
+	} else if err != nil {
+		return err
+	}
+
+	if dirty {
+		slog.Error("database schema is dirty", "version", version)
+		return apperrors.ErrSchemaDirty
+	}
+
+	if version < latest {
+		slog.Error("database schema is behind", "version", version, "latest", latest)
+		return apperrors.ErrSchemaBehind
+	}
+
+	return nil
+}
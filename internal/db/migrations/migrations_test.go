@@ -0,0 +1,116 @@
+package migrations
+
+import (
+	"io/fs"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var (
+	createTablePattern  = regexp.MustCompile(`(?s)CREATE TABLE (?:IF NOT EXISTS )?"(\w+)"\s*\((.*?)\n\);`)
+	inlineKeyPattern    = regexp.MustCompile(`(?m)^\s*"(\w+)"[^,\n]*\b(?:PRIMARY KEY|UNIQUE)\b`)
+	tableKeyPattern     = regexp.MustCompile(`(?:PRIMARY KEY|UNIQUE)\s*\(([^)]*)\)`)
+	createIndexPattern  = regexp.MustCompile(`CREATE (?:UNIQUE )?INDEX (?:IF NOT EXISTS )?"\w+"\s+ON\s+"(\w+)"(?:\s+USING\s+\w+)?\s*\(([^)]*)\)`)
+	alterKeyPattern     = regexp.MustCompile(`ALTER TABLE\s+"(\w+)"\s+ADD CONSTRAINT\s+"\w+"\s+(?:PRIMARY KEY|UNIQUE)\s*\(([^)]*)\)`)
+	foreignKeyPattern   = regexp.MustCompile(`ALTER TABLE\s+"(\w+)"\s+ADD CONSTRAINT\s+"(\w+)"\s+FOREIGN KEY\s*\(([^)]*)\)`)
+	quotedColumnPattern = regexp.MustCompile(`^"(\w+)"$`)
+)
+
+// TestForeignKeysAreIndexed mirrors the lint command's unindexed foreign key
+// check against the migration files, so a migration adding a foreign key
+// without an index fails before it reaches a database.
+func TestForeignKeysAreIndexed(t *testing.T) {
+	names, err := fs.Glob(FS, "*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(names)
+
+	indexes := map[string][][]string{}
+	type foreignKey struct {
+		file, table, name string
+		columns           []string
+	}
+	var foreignKeys []foreignKey
+
+	for _, name := range names {
+		content, err := fs.ReadFile(FS, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sql := string(content)
+
+		for _, table := range createTablePattern.FindAllStringSubmatch(sql, -1) {
+			for _, key := range inlineKeyPattern.FindAllStringSubmatch(table[2], -1) {
+				indexes[table[1]] = append(indexes[table[1]], []string{key[1]})
+			}
+			for _, key := range tableKeyPattern.FindAllStringSubmatch(table[2], -1) {
+				indexes[table[1]] = append(indexes[table[1]], splitColumns(key[1]))
+			}
+		}
+		for _, pattern := range []*regexp.Regexp{createIndexPattern, alterKeyPattern} {
+			for _, index := range pattern.FindAllStringSubmatch(sql, -1) {
+				indexes[index[1]] = append(indexes[index[1]], splitColumns(index[2]))
+			}
+		}
+		for _, key := range foreignKeyPattern.FindAllStringSubmatch(sql, -1) {
+			foreignKeys = append(foreignKeys, foreignKey{file: name, table: key[1], name: key[2], columns: splitColumns(key[3])})
+		}
+	}
+
+	if len(foreignKeys) == 0 {
+		t.Fatal("no foreign keys found, the migration patterns are out of date")
+	}
+
+	for _, key := range foreignKeys {
+		indexed := slices.ContainsFunc(indexes[key.table], func(columns []string) bool {
+			return len(columns) >= len(key.columns) && slices.Equal(columns[:len(key.columns)], key.columns)
+		})
+		if !indexed {
+			t.Errorf("%s: foreign key %s on %s(%s) has no index", key.file, key.name, key.table, strings.Join(key.columns, ", "))
+		}
+	}
+}
+
+func TestSplitColumns(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		want []string
+	}{
+		{name: "single column", list: `"user_id"`, want: []string{"user_id"}},
+		{name: "several columns", list: `"user_id", "month_year"`, want: []string{"user_id", "month_year"}},
+		{name: "sort order", list: `"user_id" DESC, "created_at"`, want: []string{"user_id", "created_at"}},
+		{name: "expression", list: `lower("owner_name"`, want: []string{""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := splitColumns(test.list)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("splitColumns(%q) = %q, want %q", test.list, got, test.want)
+			}
+		})
+	}
+}
+
+// splitColumns returns the column names of an index or key column list.
+// Expressions are returned as empty names, which match no foreign key.
+func splitColumns(list string) []string {
+	var columns []string
+	for _, column := range strings.Split(list, ",") {
+		fields := strings.Fields(column)
+		name := ""
+		if len(fields) > 0 {
+			if matches := quotedColumnPattern.FindStringSubmatch(fields[0]); matches != nil {
+				name = matches[1]
+			}
+		}
+		columns = append(columns, name)
+	}
+
+	return columns
+}
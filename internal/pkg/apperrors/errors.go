@@ -25,8 +25,128 @@ var (
 	ErrFailedToGetGithubUser = errors.New("failed to get Github user info")
 	ErrFailedToGetUserEmail = errors.New("failed to get user email from Github")
 
+	ErrGithubRequestFailed    = errors.New("request to Github failed")
+	ErrGithubRateLimited      = errors.New("Github rate limit exceeded")
+	ErrGithubUnauthorized     = errors.New("Github credentials are invalid or revoked")
+	ErrGithubNotFound         = errors.New("Github resource not found")
+	ErrGithubAppNotConfigured = errors.New("Github app is not configured")
+
+	ErrInvalidEncryptionKey = errors.New("invalid encryption key configuration")
+	ErrEncryptionFailed     = errors.New("failed to encrypt data")
+	ErrDecryptionFailed     = errors.New("failed to decrypt data")
+
+	ErrInvalidMailConfig = errors.New("invalid mail configuration")
+	ErrMailSendFailed    = errors.New("failed to send email")
+
+	ErrGithubTokenNotFound = errors.New("no Github token stored for user")
+	ErrGithubTokenInvalid  = errors.New("stored Github token is no longer valid")
+
 	ErrUserNotFound = errors.New("user not found")
+	ErrAccountPendingDeletion = errors.New("account was deleted and is pending anonymization")
 	ErrUserCreationFailed = errors.New("failed to create user")
+	ErrSessionRevoked     = errors.New("session has been revoked, please login again")
+	ErrUserNotRanked      = errors.New("user is not on the leaderboard")
+	ErrUserBlocked        = errors.New("account is blocked until an admin reviews it")
+
+	ErrPrivacySettingNotFound = errors.New("privacy setting not found")
+
+	ErrContributionAlreadyRecorded = errors.New("contribution already recorded")
+	ErrContributionScoreNotFound   = errors.New("no score configured for contribution type")
+	ErrRepoNotFound                = errors.New("repository not found")
+	ErrInvalidCursor               = errors.New("invalid pagination cursor")
+	ErrContributionNotFound        = errors.New("contribution not found")
+	ErrContributionVoided          = errors.New("contribution has already been voided")
+	ErrInvalidContributionScore    = errors.New("score must not be negative")
+	ErrAdjustmentExceedsBalance    = errors.New("user has already spent the points this adjustment takes back")
+
+	ErrDisputeNotFound    = errors.New("dispute not found")
+	ErrDisputeAlreadyOpen = errors.New("contribution already has an open dispute")
+	ErrDisputeNotOpen     = errors.New("dispute has already been resolved")
+
+	ErrContributionFlagExists   = errors.New("contribution already flagged by this rule")
+	ErrContributionFlagNotFound = errors.New("contribution flag not found")
+	ErrContributionFlagReviewed = errors.New("contribution flag has already been reviewed")
+
+	ErrRepositoryAccessRuleNotFound = errors.New("repository access rule not found")
+	ErrActivityHidden              = errors.New("user has hidden their activity")
+
+	ErrSponsorNotFound          = errors.New("sponsor not found")
+	ErrSponsorExists            = errors.New("a sponsor with this name already exists")
+	ErrSponsorBudgetUnavailable = errors.New("no sponsor budget is available for this redemption")
+
+	ErrSponsorInvitationNotFound = errors.New("sponsor invitation not found")
+	ErrSponsorInvitationUsed     = errors.New("sponsor invitation has expired or was already accepted")
+	ErrSponsorInvitationMismatch = errors.New("sponsor invitation was sent to another Github user")
+
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrUserRoleNotFound  = errors.New("user does not hold this role")
+	ErrRoleNotAssignable = errors.New("role cannot be granted or revoked directly")
+	ErrLastAdmin         = errors.New("the last admin cannot lose the admin role")
+
+	ErrNotificationNotFound     = errors.New("notification not found")
+	ErrNotificationDigestExists = errors.New("notification digest already sent for this week")
+	ErrUnknownNotificationType  = errors.New("unknown notification type")
+
+	ErrIntegrationEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrIntegrationDeliveryNotFound = errors.New("webhook endpoint delivery not found")
+	ErrInvalidIntegrationUrl       = errors.New("webhook url must be an absolute https url")
+	ErrIntegrationAddressNotAllowed = errors.New("webhook url must not point to a private or reserved address")
+	ErrIntegrationDeliveryFailed   = errors.New("webhook endpoint did not accept the delivery")
+
+	ErrBadgeAlreadyAwarded = errors.New("badge already awarded to user")
+
+	ErrInvalidTimezone  = errors.New("timezone must be a valid IANA timezone name")
+	ErrInvalidYear      = errors.New("year must be between 2000 and next year")
+	ErrInvalidMonth     = errors.New("month must be formatted as YYYY-MM")
+	ErrFreezeTokenLimit = errors.New("purchase would exceed the streak freeze token limit")
+
+	ErrTeamNotFound         = errors.New("team not found")
+	ErrTeamExists           = errors.New("a team with this slug already exists")
+	ErrInvalidInviteCode    = errors.New("invite code is invalid or was replaced")
+	ErrAlreadyTeamMember    = errors.New("user is already a member of this team")
+	ErrTeamMemberNotFound   = errors.New("user is not a member of this team")
+	ErrTeamOwnerCannotLeave = errors.New("transfer ownership before leaving the team")
+	ErrTeamGoalNotFound     = errors.New("no goal set for this team and month")
+
+	ErrChallengeNotFound            = errors.New("challenge not found")
+	ErrChallengeExists              = errors.New("a challenge with this slug already exists")
+	ErrInvalidChallengeWindow       = errors.New("challenge must end after it starts and not end in the past")
+	ErrChallengeClosed              = errors.New("challenge has ended or was cancelled")
+	ErrAlreadyChallengeParticipant  = errors.New("user already joined this challenge")
+	ErrChallengeParticipantNotFound = errors.New("user has not joined this challenge")
+
+	ErrJudgingReviewNotFound = errors.New("judging review not found")
+	ErrJudgingReviewClosed   = errors.New("judging review is already closed")
+	ErrInvalidRubricScore    = errors.New("rubric scores must be between 1 and 5")
+	ErrJudgingReviewUnscored = errors.New("judging review has no scores yet")
+
+	ErrInsufficientBalance = errors.New("not enough points to redeem")
+	ErrRedemptionNotFound  = errors.New("redemption not found")
+	ErrRedemptionReviewed  = errors.New("redemption has already been fulfilled or rejected")
+	ErrRedemptionNotFunded = errors.New("redemption is still waiting for sponsor budget")
+
+	ErrInvalidWebhookSignature        = errors.New("invalid webhook signature")
+	ErrWebhookDeliveryAlreadyReceived = errors.New("webhook delivery already received")
+	ErrWebhookDeliveryNotFound        = errors.New("webhook delivery not found")
+
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobAlreadyQueued  = errors.New("a job with the same unique key is already queued")
+	ErrJobNotRetryable   = errors.New("only dead or cancelled jobs can be retried")
+	ErrJobNotCancellable = errors.New("only pending jobs can be cancelled")
+	ErrUnknownJobType    = errors.New("no handler registered for job type")
+
+	ErrOutboxEventNotFound = errors.New("no pending outbox event")
+
+	ErrUnknownTask            = errors.New("unknown scheduled task")
+	ErrInvalidSchedule        = errors.New("invalid task schedule configuration")
+	ErrTaskRunAlreadyRecorded = errors.New("task run already recorded for this schedule slot")
+	ErrTaskRunNotFound        = errors.New("task run not found")
+	ErrTaskRunning            = errors.New("task is already running")
+	ErrInvalidScheduledFor    = errors.New("scheduled_for must be an RFC 3339 time that is not in the future")
+	ErrMonthNotEnded          = errors.New("month has not ended yet")
+
+	ErrSchemaDirty  = errors.New("database schema is dirty, a migration failed part way")
+	ErrSchemaBehind = errors.New("database schema is behind, run pending migrations")
 
 	ErrJWTCreationFailed = errors.New("failed to create jwt token")
 	ErrAuthorizationFailed=errors.New("failed to authorize user")
@@ -34,13 +154,15 @@ var (
 
 func MapError(err error) (statusCode int, errMessage string) {
 	switch err {
-	case ErrInvalidRequestBody, ErrInvalidQueryParams:
+	case ErrInvalidRequestBody, ErrInvalidQueryParams, ErrInvalidCursor, ErrRoleNotAssignable, ErrUnknownNotificationType, ErrInvalidIntegrationUrl, ErrIntegrationAddressNotAllowed, ErrInvalidTimezone, ErrInvalidYear, ErrInvalidMonth, ErrInvalidChallengeWindow, ErrInvalidRubricScore, ErrInvalidScheduledFor, ErrMonthNotEnded, ErrInvalidContributionScore:
 		return http.StatusBadRequest, err.Error()
-	case ErrUnauthorizedAccess:
+	case ErrJobAlreadyQueued, ErrJobNotRetryable, ErrJobNotCancellable, ErrContributionVoided, ErrDisputeAlreadyOpen, ErrDisputeNotOpen, ErrContributionFlagReviewed, ErrSponsorExists, ErrSponsorBudgetUnavailable, ErrInsufficientBalance, ErrRedemptionReviewed, ErrRedemptionNotFunded, ErrSponsorInvitationUsed, ErrLastAdmin, ErrFreezeTokenLimit, ErrTeamExists, ErrAlreadyTeamMember, ErrTeamOwnerCannotLeave, ErrChallengeExists, ErrChallengeClosed, ErrAlreadyChallengeParticipant, ErrJudgingReviewClosed, ErrJudgingReviewUnscored, ErrTaskRunning, ErrAdjustmentExceedsBalance:
+		return http.StatusConflict, err.Error()
+	case ErrUnauthorizedAccess, ErrInvalidWebhookSignature, ErrSessionRevoked:
 		return http.StatusUnauthorized, err.Error()
-	case ErrAccessForbidden:
+	case ErrAccessForbidden, ErrActivityHidden, ErrSponsorInvitationMismatch, ErrAccountPendingDeletion, ErrUserBlocked:
 		return http.StatusForbidden, err.Error()
-	case ErrUserNotFound:
+	case ErrUserNotFound, ErrWebhookDeliveryNotFound, ErrJobNotFound, ErrUnknownTask, ErrContributionNotFound, ErrDisputeNotFound, ErrContributionFlagNotFound, ErrRepoNotFound, ErrRepositoryAccessRuleNotFound, ErrSponsorNotFound, ErrRedemptionNotFound, ErrSponsorInvitationNotFound, ErrRoleNotFound, ErrUserRoleNotFound, ErrNotificationNotFound, ErrIntegrationEndpointNotFound, ErrIntegrationDeliveryNotFound, ErrTeamNotFound, ErrInvalidInviteCode, ErrTeamMemberNotFound, ErrTeamGoalNotFound, ErrChallengeNotFound, ErrChallengeParticipantNotFound, ErrJudgingReviewNotFound:
 		return http.StatusNotFound, err.Error()
 	case ErrInvalidToken:
 		return http.StatusUnprocessableEntity, err.Error()
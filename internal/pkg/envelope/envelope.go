@@ -0,0 +1,186 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"log/slog"
+	"strconv"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+const dataKeySize = 32
+
+// SealedKey is a per-record data key encrypted with one of the configured
+// key-encryption keys. It is stored next to the ciphertexts it protects.
+type SealedKey struct {
+	KeyID      string
+	WrappedKey []byte
+}
+
+type envelope struct {
+	activeKeyID string
+	keys        map[string][]byte
+}
+
+// Envelope seals values for storage. The associated data is authenticated
+// but not stored: a value only opens with the associated data it was sealed
+// with, so a ciphertext copied onto another record fails to open.
+type Envelope interface {
+	ActiveKeyID() string
+	Seal(associatedData []byte, plaintexts ...[]byte) (SealedKey, [][]byte, error)
+	Open(key SealedKey, associatedData []byte, ciphertexts ...[]byte) ([][]byte, error)
+}
+
+// UserData is the associated data binding a value to the user it belongs to.
+func UserData(userId int) []byte {
+	return []byte("user:" + strconv.Itoa(userId))
+}
+
+// New loads the key-encryption keys from config. Keys are base64 encoded
+// 32 byte values; retired keys stay configured until every record sealed
+// with them has been rotated. Keys are required in every environment: a
+// value sealed with a key that is gone after a restart can never be opened.
+func New(appCfg config.AppConfig) (Envelope, error) {
+	if len(appCfg.Encryption.Keys) == 0 {
+		slog.Error("no encryption keys configured")
+		return nil, apperrors.ErrInvalidEncryptionKey
+	}
+
+	keys := make(map[string][]byte, len(appCfg.Encryption.Keys))
+	for keyId, encodedKey := range appCfg.Encryption.Keys {
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil || len(key) != dataKeySize {
+			slog.Error("invalid encryption key in config", "key_id", keyId)
+			return nil, apperrors.ErrInvalidEncryptionKey
+		}
+		keys[keyId] = key
+	}
+
+	if _, ok := keys[appCfg.Encryption.ActiveKeyID]; !ok {
+		slog.Error("active encryption key is not configured", "key_id", appCfg.Encryption.ActiveKeyID)
+		return nil, apperrors.ErrInvalidEncryptionKey
+	}
+
+	return &envelope{
+		activeKeyID: appCfg.Encryption.ActiveKeyID,
+		keys:        keys,
+	}, nil
+}
+
+func (e *envelope) ActiveKeyID() string {
+	return e.activeKeyID
+}
+
+// Seal encrypts the plaintexts with a fresh data key and wraps that key with
+// the active key-encryption key, binding both to associatedData. Nil
+// plaintexts stay nil.
+func (e *envelope) Seal(associatedData []byte, plaintexts ...[]byte) (SealedKey, [][]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		slog.Error("failed to generate data key", "error", err)
+		return SealedKey{}, nil, apperrors.ErrEncryptionFailed
+	}
+
+	wrappedKey, err := encrypt(e.keys[e.activeKeyID], dataKey, associatedData)
+	if err != nil {
+		return SealedKey{}, nil, err
+	}
+
+	ciphertexts := make([][]byte, len(plaintexts))
+	for i, plaintext := range plaintexts {
+		if plaintext == nil {
+			continue
+		}
+		ciphertexts[i], err = encrypt(dataKey, plaintext, associatedData)
+		if err != nil {
+			return SealedKey{}, nil, err
+		}
+	}
+
+	return SealedKey{KeyID: e.activeKeyID, WrappedKey: wrappedKey}, ciphertexts, nil
+}
+
+func (e *envelope) Open(key SealedKey, associatedData []byte, ciphertexts ...[]byte) ([][]byte, error) {
+	keyEncryptionKey, ok := e.keys[key.KeyID]
+	if !ok {
+		slog.Error("encryption key is not configured", "key_id", key.KeyID)
+		return nil, apperrors.ErrDecryptionFailed
+	}
+
+	dataKey, err := decrypt(keyEncryptionKey, key.WrappedKey, associatedData)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintexts := make([][]byte, len(ciphertexts))
+	for i, ciphertext := range ciphertexts {
+		if ciphertext == nil {
+			continue
+		}
+		plaintexts[i], err = decrypt(dataKey, ciphertext, associatedData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return plaintexts, nil
+}
+
+// encrypt seals plaintext with AES-256-GCM, prefixing the random nonce.
+func encrypt(key, plaintext, associatedData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, apperrors.ErrEncryptionFailed
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		slog.Error("failed to generate nonce", "error", err)
+		return nil, apperrors.ErrEncryptionFailed
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+func decrypt(key, ciphertext, associatedData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, apperrors.ErrDecryptionFailed
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		slog.Error("ciphertext is too short")
+		return nil, apperrors.ErrDecryptionFailed
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		slog.Error("failed to decrypt ciphertext", "error", err)
+		return nil, apperrors.ErrDecryptionFailed
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		slog.Error("failed to create cipher", "error", err)
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		slog.Error("failed to create gcm", "error", err)
+		return nil, err
+	}
+
+	return gcm, nil
+}
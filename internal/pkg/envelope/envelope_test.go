@@ -0,0 +1,201 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+func testKey(fill byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, dataKeySize))
+}
+
+func newTestEnvelope(t *testing.T, activeKeyID string, keys map[string]string) Envelope {
+	t.Helper()
+
+	sealer, err := New(config.AppConfig{
+		IsProduction: true,
+		Encryption:   config.Encryption{ActiveKeyID: activeKeyID, Keys: keys},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return sealer
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	sealer := newTestEnvelope(t, "k1", map[string]string{"k1": testKey(1)})
+
+	tests := []struct {
+		name       string
+		plaintexts [][]byte
+	}{
+		{name: "single value", plaintexts: [][]byte{[]byte("access-token")}},
+		{name: "several values", plaintexts: [][]byte{[]byte("access-token"), []byte("refresh-token")}},
+		{name: "nil value stays nil", plaintexts: [][]byte{[]byte("access-token"), nil}},
+		{name: "empty value", plaintexts: [][]byte{{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ciphertexts, err := sealer.Seal(UserData(7), tt.plaintexts...)
+			if err != nil {
+				t.Fatalf("Seal() error = %v", err)
+			}
+			if key.KeyID != "k1" {
+				t.Errorf("Seal() key id = %q, want %q", key.KeyID, "k1")
+			}
+
+			for i, ciphertext := range ciphertexts {
+				if tt.plaintexts[i] == nil && ciphertext != nil {
+					t.Errorf("ciphertext %d = %v, want nil", i, ciphertext)
+				}
+				if len(tt.plaintexts[i]) > 0 && bytes.Contains(ciphertext, tt.plaintexts[i]) {
+					t.Errorf("ciphertext %d contains the plaintext", i)
+				}
+			}
+
+			opened, err := sealer.Open(key, UserData(7), ciphertexts...)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+
+			for i := range tt.plaintexts {
+				if tt.plaintexts[i] == nil {
+					if opened[i] != nil {
+						t.Errorf("opened %d = %v, want nil", i, opened[i])
+					}
+					continue
+				}
+				if !bytes.Equal(opened[i], tt.plaintexts[i]) {
+					t.Errorf("opened %d = %q, want %q", i, opened[i], tt.plaintexts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOpenRejectsTampering(t *testing.T) {
+	sealer := newTestEnvelope(t, "k1", map[string]string{"k1": testKey(1)})
+
+	key, ciphertexts, err := sealer.Seal(UserData(7), []byte("access-token"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	flipped := bytes.Clone(ciphertexts[0])
+	flipped[len(flipped)-1] ^= 0xff
+
+	tests := []struct {
+		name           string
+		key            SealedKey
+		associatedData []byte
+		ciphertext     []byte
+	}{
+		{name: "another user's row", key: key, associatedData: UserData(8), ciphertext: ciphertexts[0]},
+		{name: "no associated data", key: key, associatedData: nil, ciphertext: ciphertexts[0]},
+		{name: "modified ciphertext", key: key, associatedData: UserData(7), ciphertext: flipped},
+		{name: "truncated ciphertext", key: key, associatedData: UserData(7), ciphertext: ciphertexts[0][:4]},
+		{name: "unknown key id", key: SealedKey{KeyID: "missing", WrappedKey: key.WrappedKey}, associatedData: UserData(7), ciphertext: ciphertexts[0]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sealer.Open(tt.key, tt.associatedData, tt.ciphertext)
+			if !errors.Is(err, apperrors.ErrDecryptionFailed) {
+				t.Errorf("Open() error = %v, want %v", err, apperrors.ErrDecryptionFailed)
+			}
+		})
+	}
+}
+
+func TestOpenWithRetiredKey(t *testing.T) {
+	oldSealer := newTestEnvelope(t, "k1", map[string]string{"k1": testKey(1)})
+	key, ciphertexts, err := oldSealer.Seal(UserData(7), []byte("access-token"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	rotated := newTestEnvelope(t, "k2", map[string]string{"k1": testKey(1), "k2": testKey(2)})
+	opened, err := rotated.Open(key, UserData(7), ciphertexts...)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if string(opened[0]) != "access-token" {
+		t.Errorf("Open() = %q, want %q", opened[0], "access-token")
+	}
+
+	newKey, _, err := rotated.Seal(UserData(7), opened...)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if newKey.KeyID != "k2" {
+		t.Errorf("Seal() key id = %q, want %q", newKey.KeyID, "k2")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		appCfg  config.AppConfig
+		wantErr bool
+	}{
+		{
+			name:   "configured keys",
+			appCfg: config.AppConfig{IsProduction: true, Encryption: config.Encryption{ActiveKeyID: "k1", Keys: map[string]string{"k1": testKey(1)}}},
+		},
+		{
+			name:    "development without keys",
+			appCfg:  config.AppConfig{},
+			wantErr: true,
+		},
+		{
+			name:    "production without keys",
+			appCfg:  config.AppConfig{IsProduction: true},
+			wantErr: true,
+		},
+		{
+			name:    "active key not configured",
+			appCfg:  config.AppConfig{Encryption: config.Encryption{ActiveKeyID: "k2", Keys: map[string]string{"k1": testKey(1)}}},
+			wantErr: true,
+		},
+		{
+			name:    "key not base64",
+			appCfg:  config.AppConfig{Encryption: config.Encryption{ActiveKeyID: "k1", Keys: map[string]string{"k1": "not base64!"}}},
+			wantErr: true,
+		},
+		{
+			name:    "key too short",
+			appCfg:  config.AppConfig{Encryption: config.Encryption{ActiveKeyID: "k1", Keys: map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte("short"))}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealer, err := New(tt.appCfg)
+			if tt.wantErr {
+				if !errors.Is(err, apperrors.ErrInvalidEncryptionKey) {
+					t.Fatalf("New() error = %v, want %v", err, apperrors.ErrInvalidEncryptionKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			key, ciphertexts, err := sealer.Seal(UserData(1), []byte("value"))
+			if err != nil {
+				t.Fatalf("Seal() error = %v", err)
+			}
+			if _, err := sealer.Open(key, UserData(1), ciphertexts...); err != nil {
+				t.Errorf("Open() error = %v", err)
+			}
+		})
+	}
+}
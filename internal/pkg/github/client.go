@@ -0,0 +1,340 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	APIBaseURL = "https://api.github.com"
+
+	acceptHeader     = "application/vnd.github+json"
+	apiVersionHeader = "2022-11-28"
+
+	maxRetries          = 3
+	baseRetryBackoff    = 500 * time.Millisecond
+	maxRetryBackoff     = 10 * time.Second
+	maxRateLimitWait    = 2 * time.Minute
+	defaultSecondaryGap = time.Minute
+)
+
+type client struct {
+	httpClient *http.Client
+	etagStore  ETagStore
+	tracker    *Tracker
+}
+
+type Client interface {
+	Do(ctx context.Context, req *http.Request) (Response, error)
+	Get(ctx context.Context, url string, v any) (Response, error)
+	GetPaginated(ctx context.Context, url string, eachPage func(body []byte) error) error
+	Metrics() Metrics
+}
+
+// NewClient returns a GitHub REST client authenticated with the given token
+// source. Pass a nil etagStore to disable conditional requests, and the
+// tracker of the credentials from Trackers so their rate limit state
+// outlives the client; a nil tracker starts from scratch.
+func NewClient(tokenSource oauth2.TokenSource, etagStore ETagStore, tracker *Tracker) Client {
+	if tracker == nil {
+		tracker = &Tracker{}
+	}
+
+	return &client{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &oauth2.Transport{
+				Source: tokenSource,
+				Base:   http.DefaultTransport,
+			},
+		},
+		etagStore: etagStore,
+		tracker:   tracker,
+	}
+}
+
+func (c *client) Get(ctx context.Context, url string, v any) (Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		slog.Error("failed to build github request", "error", err)
+		return Response{}, apperrors.ErrGithubRequestFailed
+	}
+
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return Response{}, err
+	}
+
+	if v != nil {
+		err = json.Unmarshal(resp.Body, v)
+		if err != nil {
+			slog.Error("failed to unmarshal github response", "url", url, "error", err)
+			return Response{}, apperrors.ErrGithubRequestFailed
+		}
+	}
+
+	return resp, nil
+}
+
+func (c *client) GetPaginated(ctx context.Context, url string, eachPage func(body []byte) error) error {
+	next := url
+	for next != "" {
+		resp, err := c.Get(ctx, next, nil)
+		if err != nil {
+			return err
+		}
+
+		err = eachPage(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		next = resp.NextPage
+	}
+
+	return nil
+}
+
+// Do sends the request, waiting out rate limits and retrying transient
+// failures. GET requests are made conditional when an ETag is cached, and a
+// 304 is answered from the cache so it does not count against the quota.
+func (c *client) Do(ctx context.Context, req *http.Request) (Response, error) {
+	req.Header.Set("Accept", acceptHeader)
+	req.Header.Set("X-GitHub-Api-Version", apiVersionHeader)
+
+	var cacheKey string
+	var cached CachedResponse
+	var hasCached bool
+	if c.etagStore != nil && req.Method == http.MethodGet {
+		cacheKey = c.cacheKey(req)
+		cached, hasCached = c.etagStore.Get(cacheKey)
+		if hasCached {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		if err != nil {
+			slog.Error("failed to read github request body", "error", err)
+			return Response{}, apperrors.ErrGithubRequestFailed
+		}
+		req.Body.Close()
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := c.waitForRateLimit(ctx)
+		if err != nil {
+			return Response{}, err
+		}
+
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		httpResp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return Response{}, ctx.Err()
+			}
+			if attempt < maxRetries {
+				slog.Warn("github request failed, retrying", "url", req.URL.String(), "attempt", attempt+1, "error", err)
+				if err := c.sleep(ctx, backoff(attempt)); err != nil {
+					return Response{}, err
+				}
+				continue
+			}
+			slog.Error("github request failed", "url", req.URL.String(), "error", err)
+			return Response{}, apperrors.ErrGithubRequestFailed
+		}
+
+		body, err := io.ReadAll(httpResp.Body)
+		httpResp.Body.Close()
+		if err != nil {
+			slog.Error("failed to read github response body", "error", err)
+			return Response{}, apperrors.ErrGithubRequestFailed
+		}
+
+		rateLimit := parseRateLimit(httpResp.Header)
+		c.recordResponse(httpResp.StatusCode, rateLimit)
+
+		resp := Response{
+			StatusCode: httpResp.StatusCode,
+			Header:     httpResp.Header,
+			Body:       body,
+			NextPage:   parseNextLink(httpResp.Header.Get("Link")),
+			RateLimit:  rateLimit,
+		}
+
+		switch {
+		case httpResp.StatusCode == http.StatusNotModified && hasCached:
+			resp.StatusCode = http.StatusOK
+			resp.Body = cached.Body
+			resp.NextPage = cached.NextPage
+			resp.NotModified = true
+			return resp, nil
+
+		case httpResp.StatusCode >= 200 && httpResp.StatusCode < 300:
+			etag := httpResp.Header.Get("ETag")
+			if cacheKey != "" && etag != "" {
+				c.etagStore.Set(cacheKey, CachedResponse{ETag: etag, Body: body, NextPage: resp.NextPage})
+			}
+			return resp, nil
+
+		case httpResp.StatusCode == http.StatusUnauthorized:
+			return resp, apperrors.ErrGithubUnauthorized
+
+		case httpResp.StatusCode == http.StatusNotFound:
+			return resp, apperrors.ErrGithubNotFound
+
+		case isRateLimited(httpResp.StatusCode, httpResp.Header, body):
+			wait := rateLimitWait(httpResp.Header, rateLimit)
+			if attempt >= maxRetries || wait > maxRateLimitWait {
+				slog.Error("github rate limit exceeded", "url", req.URL.String(), "retry_after", wait)
+				return resp, apperrors.ErrGithubRateLimited
+			}
+			slog.Warn("github rate limit hit, waiting", "url", req.URL.String(), "wait", wait)
+			if err := c.sleep(ctx, wait); err != nil {
+				return Response{}, err
+			}
+			continue
+
+		case httpResp.StatusCode >= 500:
+			if attempt < maxRetries {
+				slog.Warn("github server error, retrying", "url", req.URL.String(), "status", httpResp.StatusCode, "attempt", attempt+1)
+				if err := c.sleep(ctx, backoff(attempt)); err != nil {
+					return Response{}, err
+				}
+				continue
+			}
+			slog.Error("github server error", "url", req.URL.String(), "status", httpResp.StatusCode)
+			return resp, apperrors.ErrGithubRequestFailed
+
+		default:
+			slog.Error("unexpected github response", "url", req.URL.String(), "status", httpResp.StatusCode, "body", string(body))
+			return resp, apperrors.ErrGithubRequestFailed
+		}
+	}
+}
+
+func (c *client) Metrics() Metrics {
+	return c.tracker.Metrics()
+}
+
+// waitForRateLimit blocks until the primary quota resets when the last
+// response reported it exhausted.
+func (c *client) waitForRateLimit(ctx context.Context) error {
+	rateLimit := c.tracker.Metrics().RateLimit
+
+	if rateLimit.Limit == 0 || rateLimit.Remaining > 0 {
+		return nil
+	}
+
+	wait := time.Until(rateLimit.Reset)
+	if wait <= 0 {
+		return nil
+	}
+	if wait > maxRateLimitWait {
+		slog.Error("github rate limit exhausted", "reset", rateLimit.Reset)
+		return apperrors.ErrGithubRateLimited
+	}
+
+	slog.Warn("github rate limit exhausted, waiting for reset", "wait", wait)
+	return c.sleep(ctx, wait)
+}
+
+func (c *client) recordResponse(statusCode int, rateLimit RateLimit) {
+	c.tracker.update(func(metrics *Metrics) {
+		metrics.Requests++
+		if statusCode == http.StatusNotModified {
+			metrics.NotModified++
+		}
+		if statusCode >= 500 {
+			metrics.ServerErrors++
+		}
+		if rateLimit.Limit > 0 {
+			metrics.RateLimit = rateLimit
+		}
+	})
+}
+
+func (c *client) sleep(ctx context.Context, d time.Duration) error {
+	c.tracker.update(func(metrics *Metrics) {
+		metrics.Retries++
+	})
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cacheKey scopes cached responses to the credentials used so one user's
+// private data is never served to another.
+func (c *client) cacheKey(req *http.Request) string {
+	credential := ""
+	if transport, ok := c.httpClient.Transport.(*oauth2.Transport); ok {
+		token, err := transport.Source.Token()
+		if err == nil {
+			credential = token.AccessToken
+		}
+	}
+
+	sum := sha256.Sum256([]byte(credential))
+	return hex.EncodeToString(sum[:8]) + " " + req.URL.String()
+}
+
+// backoff returns an exponential delay with full jitter for the given attempt.
+func backoff(attempt int) time.Duration {
+	d := baseRetryBackoff << attempt
+	if d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	return time.Duration(rand.Int64N(int64(d))) + baseRetryBackoff/2
+}
+
+func isRateLimited(statusCode int, header http.Header, body []byte) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if statusCode != http.StatusForbidden {
+		return false
+	}
+	if header.Get("Retry-After") != "" || header.Get("X-RateLimit-Remaining") == "0" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// rateLimitWait follows GitHub's guidance: honour Retry-After, otherwise wait
+// for the primary reset, otherwise back off for at least a minute.
+func rateLimitWait(header http.Header, rateLimit RateLimit) time.Duration {
+	if retryAfter, ok := parseRetryAfter(header); ok {
+		return retryAfter
+	}
+	if rateLimit.Limit > 0 && rateLimit.Remaining == 0 {
+		if wait := time.Until(rateLimit.Reset); wait > 0 {
+			return wait
+		}
+	}
+	return defaultSecondaryGap
+}
@@ -0,0 +1,37 @@
+package github
+
+import (
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	NextPage    string
+	NotModified bool
+	RateLimit   RateLimit
+}
+
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	Reset     time.Time `json:"reset"`
+	Resource  string    `json:"resource"`
+}
+
+type Metrics struct {
+	RateLimit    RateLimit `json:"rate_limit"`
+	Requests     int64     `json:"requests"`
+	NotModified  int64     `json:"not_modified"`
+	Retries      int64     `json:"retries"`
+	ServerErrors int64     `json:"server_errors"`
+}
+
+type CachedResponse struct {
+	ETag     string
+	Body     []byte
+	NextPage string
+}
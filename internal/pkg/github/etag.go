@@ -0,0 +1,65 @@
+package github
+
+import (
+	"container/list"
+	"sync"
+)
+
+type ETagStore interface {
+	Get(key string) (CachedResponse, bool)
+	Set(key string, value CachedResponse)
+}
+
+type memoryETagStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type memoryETagEntry struct {
+	key   string
+	value CachedResponse
+}
+
+// NewMemoryETagStore returns an in-process LRU cache holding at most capacity
+// responses.
+func NewMemoryETagStore(capacity int) ETagStore {
+	return &memoryETagStore{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (s *memoryETagStore) Get(key string) (CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return CachedResponse{}, false
+	}
+
+	s.order.MoveToFront(element)
+	return element.Value.(*memoryETagEntry).value, true
+}
+
+func (s *memoryETagStore) Set(key string, value CachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		element.Value.(*memoryETagEntry).value = value
+		s.order.MoveToFront(element)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(&memoryETagEntry{key: key, value: value})
+
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryETagEntry).key)
+	}
+}
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseNextLink(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{name: "no header", link: "", want: ""},
+		{
+			name: "next and last",
+			link: `<https://api.github.com/user/repos?page=2>; rel="next", <https://api.github.com/user/repos?page=5>; rel="last"`,
+			want: "https://api.github.com/user/repos?page=2",
+		},
+		{
+			name: "next listed after prev",
+			link: `<https://api.github.com/user/repos?page=1>; rel="prev", <https://api.github.com/user/repos?page=3>; rel="next"`,
+			want: "https://api.github.com/user/repos?page=3",
+		},
+		{
+			name: "last page",
+			link: `<https://api.github.com/user/repos?page=4>; rel="prev", <https://api.github.com/user/repos?page=1>; rel="first"`,
+			want: "",
+		},
+		{name: "malformed", link: `https://api.github.com/user/repos?page=2`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNextLink(tt.link); got != tt.want {
+				t.Errorf("parseNextLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "5000")
+	header.Set("X-RateLimit-Remaining", "4990")
+	header.Set("X-RateLimit-Used", "10")
+	header.Set("X-RateLimit-Reset", "1750000000")
+	header.Set("X-RateLimit-Resource", "core")
+
+	want := RateLimit{Limit: 5000, Remaining: 4990, Used: 10, Reset: time.Unix(1750000000, 0), Resource: "core"}
+	if got := parseRateLimit(header); got != want {
+		t.Errorf("parseRateLimit() = %+v, want %+v", got, want)
+	}
+
+	if got := parseRateLimit(http.Header{}); got != (RateLimit{}) {
+		t.Errorf("parseRateLimit() without headers = %+v, want zero", got)
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		header     map[string]string
+		body       string
+		want       bool
+	}{
+		{name: "too many requests", statusCode: http.StatusTooManyRequests, want: true},
+		{name: "forbidden with retry after", statusCode: http.StatusForbidden, header: map[string]string{"Retry-After": "30"}, want: true},
+		{name: "forbidden with quota exhausted", statusCode: http.StatusForbidden, header: map[string]string{"X-RateLimit-Remaining": "0"}, want: true},
+		{name: "secondary rate limit message", statusCode: http.StatusForbidden, body: `{"message":"You have exceeded a secondary rate limit."}`, want: true},
+		{name: "forbidden for permissions", statusCode: http.StatusForbidden, header: map[string]string{"X-RateLimit-Remaining": "4000"}, body: `{"message":"Resource not accessible by integration"}`},
+		{name: "ok with quota exhausted", statusCode: http.StatusOK, header: map[string]string{"X-RateLimit-Remaining": "0"}},
+		{name: "server error", statusCode: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, value := range tt.header {
+				header.Set(key, value)
+			}
+			if got := isRateLimited(tt.statusCode, header, []byte(tt.body)); got != tt.want {
+				t.Errorf("isRateLimited() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		rateLimit  RateLimit
+		min, max   time.Duration
+	}{
+		{name: "retry after seconds", retryAfter: "30", min: 30 * time.Second, max: 30 * time.Second},
+		{name: "retry after wins over reset", retryAfter: "5", rateLimit: RateLimit{Limit: 5000, Remaining: 0, Reset: time.Now().Add(time.Hour)}, min: 5 * time.Second, max: 5 * time.Second},
+		{name: "primary quota exhausted", rateLimit: RateLimit{Limit: 5000, Remaining: 0, Reset: time.Now().Add(90 * time.Second)}, min: 80 * time.Second, max: 90 * time.Second},
+		{name: "reset already passed", rateLimit: RateLimit{Limit: 5000, Remaining: 0, Reset: time.Now().Add(-time.Second)}, min: defaultSecondaryGap, max: defaultSecondaryGap},
+		{name: "secondary limit without hints", rateLimit: RateLimit{Limit: 5000, Remaining: 4000}, min: defaultSecondaryGap, max: defaultSecondaryGap},
+		{name: "unparseable retry after", retryAfter: "soon", min: defaultSecondaryGap, max: defaultSecondaryGap},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.retryAfter != "" {
+				header.Set("Retry-After", tt.retryAfter)
+			}
+			got := rateLimitWait(header, tt.rateLimit)
+			if got < tt.min || got > tt.max {
+				t.Errorf("rateLimitWait() = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 0, max: baseRetryBackoff},
+		{attempt: 1, max: 2 * baseRetryBackoff},
+		{attempt: 2, max: 4 * baseRetryBackoff},
+		{attempt: 10, max: maxRetryBackoff},
+	}
+
+	for _, tt := range tests {
+		for range 50 {
+			got := backoff(tt.attempt)
+			if got < baseRetryBackoff/2 || got >= tt.max+baseRetryBackoff/2 {
+				t.Fatalf("backoff(%d) = %v, want in [%v, %v)", tt.attempt, got, baseRetryBackoff/2, tt.max+baseRetryBackoff/2)
+			}
+		}
+	}
+}
+
+func TestTrackersShareStateByKey(t *testing.T) {
+	trackers := NewTrackers()
+
+	first := NewClient(nil, nil, trackers.Get(UserKey(1))).(*client)
+	first.recordResponse(http.StatusOK, RateLimit{Limit: 5000, Remaining: 0, Reset: time.Now().Add(time.Hour)})
+
+	second := NewClient(nil, nil, trackers.Get(UserKey(1)))
+	if got := second.Metrics(); got.Requests != 1 || got.RateLimit.Remaining != 0 {
+		t.Errorf("second client metrics = %+v, want the first client's request and exhausted quota", got)
+	}
+
+	other := NewClient(nil, nil, trackers.Get(UserKey(2)))
+	if got := other.Metrics(); got.Requests != 0 {
+		t.Errorf("other user's metrics = %+v, want none", got)
+	}
+
+	metrics := trackers.Metrics()
+	if len(metrics) != 2 || metrics[UserKey(1)].Requests != 1 {
+		t.Errorf("trackers.Metrics() = %+v, want both users with user 1's request", metrics)
+	}
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"golang.org/x/oauth2"
+)
+
+const installationTokenUrl = APIBaseURL + "/app/installations/%d/access_tokens"
+
+// NewOAuthTokenSource authenticates requests as the user who granted token.
+func NewOAuthTokenSource(token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(token)
+}
+
+type installationTokenSource struct {
+	appId          int64
+	installationId int64
+	privateKey     *rsa.PrivateKey
+	httpClient     *http.Client
+}
+
+type installationTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewAppInstallationTokenSource authenticates requests as a GitHub App
+// installation. Installation tokens last an hour and are minted on demand
+// from a short-lived JWT signed with the app's private key. The source is
+// not tied to a request context so one client can serve the whole process.
+func NewAppInstallationTokenSource(appCfg config.AppConfig) (oauth2.TokenSource, error) {
+	pemBytes, err := os.ReadFile(appCfg.GithubApp.PrivateKeyPath)
+	if err != nil {
+		slog.Error("failed to read github app private key", "error", err)
+		return nil, apperrors.ErrGithubAppNotConfigured
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+	if err != nil {
+		slog.Error("failed to parse github app private key", "error", err)
+		return nil, apperrors.ErrGithubAppNotConfigured
+	}
+
+	source := &installationTokenSource{
+		appId:          appCfg.GithubApp.AppID,
+		installationId: appCfg.GithubApp.InstallationID,
+		privateKey:     privateKey,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+	}
+
+	return oauth2.ReuseTokenSource(nil, source), nil
+}
+
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		// backdated to tolerate clock drift between us and GitHub
+		IssuedAt:  jwt.NewNumericDate(now.Add(-60 * time.Second)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+		Issuer:    strconv.FormatInt(s.appId, 10),
+	}
+
+	appJWT, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.privateKey)
+	if err != nil {
+		slog.Error("failed to sign github app jwt", "error", err)
+		return nil, apperrors.ErrGithubRequestFailed
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, fmt.Sprintf(installationTokenUrl, s.installationId), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+appJWT)
+	req.Header.Set("Accept", acceptHeader)
+	req.Header.Set("X-GitHub-Api-Version", apiVersionHeader)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		slog.Error("failed to request github installation token", "error", err)
+		return nil, apperrors.ErrGithubRequestFailed
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		slog.Error("unexpected status requesting github installation token", "status", resp.StatusCode)
+		return nil, apperrors.ErrGithubRequestFailed
+	}
+
+	var tokenResponse installationTokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&tokenResponse)
+	if err != nil {
+		slog.Error("failed to decode github installation token", "error", err)
+		return nil, apperrors.ErrGithubRequestFailed
+	}
+
+	return &oauth2.Token{
+		AccessToken: tokenResponse.Token,
+		TokenType:   "Bearer",
+		Expiry:      tokenResponse.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package github
+
+import (
+	"fmt"
+	"sync"
+)
+
+// InstallationKey is the tracker key of the GitHub App installation.
+const InstallationKey = "installation"
+
+// UserKey is the tracker key of the user's OAuth token.
+func UserKey(userId int) string {
+	return fmt.Sprintf("user:%d", userId)
+}
+
+// Tracker holds the rate limit state and usage of one set of credentials.
+// Clients built for the same user or installation share it, so a quota
+// exhausted by one operation is waited out by the next.
+type Tracker struct {
+	mu      sync.Mutex
+	metrics Metrics
+}
+
+func (t *Tracker) Metrics() Metrics {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.metrics
+}
+
+func (t *Tracker) update(update func(metrics *Metrics)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	update(&t.metrics)
+}
+
+// Trackers hands out one Tracker per credential key for the life of the
+// process.
+type Trackers struct {
+	mu       sync.Mutex
+	trackers map[string]*Tracker
+}
+
+func NewTrackers() *Trackers {
+	return &Trackers{trackers: map[string]*Tracker{}}
+}
+
+func (t *Trackers) Get(key string) *Tracker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracker, ok := t.trackers[key]
+	if !ok {
+		tracker = &Tracker{}
+		t.trackers[key] = tracker
+	}
+
+	return tracker
+}
+
+// Metrics returns the metrics of every credential used so far, by key.
+func (t *Trackers) Metrics() map[string]Metrics {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	metrics := make(map[string]Metrics, len(t.trackers))
+	for key, tracker := range t.trackers {
+		metrics[key] = tracker.Metrics()
+	}
+
+	return metrics
+}
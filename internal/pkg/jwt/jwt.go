@@ -7,18 +7,19 @@ import (
 	"github.com/joshsoftware/code-curiosity-2025/internal/config"
 )
 
+// Claims only identify the user. What the user may do is loaded on every
+// request, so role changes apply to tokens already issued.
 type Claims struct {
-	UserId  int
-	IsAdmin bool
+	UserId int
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userId int, isAdmin bool, appCfg config.AppConfig) (string, error) {
+func GenerateJWT(userId int, appCfg config.AppConfig) (string, error) {
 	claims := Claims{
-		UserId:  userId,
-		IsAdmin: isAdmin,
+		UserId: userId,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
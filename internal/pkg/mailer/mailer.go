@@ -0,0 +1,236 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/config"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+const (
+	DriverSMTP   = "smtp"
+	DriverFile   = "file"
+	DriverMemory = "memory"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// New builds the mailer selected by the mail driver in config. Without a
+// driver, mail is kept in memory outside production and refused in
+// production, where it would silently go nowhere.
+func New(appCfg config.AppConfig) (Mailer, error) {
+	mailCfg := appCfg.Mail
+
+	switch mailCfg.Driver {
+	case "":
+		if appCfg.IsProduction {
+			slog.Error("mail driver must be set in production")
+			return nil, apperrors.ErrInvalidMailConfig
+		}
+		return NewMemoryMailer(), nil
+	case DriverSMTP:
+		if mailCfg.SMTPHost == "" {
+			slog.Error("smtp mail driver needs a host")
+			return nil, apperrors.ErrInvalidMailConfig
+		}
+		return NewSMTPMailer(mailCfg), nil
+	case DriverFile:
+		return NewFileMailer(mailCfg.From, mailCfg.FileDir), nil
+	case DriverMemory:
+		return NewMemoryMailer(), nil
+	default:
+		slog.Error("unknown mail driver", "driver", mailCfg.Driver)
+		return nil, apperrors.ErrInvalidMailConfig
+	}
+}
+
+type smtpMailer struct {
+	host string
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(mailCfg config.Mail) Mailer {
+	var auth smtp.Auth
+	if mailCfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", mailCfg.SMTPUsername, mailCfg.SMTPPassword, mailCfg.SMTPHost)
+	}
+
+	return &smtpMailer{
+		host: mailCfg.SMTPHost,
+		addr: fmt.Sprintf("%s:%d", mailCfg.SMTPHost, mailCfg.SMTPPort),
+		from: mailCfg.From,
+		auth: auth,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message Message) error {
+	err := m.send(ctx, message)
+	if err != nil {
+		slog.Error("failed to send email over smtp", "error", err)
+		return apperrors.ErrMailSendFailed
+	}
+
+	return nil
+}
+
+// send does what smtp.SendMail does, on a connection that is closed when ctx
+// is done so a stalled server cannot hold up the caller.
+func (m *smtpMailer) send(ctx context.Context, message Message) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: m.host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support authentication")
+		}
+		err = client.Auth(m.auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(envelopeAddress(m.from))
+	if err != nil {
+		return err
+	}
+	err = client.Rcpt(message.To)
+	if err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(format(m.from, message))
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// fileMailer writes each message to its own file, for development setups
+// without an SMTP server.
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+func NewFileMailer(from string, dir string) Mailer {
+	return &fileMailer{
+		from: from,
+		dir:  dir,
+	}
+}
+
+func (m *fileMailer) Send(ctx context.Context, message Message) error {
+	err := os.MkdirAll(m.dir, 0o755)
+	if err != nil {
+		slog.Error("failed to create mail directory", "error", err)
+		return apperrors.ErrMailSendFailed
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	err = os.WriteFile(filepath.Join(m.dir, name), format(m.from, message), 0o644)
+	if err != nil {
+		slog.Error("failed to write email file", "error", err)
+		return apperrors.ErrMailSendFailed
+	}
+
+	return nil
+}
+
+// MemoryMailer keeps sent messages in memory so tests can inspect them.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, message Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
+
+func format(from string, message Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + message.To + "\r\n")
+	b.WriteString("Subject: " + message.Subject + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
+
+// envelopeAddress extracts the bare address from a "Name <address>" sender.
+func envelopeAddress(from string) string {
+	start := strings.LastIndex(from, "<")
+	end := strings.LastIndex(from, ">")
+	if start >= 0 && end > start {
+		return from[start+1 : end]
+	}
+
+	return from
+}
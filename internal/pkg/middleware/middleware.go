@@ -3,7 +3,9 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/joshsoftware/code-curiosity-2025/internal/config"
 	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
@@ -15,9 +17,78 @@ type contextKey string
 
 const (
 	UserIdKey  contextKey = "userId"
-	IsAdminKey contextKey = "isAdmin"
+	SessionKey contextKey = "session"
 )
 
+// roles, kept in sync with the roles table
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+	RoleSponsor   = "sponsor"
+	RoleJudge     = "judge"
+	RoleUser      = "user"
+)
+
+var Roles = []string{RoleAdmin, RoleModerator, RoleSponsor, RoleJudge, RoleUser}
+
+// permissions routes are guarded by, kept in sync with the permissions table
+const (
+	PermissionContributionsRead  = "contributions:read"
+	PermissionScoresWrite        = "scores:write"
+	PermissionFraudReview        = "fraud:review"
+	PermissionUsersBlock         = "users:block"
+	PermissionRepositoriesManage = "repositories:manage"
+	PermissionSponsorsManage     = "sponsors:manage"
+	PermissionRedemptionsApprove = "redemptions:approve"
+	PermissionJobsManage         = "jobs:manage"
+	PermissionRolesManage        = "roles:manage"
+	PermissionSponsorPortalRead  = "sponsor_portal:read"
+	PermissionIntegrationsManage = "integrations:manage"
+	PermissionTeamsManage        = "teams:manage"
+	PermissionChallengesManage   = "challenges:manage"
+	PermissionJudgingReview      = "judging:review"
+)
+
+var Permissions = []string{
+	PermissionContributionsRead,
+	PermissionScoresWrite,
+	PermissionFraudReview,
+	PermissionUsersBlock,
+	PermissionRepositoriesManage,
+	PermissionSponsorsManage,
+	PermissionRedemptionsApprove,
+	PermissionJobsManage,
+	PermissionRolesManage,
+	PermissionSponsorPortalRead,
+	PermissionIntegrationsManage,
+	PermissionTeamsManage,
+	PermissionChallengesManage,
+	PermissionJudgingReview,
+}
+
+// Session is what a request may do, loaded from the database on every
+// request so role changes apply to tokens already issued. SponsorId is set
+// for members of a sponsor organization.
+type Session struct {
+	Roles       []string
+	Permissions []string
+	SponsorId   int
+}
+
+func (s Session) HasRole(role string) bool {
+	return slices.Contains(s.Roles, role)
+}
+
+func (s Session) HasPermission(permission string) bool {
+	return slices.Contains(s.Permissions, permission)
+}
+
+// SessionFromContext returns the session Authentication stored in ctx.
+func SessionFromContext(ctx context.Context) (Session, bool) {
+	session, ok := ctx.Value(SessionKey).(Session)
+	return session, ok
+}
+
 func CorsMiddleware(next http.Handler, appCfg config.AppConfig) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", appCfg.ClientURL)
@@ -34,7 +105,13 @@ func CorsMiddleware(next http.Handler, appCfg config.AppConfig) http.Handler {
 	})
 }
 
-func Authentication(next http.HandlerFunc, appCfg config.AppConfig) http.HandlerFunc {
+// SessionValidator rejects tokens of deleted users and of sessions revoked
+// after the token was issued, and returns the session of valid ones.
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, userId int, issuedAt time.Time) (Session, error)
+}
+
+func Authentication(next http.HandlerFunc, appCfg config.AppConfig, sessions SessionValidator) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
@@ -49,12 +126,37 @@ func Authentication(next http.HandlerFunc, appCfg config.AppConfig) http.Handler
 			return
 		}
 
+		var issuedAt time.Time
+		if token.IssuedAt != nil {
+			issuedAt = token.IssuedAt.Time
+		}
+
+		session, err := sessions.ValidateSession(r.Context(), token.UserId, issuedAt)
+		if err != nil {
+			status, errorMessage := apperrors.MapError(err)
+			response.WriteJson(w, status, errorMessage, nil)
+			return
+		}
+
 		userId := token.UserId
 		ctx := context.WithValue(r.Context(), UserIdKey, userId)
-		isAdmin := token.IsAdmin
-		ctx = context.WithValue(ctx, IsAdminKey, isAdmin)
+		ctx = context.WithValue(ctx, SessionKey, session)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RequirePermission lets the request through only when one of the
+// session's roles grants permission. It must be wrapped by Authentication.
+func RequirePermission(next http.HandlerFunc, permission string) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, ok := SessionFromContext(r.Context())
+		if !ok || !session.HasPermission(permission) {
+			response.WriteJson(w, http.StatusForbidden, apperrors.ErrAccessForbidden.Error(), nil)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
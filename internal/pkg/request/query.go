@@ -0,0 +1,69 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// ParsePagination reads the optional limit and offset query parameters,
+// capping limit at MaxLimit.
+func ParsePagination(r *http.Request) (int, int, error) {
+	limit, err := ParseLimit(r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	offset := 0
+	if offsetParam := r.URL.Query().Get("offset"); offsetParam != "" {
+		offset, err = strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			return 0, 0, apperrors.ErrInvalidQueryParams
+		}
+	}
+
+	return limit, offset, nil
+}
+
+// ParseLimit reads the optional limit query parameter, capping it at MaxLimit.
+func ParseLimit(r *http.Request) (int, error) {
+	limitParam := r.URL.Query().Get("limit")
+	if limitParam == "" {
+		return DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil || limit <= 0 {
+		return 0, apperrors.ErrInvalidQueryParams
+	}
+
+	return min(limit, MaxLimit), nil
+}
+
+// ParseTime reads an optional RFC 3339 timestamp or YYYY-MM-DD date query
+// parameter. It returns the zero time when the parameter is missing.
+func ParseTime(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsed, nil
+	}
+
+	parsed, err = time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, apperrors.ErrInvalidQueryParams
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type badgeRepository struct {
+	BaseRepository
+}
+
+type BadgeRepository interface {
+	RepositoryTransaction
+	GetBadgesByUserId(ctx context.Context, tx *sqlx.Tx, userId int) ([]Badge, error)
+	AwardBadge(ctx context.Context, tx *sqlx.Tx, userId int, badgeType string) (Badge, error)
+}
+
+func NewBadgeRepository(db *sqlx.DB) BadgeRepository {
+	return &badgeRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	badgeColumns = `
+	id,
+	user_id,
+	badge_type,
+	earned_at,
+	created_at,
+	updated_at`
+
+	getBadgesByUserIdQuery = "SELECT" + badgeColumns + " from badges where user_id=$1 order by earned_at"
+
+	awardBadgeQuery = `
+	INSERT INTO badges (
+	user_id,
+	badge_type,
+	earned_at
+	)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (user_id, badge_type) DO NOTHING
+	RETURNING` + badgeColumns
+)
+
+func (br *badgeRepository) GetBadgesByUserId(ctx context.Context, tx *sqlx.Tx, userId int) ([]Badge, error) {
+	executer := br.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getBadgesByUserIdQuery, userId)
+	if err != nil {
+		slog.Error("error occurred while listing badges", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	badges := []Badge{}
+	for rows.Next() {
+		badge, err := scanBadge(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning badge", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		badges = append(badges, badge)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating badges", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return badges, nil
+}
+
+// AwardBadge returns ErrBadgeAlreadyAwarded when the user already holds the
+// badge.
+func (br *badgeRepository) AwardBadge(ctx context.Context, tx *sqlx.Tx, userId int, badgeType string) (Badge, error) {
+	executer := br.BaseRepository.initiateQueryExecuter(tx)
+
+	badge, err := scanBadge(executer.QueryRowContext(ctx, awardBadgeQuery, userId, badgeType, time.Now()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Badge{}, apperrors.ErrBadgeAlreadyAwarded
+		}
+		slog.Error("error occurred while awarding badge", "error", err)
+		return Badge{}, apperrors.ErrInternalServer
+	}
+
+	return badge, nil
+}
+
+func scanBadge(row rowScanner) (Badge, error) {
+	var badge Badge
+	err := row.Scan(
+		&badge.Id,
+		&badge.UserId,
+		&badge.BadgeType,
+		&badge.EarnedAt,
+		&badge.CreatedAt,
+		&badge.UpdatedAt,
+	)
+
+	return badge, err
+}
@@ -21,10 +21,17 @@ type RepositoryTransaction interface {
 }
 
 type QueryExecuter interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 }
 
+// rowScanner is satisfied by both *sql.Row and *sql.Rows so scan helpers can
+// be shared between single-row and list queries.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
 func (b *BaseRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
 	tx, err := b.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -70,6 +77,20 @@ func (b *BaseRepository) HandleTransaction(ctx context.Context, tx *sqlx.Tx, inc
 	return nil
 }
 
+// requireAffected returns notFoundErr when an update or delete matched no rows.
+func requireAffected(result sql.Result, notFoundErr error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to read affected rows", "error", err)
+		return apperrors.ErrInternalServer
+	}
+	if affected == 0 {
+		return notFoundErr
+	}
+
+	return nil
+}
+
 func (b *BaseRepository) initiateQueryExecuter(tx *sqlx.Tx) QueryExecuter {
 	if tx != nil {
 		return tx
@@ -0,0 +1,565 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type challengeRepository struct {
+	BaseRepository
+}
+
+type ChallengeRepository interface {
+	RepositoryTransaction
+	CreateChallenge(ctx context.Context, tx *sqlx.Tx, challenge Challenge) (Challenge, error)
+	AddChallengeRepositories(ctx context.Context, tx *sqlx.Tx, challengeId int64, repositoryIds []int) error
+	CreateChallengeMultiplier(ctx context.Context, tx *sqlx.Tx, multiplier ChallengeMultiplier) error
+	CreateChallengePrize(ctx context.Context, tx *sqlx.Tx, prize ChallengePrize) error
+	GetChallengeBySlug(ctx context.Context, tx *sqlx.Tx, slug string) (Challenge, error)
+	LockChallenge(ctx context.Context, tx *sqlx.Tx, challengeId int64) (Challenge, error)
+	ListChallenges(ctx context.Context, tx *sqlx.Tx, status string, limit int, offset int) ([]Challenge, error)
+	ListDueChallengeIds(ctx context.Context, tx *sqlx.Tx, endedBefore time.Time) ([]int64, error)
+	UpdateChallengeStatus(ctx context.Context, tx *sqlx.Tx, challengeId int64, status string, finalizedAt sql.NullTime) error
+	ListChallengeRepositories(ctx context.Context, tx *sqlx.Tx, challengeId int64) ([]EligibleRepository, error)
+	ListChallengeMultipliers(ctx context.Context, tx *sqlx.Tx, challengeId int64) ([]ChallengeMultiplier, error)
+	ListChallengePrizes(ctx context.Context, tx *sqlx.Tx, challengeId int64) ([]ChallengePrize, error)
+	CountChallengeParticipants(ctx context.Context, tx *sqlx.Tx, challengeId int64) (int, error)
+	AddChallengeParticipant(ctx context.Context, tx *sqlx.Tx, challengeId int64, userId int) error
+	RemoveChallengeParticipant(ctx context.Context, tx *sqlx.Tx, challengeId int64, userId int) error
+	ListChallengeStandings(ctx context.Context, tx *sqlx.Tx, challengeId int64, limit int, offset int) ([]ChallengeStanding, error)
+	ListFinalChallengeStandings(ctx context.Context, tx *sqlx.Tx, challengeId int64, limit int, offset int) ([]ChallengeStanding, error)
+	FinalizeChallengeParticipant(ctx context.Context, tx *sqlx.Tx, challengeId int64, standing ChallengeStanding, transactionId sql.NullInt64) error
+}
+
+func NewChallengeRepository(db *sqlx.DB) ChallengeRepository {
+	return &challengeRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	challengeColumns = `
+	id,
+	slug,
+	name,
+	description,
+	starts_at,
+	ends_at,
+	topics,
+	labels,
+	multiplier,
+	status,
+	finalized_at,
+	created_by,
+	created_at,
+	updated_at`
+
+	createChallengeQuery = `
+	INSERT INTO challenges (
+	slug,
+	name,
+	description,
+	starts_at,
+	ends_at,
+	topics,
+	labels,
+	multiplier,
+	created_by
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT DO NOTHING
+	RETURNING` + challengeColumns
+
+	addChallengeRepositoriesQuery = `
+	INSERT INTO challenge_repositories (challenge_id, repository_id)
+	SELECT $1, r.id from repositories r where r.id = ANY($2::bigint[])`
+
+	createChallengeMultiplierQuery = "INSERT INTO challenge_multipliers (challenge_id, contribution_type, multiplier) VALUES ($1, $2, $3)"
+
+	createChallengePrizeQuery = "INSERT INTO challenge_prizes (challenge_id, rank_from, rank_to, points) VALUES ($1, $2, $3, $4)"
+
+	getChallengeBySlugQuery = "SELECT" + challengeColumns + " from challenges where slug=lower($1)"
+
+	lockChallengeQuery = "SELECT" + challengeColumns + " from challenges where id=$1 FOR UPDATE"
+
+	listChallengesQuery = "SELECT" + challengeColumns + " from challenges where ($1='' or status=$1) order by starts_at desc, id desc limit $2 offset $3"
+
+	listDueChallengeIdsQuery = "SELECT id from challenges where status='open' and ends_at<=$1 order by ends_at, id"
+
+	updateChallengeStatusQuery = "UPDATE challenges SET status=$1, finalized_at=$2, updated_at=$3 where id=$4"
+
+	listChallengeRepositoriesQuery = `
+	SELECT r.id, r.owner_name || '/' || r.repo_name
+	from challenge_repositories cr
+	join repositories r on r.id=cr.repository_id
+	where cr.challenge_id=$1
+	order by 2`
+
+	listChallengeMultipliersQuery = "SELECT challenge_id, contribution_type, multiplier from challenge_multipliers where challenge_id=$1 order by contribution_type"
+
+	listChallengePrizesQuery = "SELECT challenge_id, rank_from, rank_to, points from challenge_prizes where challenge_id=$1 order by rank_from"
+
+	countChallengeParticipantsQuery = "SELECT COUNT(*) from challenge_participants where challenge_id=$1"
+
+	addChallengeParticipantQuery = `
+	INSERT INTO challenge_participants (challenge_id, user_id, joined_at)
+	VALUES ($1, $2, $3)
+	ON CONFLICT DO NOTHING`
+
+	removeChallengeParticipantQuery = "DELETE from challenge_participants where challenge_id=$1 and user_id=$2"
+
+	// a contribution counts when it was made inside the window and is
+	// eligible through its repository or labels, worth its points times the multiplier for its
+	// type. Ties share a rank and prizes are only known once finalized. A
+	// limit of 0 lists everyone.
+	listChallengeStandingsQuery = `
+	SELECT s.user_id, s.github_username, s.avatar_url, s.contributions, s.points, RANK() OVER (order by s.points desc), 0
+	from (
+		SELECT
+		u.id AS user_id,
+		u.github_username,
+		u.avatar_url,
+		COUNT(c.id) AS contributions,
+		COALESCE(SUM(ROUND(cs.balance_change * COALESCE(m.multiplier, ch.multiplier))), 0)::bigint AS points
+		from challenge_participants p
+		join challenges ch on ch.id=p.challenge_id
+		join users u on u.id=p.user_id
+		left join (contributions c join contribution_scores cs on cs.contribution_id=c.id join repositories r on r.id=c.repository_id)
+		on c.user_id=p.user_id
+		and cs.status<>'voided'
+		and c.contributed_at>=ch.starts_at and c.contributed_at<ch.ends_at
+		and (
+			(cardinality(ch.topics)=0 and cardinality(ch.labels)=0 and not exists (SELECT 1 from challenge_repositories cr where cr.challenge_id=ch.id))
+			or r.topics && ch.topics
+			or c.labels && ch.labels
+			or exists (SELECT 1 from challenge_repositories cr where cr.challenge_id=ch.id and cr.repository_id=c.repository_id)
+		)
+		left join challenge_multipliers m on m.challenge_id=ch.id and m.contribution_type=c.contribution_type
+		where p.challenge_id=$1
+		and not u.is_blocked
+		and not u.is_deleted
+		group by u.id
+	) s
+	order by s.points desc, s.user_id
+	limit NULLIF($2, 0) offset $3`
+
+	listFinalChallengeStandingsQuery = `
+	SELECT u.id, u.github_username, u.avatar_url, p.final_contributions, p.final_points, p.final_rank, p.prize_points
+	from challenge_participants p
+	join users u on u.id=p.user_id
+	where p.challenge_id=$1 and p.final_rank IS NOT NULL
+	order by p.final_rank, u.id
+	limit $2 offset $3`
+
+	finalizeChallengeParticipantQuery = `
+	UPDATE challenge_participants SET
+	final_contributions=$1,
+	final_points=$2,
+	final_rank=$3,
+	prize_points=$4,
+	transaction_id=$5,
+	updated_at=$6
+	where challenge_id=$7 and user_id=$8`
+)
+
+// CreateChallenge returns ErrChallengeExists when the slug is already taken.
+func (cr *challengeRepository) CreateChallenge(ctx context.Context, tx *sqlx.Tx, challenge Challenge) (Challenge, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanChallenge(executer.QueryRowContext(ctx, createChallengeQuery,
+		challenge.Slug,
+		challenge.Name,
+		challenge.Description,
+		challenge.StartsAt,
+		challenge.EndsAt,
+		pq.Array(challenge.Topics),
+		pq.Array(challenge.Labels),
+		challenge.Multiplier,
+		challenge.CreatedBy,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Challenge{}, apperrors.ErrChallengeExists
+		}
+		slog.Error("error occurred while creating challenge", "error", err)
+		return Challenge{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+// AddChallengeRepositories returns ErrRepoNotFound unless every one of the
+// distinct repositoryIds names a repository.
+func (cr *challengeRepository) AddChallengeRepositories(ctx context.Context, tx *sqlx.Tx, challengeId int64, repositoryIds []int) error {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, addChallengeRepositoriesQuery, challengeId, pq.Array(repositoryIds))
+	if err != nil {
+		slog.Error("failed to add challenge repositories", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to read affected rows", "error", err)
+		return apperrors.ErrInternalServer
+	}
+	if int(affected) != len(repositoryIds) {
+		return apperrors.ErrRepoNotFound
+	}
+
+	return nil
+}
+
+func (cr *challengeRepository) CreateChallengeMultiplier(ctx context.Context, tx *sqlx.Tx, multiplier ChallengeMultiplier) error {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, createChallengeMultiplierQuery, multiplier.ChallengeId, multiplier.ContributionType, multiplier.Multiplier)
+	if err != nil {
+		slog.Error("failed to create challenge multiplier", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (cr *challengeRepository) CreateChallengePrize(ctx context.Context, tx *sqlx.Tx, prize ChallengePrize) error {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, createChallengePrizeQuery, prize.ChallengeId, prize.RankFrom, prize.RankTo, prize.Points)
+	if err != nil {
+		slog.Error("failed to create challenge prize", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (cr *challengeRepository) GetChallengeBySlug(ctx context.Context, tx *sqlx.Tx, slug string) (Challenge, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	challenge, err := scanChallenge(executer.QueryRowContext(ctx, getChallengeBySlugQuery, slug))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Challenge{}, apperrors.ErrChallengeNotFound
+		}
+		slog.Error("error occurred while getting challenge by slug", "error", err)
+		return Challenge{}, apperrors.ErrInternalServer
+	}
+
+	return challenge, nil
+}
+
+// LockChallenge returns the challenge locked until tx ends.
+func (cr *challengeRepository) LockChallenge(ctx context.Context, tx *sqlx.Tx, challengeId int64) (Challenge, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	challenge, err := scanChallenge(executer.QueryRowContext(ctx, lockChallengeQuery, challengeId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Challenge{}, apperrors.ErrChallengeNotFound
+		}
+		slog.Error("error occurred while locking challenge", "error", err)
+		return Challenge{}, apperrors.ErrInternalServer
+	}
+
+	return challenge, nil
+}
+
+// ListChallenges lists challenges with the given status, or all of them
+// when status is empty, most recently started first.
+func (cr *challengeRepository) ListChallenges(ctx context.Context, tx *sqlx.Tx, status string, limit int, offset int) ([]Challenge, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listChallengesQuery, status, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing challenges", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	challenges := []Challenge{}
+	for rows.Next() {
+		challenge, err := scanChallenge(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning challenge", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating challenges", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return challenges, nil
+}
+
+// ListDueChallengeIds lists open challenges that ended before endedBefore.
+func (cr *challengeRepository) ListDueChallengeIds(ctx context.Context, tx *sqlx.Tx, endedBefore time.Time) ([]int64, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listDueChallengeIdsQuery, endedBefore)
+	if err != nil {
+		slog.Error("error occurred while listing due challenges", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	var challengeIds []int64
+	for rows.Next() {
+		var challengeId int64
+		if err := rows.Scan(&challengeId); err != nil {
+			slog.Error("error occurred while scanning challenge id", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		challengeIds = append(challengeIds, challengeId)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating due challenges", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return challengeIds, nil
+}
+
+func (cr *challengeRepository) UpdateChallengeStatus(ctx context.Context, tx *sqlx.Tx, challengeId int64, status string, finalizedAt sql.NullTime) error {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, updateChallengeStatusQuery, status, finalizedAt, time.Now(), challengeId)
+	if err != nil {
+		slog.Error("failed to update challenge status", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrChallengeNotFound)
+}
+
+func (cr *challengeRepository) ListChallengeRepositories(ctx context.Context, tx *sqlx.Tx, challengeId int64) ([]EligibleRepository, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listChallengeRepositoriesQuery, challengeId)
+	if err != nil {
+		slog.Error("error occurred while listing challenge repositories", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	repositories := []EligibleRepository{}
+	for rows.Next() {
+		var repository EligibleRepository
+		err = rows.Scan(&repository.RepositoryId, &repository.FullName)
+		if err != nil {
+			slog.Error("error occurred while scanning challenge repository", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		repositories = append(repositories, repository)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating challenge repositories", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return repositories, nil
+}
+
+func (cr *challengeRepository) ListChallengeMultipliers(ctx context.Context, tx *sqlx.Tx, challengeId int64) ([]ChallengeMultiplier, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listChallengeMultipliersQuery, challengeId)
+	if err != nil {
+		slog.Error("error occurred while listing challenge multipliers", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	multipliers := []ChallengeMultiplier{}
+	for rows.Next() {
+		var multiplier ChallengeMultiplier
+		err = rows.Scan(&multiplier.ChallengeId, &multiplier.ContributionType, &multiplier.Multiplier)
+		if err != nil {
+			slog.Error("error occurred while scanning challenge multiplier", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		multipliers = append(multipliers, multiplier)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating challenge multipliers", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return multipliers, nil
+}
+
+func (cr *challengeRepository) ListChallengePrizes(ctx context.Context, tx *sqlx.Tx, challengeId int64) ([]ChallengePrize, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listChallengePrizesQuery, challengeId)
+	if err != nil {
+		slog.Error("error occurred while listing challenge prizes", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	prizes := []ChallengePrize{}
+	for rows.Next() {
+		var prize ChallengePrize
+		err = rows.Scan(&prize.ChallengeId, &prize.RankFrom, &prize.RankTo, &prize.Points)
+		if err != nil {
+			slog.Error("error occurred while scanning challenge prize", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		prizes = append(prizes, prize)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating challenge prizes", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return prizes, nil
+}
+
+func (cr *challengeRepository) CountChallengeParticipants(ctx context.Context, tx *sqlx.Tx, challengeId int64) (int, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	var count int
+	err := executer.QueryRowContext(ctx, countChallengeParticipantsQuery, challengeId).Scan(&count)
+	if err != nil {
+		slog.Error("error occurred while counting challenge participants", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return count, nil
+}
+
+// AddChallengeParticipant returns ErrAlreadyChallengeParticipant when the
+// user already joined the challenge.
+func (cr *challengeRepository) AddChallengeParticipant(ctx context.Context, tx *sqlx.Tx, challengeId int64, userId int) error {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, addChallengeParticipantQuery, challengeId, userId, time.Now())
+	if err != nil {
+		slog.Error("failed to add challenge participant", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrAlreadyChallengeParticipant)
+}
+
+func (cr *challengeRepository) RemoveChallengeParticipant(ctx context.Context, tx *sqlx.Tx, challengeId int64, userId int) error {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, removeChallengeParticipantQuery, challengeId, userId)
+	if err != nil {
+		slog.Error("failed to remove challenge participant", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrChallengeParticipantNotFound)
+}
+
+// ListChallengeStandings computes the standings from the participants'
+// contributions. A limit of 0 lists every participant.
+func (cr *challengeRepository) ListChallengeStandings(ctx context.Context, tx *sqlx.Tx, challengeId int64, limit int, offset int) ([]ChallengeStanding, error) {
+	return cr.listChallengeStandings(ctx, tx, listChallengeStandingsQuery, challengeId, limit, offset)
+}
+
+// ListFinalChallengeStandings reads the standings frozen when the challenge
+// was finalized.
+func (cr *challengeRepository) ListFinalChallengeStandings(ctx context.Context, tx *sqlx.Tx, challengeId int64, limit int, offset int) ([]ChallengeStanding, error) {
+	return cr.listChallengeStandings(ctx, tx, listFinalChallengeStandingsQuery, challengeId, limit, offset)
+}
+
+func (cr *challengeRepository) listChallengeStandings(ctx context.Context, tx *sqlx.Tx, query string, challengeId int64, limit int, offset int) ([]ChallengeStanding, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, query, challengeId, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing challenge standings", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	standings := []ChallengeStanding{}
+	for rows.Next() {
+		var standing ChallengeStanding
+		err = rows.Scan(
+			&standing.UserId,
+			&standing.GithubUsername,
+			&standing.AvatarUrl,
+			&standing.Contributions,
+			&standing.Points,
+			&standing.Rank,
+			&standing.PrizePoints,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning challenge standing", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		standings = append(standings, standing)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating challenge standings", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return standings, nil
+}
+
+// FinalizeChallengeParticipant freezes the participant's standing and the
+// wallet transaction paying their prize, if any.
+func (cr *challengeRepository) FinalizeChallengeParticipant(ctx context.Context, tx *sqlx.Tx, challengeId int64, standing ChallengeStanding, transactionId sql.NullInt64) error {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, finalizeChallengeParticipantQuery,
+		standing.Contributions,
+		standing.Points,
+		standing.Rank,
+		standing.PrizePoints,
+		transactionId,
+		time.Now(),
+		challengeId,
+		standing.UserId,
+	)
+	if err != nil {
+		slog.Error("failed to finalize challenge participant", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrChallengeParticipantNotFound)
+}
+
+func scanChallenge(row rowScanner) (Challenge, error) {
+	var challenge Challenge
+	err := row.Scan(
+		&challenge.Id,
+		&challenge.Slug,
+		&challenge.Name,
+		&challenge.Description,
+		&challenge.StartsAt,
+		&challenge.EndsAt,
+		pq.Array(&challenge.Topics),
+		pq.Array(&challenge.Labels),
+		&challenge.Multiplier,
+		&challenge.Status,
+		&challenge.FinalizedAt,
+		&challenge.CreatedBy,
+		&challenge.CreatedAt,
+		&challenge.UpdatedAt,
+	)
+
+	return challenge, err
+}
@@ -0,0 +1,383 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type contributionRepository struct {
+	BaseRepository
+}
+
+type ContributionRepository interface {
+	RepositoryTransaction
+	CreateContribution(ctx context.Context, tx *sqlx.Tx, contributionInfo Contribution) (Contribution, error)
+	GetContributionsByUserId(ctx context.Context, tx *sqlx.Tx, userId int) ([]Contribution, error)
+	GetContributionByIdForUpdate(ctx context.Context, tx *sqlx.Tx, contributionId int) (Contribution, error)
+	ListContributions(ctx context.Context, tx *sqlx.Tx, filter ContributionFilter) ([]ContributionFeedItem, error)
+	GetContributionByExternalId(ctx context.Context, tx *sqlx.Tx, externalId string) (Contribution, error)
+	CountUserContributionsBetween(ctx context.Context, tx *sqlx.Tx, userId int, from time.Time, to time.Time, excludeDeliveryId string) (int, error)
+	CountOtherRepositoryContributors(ctx context.Context, tx *sqlx.Tx, repositoryId int, userId int) (int, error)
+	IsCommitShaRecorded(ctx context.Context, tx *sqlx.Tx, sha string, externalId string) (bool, error)
+}
+
+func NewContributionRepository(db *sqlx.DB) ContributionRepository {
+	return &contributionRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	// the balance change and status in effect come from contribution_scores,
+	// contributions keep what was recorded at ingestion
+	contributionColumns = `
+	c.id,
+	c.user_id,
+	c.repository_id,
+	c.contribution_score_id,
+	c.contribution_type,
+	cs.balance_change,
+	c.contributed_at,
+	c.created_at,
+	c.updated_at,
+	c.external_id,
+	c.base_score,
+	c.multiplier,
+	c.bonus,
+	cs.status,
+	c.delivery_id`
+
+	scoredContributionsTable = " from contributions c join contribution_scores cs on cs.contribution_id=c.id"
+
+	// a contribution that was just recorded has no adjustments yet
+	recordedContributionColumns = `
+	id,
+	user_id,
+	repository_id,
+	contribution_score_id,
+	contribution_type,
+	balance_change,
+	contributed_at,
+	created_at,
+	updated_at,
+	external_id,
+	base_score,
+	multiplier,
+	bonus,
+	'active',
+	delivery_id`
+
+	contributionFeedColumns = contributionColumns + `,
+	r.repo_name,
+	r.owner_name,
+	r.language`
+
+	createContributionQuery = `
+	INSERT INTO contributions (
+	user_id,
+	repository_id,
+	contribution_score_id,
+	contribution_type,
+	balance_change,
+	contributed_at,
+	external_id,
+	base_score,
+	multiplier,
+	bonus,
+	delivery_id,
+	labels
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	ON CONFLICT (external_id) DO NOTHING
+	RETURNING` + recordedContributionColumns
+
+	getContributionsByUserIdQuery = "SELECT" + contributionColumns + scoredContributionsTable + " where c.user_id=$1 order by c.contributed_at"
+
+	getContributionByIdForUpdateQuery = "SELECT" + contributionColumns + scoredContributionsTable + " where c.id=$1 FOR UPDATE OF c"
+
+	getContributionByExternalIdQuery = "SELECT" + contributionColumns + scoredContributionsTable + " where c.external_id=$1"
+
+	// contributions without a delivery count one each, the commits of a push
+	// count once, and the delivery being screened is left out
+	countUserContributionsBetweenQuery = `
+	SELECT count(DISTINCT COALESCE(delivery_id, 'contribution:' || id))
+	from contributions
+	where user_id=$1 and contributed_at>=$2 and contributed_at<=$3
+	and delivery_id IS DISTINCT FROM $4`
+
+	countOtherRepositoryContributorsQuery = "SELECT count(DISTINCT user_id) from contributions where repository_id=$1 and user_id<>$2"
+
+	// matches the partial contributions_commit_sha_index
+	isCommitShaRecordedQuery = "SELECT exists(SELECT 1 from contributions where external_id LIKE 'commit:%' and split_part(external_id, ':', 3)=$1 and external_id<>$2)"
+
+	listContributionsQuery = `
+	SELECT` + contributionFeedColumns + scoredContributionsTable + `
+	join repositories r on r.id=c.repository_id
+	join users u on u.id=c.user_id
+	where not u.is_deleted
+	and ($1=0 or c.user_id=$1)
+	and ($2='' or c.contribution_type=$2)
+	and ($3=0 or c.repository_id=$3)
+	and ($4::timestamptz IS NULL or c.contributed_at>=$4)
+	and ($5::timestamptz IS NULL or c.contributed_at<$5)
+	and ($6='' or lower(r.language)=lower($6))`
+)
+
+// ContributionSort values accepted by ListContributions
+const (
+	SortNewest       = "newest"
+	SortOldest       = "oldest"
+	SortHighestScore = "highest_score"
+)
+
+// contributionFeedOrders holds the keyset condition and ordering for each
+// sort, continuing after the cursor ($7 id, $8 sort key of the last item)
+var contributionFeedOrders = map[string]string{
+	SortNewest: `
+	and ($7=0 or (c.contributed_at, c.id)<($8::timestamptz, $7))
+	order by c.contributed_at desc, c.id desc
+	limit $9`,
+	SortOldest: `
+	and ($7=0 or (c.contributed_at, c.id)>($8::timestamptz, $7))
+	order by c.contributed_at, c.id
+	limit $9`,
+	SortHighestScore: `
+	and ($7=0 or (cs.balance_change, c.id)<($8::bigint, $7))
+	order by cs.balance_change desc, c.id desc
+	limit $9`,
+}
+
+// CreateContribution returns ErrContributionAlreadyRecorded when a
+// contribution with the same external id exists, so redelivered events are
+// never scored twice.
+func (cr *contributionRepository) CreateContribution(ctx context.Context, tx *sqlx.Tx, contributionInfo Contribution) (Contribution, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	contribution, err := scanContribution(executer.QueryRowContext(ctx, createContributionQuery,
+		contributionInfo.UserId,
+		contributionInfo.RepositoryId,
+		contributionInfo.ContributionScoreId,
+		contributionInfo.ContributionType,
+		contributionInfo.BalanceChange,
+		contributionInfo.ContributedAt,
+		contributionInfo.ExternalId,
+		contributionInfo.BaseScore,
+		contributionInfo.Multiplier,
+		contributionInfo.Bonus,
+		contributionInfo.DeliveryId,
+		pq.Array(contributionInfo.Labels),
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Contribution{}, apperrors.ErrContributionAlreadyRecorded
+		}
+		slog.Error("error occurred while creating contribution", "error", err)
+		return Contribution{}, apperrors.ErrInternalServer
+	}
+
+	return contribution, nil
+}
+
+func (cr *contributionRepository) GetContributionsByUserId(ctx context.Context, tx *sqlx.Tx, userId int) ([]Contribution, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getContributionsByUserIdQuery, userId)
+	if err != nil {
+		slog.Error("error occurred while listing contributions", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	contributions := []Contribution{}
+	for rows.Next() {
+		contribution, err := scanContribution(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning contribution", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		contributions = append(contributions, contribution)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating contributions", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return contributions, nil
+}
+
+func (cr *contributionRepository) GetContributionByIdForUpdate(ctx context.Context, tx *sqlx.Tx, contributionId int) (Contribution, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	contribution, err := scanContribution(executer.QueryRowContext(ctx, getContributionByIdForUpdateQuery, contributionId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Contribution{}, apperrors.ErrContributionNotFound
+		}
+		slog.Error("error occurred while getting contribution by id", "error", err)
+		return Contribution{}, apperrors.ErrInternalServer
+	}
+
+	return contribution, nil
+}
+
+// ListContributions returns one page of contributions of non-deleted users
+// matching the filter. An unknown sort falls back to newest first.
+func (cr *contributionRepository) ListContributions(ctx context.Context, tx *sqlx.Tx, filter ContributionFilter) ([]ContributionFeedItem, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	order, ok := contributionFeedOrders[filter.Sort]
+	if !ok {
+		filter.Sort = SortNewest
+		order = contributionFeedOrders[SortNewest]
+	}
+
+	var cursor any = filter.CursorContributedAt
+	if filter.Sort == SortHighestScore {
+		cursor = filter.CursorBalanceChange
+	}
+
+	rows, err := executer.QueryContext(ctx, listContributionsQuery+order,
+		filter.UserId,
+		filter.ContributionType,
+		filter.RepositoryId,
+		filter.From,
+		filter.To,
+		filter.Language,
+		filter.CursorId,
+		cursor,
+		filter.Limit,
+	)
+	if err != nil {
+		slog.Error("error occurred while listing contribution feed", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	items := []ContributionFeedItem{}
+	for rows.Next() {
+		var item ContributionFeedItem
+		err := rows.Scan(
+			&item.Id,
+			&item.UserId,
+			&item.RepositoryId,
+			&item.ContributionScoreId,
+			&item.ContributionType,
+			&item.BalanceChange,
+			&item.ContributedAt,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.ExternalId,
+			&item.BaseScore,
+			&item.Multiplier,
+			&item.Bonus,
+			&item.Status,
+			&item.DeliveryId,
+			&item.RepoName,
+			&item.OwnerName,
+			&item.Language,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning contribution feed item", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating contribution feed", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return items, nil
+}
+
+func scanContribution(row rowScanner) (Contribution, error) {
+	var contribution Contribution
+	err := row.Scan(
+		&contribution.Id,
+		&contribution.UserId,
+		&contribution.RepositoryId,
+		&contribution.ContributionScoreId,
+		&contribution.ContributionType,
+		&contribution.BalanceChange,
+		&contribution.ContributedAt,
+		&contribution.CreatedAt,
+		&contribution.UpdatedAt,
+		&contribution.ExternalId,
+		&contribution.BaseScore,
+		&contribution.Multiplier,
+		&contribution.Bonus,
+		&contribution.Status,
+		&contribution.DeliveryId,
+	)
+
+	return contribution, err
+}
+
+func (cr *contributionRepository) GetContributionByExternalId(ctx context.Context, tx *sqlx.Tx, externalId string) (Contribution, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	contribution, err := scanContribution(executer.QueryRowContext(ctx, getContributionByExternalIdQuery, externalId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Contribution{}, apperrors.ErrContributionNotFound
+		}
+		slog.Error("error occurred while getting contribution by external id", "error", err)
+		return Contribution{}, apperrors.ErrInternalServer
+	}
+
+	return contribution, nil
+}
+
+// CountUserContributionsBetween counts the contributions the user made from
+// from to to, leaving out those recorded from excludeDeliveryId.
+func (cr *contributionRepository) CountUserContributionsBetween(ctx context.Context, tx *sqlx.Tx, userId int, from time.Time, to time.Time, excludeDeliveryId string) (int, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	var count int
+	err := executer.QueryRowContext(ctx, countUserContributionsBetweenQuery, userId, from, to, sql.NullString{String: excludeDeliveryId, Valid: excludeDeliveryId != ""}).Scan(&count)
+	if err != nil {
+		slog.Error("error occurred while counting user contributions", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return count, nil
+}
+
+// CountOtherRepositoryContributors counts the registered users other than
+// userId with a contribution to the repository.
+func (cr *contributionRepository) CountOtherRepositoryContributors(ctx context.Context, tx *sqlx.Tx, repositoryId int, userId int) (int, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	var count int
+	err := executer.QueryRowContext(ctx, countOtherRepositoryContributorsQuery, repositoryId, userId).Scan(&count)
+	if err != nil {
+		slog.Error("error occurred while counting repository contributors", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return count, nil
+}
+
+// IsCommitShaRecorded reports whether the commit was already recorded under
+// another external id, which happens when it is pushed to a fork.
+func (cr *contributionRepository) IsCommitShaRecorded(ctx context.Context, tx *sqlx.Tx, sha string, externalId string) (bool, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	var recorded bool
+	err := executer.QueryRowContext(ctx, isCommitShaRecordedQuery, sha, externalId).Scan(&recorded)
+	if err != nil {
+		slog.Error("error occurred while looking up commit sha", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	return recorded, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type contributionAdjustmentRepository struct {
+	BaseRepository
+}
+
+type ContributionAdjustmentRepository interface {
+	RepositoryTransaction
+	CreateContributionAdjustment(ctx context.Context, tx *sqlx.Tx, adjustment ContributionAdjustment) (ContributionAdjustment, error)
+}
+
+func NewContributionAdjustmentRepository(db *sqlx.DB) ContributionAdjustmentRepository {
+	return &contributionAdjustmentRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	contributionAdjustmentColumns = `
+	id,
+	contribution_id,
+	dispute_id,
+	admin_id,
+	action,
+	previous_balance_change,
+	new_balance_change,
+	reason,
+	created_at,
+	updated_at`
+
+	createContributionAdjustmentQuery = `
+	INSERT INTO contribution_adjustments (
+	contribution_id,
+	dispute_id,
+	admin_id,
+	action,
+	previous_balance_change,
+	new_balance_change,
+	reason
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING` + contributionAdjustmentColumns
+)
+
+func (ar *contributionAdjustmentRepository) CreateContributionAdjustment(ctx context.Context, tx *sqlx.Tx, adjustment ContributionAdjustment) (ContributionAdjustment, error) {
+	executer := ar.BaseRepository.initiateQueryExecuter(tx)
+
+	var created ContributionAdjustment
+	err := executer.QueryRowContext(ctx, createContributionAdjustmentQuery,
+		adjustment.ContributionId,
+		adjustment.DisputeId,
+		adjustment.AdminId,
+		adjustment.Action,
+		adjustment.PreviousBalanceChange,
+		adjustment.NewBalanceChange,
+		adjustment.Reason,
+	).Scan(
+		&created.Id,
+		&created.ContributionId,
+		&created.DisputeId,
+		&created.AdminId,
+		&created.Action,
+		&created.PreviousBalanceChange,
+		&created.NewBalanceChange,
+		&created.Reason,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if err != nil {
+		slog.Error("error occurred while creating contribution adjustment", "error", err)
+		return ContributionAdjustment{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type contributionDisputeRepository struct {
+	BaseRepository
+}
+
+type ContributionDisputeRepository interface {
+	RepositoryTransaction
+	CreateDispute(ctx context.Context, tx *sqlx.Tx, dispute ContributionDispute) (ContributionDispute, error)
+	GetDisputeByIdForUpdate(ctx context.Context, tx *sqlx.Tx, disputeId int) (ContributionDispute, error)
+	ListDisputes(ctx context.Context, tx *sqlx.Tx, status string, limit int, offset int) ([]ContributionDispute, error)
+	ResolveDispute(ctx context.Context, tx *sqlx.Tx, disputeId int, status string, adminId int, note string) error
+	ResolveOpenDisputesForContribution(ctx context.Context, tx *sqlx.Tx, contributionId int, status string, adminId int, note string) error
+}
+
+func NewContributionDisputeRepository(db *sqlx.DB) ContributionDisputeRepository {
+	return &contributionDisputeRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	contributionDisputeColumns = `
+	id,
+	contribution_id,
+	user_id,
+	reason,
+	status,
+	resolution_note,
+	resolved_by,
+	resolved_at,
+	created_at,
+	updated_at`
+
+	createDisputeQuery = `
+	INSERT INTO contribution_disputes (
+	contribution_id,
+	user_id,
+	reason
+	)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (contribution_id) WHERE status='open' DO NOTHING
+	RETURNING` + contributionDisputeColumns
+
+	getDisputeByIdForUpdateQuery = "SELECT" + contributionDisputeColumns + " from contribution_disputes where id=$1 FOR UPDATE"
+
+	listDisputesQuery = "SELECT" + contributionDisputeColumns + " from contribution_disputes where ($1='' or status=$1) order by id desc limit $2 offset $3"
+
+	resolveDisputeQuery = "UPDATE contribution_disputes SET status=$1, resolved_by=$2, resolution_note=$3, resolved_at=$4, updated_at=$4 where id=$5 and status='open'"
+
+	resolveOpenDisputesForContributionQuery = "UPDATE contribution_disputes SET status=$1, resolved_by=$2, resolution_note=$3, resolved_at=$4, updated_at=$4 where contribution_id=$5 and status='open'"
+)
+
+// CreateDispute returns ErrDisputeAlreadyOpen when the contribution already
+// has an open dispute.
+func (dr *contributionDisputeRepository) CreateDispute(ctx context.Context, tx *sqlx.Tx, dispute ContributionDispute) (ContributionDispute, error) {
+	executer := dr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanContributionDispute(executer.QueryRowContext(ctx, createDisputeQuery,
+		dispute.ContributionId,
+		dispute.UserId,
+		dispute.Reason,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ContributionDispute{}, apperrors.ErrDisputeAlreadyOpen
+		}
+		slog.Error("error occurred while creating dispute", "error", err)
+		return ContributionDispute{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (dr *contributionDisputeRepository) GetDisputeByIdForUpdate(ctx context.Context, tx *sqlx.Tx, disputeId int) (ContributionDispute, error) {
+	executer := dr.BaseRepository.initiateQueryExecuter(tx)
+
+	dispute, err := scanContributionDispute(executer.QueryRowContext(ctx, getDisputeByIdForUpdateQuery, disputeId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ContributionDispute{}, apperrors.ErrDisputeNotFound
+		}
+		slog.Error("error occurred while getting dispute by id", "error", err)
+		return ContributionDispute{}, apperrors.ErrInternalServer
+	}
+
+	return dispute, nil
+}
+
+func (dr *contributionDisputeRepository) ListDisputes(ctx context.Context, tx *sqlx.Tx, status string, limit int, offset int) ([]ContributionDispute, error) {
+	executer := dr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listDisputesQuery, status, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing disputes", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	disputes := []ContributionDispute{}
+	for rows.Next() {
+		dispute, err := scanContributionDispute(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning dispute", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		disputes = append(disputes, dispute)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating disputes", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return disputes, nil
+}
+
+func (dr *contributionDisputeRepository) ResolveDispute(ctx context.Context, tx *sqlx.Tx, disputeId int, status string, adminId int, note string) error {
+	executer := dr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, resolveDisputeQuery, status, adminId, note, time.Now(), disputeId)
+	if err != nil {
+		slog.Error("failed to resolve dispute", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrDisputeNotOpen)
+}
+
+// ResolveOpenDisputesForContribution closes a dispute made moot by an admin
+// adjusting the contribution directly.
+func (dr *contributionDisputeRepository) ResolveOpenDisputesForContribution(ctx context.Context, tx *sqlx.Tx, contributionId int, status string, adminId int, note string) error {
+	executer := dr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, resolveOpenDisputesForContributionQuery, status, adminId, note, time.Now(), contributionId)
+	if err != nil {
+		slog.Error("failed to resolve open disputes of contribution", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func scanContributionDispute(row rowScanner) (ContributionDispute, error) {
+	var dispute ContributionDispute
+	err := row.Scan(
+		&dispute.Id,
+		&dispute.ContributionId,
+		&dispute.UserId,
+		&dispute.Reason,
+		&dispute.Status,
+		&dispute.ResolutionNote,
+		&dispute.ResolvedBy,
+		&dispute.ResolvedAt,
+		&dispute.CreatedAt,
+		&dispute.UpdatedAt,
+	)
+
+	return dispute, err
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type contributionFlagRepository struct {
+	BaseRepository
+}
+
+type ContributionFlagRepository interface {
+	RepositoryTransaction
+	CreateContributionFlag(ctx context.Context, tx *sqlx.Tx, flag ContributionFlag) (ContributionFlag, error)
+	GetContributionFlagByIdForUpdate(ctx context.Context, tx *sqlx.Tx, flagId int64) (ContributionFlag, error)
+	ListContributionFlags(ctx context.Context, tx *sqlx.Tx, filter ContributionFlagFilter) ([]ContributionFlag, error)
+	ReviewContributionFlag(ctx context.Context, tx *sqlx.Tx, flagId int64, status string, adminId int, note string) error
+}
+
+func NewContributionFlagRepository(db *sqlx.DB) ContributionFlagRepository {
+	return &contributionFlagRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	contributionFlagColumns = `
+	id,
+	user_id,
+	contribution_id,
+	external_id,
+	rule,
+	action,
+	reason,
+	risk_points,
+	status,
+	review_note,
+	reviewed_by,
+	reviewed_at,
+	created_at,
+	updated_at`
+
+	createContributionFlagQuery = `
+	INSERT INTO contribution_flags (
+	user_id,
+	contribution_id,
+	external_id,
+	rule,
+	action,
+	reason,
+	risk_points,
+	status
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (external_id, rule) DO NOTHING
+	RETURNING` + contributionFlagColumns
+
+	getContributionFlagByIdForUpdateQuery = "SELECT" + contributionFlagColumns + " from contribution_flags where id=$1 FOR UPDATE"
+
+	listContributionFlagsQuery = `
+	SELECT` + contributionFlagColumns + `
+	from contribution_flags
+	where ($1='' or status=$1)
+	and ($2=0 or user_id=$2)
+	order by id desc
+	limit $3 offset $4`
+
+	reviewContributionFlagQuery = "UPDATE contribution_flags SET status=$1, reviewed_by=$2, review_note=$3, reviewed_at=$4, updated_at=$4 where id=$5 and status='pending'"
+)
+
+// CreateContributionFlag returns ErrContributionFlagExists when the rule has
+// already flagged the contribution, so reprocessed deliveries do not add risk
+// twice.
+func (fr *contributionFlagRepository) CreateContributionFlag(ctx context.Context, tx *sqlx.Tx, flag ContributionFlag) (ContributionFlag, error) {
+	executer := fr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanContributionFlag(executer.QueryRowContext(ctx, createContributionFlagQuery,
+		flag.UserId,
+		flag.ContributionId,
+		flag.ExternalId,
+		flag.Rule,
+		flag.Action,
+		flag.Reason,
+		flag.RiskPoints,
+		flag.Status,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ContributionFlag{}, apperrors.ErrContributionFlagExists
+		}
+		slog.Error("error occurred while creating contribution flag", "error", err)
+		return ContributionFlag{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (fr *contributionFlagRepository) GetContributionFlagByIdForUpdate(ctx context.Context, tx *sqlx.Tx, flagId int64) (ContributionFlag, error) {
+	executer := fr.BaseRepository.initiateQueryExecuter(tx)
+
+	flag, err := scanContributionFlag(executer.QueryRowContext(ctx, getContributionFlagByIdForUpdateQuery, flagId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ContributionFlag{}, apperrors.ErrContributionFlagNotFound
+		}
+		slog.Error("error occurred while getting contribution flag by id", "error", err)
+		return ContributionFlag{}, apperrors.ErrInternalServer
+	}
+
+	return flag, nil
+}
+
+func (fr *contributionFlagRepository) ListContributionFlags(ctx context.Context, tx *sqlx.Tx, filter ContributionFlagFilter) ([]ContributionFlag, error) {
+	executer := fr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listContributionFlagsQuery, filter.Status, filter.UserId, filter.Limit, filter.Offset)
+	if err != nil {
+		slog.Error("error occurred while listing contribution flags", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	flags := []ContributionFlag{}
+	for rows.Next() {
+		flag, err := scanContributionFlag(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning contribution flag", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		flags = append(flags, flag)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating contribution flags", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return flags, nil
+}
+
+func (fr *contributionFlagRepository) ReviewContributionFlag(ctx context.Context, tx *sqlx.Tx, flagId int64, status string, adminId int, note string) error {
+	executer := fr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, reviewContributionFlagQuery, status, adminId, note, time.Now(), flagId)
+	if err != nil {
+		slog.Error("failed to review contribution flag", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrContributionFlagReviewed)
+}
+
+func scanContributionFlag(row rowScanner) (ContributionFlag, error) {
+	var flag ContributionFlag
+	err := row.Scan(
+		&flag.Id,
+		&flag.UserId,
+		&flag.ContributionId,
+		&flag.ExternalId,
+		&flag.Rule,
+		&flag.Action,
+		&flag.Reason,
+		&flag.RiskPoints,
+		&flag.Status,
+		&flag.ReviewNote,
+		&flag.ReviewedBy,
+		&flag.ReviewedAt,
+		&flag.CreatedAt,
+		&flag.UpdatedAt,
+	)
+
+	return flag, err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type contributionScoreRepository struct {
+	BaseRepository
+}
+
+type ContributionScoreRepository interface {
+	RepositoryTransaction
+	GetContributionScoreByType(ctx context.Context, tx *sqlx.Tx, contributionType string) (ContributionScore, error)
+}
+
+func NewContributionScoreRepository(db *sqlx.DB) ContributionScoreRepository {
+	return &contributionScoreRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	contributionScoreColumns = `
+	id,
+	admin_id,
+	contribution_type,
+	score,
+	created_at,
+	updated_at`
+
+	getContributionScoreByTypeQuery = "SELECT" + contributionScoreColumns + " from contribution_score where contribution_type=$1 order by id desc limit 1"
+)
+
+// GetContributionScoreByType returns the most recently configured score for
+// the contribution type.
+func (cr *contributionScoreRepository) GetContributionScoreByType(ctx context.Context, tx *sqlx.Tx, contributionType string) (ContributionScore, error) {
+	executer := cr.BaseRepository.initiateQueryExecuter(tx)
+
+	var score ContributionScore
+	err := executer.QueryRowContext(ctx, getContributionScoreByTypeQuery, contributionType).Scan(
+		&score.Id,
+		&score.AdminId,
+		&score.ContributionType,
+		&score.Score,
+		&score.CreatedAt,
+		&score.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ContributionScore{}, apperrors.ErrContributionScoreNotFound
+		}
+		slog.Error("error occurred while getting contribution score by type", "error", err)
+		return ContributionScore{}, apperrors.ErrInternalServer
+	}
+
+	return score, nil
+}
@@ -14,18 +14,672 @@ type User struct {
 	CurrentBalance      int
 	CurrentActiveGoalId sql.NullInt64
 	IsBlocked           bool
-	IsAdmin             bool
 	Password            string
 	IsDeleted           bool
 	DeletedAt           sql.NullTime
+	Timezone            string
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
 }
 
+// UserSession is what a signed in user may do. Roles include the user role
+// every signed in user holds.
+type UserSession struct {
+	UserId      int
+	SponsorId   sql.NullInt64
+	Roles       []string
+	Permissions []string
+}
+
 type CreateUserRequestBody struct {
 	GithubId       int
 	GithubUsername string
 	AvatarUrl      string
 	Email          string
-	IsAdmin        bool
+}
+
+type GithubToken struct {
+	Id            int
+	UserId        int
+	KeyId         string
+	WrappedKey    []byte
+	AccessToken   []byte
+	RefreshToken  []byte
+	TokenType     string
+	ExpiresAt     sql.NullTime
+	IsValid       bool
+	InvalidatedAt sql.NullTime
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type Contribution struct {
+	Id                  int
+	UserId              int
+	RepositoryId        int
+	ContributionScoreId int
+	ContributionType    string
+	BalanceChange       int
+	ContributedAt       time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	ExternalId          sql.NullString
+	BaseScore           int
+	Multiplier          float64
+	Bonus               int
+	Status              string
+	DeliveryId          sql.NullString
+	Labels              []string
+}
+
+// ContributionFilter narrows a contribution listing. Zero values disable a
+// filter. The cursor fields hold the sort key of the last item of the
+// previous page, CursorId being 0 on the first page.
+type ContributionFilter struct {
+	UserId              int
+	ContributionType    string
+	RepositoryId        int
+	From                sql.NullTime
+	To                  sql.NullTime
+	Language            string
+	Sort                string
+	CursorContributedAt time.Time
+	CursorBalanceChange int
+	CursorId            int
+	Limit               int
+}
+
+type ContributionFeedItem struct {
+	Contribution
+	RepoName  string
+	OwnerName string
+	Language  string
+}
+
+type ContributionScore struct {
+	Id               int
+	AdminId          int
+	ContributionType string
+	Score            int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type Repo struct {
+	Id           int
+	GithubRepoId int
+	RepoName     string
+	Description  string
+	LanguagesUrl string
+	RepoUrl      string
+	OwnerName    string
+	UpdateDate   time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Language     string
+	Stars        int
+	Topics       []string
+	License      string
+	IsArchived   bool
+}
+
+type WebhookDelivery struct {
+	Id          int
+	DeliveryId  string
+	Event       string
+	Action      string
+	Payload     []byte
+	Status      string
+	Attempts    int
+	LastError   sql.NullString
+	ProcessedAt sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Job struct {
+	Id          int64
+	Type        string
+	Payload     []byte
+	Status      string
+	UniqueKey   sql.NullString
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	LockedAt    sql.NullTime
+	LockedBy    sql.NullString
+	LastError   sql.NullString
+	CompletedAt sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type ScheduledTaskRun struct {
+	Id           int64
+	TaskName     string
+	ScheduledFor time.Time
+	Trigger      string
+	Status       string
+	Error        sql.NullString
+	StartedAt    time.Time
+	FinishedAt   sql.NullTime
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type Transaction struct {
+	Id                int
+	UserId            int
+	ContributionId    sql.NullInt64
+	RedemptionId      sql.NullInt64
+	IsRedeemed        bool
+	IsGained          bool
+	TransactedBalance int
+	TransactedAt      time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+type Badge struct {
+	Id        int
+	UserId    int
+	BadgeType string
+	EarnedAt  time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Summary struct {
+	Id             int
+	UserId         int
+	MonthYear      int
+	NetBalance     int
+	BadgesCount    int
+	Rank           int
+	ContributionId int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type PrivacySetting struct {
+	UserId       int
+	HideBalance  bool
+	HideActivity bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type RepositoryContributionCount struct {
+	RepoName      string
+	OwnerName     string
+	RepoUrl       string
+	Language      string
+	Contributions int
+}
+
+type LanguageContributionCount struct {
+	Language      string
+	Contributions int
+}
+
+type ContributionDispute struct {
+	Id             int
+	ContributionId int
+	UserId         int
+	Reason         string
+	Status         string
+	ResolutionNote sql.NullString
+	ResolvedBy     sql.NullInt64
+	ResolvedAt     sql.NullTime
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type ContributionAdjustment struct {
+	Id                    int
+	ContributionId        int
+	DisputeId             sql.NullInt64
+	AdminId               int
+	Action                string
+	PreviousBalanceChange int
+	NewBalanceChange      int
+	Reason                string
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+type Notification struct {
+	Id        int64
+	UserId    int
+	Type      string
+	Title     string
+	Body      string
+	Data      []byte
+	ReadAt    sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type NotificationPreference struct {
+	UserId int
+	Type   string
+	InApp  bool
+	Email  bool
+}
+
+type DigestRecipient struct {
+	UserId              int
+	GithubUsername      string
+	Email               string
+	Contributions       int
+	Points              int
+	UnreadNotifications int
+}
+
+type ContributionFlag struct {
+	Id             int64
+	UserId         int
+	ContributionId sql.NullInt64
+	ExternalId     string
+	Rule           string
+	Action         string
+	Reason         string
+	RiskPoints     int
+	Status         string
+	ReviewNote     sql.NullString
+	ReviewedBy     sql.NullInt64
+	ReviewedAt     sql.NullTime
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type ContributionFlagFilter struct {
+	Status string
+	UserId int
+	Limit  int
+	Offset int
+}
+
+type UserRiskScore struct {
+	UserId         int
+	GithubUsername string
+	IsBlocked      bool
+	Score          int
+	UpdatedAt      time.Time
+}
+
+// RepoStats is a tracked repository with what registered users earned on it
+type RepoStats struct {
+	Repo
+	Contributors int
+	TotalPoints  int
+}
+
+type RepositoryAccessRule struct {
+	Id        int
+	OwnerName string
+	RepoName  sql.NullString
+	Access    string
+	Reason    string
+	CreatedBy int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RepositoryLanguage struct {
+	RepositoryId int
+	Language     string
+	Bytes        int64
+	Share        float64
+}
+
+type UserLanguageStat struct {
+	Language      string
+	Contributions int
+	Repositories  int
+	Points        int
+}
+
+type LanguageLeaderboardEntry struct {
+	UserId         int
+	GithubUsername string
+	AvatarUrl      string
+	Contributions  int
+	Points         int
+	Rank           int
+}
+
+type Sponsor struct {
+	Id           int
+	Name         string
+	WebsiteUrl   string
+	PointsPerUsd int
+	IsActive     bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// SponsorBudget is a sponsor with its ledger totals. Reserved is owed to
+// funded redemptions that have not been fulfilled yet.
+type SponsorBudget struct {
+	Sponsor
+	EarmarkedOwners []string
+	PledgedCents    int64
+	SpentCents      int64
+	ReservedCents   int64
+	BalanceCents    int64
+}
+
+type SponsorBudgetEntry struct {
+	Id           int
+	SponsorId    int
+	EntryType    string
+	AmountCents  int64
+	RedemptionId sql.NullInt64
+	Note         string
+	CreatedBy    sql.NullInt64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type Redemption struct {
+	Id          int
+	UserId      int
+	SponsorId   sql.NullInt64
+	Points      int
+	AmountCents int64
+	Store       string
+	Status      string
+	Note        string
+	ReviewedBy  sql.NullInt64
+	ReviewedAt  sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type RedemptionFilter struct {
+	UserId    int
+	SponsorId int
+	Status    string
+	Limit     int
+	Offset    int
+}
+
+type SponsorInvitation struct {
+	Id             int
+	SponsorId      int
+	GithubUsername string
+	TokenHash      string
+	InvitedBy      int
+	AcceptedBy     sql.NullInt64
+	AcceptedAt     sql.NullTime
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// SponsorContribution is a contribution made to a repository a sponsor is
+// earmarked for.
+type SponsorContribution struct {
+	Id               int
+	GithubUsername   string
+	RepositoryName   string
+	ContributionType string
+	BalanceChange    int
+	ContributedAt    time.Time
+}
+
+type SponsorContributor struct {
+	UserId         int
+	GithubUsername string
+	AvatarUrl      string
+	Contributions  int
+	Points         int
+}
+
+type Role struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+type UserRole struct {
+	UserId    int
+	Role      string
+	GrantedBy sql.NullInt64
+	CreatedAt time.Time
+}
+
+type IntegrationEndpoint struct {
+	Id          int64
+	UserId      sql.NullInt64
+	CreatedBy   int
+	Url         string
+	Description string
+	EventTypes  []string
+	KeyId       string
+	WrappedKey  []byte
+	Secret      []byte
+	IsActive    bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type IntegrationDelivery struct {
+	Id             int64
+	EndpointId     int64
+	EventId        string
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	ResponseStatus sql.NullInt64
+	ResponseBody   sql.NullString
+	LastError      sql.NullString
+	DurationMs     sql.NullInt64
+	DeliveredAt    sql.NullTime
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IntegrationDeliveryAttempt is the outcome of one attempt at sending a
+// delivery.
+type IntegrationDeliveryAttempt struct {
+	Status         string
+	ResponseStatus sql.NullInt64
+	ResponseBody   sql.NullString
+	LastError      sql.NullString
+	DurationMs     sql.NullInt64
+	DeliveredAt    sql.NullTime
+}
+
+type OutboxEvent struct {
+	Id          int64
+	EventId     string
+	EventType   string
+	Subscriber  string
+	UserId      sql.NullInt64
+	Payload     []byte
+	OccurredAt  time.Time
+	Status      string
+	Attempts    int
+	AvailableAt time.Time
+	LastError   sql.NullString
+	ProcessedAt sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type DailyActivity struct {
+	UserId        int
+	ActivityDate  time.Time
+	Contributions int
+	Points        int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type Streak struct {
+	UserId         int
+	CurrentStreak  int
+	LongestStreak  int
+	LastActiveDate sql.NullTime
+	FreezeTokens   int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// StreakDay is a day counting towards a streak, either active or covered by
+// a freeze token.
+type StreakDay struct {
+	Date   time.Time
+	Frozen bool
+}
+
+type Team struct {
+	Id          int64
+	Slug        string
+	Name        string
+	Description string
+	Kind        string
+	InviteCode  string
+	CreatedBy   int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type TeamMember struct {
+	Id        int64
+	TeamId    int64
+	UserId    int
+	Role      string
+	JoinedAt  time.Time
+	LeftAt    sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserTeam is a team the user currently belongs to, with their role in it.
+type UserTeam struct {
+	Team
+	Role     string
+	JoinedAt time.Time
+}
+
+// TeamMemberStanding is what a member earned for a team over a period.
+// IsMember is false for members who left during the period.
+type TeamMemberStanding struct {
+	UserId         int
+	GithubUsername string
+	AvatarUrl      string
+	Role           string
+	IsMember       bool
+	Contributions  int
+	Points         int
+	Rank           int
+}
+
+type TeamGoal struct {
+	TeamId       int64
+	MonthYear    int
+	TargetPoints int
+	SetBy        int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type TeamStanding struct {
+	TeamId      int64
+	Slug        string
+	Name        string
+	Kind        string
+	MonthYear   int
+	Points      int
+	MemberCount int
+	Rank        int
+	RefreshedAt time.Time
+}
+
+type Challenge struct {
+	Id          int64
+	Slug        string
+	Name        string
+	Description string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Topics      []string
+	Labels      []string
+	Multiplier  float64
+	Status      string
+	FinalizedAt sql.NullTime
+	CreatedBy   int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type EligibleRepository struct {
+	RepositoryId int
+	FullName     string
+}
+
+type ChallengeMultiplier struct {
+	ChallengeId      int64
+	ContributionType string
+	Multiplier       float64
+}
+
+type ChallengePrize struct {
+	ChallengeId int64
+	RankFrom    int
+	RankTo      int
+	Points      int
+}
+
+// ChallengeStanding is a participant's result in a challenge, computed
+// from their contributions while the challenge is open and frozen once it
+// is finalized.
+type ChallengeStanding struct {
+	UserId         int
+	GithubUsername string
+	AvatarUrl      string
+	Contributions  int
+	Points         int
+	Rank           int
+	PrizePoints    int
+}
+
+// JudgingReview is a contribution queued for the judges panel, joined with
+// the contributor and repository it belongs to. AutomatedScore is the
+// balance the contribution had when it was queued.
+type JudgingReview struct {
+	Id                 int64
+	ContributionId     int
+	UserId             int
+	GithubUsername     string
+	RepositoryId       int
+	RepositoryFullName string
+	Reason             string
+	LinesChanged       int
+	Title              string
+	Url                string
+	AutomatedScore     int
+	FinalScore         sql.NullInt64
+	Status             string
+	AdjustmentId       sql.NullInt64
+	ScoreCount         int
+	FinalizedAt        sql.NullTime
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+type JudgingScore struct {
+	ReviewId            int64
+	JudgeId             int
+	JudgeGithubUsername string
+	Impact              int
+	Quality             int
+	Docs                int
+	Comment             string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
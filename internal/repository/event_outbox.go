@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type eventOutboxRepository struct {
+	BaseRepository
+}
+
+type EventOutboxRepository interface {
+	RepositoryTransaction
+	CreateOutboxEvent(ctx context.Context, tx *sqlx.Tx, eventInfo OutboxEvent) error
+	ClaimNextOutboxEvent(ctx context.Context, tx *sqlx.Tx, subscribers []string) (OutboxEvent, error)
+	MarkOutboxEventProcessed(ctx context.Context, tx *sqlx.Tx, outboxEventId int64) error
+	MarkOutboxEventFailed(ctx context.Context, tx *sqlx.Tx, outboxEventId int64, status string, lastError string, availableAt time.Time) error
+	DeleteProcessedOutboxEvents(ctx context.Context, tx *sqlx.Tx, before time.Time, limit int) (int64, error)
+}
+
+func NewEventOutboxRepository(db *sqlx.DB) EventOutboxRepository {
+	return &eventOutboxRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	outboxEventColumns = `
+	id,
+	event_id,
+	event_type,
+	subscriber,
+	user_id,
+	payload,
+	occurred_at,
+	status,
+	attempts,
+	available_at,
+	last_error,
+	processed_at,
+	created_at,
+	updated_at`
+
+	createOutboxEventQuery = `
+	INSERT INTO event_outbox (
+	event_id,
+	event_type,
+	subscriber,
+	user_id,
+	payload,
+	occurred_at
+	)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (event_id, subscriber) DO NOTHING`
+
+	// the row stays locked for the claiming transaction, so a crashed
+	// dispatcher simply releases it
+	claimNextOutboxEventQuery = "SELECT" + outboxEventColumns + `
+	from event_outbox
+	where status='pending' and available_at<=$1 and subscriber = ANY($2)
+	order by available_at, id
+	FOR UPDATE SKIP LOCKED
+	limit 1`
+
+	markOutboxEventProcessedQuery = "UPDATE event_outbox SET status='processed', attempts=attempts+1, last_error=NULL, processed_at=$1, updated_at=$1 where id=$2"
+
+	markOutboxEventFailedQuery = "UPDATE event_outbox SET status=$1, attempts=attempts+1, last_error=$2, available_at=$3, updated_at=$4 where id=$5"
+
+	deleteProcessedOutboxEventsQuery = `
+	DELETE FROM event_outbox where id IN (
+		SELECT id from event_outbox
+		where status='processed' and processed_at<$1
+		limit $2
+	)`
+)
+
+// CreateOutboxEvent ignores an event already stored for the subscriber.
+func (er *eventOutboxRepository) CreateOutboxEvent(ctx context.Context, tx *sqlx.Tx, eventInfo OutboxEvent) error {
+	executer := er.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, createOutboxEventQuery,
+		eventInfo.EventId,
+		eventInfo.EventType,
+		eventInfo.Subscriber,
+		eventInfo.UserId,
+		eventInfo.Payload,
+		eventInfo.OccurredAt,
+	)
+	if err != nil {
+		slog.Error("error occurred while storing outbox event", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// ClaimNextOutboxEvent locks the oldest pending event for one of the given
+// subscribers until tx ends. It returns ErrOutboxEventNotFound when nothing
+// is pending.
+func (er *eventOutboxRepository) ClaimNextOutboxEvent(ctx context.Context, tx *sqlx.Tx, subscribers []string) (OutboxEvent, error) {
+	executer := er.BaseRepository.initiateQueryExecuter(tx)
+
+	outboxEvent, err := scanOutboxEvent(executer.QueryRowContext(ctx, claimNextOutboxEventQuery, time.Now(), pq.Array(subscribers)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OutboxEvent{}, apperrors.ErrOutboxEventNotFound
+		}
+		slog.Error("error occurred while claiming outbox event", "error", err)
+		return OutboxEvent{}, apperrors.ErrInternalServer
+	}
+
+	return outboxEvent, nil
+}
+
+func (er *eventOutboxRepository) MarkOutboxEventProcessed(ctx context.Context, tx *sqlx.Tx, outboxEventId int64) error {
+	executer := er.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markOutboxEventProcessedQuery, time.Now(), outboxEventId)
+	if err != nil {
+		slog.Error("failed to mark outbox event processed", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (er *eventOutboxRepository) MarkOutboxEventFailed(ctx context.Context, tx *sqlx.Tx, outboxEventId int64, status string, lastError string, availableAt time.Time) error {
+	executer := er.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markOutboxEventFailedQuery, status, lastError, availableAt, time.Now(), outboxEventId)
+	if err != nil {
+		slog.Error("failed to mark outbox event failed", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// DeleteProcessedOutboxEvents deletes up to limit events processed before
+// the given time and returns how many it deleted.
+func (er *eventOutboxRepository) DeleteProcessedOutboxEvents(ctx context.Context, tx *sqlx.Tx, before time.Time, limit int) (int64, error) {
+	executer := er.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, deleteProcessedOutboxEventsQuery, before, limit)
+	if err != nil {
+		slog.Error("failed to delete processed outbox events", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to count deleted outbox events", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return deleted, nil
+}
+
+func scanOutboxEvent(row rowScanner) (OutboxEvent, error) {
+	var outboxEvent OutboxEvent
+	err := row.Scan(
+		&outboxEvent.Id,
+		&outboxEvent.EventId,
+		&outboxEvent.EventType,
+		&outboxEvent.Subscriber,
+		&outboxEvent.UserId,
+		&outboxEvent.Payload,
+		&outboxEvent.OccurredAt,
+		&outboxEvent.Status,
+		&outboxEvent.Attempts,
+		&outboxEvent.AvailableAt,
+		&outboxEvent.LastError,
+		&outboxEvent.ProcessedAt,
+		&outboxEvent.CreatedAt,
+		&outboxEvent.UpdatedAt,
+	)
+
+	return outboxEvent, err
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type githubTokenRepository struct {
+	BaseRepository
+}
+
+type GithubTokenRepository interface {
+	RepositoryTransaction
+	UpsertGithubToken(ctx context.Context, tx *sqlx.Tx, token GithubToken) error
+	GetGithubTokenByUserId(ctx context.Context, tx *sqlx.Tx, userId int) (GithubToken, error)
+	MarkGithubTokenInvalid(ctx context.Context, tx *sqlx.Tx, userId int) error
+	GetGithubTokensAfterId(ctx context.Context, tx *sqlx.Tx, afterId int, limit int) ([]GithubToken, error)
+	UpdateGithubTokenCiphertext(ctx context.Context, tx *sqlx.Tx, token GithubToken) error
+	DeleteGithubToken(ctx context.Context, tx *sqlx.Tx, userId int) error
+}
+
+func NewGithubTokenRepository(db *sqlx.DB) GithubTokenRepository {
+	return &githubTokenRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	githubTokenColumns = `
+	id,
+	user_id,
+	key_id,
+	wrapped_key,
+	access_token,
+	refresh_token,
+	token_type,
+	expires_at,
+	is_valid,
+	invalidated_at,
+	created_at,
+	updated_at`
+
+	upsertGithubTokenQuery = `
+	INSERT INTO github_tokens (
+	user_id,
+	key_id,
+	wrapped_key,
+	access_token,
+	refresh_token,
+	token_type,
+	expires_at
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (user_id) DO UPDATE SET
+	key_id=EXCLUDED.key_id,
+	wrapped_key=EXCLUDED.wrapped_key,
+	access_token=EXCLUDED.access_token,
+	refresh_token=EXCLUDED.refresh_token,
+	token_type=EXCLUDED.token_type,
+	expires_at=EXCLUDED.expires_at,
+	is_valid=TRUE,
+	invalidated_at=NULL,
+	updated_at=$8`
+
+	getGithubTokenByUserIdQuery = "SELECT" + githubTokenColumns + " from github_tokens where user_id=$1"
+
+	markGithubTokenInvalidQuery = "UPDATE github_tokens SET is_valid=FALSE, invalidated_at=$1, updated_at=$1 where user_id=$2 and is_valid"
+
+	getGithubTokensAfterIdQuery = "SELECT" + githubTokenColumns + " from github_tokens where id>$1 order by id limit $2"
+
+	updateGithubTokenCiphertextQuery = `
+	UPDATE github_tokens SET
+	key_id=$1,
+	wrapped_key=$2,
+	access_token=$3,
+	refresh_token=$4,
+	updated_at=$5
+	where id=$6`
+
+	deleteGithubTokenQuery = "DELETE from github_tokens where user_id=$1"
+)
+
+func (gr *githubTokenRepository) UpsertGithubToken(ctx context.Context, tx *sqlx.Tx, token GithubToken) error {
+	executer := gr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, upsertGithubTokenQuery,
+		token.UserId,
+		token.KeyId,
+		token.WrappedKey,
+		token.AccessToken,
+		token.RefreshToken,
+		token.TokenType,
+		token.ExpiresAt,
+		time.Now(),
+	)
+	if err != nil {
+		slog.Error("failed to upsert github token", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (gr *githubTokenRepository) GetGithubTokenByUserId(ctx context.Context, tx *sqlx.Tx, userId int) (GithubToken, error) {
+	executer := gr.BaseRepository.initiateQueryExecuter(tx)
+
+	token, err := scanGithubToken(executer.QueryRowContext(ctx, getGithubTokenByUserIdQuery, userId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return GithubToken{}, apperrors.ErrGithubTokenNotFound
+		}
+		slog.Error("error occurred while getting github token by user id", "error", err)
+		return GithubToken{}, apperrors.ErrInternalServer
+	}
+
+	return token, nil
+}
+
+func (gr *githubTokenRepository) MarkGithubTokenInvalid(ctx context.Context, tx *sqlx.Tx, userId int) error {
+	executer := gr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markGithubTokenInvalidQuery, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to mark github token invalid", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (gr *githubTokenRepository) GetGithubTokensAfterId(ctx context.Context, tx *sqlx.Tx, afterId int, limit int) ([]GithubToken, error) {
+	executer := gr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getGithubTokensAfterIdQuery, afterId, limit)
+	if err != nil {
+		slog.Error("error occurred while listing github tokens", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	var tokens []GithubToken
+	for rows.Next() {
+		token, err := scanGithubToken(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning github token", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating github tokens", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return tokens, nil
+}
+
+func (gr *githubTokenRepository) UpdateGithubTokenCiphertext(ctx context.Context, tx *sqlx.Tx, token GithubToken) error {
+	executer := gr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, updateGithubTokenCiphertextQuery,
+		token.KeyId,
+		token.WrappedKey,
+		token.AccessToken,
+		token.RefreshToken,
+		time.Now(),
+		token.Id,
+	)
+	if err != nil {
+		slog.Error("failed to update github token ciphertext", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (gr *githubTokenRepository) DeleteGithubToken(ctx context.Context, tx *sqlx.Tx, userId int) error {
+	executer := gr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, deleteGithubTokenQuery, userId)
+	if err != nil {
+		slog.Error("failed to delete github token", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func scanGithubToken(row rowScanner) (GithubToken, error) {
+	var token GithubToken
+	err := row.Scan(
+		&token.Id,
+		&token.UserId,
+		&token.KeyId,
+		&token.WrappedKey,
+		&token.AccessToken,
+		&token.RefreshToken,
+		&token.TokenType,
+		&token.ExpiresAt,
+		&token.IsValid,
+		&token.InvalidatedAt,
+		&token.CreatedAt,
+		&token.UpdatedAt,
+	)
+
+	return token, err
+}
@@ -0,0 +1,371 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type integrationRepository struct {
+	BaseRepository
+}
+
+type IntegrationRepository interface {
+	RepositoryTransaction
+	CreateEndpoint(ctx context.Context, tx *sqlx.Tx, endpoint IntegrationEndpoint) (IntegrationEndpoint, error)
+	ListEndpoints(ctx context.Context, tx *sqlx.Tx, ownerId sql.NullInt64) ([]IntegrationEndpoint, error)
+	GetEndpoint(ctx context.Context, tx *sqlx.Tx, endpointId int64, ownerId sql.NullInt64) (IntegrationEndpoint, error)
+	GetEndpointById(ctx context.Context, tx *sqlx.Tx, endpointId int64) (IntegrationEndpoint, error)
+	DeleteEndpoint(ctx context.Context, tx *sqlx.Tx, endpointId int64, ownerId sql.NullInt64) error
+	ListSubscribedEndpoints(ctx context.Context, tx *sqlx.Tx, eventType string, userId sql.NullInt64) ([]IntegrationEndpoint, error)
+	CreateDelivery(ctx context.Context, tx *sqlx.Tx, delivery IntegrationDelivery) (IntegrationDelivery, error)
+	GetDeliveryById(ctx context.Context, tx *sqlx.Tx, deliveryId int64) (IntegrationDelivery, error)
+	ListDeliveries(ctx context.Context, tx *sqlx.Tx, endpointId int64, limit int, offset int) ([]IntegrationDelivery, error)
+	RecordDeliveryAttempt(ctx context.Context, tx *sqlx.Tx, deliveryId int64, attempt IntegrationDeliveryAttempt) (IntegrationDelivery, error)
+}
+
+func NewIntegrationRepository(db *sqlx.DB) IntegrationRepository {
+	return &integrationRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	integrationEndpointColumns = `
+	id,
+	user_id,
+	created_by,
+	url,
+	description,
+	event_types,
+	key_id,
+	wrapped_key,
+	secret,
+	is_active,
+	created_at,
+	updated_at`
+
+	integrationDeliveryColumns = `
+	id,
+	endpoint_id,
+	event_id,
+	event_type,
+	payload,
+	status,
+	attempts,
+	response_status,
+	response_body,
+	last_error,
+	duration_ms,
+	delivered_at,
+	created_at,
+	updated_at`
+
+	createIntegrationEndpointQuery = `
+	INSERT INTO integration_endpoints (
+	user_id,
+	created_by,
+	url,
+	description,
+	event_types,
+	key_id,
+	wrapped_key,
+	secret
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING` + integrationEndpointColumns
+
+	// a NULL owner selects the endpoints registered by admins
+	listIntegrationEndpointsQuery = "SELECT" + integrationEndpointColumns + " from integration_endpoints where user_id IS NOT DISTINCT FROM $1 order by id"
+
+	getIntegrationEndpointQuery = "SELECT" + integrationEndpointColumns + " from integration_endpoints where id=$1 and user_id IS NOT DISTINCT FROM $2"
+
+	getIntegrationEndpointByIdQuery = "SELECT" + integrationEndpointColumns + " from integration_endpoints where id=$1"
+
+	deleteIntegrationEndpointQuery = "DELETE from integration_endpoints where id=$1 and user_id IS NOT DISTINCT FROM $2"
+
+	// admin endpoints get every event, user endpoints only events about
+	// their owner and events about everyone
+	listSubscribedIntegrationEndpointsQuery = `
+	SELECT` + integrationEndpointColumns + `
+	from integration_endpoints e
+	where e.is_active
+	and $1 = ANY(e.event_types)
+	and (
+		e.user_id IS NULL
+		or $2::bigint IS NULL
+		or e.user_id=$2
+	)
+	and (
+		e.user_id IS NULL
+		or exists (SELECT 1 from users u where u.id=e.user_id and not u.is_deleted and not u.is_blocked)
+	)
+	order by e.id`
+
+	createIntegrationDeliveryQuery = `
+	INSERT INTO integration_deliveries (
+	endpoint_id,
+	event_id,
+	event_type,
+	payload
+	)
+	VALUES ($1, $2, $3, $4)
+	RETURNING` + integrationDeliveryColumns
+
+	getIntegrationDeliveryByIdQuery = "SELECT" + integrationDeliveryColumns + " from integration_deliveries where id=$1"
+
+	listIntegrationDeliveriesQuery = `
+	SELECT` + integrationDeliveryColumns + `
+	from integration_deliveries
+	where endpoint_id=$1
+	order by created_at desc, id desc
+	limit $2 offset $3`
+
+	recordIntegrationDeliveryAttemptQuery = `
+	UPDATE integration_deliveries SET
+	status=$1,
+	attempts=attempts+1,
+	response_status=$2,
+	response_body=$3,
+	last_error=$4,
+	duration_ms=$5,
+	delivered_at=$6,
+	updated_at=$7
+	where id=$8
+	RETURNING` + integrationDeliveryColumns
+)
+
+func (ir *integrationRepository) CreateEndpoint(ctx context.Context, tx *sqlx.Tx, endpoint IntegrationEndpoint) (IntegrationEndpoint, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanIntegrationEndpoint(executer.QueryRowContext(ctx, createIntegrationEndpointQuery,
+		endpoint.UserId,
+		endpoint.CreatedBy,
+		endpoint.Url,
+		endpoint.Description,
+		pq.Array(endpoint.EventTypes),
+		endpoint.KeyId,
+		endpoint.WrappedKey,
+		endpoint.Secret,
+	))
+	if err != nil {
+		slog.Error("error occurred while creating integration endpoint", "error", err)
+		return IntegrationEndpoint{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (ir *integrationRepository) ListEndpoints(ctx context.Context, tx *sqlx.Tx, ownerId sql.NullInt64) ([]IntegrationEndpoint, error) {
+	return ir.listEndpoints(ctx, tx, listIntegrationEndpointsQuery, ownerId)
+}
+
+func (ir *integrationRepository) GetEndpoint(ctx context.Context, tx *sqlx.Tx, endpointId int64, ownerId sql.NullInt64) (IntegrationEndpoint, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	endpoint, err := scanIntegrationEndpoint(executer.QueryRowContext(ctx, getIntegrationEndpointQuery, endpointId, ownerId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return IntegrationEndpoint{}, apperrors.ErrIntegrationEndpointNotFound
+		}
+		slog.Error("error occurred while getting integration endpoint", "error", err)
+		return IntegrationEndpoint{}, apperrors.ErrInternalServer
+	}
+
+	return endpoint, nil
+}
+
+func (ir *integrationRepository) GetEndpointById(ctx context.Context, tx *sqlx.Tx, endpointId int64) (IntegrationEndpoint, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	endpoint, err := scanIntegrationEndpoint(executer.QueryRowContext(ctx, getIntegrationEndpointByIdQuery, endpointId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return IntegrationEndpoint{}, apperrors.ErrIntegrationEndpointNotFound
+		}
+		slog.Error("error occurred while getting integration endpoint", "error", err)
+		return IntegrationEndpoint{}, apperrors.ErrInternalServer
+	}
+
+	return endpoint, nil
+}
+
+// DeleteEndpoint removes the endpoint along with its delivery log.
+func (ir *integrationRepository) DeleteEndpoint(ctx context.Context, tx *sqlx.Tx, endpointId int64, ownerId sql.NullInt64) error {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, deleteIntegrationEndpointQuery, endpointId, ownerId)
+	if err != nil {
+		slog.Error("failed to delete integration endpoint", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrIntegrationEndpointNotFound)
+}
+
+// ListSubscribedEndpoints returns the active endpoints that should receive
+// an event of eventType about userId. A NULL userId marks an event about
+// everyone.
+func (ir *integrationRepository) ListSubscribedEndpoints(ctx context.Context, tx *sqlx.Tx, eventType string, userId sql.NullInt64) ([]IntegrationEndpoint, error) {
+	return ir.listEndpoints(ctx, tx, listSubscribedIntegrationEndpointsQuery, eventType, userId)
+}
+
+func (ir *integrationRepository) listEndpoints(ctx context.Context, tx *sqlx.Tx, query string, args ...any) ([]IntegrationEndpoint, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("error occurred while listing integration endpoints", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	endpoints := []IntegrationEndpoint{}
+	for rows.Next() {
+		endpoint, err := scanIntegrationEndpoint(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning integration endpoint", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating integration endpoints", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return endpoints, nil
+}
+
+func (ir *integrationRepository) CreateDelivery(ctx context.Context, tx *sqlx.Tx, delivery IntegrationDelivery) (IntegrationDelivery, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanIntegrationDelivery(executer.QueryRowContext(ctx, createIntegrationDeliveryQuery,
+		delivery.EndpointId,
+		delivery.EventId,
+		delivery.EventType,
+		delivery.Payload,
+	))
+	if err != nil {
+		slog.Error("error occurred while creating integration delivery", "error", err)
+		return IntegrationDelivery{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (ir *integrationRepository) GetDeliveryById(ctx context.Context, tx *sqlx.Tx, deliveryId int64) (IntegrationDelivery, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	delivery, err := scanIntegrationDelivery(executer.QueryRowContext(ctx, getIntegrationDeliveryByIdQuery, deliveryId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return IntegrationDelivery{}, apperrors.ErrIntegrationDeliveryNotFound
+		}
+		slog.Error("error occurred while getting integration delivery", "error", err)
+		return IntegrationDelivery{}, apperrors.ErrInternalServer
+	}
+
+	return delivery, nil
+}
+
+func (ir *integrationRepository) ListDeliveries(ctx context.Context, tx *sqlx.Tx, endpointId int64, limit int, offset int) ([]IntegrationDelivery, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listIntegrationDeliveriesQuery, endpointId, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing integration deliveries", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	deliveries := []IntegrationDelivery{}
+	for rows.Next() {
+		delivery, err := scanIntegrationDelivery(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning integration delivery", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating integration deliveries", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return deliveries, nil
+}
+
+func (ir *integrationRepository) RecordDeliveryAttempt(ctx context.Context, tx *sqlx.Tx, deliveryId int64, attempt IntegrationDeliveryAttempt) (IntegrationDelivery, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	delivery, err := scanIntegrationDelivery(executer.QueryRowContext(ctx, recordIntegrationDeliveryAttemptQuery,
+		attempt.Status,
+		attempt.ResponseStatus,
+		attempt.ResponseBody,
+		attempt.LastError,
+		attempt.DurationMs,
+		attempt.DeliveredAt,
+		time.Now(),
+		deliveryId,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return IntegrationDelivery{}, apperrors.ErrIntegrationDeliveryNotFound
+		}
+		slog.Error("failed to record integration delivery attempt", "error", err)
+		return IntegrationDelivery{}, apperrors.ErrInternalServer
+	}
+
+	return delivery, nil
+}
+
+func scanIntegrationEndpoint(row rowScanner) (IntegrationEndpoint, error) {
+	var endpoint IntegrationEndpoint
+	err := row.Scan(
+		&endpoint.Id,
+		&endpoint.UserId,
+		&endpoint.CreatedBy,
+		&endpoint.Url,
+		&endpoint.Description,
+		pq.Array(&endpoint.EventTypes),
+		&endpoint.KeyId,
+		&endpoint.WrappedKey,
+		&endpoint.Secret,
+		&endpoint.IsActive,
+		&endpoint.CreatedAt,
+		&endpoint.UpdatedAt,
+	)
+
+	return endpoint, err
+}
+
+func scanIntegrationDelivery(row rowScanner) (IntegrationDelivery, error) {
+	var delivery IntegrationDelivery
+	err := row.Scan(
+		&delivery.Id,
+		&delivery.EndpointId,
+		&delivery.EventId,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseStatus,
+		&delivery.ResponseBody,
+		&delivery.LastError,
+		&delivery.DurationMs,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+
+	return delivery, err
+}
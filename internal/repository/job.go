@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type jobRepository struct {
+	BaseRepository
+}
+
+type JobRepository interface {
+	RepositoryTransaction
+	EnqueueJob(ctx context.Context, tx *sqlx.Tx, jobInfo Job) (Job, error)
+	ClaimNextJob(ctx context.Context, tx *sqlx.Tx, workerId string, types []string, staleAfter time.Duration) (Job, error)
+	MarkJobSucceeded(ctx context.Context, tx *sqlx.Tx, jobId int64) error
+	MarkJobFailed(ctx context.Context, tx *sqlx.Tx, jobId int64, status string, lastError string, runAt time.Time) error
+	GetJobById(ctx context.Context, tx *sqlx.Tx, jobId int64) (Job, error)
+	ListJobs(ctx context.Context, tx *sqlx.Tx, status string, limit int, offset int) ([]Job, error)
+	RetryJob(ctx context.Context, tx *sqlx.Tx, jobId int64) error
+	CancelJob(ctx context.Context, tx *sqlx.Tx, jobId int64) error
+}
+
+func NewJobRepository(db *sqlx.DB) JobRepository {
+	return &jobRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	jobColumns = `
+	id,
+	type,
+	payload,
+	status,
+	unique_key,
+	attempts,
+	max_attempts,
+	run_at,
+	locked_at,
+	locked_by,
+	last_error,
+	completed_at,
+	created_at,
+	updated_at`
+
+	enqueueJobQuery = `
+	INSERT INTO jobs (
+	type,
+	payload,
+	unique_key,
+	max_attempts,
+	run_at
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (unique_key) WHERE status IN ('pending', 'running') DO NOTHING
+	RETURNING` + jobColumns
+
+	// jobs left running by a crashed worker are reclaimed once their lock is
+	// older than staleAfter
+	claimNextJobQuery = `
+	UPDATE jobs SET
+	status='running',
+	attempts=attempts+1,
+	locked_at=$1,
+	locked_by=$2,
+	updated_at=$1
+	where id = (
+		SELECT id from jobs
+		where type = ANY($3)
+		and ((status='pending' and run_at<=$1) or (status='running' and locked_at<$4))
+		order by run_at, id
+		FOR UPDATE SKIP LOCKED
+		limit 1
+	)
+	RETURNING` + jobColumns
+
+	markJobSucceededQuery = "UPDATE jobs SET status='succeeded', last_error=NULL, locked_at=NULL, locked_by=NULL, completed_at=$1, updated_at=$1 where id=$2"
+
+	markJobFailedQuery = "UPDATE jobs SET status=$1, last_error=$2, run_at=$3, locked_at=NULL, locked_by=NULL, updated_at=$4 where id=$5"
+
+	getJobByIdQuery = "SELECT" + jobColumns + " from jobs where id=$1"
+
+	listJobsQuery = "SELECT" + jobColumns + " from jobs where ($1='' or status=$1) order by id desc limit $2 offset $3"
+
+	retryJobQuery = "UPDATE jobs SET status='pending', attempts=0, run_at=$1, last_error=NULL, updated_at=$1 where id=$2 and status IN ('dead', 'cancelled')"
+
+	cancelJobQuery = "UPDATE jobs SET status='cancelled', updated_at=$1 where id=$2 and status='pending'"
+)
+
+// EnqueueJob returns ErrJobAlreadyQueued when a pending or running job holds
+// the same unique key.
+func (jr *jobRepository) EnqueueJob(ctx context.Context, tx *sqlx.Tx, jobInfo Job) (Job, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	job, err := scanJob(executer.QueryRowContext(ctx, enqueueJobQuery,
+		jobInfo.Type,
+		jobInfo.Payload,
+		jobInfo.UniqueKey,
+		jobInfo.MaxAttempts,
+		jobInfo.RunAt,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Job{}, apperrors.ErrJobAlreadyQueued
+		}
+		slog.Error("error occurred while enqueueing job", "error", err)
+		return Job{}, apperrors.ErrInternalServer
+	}
+
+	return job, nil
+}
+
+// ClaimNextJob locks the next runnable job of the given types for workerId.
+// It returns ErrJobNotFound when nothing is runnable.
+func (jr *jobRepository) ClaimNextJob(ctx context.Context, tx *sqlx.Tx, workerId string, types []string, staleAfter time.Duration) (Job, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	now := time.Now()
+	job, err := scanJob(executer.QueryRowContext(ctx, claimNextJobQuery, now, workerId, pq.Array(types), now.Add(-staleAfter)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Job{}, apperrors.ErrJobNotFound
+		}
+		slog.Error("error occurred while claiming job", "error", err)
+		return Job{}, apperrors.ErrInternalServer
+	}
+
+	return job, nil
+}
+
+func (jr *jobRepository) MarkJobSucceeded(ctx context.Context, tx *sqlx.Tx, jobId int64) error {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markJobSucceededQuery, time.Now(), jobId)
+	if err != nil {
+		slog.Error("failed to mark job succeeded", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (jr *jobRepository) MarkJobFailed(ctx context.Context, tx *sqlx.Tx, jobId int64, status string, lastError string, runAt time.Time) error {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markJobFailedQuery, status, lastError, runAt, time.Now(), jobId)
+	if err != nil {
+		slog.Error("failed to mark job failed", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (jr *jobRepository) GetJobById(ctx context.Context, tx *sqlx.Tx, jobId int64) (Job, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	job, err := scanJob(executer.QueryRowContext(ctx, getJobByIdQuery, jobId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Job{}, apperrors.ErrJobNotFound
+		}
+		slog.Error("error occurred while getting job by id", "error", err)
+		return Job{}, apperrors.ErrInternalServer
+	}
+
+	return job, nil
+}
+
+func (jr *jobRepository) ListJobs(ctx context.Context, tx *sqlx.Tx, status string, limit int, offset int) ([]Job, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listJobsQuery, status, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing jobs", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	jobs := []Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning job", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating jobs", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return jobs, nil
+}
+
+// RetryJob requeues a dead or cancelled job with a fresh attempt budget.
+func (jr *jobRepository) RetryJob(ctx context.Context, tx *sqlx.Tx, jobId int64) error {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, retryJobQuery, time.Now(), jobId)
+	if err != nil {
+		slog.Error("failed to retry job", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrJobNotRetryable)
+}
+
+// CancelJob cancels a job that has not started yet.
+func (jr *jobRepository) CancelJob(ctx context.Context, tx *sqlx.Tx, jobId int64) error {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, cancelJobQuery, time.Now(), jobId)
+	if err != nil {
+		slog.Error("failed to cancel job", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrJobNotCancellable)
+}
+
+func scanJob(row rowScanner) (Job, error) {
+	var job Job
+	err := row.Scan(
+		&job.Id,
+		&job.Type,
+		&job.Payload,
+		&job.Status,
+		&job.UniqueKey,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LockedAt,
+		&job.LockedBy,
+		&job.LastError,
+		&job.CompletedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+
+	return job, err
+}
@@ -0,0 +1,408 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type judgingRepository struct {
+	BaseRepository
+}
+
+type JudgingRepository interface {
+	RepositoryTransaction
+	IsRepositoryFeatured(ctx context.Context, tx *sqlx.Tx, repositoryId int) (bool, error)
+	SetRepositoryFeatured(ctx context.Context, tx *sqlx.Tx, repositoryId int, featured bool) error
+	CreateJudgingReview(ctx context.Context, tx *sqlx.Tx, review JudgingReview) error
+	GetJudgingReview(ctx context.Context, tx *sqlx.Tx, reviewId int64) (JudgingReview, error)
+	LockJudgingReview(ctx context.Context, tx *sqlx.Tx, reviewId int64) (JudgingReview, error)
+	ListJudgingReviews(ctx context.Context, tx *sqlx.Tx, status string, limit int, offset int) ([]JudgingReview, error)
+	CountOpenJudgingAssignments(ctx context.Context, tx *sqlx.Tx, judgeId int) (int, error)
+	AssignJudgingReviews(ctx context.Context, tx *sqlx.Tx, judgeId int, judgesPerReview int, limit int) error
+	ListJudgeQueue(ctx context.Context, tx *sqlx.Tx, judgeId int, limit int, offset int) ([]JudgingReview, error)
+	IsJudgeAssigned(ctx context.Context, tx *sqlx.Tx, reviewId int64, judgeId int) (bool, error)
+	UpsertJudgingScore(ctx context.Context, tx *sqlx.Tx, score JudgingScore) error
+	ListJudgingScores(ctx context.Context, tx *sqlx.Tx, reviewId int64) ([]JudgingScore, error)
+	CloseJudgingReview(ctx context.Context, tx *sqlx.Tx, reviewId int64, status string, finalScore sql.NullInt64, adjustmentId sql.NullInt64) error
+}
+
+func NewJudgingRepository(db *sqlx.DB) JudgingRepository {
+	return &judgingRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	judgingReviewSelect = `
+	SELECT
+	r.id,
+	r.contribution_id,
+	c.user_id,
+	u.github_username,
+	c.repository_id,
+	rp.owner_name || '/' || rp.repo_name,
+	r.reason,
+	r.lines_changed,
+	r.title,
+	r.url,
+	r.automated_score,
+	r.final_score,
+	r.status,
+	r.adjustment_id,
+	(SELECT COUNT(*) from judging_scores s where s.review_id=r.id),
+	r.finalized_at,
+	r.created_at,
+	r.updated_at
+	from judging_reviews r
+	join contributions c on c.id=r.contribution_id
+	join users u on u.id=c.user_id
+	join repositories rp on rp.id=c.repository_id`
+
+	isRepositoryFeaturedQuery = "SELECT is_featured from repositories where id=$1"
+
+	setRepositoryFeaturedQuery = "UPDATE repositories SET is_featured=$1, updated_at=$2 where id=$3"
+
+	createJudgingReviewQuery = `
+	INSERT INTO judging_reviews (
+	contribution_id,
+	reason,
+	lines_changed,
+	title,
+	url,
+	automated_score
+	)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT DO NOTHING`
+
+	getJudgingReviewQuery = judgingReviewSelect + " where r.id=$1"
+
+	lockJudgingReviewQuery = judgingReviewSelect + " where r.id=$1 FOR UPDATE OF r"
+
+	listJudgingReviewsQuery = judgingReviewSelect + " where ($1='' or r.status=$1) order by r.created_at desc, r.id desc limit $2 offset $3"
+
+	// open assignments are the ones the judge still has to score
+	countOpenJudgingAssignmentsQuery = `
+	SELECT COUNT(*)
+	from judging_assignments a
+	join judging_reviews r on r.id=a.review_id
+	where a.judge_id=$1
+	and r.status='pending'
+	and not exists (SELECT 1 from judging_scores s where s.review_id=a.review_id and s.judge_id=a.judge_id)`
+
+	// oldest reviews still short of judges go first. Judges never review
+	// their own contributions.
+	assignJudgingReviewsQuery = `
+	INSERT INTO judging_assignments (review_id, judge_id, assigned_at)
+	SELECT r.id, $1, $4
+	from judging_reviews r
+	join contribution_scores cs on cs.contribution_id=r.contribution_id
+	join contributions c on c.id=r.contribution_id
+	where r.status='pending'
+	and cs.status<>'voided'
+	and c.user_id<>$1
+	and not exists (SELECT 1 from judging_assignments a where a.review_id=r.id and a.judge_id=$1)
+	and (SELECT COUNT(*) from judging_assignments a where a.review_id=r.id) < $2
+	order by r.created_at, r.id
+	limit $3
+	ON CONFLICT DO NOTHING`
+
+	listJudgeQueueQuery = judgingReviewSelect + `
+	join judging_assignments a on a.review_id=r.id and a.judge_id=$1
+	where r.status='pending'
+	and not exists (SELECT 1 from judging_scores s where s.review_id=r.id and s.judge_id=$1)
+	order by a.assigned_at, r.id
+	limit $2 offset $3`
+
+	isJudgeAssignedQuery = "SELECT EXISTS (SELECT 1 from judging_assignments where review_id=$1 and judge_id=$2)"
+
+	upsertJudgingScoreQuery = `
+	INSERT INTO judging_scores (review_id, judge_id, impact, quality, docs, comment)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (review_id, judge_id) DO UPDATE SET
+	impact=EXCLUDED.impact,
+	quality=EXCLUDED.quality,
+	docs=EXCLUDED.docs,
+	comment=EXCLUDED.comment,
+	updated_at=$7`
+
+	listJudgingScoresQuery = `
+	SELECT s.review_id, s.judge_id, u.github_username, s.impact, s.quality, s.docs, s.comment, s.created_at, s.updated_at
+	from judging_scores s
+	join users u on u.id=s.judge_id
+	where s.review_id=$1
+	order by s.created_at, s.judge_id`
+
+	closeJudgingReviewQuery = `
+	UPDATE judging_reviews SET
+	status=$1,
+	final_score=$2,
+	adjustment_id=$3,
+	finalized_at=$4,
+	updated_at=$4
+	where id=$5`
+)
+
+func (jr *judgingRepository) IsRepositoryFeatured(ctx context.Context, tx *sqlx.Tx, repositoryId int) (bool, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	var featured bool
+	err := executer.QueryRowContext(ctx, isRepositoryFeaturedQuery, repositoryId).Scan(&featured)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, apperrors.ErrRepoNotFound
+		}
+		slog.Error("error occurred while checking if repository is featured", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	return featured, nil
+}
+
+func (jr *judgingRepository) SetRepositoryFeatured(ctx context.Context, tx *sqlx.Tx, repositoryId int, featured bool) error {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, setRepositoryFeaturedQuery, featured, time.Now(), repositoryId)
+	if err != nil {
+		slog.Error("error occurred while setting repository featured", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrRepoNotFound)
+}
+
+// CreateJudgingReview queues a contribution for judging. Queueing the same
+// contribution again is a no-op so redelivered events are harmless.
+func (jr *judgingRepository) CreateJudgingReview(ctx context.Context, tx *sqlx.Tx, review JudgingReview) error {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, createJudgingReviewQuery,
+		review.ContributionId,
+		review.Reason,
+		review.LinesChanged,
+		review.Title,
+		review.Url,
+		review.AutomatedScore,
+	)
+	if err != nil {
+		slog.Error("error occurred while creating judging review", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (jr *judgingRepository) GetJudgingReview(ctx context.Context, tx *sqlx.Tx, reviewId int64) (JudgingReview, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	review, err := scanJudgingReview(executer.QueryRowContext(ctx, getJudgingReviewQuery, reviewId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return JudgingReview{}, apperrors.ErrJudgingReviewNotFound
+		}
+		slog.Error("error occurred while getting judging review", "error", err)
+		return JudgingReview{}, apperrors.ErrInternalServer
+	}
+
+	return review, nil
+}
+
+// LockJudgingReview locks the review so concurrent scores settle it once.
+func (jr *judgingRepository) LockJudgingReview(ctx context.Context, tx *sqlx.Tx, reviewId int64) (JudgingReview, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	review, err := scanJudgingReview(executer.QueryRowContext(ctx, lockJudgingReviewQuery, reviewId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return JudgingReview{}, apperrors.ErrJudgingReviewNotFound
+		}
+		slog.Error("error occurred while locking judging review", "error", err)
+		return JudgingReview{}, apperrors.ErrInternalServer
+	}
+
+	return review, nil
+}
+
+func (jr *judgingRepository) ListJudgingReviews(ctx context.Context, tx *sqlx.Tx, status string, limit int, offset int) ([]JudgingReview, error) {
+	return jr.listJudgingReviews(ctx, tx, listJudgingReviewsQuery, status, limit, offset)
+}
+
+func (jr *judgingRepository) CountOpenJudgingAssignments(ctx context.Context, tx *sqlx.Tx, judgeId int) (int, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	var count int
+	err := executer.QueryRowContext(ctx, countOpenJudgingAssignmentsQuery, judgeId).Scan(&count)
+	if err != nil {
+		slog.Error("error occurred while counting open judging assignments", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return count, nil
+}
+
+// AssignJudgingReviews assigns the judge up to limit pending reviews that
+// have fewer than judgesPerReview judges.
+func (jr *judgingRepository) AssignJudgingReviews(ctx context.Context, tx *sqlx.Tx, judgeId int, judgesPerReview int, limit int) error {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, assignJudgingReviewsQuery, judgeId, judgesPerReview, limit, time.Now())
+	if err != nil {
+		slog.Error("error occurred while assigning judging reviews", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// ListJudgeQueue lists the pending reviews assigned to the judge that they
+// have not scored yet, in the order they were assigned.
+func (jr *judgingRepository) ListJudgeQueue(ctx context.Context, tx *sqlx.Tx, judgeId int, limit int, offset int) ([]JudgingReview, error) {
+	return jr.listJudgingReviews(ctx, tx, listJudgeQueueQuery, judgeId, limit, offset)
+}
+
+func (jr *judgingRepository) IsJudgeAssigned(ctx context.Context, tx *sqlx.Tx, reviewId int64, judgeId int) (bool, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	var assigned bool
+	err := executer.QueryRowContext(ctx, isJudgeAssignedQuery, reviewId, judgeId).Scan(&assigned)
+	if err != nil {
+		slog.Error("error occurred while checking judging assignment", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	return assigned, nil
+}
+
+// UpsertJudgingScore records the judge's score, replacing one they already
+// gave for the review.
+func (jr *judgingRepository) UpsertJudgingScore(ctx context.Context, tx *sqlx.Tx, score JudgingScore) error {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, upsertJudgingScoreQuery,
+		score.ReviewId,
+		score.JudgeId,
+		score.Impact,
+		score.Quality,
+		score.Docs,
+		score.Comment,
+		time.Now(),
+	)
+	if err != nil {
+		slog.Error("error occurred while saving judging score", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (jr *judgingRepository) ListJudgingScores(ctx context.Context, tx *sqlx.Tx, reviewId int64) ([]JudgingScore, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listJudgingScoresQuery, reviewId)
+	if err != nil {
+		slog.Error("error occurred while listing judging scores", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	scores := []JudgingScore{}
+	for rows.Next() {
+		var score JudgingScore
+		err := rows.Scan(
+			&score.ReviewId,
+			&score.JudgeId,
+			&score.JudgeGithubUsername,
+			&score.Impact,
+			&score.Quality,
+			&score.Docs,
+			&score.Comment,
+			&score.CreatedAt,
+			&score.UpdatedAt,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning judging score", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		scores = append(scores, score)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating judging scores", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return scores, nil
+}
+
+// CloseJudgingReview takes the review out of the queue with its final
+// score and the adjustment that applied it.
+func (jr *judgingRepository) CloseJudgingReview(ctx context.Context, tx *sqlx.Tx, reviewId int64, status string, finalScore sql.NullInt64, adjustmentId sql.NullInt64) error {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, closeJudgingReviewQuery, status, finalScore, adjustmentId, time.Now(), reviewId)
+	if err != nil {
+		slog.Error("error occurred while closing judging review", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrJudgingReviewNotFound)
+}
+
+func (jr *judgingRepository) listJudgingReviews(ctx context.Context, tx *sqlx.Tx, query string, args ...any) ([]JudgingReview, error) {
+	executer := jr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("error occurred while listing judging reviews", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	reviews := []JudgingReview{}
+	for rows.Next() {
+		review, err := scanJudgingReview(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning judging review", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating judging reviews", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return reviews, nil
+}
+
+func scanJudgingReview(row rowScanner) (JudgingReview, error) {
+	var review JudgingReview
+	err := row.Scan(
+		&review.Id,
+		&review.ContributionId,
+		&review.UserId,
+		&review.GithubUsername,
+		&review.RepositoryId,
+		&review.RepositoryFullName,
+		&review.Reason,
+		&review.LinesChanged,
+		&review.Title,
+		&review.Url,
+		&review.AutomatedScore,
+		&review.FinalScore,
+		&review.Status,
+		&review.AdjustmentId,
+		&review.ScoreCount,
+		&review.FinalizedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+
+	return review, err
+}
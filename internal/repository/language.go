@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type languageRepository struct {
+	BaseRepository
+}
+
+type LanguageRepository interface {
+	RepositoryTransaction
+	ReplaceRepositoryLanguages(ctx context.Context, tx *sqlx.Tx, repositoryId int, languages []RepositoryLanguage) error
+	GetUserLanguageStats(ctx context.Context, tx *sqlx.Tx, userId int, from sql.NullTime, to sql.NullTime) ([]UserLanguageStat, error)
+	GetLanguageLeaderboard(ctx context.Context, tx *sqlx.Tx, language string, from sql.NullTime, to sql.NullTime, limit int, offset int) ([]LanguageLeaderboardEntry, error)
+}
+
+func NewLanguageRepository(db *sqlx.DB) LanguageRepository {
+	return &languageRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	deleteRepositoryLanguagesQuery = "DELETE from repository_languages where repository_id=$1"
+
+	createRepositoryLanguageQuery = `
+	INSERT INTO repository_languages (
+	repository_id,
+	language,
+	bytes,
+	share,
+	updated_at
+	)
+	VALUES ($1, $2, $3, $4, $5)`
+
+	// a contribution is attributed to every language making up at least 5%
+	// of its repository, and its points are split by byte share
+	getUserLanguageStatsQuery = `
+	SELECT
+	rl.language,
+	count(*),
+	count(DISTINCT c.repository_id),
+	ROUND(SUM(cs.balance_change * rl.share))::bigint AS points
+	from contributions c
+	join contribution_scores cs on cs.contribution_id=c.id
+	join repository_languages rl on rl.repository_id=c.repository_id
+	where c.user_id=$1
+	and cs.status<>'voided'
+	and rl.share>=0.05
+	and ($2::timestamptz IS NULL or c.contributed_at>=$2)
+	and ($3::timestamptz IS NULL or c.contributed_at<$3)
+	group by rl.language
+	order by points desc, rl.language`
+
+	getLanguageLeaderboardQuery = `
+	SELECT
+	u.id,
+	u.github_username,
+	u.avatar_url,
+	stats.contributions,
+	stats.points,
+	RANK() OVER (ORDER BY stats.points DESC)
+	from (
+		SELECT c.user_id, count(*) AS contributions, ROUND(SUM(cs.balance_change * rl.share))::bigint AS points
+		from contributions c
+		join contribution_scores cs on cs.contribution_id=c.id
+		join repository_languages rl on rl.repository_id=c.repository_id
+		where lower(rl.language)=lower($1)
+		and cs.status<>'voided'
+		and rl.share>=0.05
+		and ($2::timestamptz IS NULL or c.contributed_at>=$2)
+		and ($3::timestamptz IS NULL or c.contributed_at<$3)
+		group by c.user_id
+	) stats
+	join users u on u.id=stats.user_id
+	where not u.is_blocked and not u.is_deleted
+	order by stats.points desc, u.id
+	limit $4 offset $5`
+)
+
+// ReplaceRepositoryLanguages swaps the stored language breakdown of a
+// repository for a freshly fetched one. Run it in a transaction.
+func (lr *languageRepository) ReplaceRepositoryLanguages(ctx context.Context, tx *sqlx.Tx, repositoryId int, languages []RepositoryLanguage) error {
+	executer := lr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, deleteRepositoryLanguagesQuery, repositoryId)
+	if err != nil {
+		slog.Error("failed to delete repository languages", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	now := time.Now()
+	for _, language := range languages {
+		_, err = executer.ExecContext(ctx, createRepositoryLanguageQuery, repositoryId, language.Language, language.Bytes, language.Share, now)
+		if err != nil {
+			slog.Error("failed to create repository language", "error", err)
+			return apperrors.ErrInternalServer
+		}
+	}
+
+	return nil
+}
+
+func (lr *languageRepository) GetUserLanguageStats(ctx context.Context, tx *sqlx.Tx, userId int, from sql.NullTime, to sql.NullTime) ([]UserLanguageStat, error) {
+	executer := lr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getUserLanguageStatsQuery, userId, from, to)
+	if err != nil {
+		slog.Error("error occurred while getting user language stats", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	stats := []UserLanguageStat{}
+	for rows.Next() {
+		var stat UserLanguageStat
+		err := rows.Scan(&stat.Language, &stat.Contributions, &stat.Repositories, &stat.Points)
+		if err != nil {
+			slog.Error("error occurred while scanning user language stat", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		stats = append(stats, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating user language stats", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return stats, nil
+}
+
+// GetLanguageLeaderboard ranks active users by the points attributed to the
+// language.
+func (lr *languageRepository) GetLanguageLeaderboard(ctx context.Context, tx *sqlx.Tx, language string, from sql.NullTime, to sql.NullTime, limit int, offset int) ([]LanguageLeaderboardEntry, error) {
+	executer := lr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getLanguageLeaderboardQuery, language, from, to, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while getting language leaderboard", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	entries := []LanguageLeaderboardEntry{}
+	for rows.Next() {
+		var entry LanguageLeaderboardEntry
+		err := rows.Scan(&entry.UserId, &entry.GithubUsername, &entry.AvatarUrl, &entry.Contributions, &entry.Points, &entry.Rank)
+		if err != nil {
+			slog.Error("error occurred while scanning language leaderboard entry", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating language leaderboard", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type leaderboardRepository struct {
+	BaseRepository
+}
+
+type LeaderboardRepository interface {
+	RepositoryTransaction
+	CreateLeaderboardSnapshot(ctx context.Context, tx *sqlx.Tx, refreshedAt time.Time) error
+	DeleteLeaderboardSnapshotsBefore(ctx context.Context, tx *sqlx.Tx, before time.Time) error
+	DeleteUserLeaderboardEntries(ctx context.Context, tx *sqlx.Tx, userId int) error
+	CreateTeamLeaderboardSnapshot(ctx context.Context, tx *sqlx.Tx, refreshedAt time.Time, monthYear int, monthStart time.Time, monthEnd time.Time) error
+	DeleteTeamLeaderboardSnapshotsBefore(ctx context.Context, tx *sqlx.Tx, before time.Time) error
+}
+
+func NewLeaderboardRepository(db *sqlx.DB) LeaderboardRepository {
+	return &leaderboardRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	createLeaderboardSnapshotQuery = `
+	INSERT INTO leaderboard_hourly (
+	user_id,
+	github_id,
+	avatar_url,
+	current_balance,
+	rank,
+	refreshed_at
+	)
+	SELECT
+	id,
+	github_id,
+	avatar_url,
+	current_balance,
+	RANK() OVER (ORDER BY current_balance DESC),
+	$1
+	from users
+	where not is_blocked and not is_deleted`
+
+	deleteLeaderboardSnapshotsBeforeQuery = "DELETE from leaderboard_hourly where refreshed_at<$1"
+
+	deleteUserLeaderboardEntriesQuery = "DELETE from leaderboard_hourly where user_id=$1"
+
+	// teams and organizations are ranked separately, by the points members
+	// earned for them during the month under the same attribution rule as
+	// team totals, so the month bounds take the $2 and $3 the shared join
+	// expects
+	createTeamLeaderboardSnapshotQuery = `
+	INSERT INTO team_leaderboard_hourly (
+	team_id,
+	month_year,
+	points,
+	member_count,
+	rank,
+	refreshed_at
+	)
+	SELECT
+	t.id,
+	$4,
+	COALESCE(p.points, 0),
+	COALESCE(mc.member_count, 0),
+	RANK() OVER (PARTITION BY t.kind ORDER BY COALESCE(p.points, 0) DESC),
+	$1
+	from teams t
+	left join (
+		SELECT m.team_id, SUM(cs.balance_change) as points
+		from team_members m` + teamContributionsJoin + teamPointsMembersJoin + `
+		group by m.team_id
+	) p on p.team_id=t.id
+	left join (
+		SELECT team_id, COUNT(*) as member_count
+		from team_members
+		where left_at IS NULL
+		group by team_id
+	) mc on mc.team_id=t.id`
+
+	deleteTeamLeaderboardSnapshotsBeforeQuery = "DELETE from team_leaderboard_hourly where refreshed_at<$1"
+)
+
+func (lr *leaderboardRepository) CreateLeaderboardSnapshot(ctx context.Context, tx *sqlx.Tx, refreshedAt time.Time) error {
+	executer := lr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, createLeaderboardSnapshotQuery, refreshedAt)
+	if err != nil {
+		slog.Error("failed to create leaderboard snapshot", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (lr *leaderboardRepository) DeleteLeaderboardSnapshotsBefore(ctx context.Context, tx *sqlx.Tx, before time.Time) error {
+	executer := lr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, deleteLeaderboardSnapshotsBeforeQuery, before)
+	if err != nil {
+		slog.Error("failed to delete old leaderboard snapshots", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (lr *leaderboardRepository) DeleteUserLeaderboardEntries(ctx context.Context, tx *sqlx.Tx, userId int) error {
+	executer := lr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, deleteUserLeaderboardEntriesQuery, userId)
+	if err != nil {
+		slog.Error("failed to delete user leaderboard entries", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (lr *leaderboardRepository) CreateTeamLeaderboardSnapshot(ctx context.Context, tx *sqlx.Tx, refreshedAt time.Time, monthYear int, monthStart time.Time, monthEnd time.Time) error {
+	executer := lr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, createTeamLeaderboardSnapshotQuery, refreshedAt, monthStart, monthEnd, monthYear)
+	if err != nil {
+		slog.Error("failed to create team leaderboard snapshot", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (lr *leaderboardRepository) DeleteTeamLeaderboardSnapshotsBefore(ctx context.Context, tx *sqlx.Tx, before time.Time) error {
+	executer := lr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, deleteTeamLeaderboardSnapshotsBeforeQuery, before)
+	if err != nil {
+		slog.Error("failed to delete old team leaderboard snapshots", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+// Advisory lock keys. They only need to be unique within this database.
+const (
+	SchedulerLeaderLockKey int64 = 20250601
+	MigrationLockKey       int64 = 20250602
+
+	// task run locks take the high half of the key, leaving the low half
+	// for the task name
+	schedulerTaskLockKeySpace int64 = 20250603
+)
+
+// SchedulerTaskLockKey is the key a scheduled task holds while one of its
+// runs is in progress.
+func SchedulerTaskLockKey(taskName string) int64 {
+	hash := fnv.New32a()
+	hash.Write([]byte(taskName))
+	return schedulerTaskLockKeySpace<<32 | int64(hash.Sum32())
+}
+
+type advisoryLock struct {
+	db  *sqlx.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// AdvisoryLock is a session-level Postgres advisory lock. It pins one pooled
+// connection for as long as the lock is held, because the lock is released
+// automatically if that connection drops.
+type AdvisoryLock interface {
+	TryLock(ctx context.Context) (bool, error)
+	Lock(ctx context.Context) error
+	Held(ctx context.Context) bool
+	Unlock(ctx context.Context) error
+}
+
+func NewAdvisoryLock(db *sqlx.DB, key int64) AdvisoryLock {
+	return &advisoryLock{
+		db:  db,
+		key: key,
+	}
+}
+
+const (
+	tryAdvisoryLockQuery = "SELECT pg_try_advisory_lock($1)"
+	advisoryLockQuery    = "SELECT pg_advisory_lock($1)"
+	advisoryUnlockQuery  = "SELECT pg_advisory_unlock($1)"
+)
+
+func (l *advisoryLock) TryLock(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	conn, err := l.connection(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx, tryAdvisoryLockQuery, l.key).Scan(&acquired)
+	if err != nil {
+		slog.Error("failed to try advisory lock", "key", l.key, "error", err)
+		l.release()
+		return false, apperrors.ErrInternalServer
+	}
+
+	if !acquired {
+		l.release()
+	}
+	return acquired, nil
+}
+
+func (l *advisoryLock) Lock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	conn, err := l.connection(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx, advisoryLockQuery, l.key)
+	if err != nil {
+		slog.Error("failed to acquire advisory lock", "key", l.key, "error", err)
+		l.release()
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// Held reports whether the connection holding the lock is still alive.
+func (l *advisoryLock) Held(ctx context.Context) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return false
+	}
+
+	err := l.conn.PingContext(ctx)
+	if err != nil {
+		slog.Error("lost advisory lock connection", "key", l.key, "error", err)
+		l.release()
+		return false
+	}
+
+	return true
+}
+
+func (l *advisoryLock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	defer l.release()
+
+	_, err := l.conn.ExecContext(ctx, advisoryUnlockQuery, l.key)
+	if err != nil {
+		slog.Error("failed to release advisory lock", "key", l.key, "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (l *advisoryLock) connection(ctx context.Context) (*sql.Conn, error) {
+	if l.conn != nil {
+		return l.conn, nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		slog.Error("failed to get connection for advisory lock", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	l.conn = conn
+	return conn, nil
+}
+
+func (l *advisoryLock) release() {
+	if l.conn != nil {
+		l.conn.Close()
+		l.conn = nil
+	}
+}
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type notificationRepository struct {
+	BaseRepository
+}
+
+type NotificationRepository interface {
+	RepositoryTransaction
+	CreateNotification(ctx context.Context, tx *sqlx.Tx, notification Notification) (Notification, error)
+	ListNotifications(ctx context.Context, tx *sqlx.Tx, userId int, unreadOnly bool, limit int, offset int) ([]Notification, error)
+	CountUnreadNotifications(ctx context.Context, tx *sqlx.Tx, userId int) (int, error)
+	MarkNotificationRead(ctx context.Context, tx *sqlx.Tx, userId int, notificationId int64) error
+	MarkAllNotificationsRead(ctx context.Context, tx *sqlx.Tx, userId int) (int64, error)
+	ListNotificationPreferences(ctx context.Context, tx *sqlx.Tx, userId int) ([]NotificationPreference, error)
+	UpsertNotificationPreference(ctx context.Context, tx *sqlx.Tx, preference NotificationPreference) error
+	ListDigestRecipients(ctx context.Context, tx *sqlx.Tx, digestType string, weekStart time.Time, weekEnd time.Time) ([]DigestRecipient, error)
+	RecordDigest(ctx context.Context, tx *sqlx.Tx, userId int, weekStart time.Time) error
+}
+
+func NewNotificationRepository(db *sqlx.DB) NotificationRepository {
+	return &notificationRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	notificationColumns = `
+	id,
+	user_id,
+	type,
+	title,
+	body,
+	data,
+	read_at,
+	created_at,
+	updated_at`
+
+	createNotificationQuery = `
+	INSERT INTO notifications (
+	user_id,
+	type,
+	title,
+	body,
+	data
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING` + notificationColumns
+
+	listNotificationsQuery = `
+	SELECT` + notificationColumns + `
+	from notifications
+	where user_id=$1
+	and (not $2 or read_at IS NULL)
+	order by created_at desc, id desc
+	limit $3 offset $4`
+
+	countUnreadNotificationsQuery = "SELECT count(*) from notifications where user_id=$1 and read_at IS NULL"
+
+	markNotificationReadQuery = "UPDATE notifications SET read_at=COALESCE(read_at, $1), updated_at=$1 where id=$2 and user_id=$3"
+
+	markAllNotificationsReadQuery = "UPDATE notifications SET read_at=$1, updated_at=$1 where user_id=$2 and read_at IS NULL"
+
+	listNotificationPreferencesQuery = "SELECT user_id, type, in_app, email from notification_preferences where user_id=$1 order by type"
+
+	upsertNotificationPreferenceQuery = `
+	INSERT INTO notification_preferences (user_id, type, in_app, email)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (user_id, type) DO UPDATE SET in_app=EXCLUDED.in_app, email=EXCLUDED.email, updated_at=CURRENT_TIMESTAMP`
+
+	// users with something to report for the week who have not opted out of
+	// the digest and have not been sent this week's digest yet
+	listDigestRecipientsQuery = `
+	SELECT recipients.* from (
+		SELECT
+		u.id,
+		u.github_username,
+		u.email,
+		(SELECT count(*) from contributions c
+			join contribution_scores cs on cs.contribution_id=c.id
+			where c.user_id=u.id and cs.status<>'voided'
+			and c.contributed_at>=$2 and c.contributed_at<$3) as contributions,
+		(SELECT COALESCE(SUM(cs.balance_change), 0) from contributions c
+			join contribution_scores cs on cs.contribution_id=c.id
+			where c.user_id=u.id and cs.status<>'voided'
+			and c.contributed_at>=$2 and c.contributed_at<$3) as points,
+		(SELECT count(*) from notifications n
+			where n.user_id=u.id and n.read_at IS NULL) as unread_notifications
+		from users u
+		where not u.is_deleted
+		and not u.is_blocked
+		and u.email<>''
+		and not exists (
+			SELECT 1 from notification_preferences np
+			where np.user_id=u.id and np.type=$1 and not np.email
+		)
+		and not exists (
+			SELECT 1 from notification_digests nd
+			where nd.user_id=u.id and nd.week_start=$2::date
+		)
+	) recipients
+	where recipients.contributions>0 or recipients.unread_notifications>0
+	order by recipients.id`
+
+	recordDigestQuery = "INSERT INTO notification_digests (user_id, week_start) VALUES ($1, $2::date) ON CONFLICT DO NOTHING RETURNING user_id"
+)
+
+func (nr *notificationRepository) CreateNotification(ctx context.Context, tx *sqlx.Tx, notification Notification) (Notification, error) {
+	executer := nr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanNotification(executer.QueryRowContext(ctx, createNotificationQuery,
+		notification.UserId,
+		notification.Type,
+		notification.Title,
+		notification.Body,
+		notification.Data,
+	))
+	if err != nil {
+		slog.Error("error occurred while creating notification", "error", err)
+		return Notification{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (nr *notificationRepository) ListNotifications(ctx context.Context, tx *sqlx.Tx, userId int, unreadOnly bool, limit int, offset int) ([]Notification, error) {
+	executer := nr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listNotificationsQuery, userId, unreadOnly, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing notifications", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	notifications := []Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning notification", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating notifications", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return notifications, nil
+}
+
+func (nr *notificationRepository) CountUnreadNotifications(ctx context.Context, tx *sqlx.Tx, userId int) (int, error) {
+	executer := nr.BaseRepository.initiateQueryExecuter(tx)
+
+	var count int
+	err := executer.QueryRowContext(ctx, countUnreadNotificationsQuery, userId).Scan(&count)
+	if err != nil {
+		slog.Error("error occurred while counting unread notifications", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return count, nil
+}
+
+// MarkNotificationRead keeps the original read time of a notification that
+// was already read.
+func (nr *notificationRepository) MarkNotificationRead(ctx context.Context, tx *sqlx.Tx, userId int, notificationId int64) error {
+	executer := nr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, markNotificationReadQuery, time.Now(), notificationId, userId)
+	if err != nil {
+		slog.Error("failed to mark notification read", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrNotificationNotFound)
+}
+
+func (nr *notificationRepository) MarkAllNotificationsRead(ctx context.Context, tx *sqlx.Tx, userId int) (int64, error) {
+	executer := nr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, markAllNotificationsReadQuery, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to mark notifications read", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	marked, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to get affected rows", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return marked, nil
+}
+
+func (nr *notificationRepository) ListNotificationPreferences(ctx context.Context, tx *sqlx.Tx, userId int) ([]NotificationPreference, error) {
+	executer := nr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listNotificationPreferencesQuery, userId)
+	if err != nil {
+		slog.Error("error occurred while listing notification preferences", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	preferences := []NotificationPreference{}
+	for rows.Next() {
+		var preference NotificationPreference
+		err := rows.Scan(&preference.UserId, &preference.Type, &preference.InApp, &preference.Email)
+		if err != nil {
+			slog.Error("error occurred while scanning notification preference", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		preferences = append(preferences, preference)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating notification preferences", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return preferences, nil
+}
+
+func (nr *notificationRepository) UpsertNotificationPreference(ctx context.Context, tx *sqlx.Tx, preference NotificationPreference) error {
+	executer := nr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, upsertNotificationPreferenceQuery, preference.UserId, preference.Type, preference.InApp, preference.Email)
+	if err != nil {
+		slog.Error("failed to save notification preference", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// ListDigestRecipients returns the users due a digest for the week starting
+// at weekStart, along with what happened for them that week.
+func (nr *notificationRepository) ListDigestRecipients(ctx context.Context, tx *sqlx.Tx, digestType string, weekStart time.Time, weekEnd time.Time) ([]DigestRecipient, error) {
+	executer := nr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listDigestRecipientsQuery, digestType, weekStart, weekEnd)
+	if err != nil {
+		slog.Error("error occurred while listing digest recipients", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	recipients := []DigestRecipient{}
+	for rows.Next() {
+		var recipient DigestRecipient
+		err := rows.Scan(
+			&recipient.UserId,
+			&recipient.GithubUsername,
+			&recipient.Email,
+			&recipient.Contributions,
+			&recipient.Points,
+			&recipient.UnreadNotifications,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning digest recipient", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating digest recipients", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return recipients, nil
+}
+
+func (nr *notificationRepository) RecordDigest(ctx context.Context, tx *sqlx.Tx, userId int, weekStart time.Time) error {
+	executer := nr.BaseRepository.initiateQueryExecuter(tx)
+
+	var recorded int
+	err := executer.QueryRowContext(ctx, recordDigestQuery, userId, weekStart).Scan(&recorded)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrNotificationDigestExists
+		}
+		slog.Error("failed to record notification digest", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func scanNotification(row rowScanner) (Notification, error) {
+	var notification Notification
+	err := row.Scan(
+		&notification.Id,
+		&notification.UserId,
+		&notification.Type,
+		&notification.Title,
+		&notification.Body,
+		&notification.Data,
+		&notification.ReadAt,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
+	)
+
+	return notification, err
+}
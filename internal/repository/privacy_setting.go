@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type privacySettingRepository struct {
+	BaseRepository
+}
+
+type PrivacySettingRepository interface {
+	RepositoryTransaction
+	GetPrivacySettingByUserId(ctx context.Context, tx *sqlx.Tx, userId int) (PrivacySetting, error)
+	UpsertPrivacySetting(ctx context.Context, tx *sqlx.Tx, setting PrivacySetting) (PrivacySetting, error)
+}
+
+func NewPrivacySettingRepository(db *sqlx.DB) PrivacySettingRepository {
+	return &privacySettingRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	privacySettingColumns = `
+	user_id,
+	hide_balance,
+	hide_activity,
+	created_at,
+	updated_at`
+
+	getPrivacySettingByUserIdQuery = "SELECT" + privacySettingColumns + " from user_privacy_settings where user_id=$1"
+
+	upsertPrivacySettingQuery = `
+	INSERT INTO user_privacy_settings (
+	user_id,
+	hide_balance,
+	hide_activity
+	)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (user_id) DO UPDATE SET
+	hide_balance=EXCLUDED.hide_balance,
+	hide_activity=EXCLUDED.hide_activity,
+	updated_at=$4
+	RETURNING` + privacySettingColumns
+)
+
+// GetPrivacySettingByUserId returns ErrPrivacySettingNotFound for users who
+// never changed their settings.
+func (pr *privacySettingRepository) GetPrivacySettingByUserId(ctx context.Context, tx *sqlx.Tx, userId int) (PrivacySetting, error) {
+	executer := pr.BaseRepository.initiateQueryExecuter(tx)
+
+	setting, err := scanPrivacySetting(executer.QueryRowContext(ctx, getPrivacySettingByUserIdQuery, userId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PrivacySetting{}, apperrors.ErrPrivacySettingNotFound
+		}
+		slog.Error("error occurred while getting privacy setting", "error", err)
+		return PrivacySetting{}, apperrors.ErrInternalServer
+	}
+
+	return setting, nil
+}
+
+func (pr *privacySettingRepository) UpsertPrivacySetting(ctx context.Context, tx *sqlx.Tx, setting PrivacySetting) (PrivacySetting, error) {
+	executer := pr.BaseRepository.initiateQueryExecuter(tx)
+
+	updated, err := scanPrivacySetting(executer.QueryRowContext(ctx, upsertPrivacySettingQuery,
+		setting.UserId,
+		setting.HideBalance,
+		setting.HideActivity,
+		time.Now(),
+	))
+	if err != nil {
+		slog.Error("error occurred while upserting privacy setting", "error", err)
+		return PrivacySetting{}, apperrors.ErrInternalServer
+	}
+
+	return updated, nil
+}
+
+func scanPrivacySetting(row rowScanner) (PrivacySetting, error) {
+	var setting PrivacySetting
+	err := row.Scan(
+		&setting.UserId,
+		&setting.HideBalance,
+		&setting.HideActivity,
+		&setting.CreatedAt,
+		&setting.UpdatedAt,
+	)
+
+	return setting, err
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type profileRepository struct {
+	BaseRepository
+}
+
+type ProfileRepository interface {
+	RepositoryTransaction
+	GetUserRank(ctx context.Context, tx *sqlx.Tx, userId int) (int, error)
+	GetTopRepositories(ctx context.Context, tx *sqlx.Tx, userId int, limit int) ([]RepositoryContributionCount, error)
+	GetLanguages(ctx context.Context, tx *sqlx.Tx, userId int) ([]LanguageContributionCount, error)
+}
+
+func NewProfileRepository(db *sqlx.DB) ProfileRepository {
+	return &profileRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	getUserRankQuery = "SELECT rank from leaderboard_hourly where user_id=$1 order by refreshed_at desc limit 1"
+
+	getTopRepositoriesQuery = `
+	SELECT r.repo_name, r.owner_name, r.repo_url, r.language, count(*)
+	from contributions c
+	join repositories r on r.id=c.repository_id
+	where c.user_id=$1
+	group by r.id
+	order by count(*) desc, r.repo_name
+	limit $2`
+
+	getLanguagesQuery = `
+	SELECT r.language, count(*)
+	from contributions c
+	join repositories r on r.id=c.repository_id
+	where c.user_id=$1 and r.language<>''
+	group by r.language
+	order by count(*) desc, r.language`
+)
+
+// GetUserRank returns the rank in the latest leaderboard snapshot and
+// ErrUserNotRanked when the user is not on it.
+func (pr *profileRepository) GetUserRank(ctx context.Context, tx *sqlx.Tx, userId int) (int, error) {
+	executer := pr.BaseRepository.initiateQueryExecuter(tx)
+
+	var rank int
+	err := executer.QueryRowContext(ctx, getUserRankQuery, userId).Scan(&rank)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperrors.ErrUserNotRanked
+		}
+		slog.Error("error occurred while getting user rank", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return rank, nil
+}
+
+func (pr *profileRepository) GetTopRepositories(ctx context.Context, tx *sqlx.Tx, userId int, limit int) ([]RepositoryContributionCount, error) {
+	executer := pr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getTopRepositoriesQuery, userId, limit)
+	if err != nil {
+		slog.Error("error occurred while getting top repositories", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	repositories := []RepositoryContributionCount{}
+	for rows.Next() {
+		var repository RepositoryContributionCount
+		err := rows.Scan(
+			&repository.RepoName,
+			&repository.OwnerName,
+			&repository.RepoUrl,
+			&repository.Language,
+			&repository.Contributions,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning top repository", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		repositories = append(repositories, repository)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating top repositories", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return repositories, nil
+}
+
+func (pr *profileRepository) GetLanguages(ctx context.Context, tx *sqlx.Tx, userId int) ([]LanguageContributionCount, error) {
+	executer := pr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getLanguagesQuery, userId)
+	if err != nil {
+		slog.Error("error occurred while getting languages", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	languages := []LanguageContributionCount{}
+	for rows.Next() {
+		var language LanguageContributionCount
+		err := rows.Scan(&language.Language, &language.Contributions)
+		if err != nil {
+			slog.Error("error occurred while scanning language", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		languages = append(languages, language)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating languages", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return languages, nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type redemptionRepository struct {
+	BaseRepository
+}
+
+type RedemptionRepository interface {
+	RepositoryTransaction
+	CreateRedemption(ctx context.Context, tx *sqlx.Tx, redemption Redemption) (Redemption, error)
+	GetRedemptionByIdForUpdate(ctx context.Context, tx *sqlx.Tx, redemptionId int) (Redemption, error)
+	ListRedemptions(ctx context.Context, tx *sqlx.Tx, filter RedemptionFilter) ([]Redemption, error)
+	ListQueuedRedemptions(ctx context.Context, tx *sqlx.Tx, limit int) ([]Redemption, error)
+	FundRedemption(ctx context.Context, tx *sqlx.Tx, redemptionId int, sponsorId int, amountCents int64) error
+	ReviewRedemption(ctx context.Context, tx *sqlx.Tx, redemptionId int, status string, adminId int, note string) error
+}
+
+func NewRedemptionRepository(db *sqlx.DB) RedemptionRepository {
+	return &redemptionRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	redemptionColumns = `
+	id,
+	user_id,
+	sponsor_id,
+	points,
+	amount_cents,
+	store,
+	status,
+	note,
+	reviewed_by,
+	reviewed_at,
+	created_at,
+	updated_at`
+
+	createRedemptionQuery = `
+	INSERT INTO redemptions (
+	user_id,
+	sponsor_id,
+	points,
+	amount_cents,
+	store,
+	status
+	)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING` + redemptionColumns
+
+	getRedemptionByIdForUpdateQuery = "SELECT" + redemptionColumns + " from redemptions where id=$1 FOR UPDATE"
+
+	listRedemptionsQuery = `
+	SELECT` + redemptionColumns + `
+	from redemptions
+	where ($1=0 or user_id=$1)
+	and ($2=0 or sponsor_id=$2)
+	and ($3='' or status=$3)
+	order by id desc
+	limit $4 offset $5`
+
+	listQueuedRedemptionsQuery = "SELECT" + redemptionColumns + " from redemptions where status='queued' order by id limit $1"
+
+	fundRedemptionQuery = "UPDATE redemptions SET sponsor_id=$1, amount_cents=$2, status='pending', updated_at=$3 where id=$4 and status='queued'"
+
+	reviewRedemptionQuery = "UPDATE redemptions SET status=$1, reviewed_by=$2, note=$3, reviewed_at=$4, updated_at=$4 where id=$5"
+)
+
+func (rr *redemptionRepository) CreateRedemption(ctx context.Context, tx *sqlx.Tx, redemption Redemption) (Redemption, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanRedemption(executer.QueryRowContext(ctx, createRedemptionQuery,
+		redemption.UserId,
+		redemption.SponsorId,
+		redemption.Points,
+		redemption.AmountCents,
+		redemption.Store,
+		redemption.Status,
+	))
+	if err != nil {
+		slog.Error("error occurred while creating redemption", "error", err)
+		return Redemption{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (rr *redemptionRepository) GetRedemptionByIdForUpdate(ctx context.Context, tx *sqlx.Tx, redemptionId int) (Redemption, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	redemption, err := scanRedemption(executer.QueryRowContext(ctx, getRedemptionByIdForUpdateQuery, redemptionId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Redemption{}, apperrors.ErrRedemptionNotFound
+		}
+		slog.Error("error occurred while getting redemption by id", "error", err)
+		return Redemption{}, apperrors.ErrInternalServer
+	}
+
+	return redemption, nil
+}
+
+func (rr *redemptionRepository) ListRedemptions(ctx context.Context, tx *sqlx.Tx, filter RedemptionFilter) ([]Redemption, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listRedemptionsQuery, filter.UserId, filter.SponsorId, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		slog.Error("error occurred while listing redemptions", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return collectRedemptions(rows)
+}
+
+// ListQueuedRedemptions returns the redemptions waiting for sponsor budget,
+// oldest first.
+func (rr *redemptionRepository) ListQueuedRedemptions(ctx context.Context, tx *sqlx.Tx, limit int) ([]Redemption, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listQueuedRedemptionsQuery, limit)
+	if err != nil {
+		slog.Error("error occurred while listing queued redemptions", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return collectRedemptions(rows)
+}
+
+// FundRedemption assigns a queued redemption to a sponsor, reserving
+// amountCents of its budget until the redemption is fulfilled or rejected.
+func (rr *redemptionRepository) FundRedemption(ctx context.Context, tx *sqlx.Tx, redemptionId int, sponsorId int, amountCents int64) error {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, fundRedemptionQuery, sponsorId, amountCents, time.Now(), redemptionId)
+	if err != nil {
+		slog.Error("failed to fund redemption", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrRedemptionReviewed)
+}
+
+func (rr *redemptionRepository) ReviewRedemption(ctx context.Context, tx *sqlx.Tx, redemptionId int, status string, adminId int, note string) error {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, reviewRedemptionQuery, status, adminId, note, time.Now(), redemptionId)
+	if err != nil {
+		slog.Error("failed to review redemption", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrRedemptionNotFound)
+}
+
+func collectRedemptions(rows *sql.Rows) ([]Redemption, error) {
+	defer rows.Close()
+
+	redemptions := []Redemption{}
+	for rows.Next() {
+		redemption, err := scanRedemption(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning redemption", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		redemptions = append(redemptions, redemption)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating redemptions", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return redemptions, nil
+}
+
+func scanRedemption(row rowScanner) (Redemption, error) {
+	var redemption Redemption
+	err := row.Scan(
+		&redemption.Id,
+		&redemption.UserId,
+		&redemption.SponsorId,
+		&redemption.Points,
+		&redemption.AmountCents,
+		&redemption.Store,
+		&redemption.Status,
+		&redemption.Note,
+		&redemption.ReviewedBy,
+		&redemption.ReviewedAt,
+		&redemption.CreatedAt,
+		&redemption.UpdatedAt,
+	)
+
+	return redemption, err
+}
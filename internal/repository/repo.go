@@ -0,0 +1,496 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type repoRepository struct {
+	BaseRepository
+}
+
+type RepoRepository interface {
+	RepositoryTransaction
+	GetRepoByGithubRepoId(ctx context.Context, tx *sqlx.Tx, githubRepoId int) (Repo, error)
+	CreateRepo(ctx context.Context, tx *sqlx.Tx, repoInfo Repo) (Repo, error)
+	UpdateRepo(ctx context.Context, tx *sqlx.Tx, repoInfo Repo) (Repo, error)
+	SyncRepo(ctx context.Context, tx *sqlx.Tx, repoInfo Repo) error
+	MarkRepoSynced(ctx context.Context, tx *sqlx.Tx, repoId int) error
+	ListReposDueForSync(ctx context.Context, tx *sqlx.Tx, limit int) ([]Repo, error)
+	ListRepoStats(ctx context.Context, tx *sqlx.Tx, limit int, offset int) ([]RepoStats, error)
+	GetRepositoryAccess(ctx context.Context, tx *sqlx.Tx, ownerName string, repoName string) (string, error)
+	ListRepositoryAccessRules(ctx context.Context, tx *sqlx.Tx) ([]RepositoryAccessRule, error)
+	UpsertRepositoryAccessRule(ctx context.Context, tx *sqlx.Tx, rule RepositoryAccessRule) (RepositoryAccessRule, error)
+	DeleteRepositoryAccessRule(ctx context.Context, tx *sqlx.Tx, ruleId int) error
+}
+
+func NewRepoRepository(db *sqlx.DB) RepoRepository {
+	return &repoRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	repoColumns = `
+	id,
+	github_repo_id,
+	repo_name,
+	description,
+	languages_url,
+	repo_url,
+	owner_name,
+	update_date,
+	created_at,
+	updated_at,
+	language,
+	stars,
+	topics,
+	license,
+	is_archived`
+
+	getRepoByGithubRepoIdQuery = "SELECT" + repoColumns + " from repositories where github_repo_id=$1"
+
+	createRepoQuery = `
+	INSERT INTO repositories (
+	github_repo_id,
+	repo_name,
+	description,
+	languages_url,
+	repo_url,
+	owner_name,
+	update_date,
+	language,
+	stars,
+	topics,
+	license,
+	is_archived
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE($10::text[], '{}'), $11, $12)
+	RETURNING` + repoColumns
+
+	updateRepoQuery = `
+	UPDATE repositories SET
+	repo_name=$1,
+	description=$2,
+	languages_url=$3,
+	repo_url=$4,
+	owner_name=$5,
+	update_date=$6,
+	language=$7,
+	stars=$8,
+	topics=COALESCE($9::text[], '{}'),
+	license=$10,
+	is_archived=$11,
+	updated_at=$12
+	where id=$13
+	RETURNING` + repoColumns
+
+	syncRepoQuery = `
+	UPDATE repositories SET
+	repo_name=$1,
+	description=$2,
+	languages_url=$3,
+	repo_url=$4,
+	owner_name=$5,
+	update_date=$6,
+	language=$7,
+	stars=$8,
+	topics=COALESCE($9::text[], '{}'),
+	license=$10,
+	is_archived=$11,
+	synced_at=$12,
+	updated_at=$12
+	where id=$13`
+
+	markRepoSyncedQuery = "UPDATE repositories SET synced_at=$1 where id=$2"
+
+	listReposDueForSyncQuery = "SELECT" + repoColumns + " from repositories order by synced_at NULLS FIRST, id limit $1"
+
+	// the most specific rule wins: a rule naming the repository overrides
+	// the rule for its owner
+	getRepositoryAccessQuery = `
+	SELECT access from repository_access_rules
+	where lower(owner_name)=lower($1)
+	and (repo_name IS NULL or lower(repo_name)=lower($2))
+	order by repo_name NULLS LAST
+	limit 1`
+
+	// denied repositories are left out of the public listing
+	listRepoStatsQuery = `
+	SELECT
+	r.id,
+	r.github_repo_id,
+	r.repo_name,
+	r.description,
+	r.languages_url,
+	r.repo_url,
+	r.owner_name,
+	r.update_date,
+	r.created_at,
+	r.updated_at,
+	r.language,
+	r.stars,
+	r.topics,
+	r.license,
+	r.is_archived,
+	COALESCE(stats.contributors, 0) AS contributors,
+	COALESCE(stats.total_points, 0) AS total_points
+	from repositories r
+	left join lateral (
+		SELECT count(DISTINCT c.user_id) AS contributors, SUM(cs.balance_change) AS total_points
+		from contributions c
+		join contribution_scores cs on cs.contribution_id=c.id
+		where c.repository_id=r.id and cs.status<>'voided'
+	) stats on true
+	where COALESCE((
+		SELECT a.access from repository_access_rules a
+		where lower(a.owner_name)=lower(r.owner_name)
+		and (a.repo_name IS NULL or lower(a.repo_name)=lower(r.repo_name))
+		order by a.repo_name NULLS LAST
+		limit 1
+	), '')<>'deny'
+	order by total_points desc, r.id
+	limit $1 offset $2`
+
+	repositoryAccessRuleColumns = `
+	id,
+	owner_name,
+	repo_name,
+	access,
+	reason,
+	created_by,
+	created_at,
+	updated_at`
+
+	listRepositoryAccessRulesQuery = "SELECT" + repositoryAccessRuleColumns + " from repository_access_rules order by lower(owner_name), repo_name NULLS FIRST"
+
+	upsertRepositoryAccessRuleQuery = `
+	INSERT INTO repository_access_rules (
+	owner_name,
+	repo_name,
+	access,
+	reason,
+	created_by
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT ((lower(owner_name)), (lower(COALESCE(repo_name, '')))) DO UPDATE SET
+	access=EXCLUDED.access,
+	reason=EXCLUDED.reason,
+	created_by=EXCLUDED.created_by,
+	updated_at=$6
+	RETURNING` + repositoryAccessRuleColumns
+
+	deleteRepositoryAccessRuleQuery = "DELETE from repository_access_rules where id=$1"
+)
+
+func (rr *repoRepository) GetRepoByGithubRepoId(ctx context.Context, tx *sqlx.Tx, githubRepoId int) (Repo, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	repo, err := scanRepo(executer.QueryRowContext(ctx, getRepoByGithubRepoIdQuery, githubRepoId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Repo{}, apperrors.ErrRepoNotFound
+		}
+		slog.Error("error occurred while getting repository by github repo id", "error", err)
+		return Repo{}, apperrors.ErrInternalServer
+	}
+
+	return repo, nil
+}
+
+func (rr *repoRepository) CreateRepo(ctx context.Context, tx *sqlx.Tx, repoInfo Repo) (Repo, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	repo, err := scanRepo(executer.QueryRowContext(ctx, createRepoQuery,
+		repoInfo.GithubRepoId,
+		repoInfo.RepoName,
+		repoInfo.Description,
+		repoInfo.LanguagesUrl,
+		repoInfo.RepoUrl,
+		repoInfo.OwnerName,
+		repoInfo.UpdateDate,
+		repoInfo.Language,
+		repoInfo.Stars,
+		pq.Array(repoInfo.Topics),
+		repoInfo.License,
+		repoInfo.IsArchived,
+	))
+	if err != nil {
+		slog.Error("error occurred while creating repository", "error", err)
+		return Repo{}, apperrors.ErrInternalServer
+	}
+
+	return repo, nil
+}
+
+func (rr *repoRepository) UpdateRepo(ctx context.Context, tx *sqlx.Tx, repoInfo Repo) (Repo, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	repo, err := scanRepo(executer.QueryRowContext(ctx, updateRepoQuery,
+		repoInfo.RepoName,
+		repoInfo.Description,
+		repoInfo.LanguagesUrl,
+		repoInfo.RepoUrl,
+		repoInfo.OwnerName,
+		repoInfo.UpdateDate,
+		repoInfo.Language,
+		repoInfo.Stars,
+		pq.Array(repoInfo.Topics),
+		repoInfo.License,
+		repoInfo.IsArchived,
+		time.Now(),
+		repoInfo.Id,
+	))
+	if err != nil {
+		slog.Error("error occurred while updating repository", "error", err)
+		return Repo{}, apperrors.ErrInternalServer
+	}
+
+	return repo, nil
+}
+
+// SyncRepo stores metadata fetched from GitHub and marks the repository
+// synced.
+func (rr *repoRepository) SyncRepo(ctx context.Context, tx *sqlx.Tx, repoInfo Repo) error {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, syncRepoQuery,
+		repoInfo.RepoName,
+		repoInfo.Description,
+		repoInfo.LanguagesUrl,
+		repoInfo.RepoUrl,
+		repoInfo.OwnerName,
+		repoInfo.UpdateDate,
+		repoInfo.Language,
+		repoInfo.Stars,
+		pq.Array(repoInfo.Topics),
+		repoInfo.License,
+		repoInfo.IsArchived,
+		time.Now(),
+		repoInfo.Id,
+	)
+	if err != nil {
+		slog.Error("failed to sync repository", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrRepoNotFound)
+}
+
+// MarkRepoSynced moves a repository to the back of the sync queue without
+// changing its metadata, for repositories GitHub no longer serves.
+func (rr *repoRepository) MarkRepoSynced(ctx context.Context, tx *sqlx.Tx, repoId int) error {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markRepoSyncedQuery, time.Now(), repoId)
+	if err != nil {
+		slog.Error("failed to mark repository synced", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// ListReposDueForSync returns the repositories synced longest ago, never
+// synced ones first.
+func (rr *repoRepository) ListReposDueForSync(ctx context.Context, tx *sqlx.Tx, limit int) ([]Repo, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listReposDueForSyncQuery, limit)
+	if err != nil {
+		slog.Error("error occurred while listing repositories due for sync", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	repos := []Repo{}
+	for rows.Next() {
+		repo, err := scanRepo(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning repository", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		repos = append(repos, repo)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating repositories", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return repos, nil
+}
+
+// ListRepoStats lists tracked repositories that are not denied, the ones
+// that earned the most points first. Voided contributions are not counted.
+func (rr *repoRepository) ListRepoStats(ctx context.Context, tx *sqlx.Tx, limit int, offset int) ([]RepoStats, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listRepoStatsQuery, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing repository stats", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	repos := []RepoStats{}
+	for rows.Next() {
+		var repo RepoStats
+		err := rows.Scan(
+			&repo.Id,
+			&repo.GithubRepoId,
+			&repo.RepoName,
+			&repo.Description,
+			&repo.LanguagesUrl,
+			&repo.RepoUrl,
+			&repo.OwnerName,
+			&repo.UpdateDate,
+			&repo.CreatedAt,
+			&repo.UpdatedAt,
+			&repo.Language,
+			&repo.Stars,
+			pq.Array(&repo.Topics),
+			&repo.License,
+			&repo.IsArchived,
+			&repo.Contributors,
+			&repo.TotalPoints,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning repository stats", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		repos = append(repos, repo)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating repository stats", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return repos, nil
+}
+
+// GetRepositoryAccess returns the access of the most specific rule matching
+// the repository, or ErrRepositoryAccessRuleNotFound when none does.
+func (rr *repoRepository) GetRepositoryAccess(ctx context.Context, tx *sqlx.Tx, ownerName string, repoName string) (string, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	var access string
+	err := executer.QueryRowContext(ctx, getRepositoryAccessQuery, ownerName, repoName).Scan(&access)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperrors.ErrRepositoryAccessRuleNotFound
+		}
+		slog.Error("error occurred while getting repository access", "error", err)
+		return "", apperrors.ErrInternalServer
+	}
+
+	return access, nil
+}
+
+func (rr *repoRepository) ListRepositoryAccessRules(ctx context.Context, tx *sqlx.Tx) ([]RepositoryAccessRule, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listRepositoryAccessRulesQuery)
+	if err != nil {
+		slog.Error("error occurred while listing repository access rules", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	rules := []RepositoryAccessRule{}
+	for rows.Next() {
+		rule, err := scanRepositoryAccessRule(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning repository access rule", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating repository access rules", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return rules, nil
+}
+
+// UpsertRepositoryAccessRule creates the rule for the owner or repository, or
+// replaces the existing one.
+func (rr *repoRepository) UpsertRepositoryAccessRule(ctx context.Context, tx *sqlx.Tx, rule RepositoryAccessRule) (RepositoryAccessRule, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanRepositoryAccessRule(executer.QueryRowContext(ctx, upsertRepositoryAccessRuleQuery,
+		rule.OwnerName,
+		rule.RepoName,
+		rule.Access,
+		rule.Reason,
+		rule.CreatedBy,
+		time.Now(),
+	))
+	if err != nil {
+		slog.Error("error occurred while upserting repository access rule", "error", err)
+		return RepositoryAccessRule{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (rr *repoRepository) DeleteRepositoryAccessRule(ctx context.Context, tx *sqlx.Tx, ruleId int) error {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, deleteRepositoryAccessRuleQuery, ruleId)
+	if err != nil {
+		slog.Error("failed to delete repository access rule", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrRepositoryAccessRuleNotFound)
+}
+
+func scanRepo(row rowScanner) (Repo, error) {
+	var repo Repo
+	err := row.Scan(
+		&repo.Id,
+		&repo.GithubRepoId,
+		&repo.RepoName,
+		&repo.Description,
+		&repo.LanguagesUrl,
+		&repo.RepoUrl,
+		&repo.OwnerName,
+		&repo.UpdateDate,
+		&repo.CreatedAt,
+		&repo.UpdatedAt,
+		&repo.Language,
+		&repo.Stars,
+		pq.Array(&repo.Topics),
+		&repo.License,
+		&repo.IsArchived,
+	)
+
+	return repo, err
+}
+
+func scanRepositoryAccessRule(row rowScanner) (RepositoryAccessRule, error) {
+	var rule RepositoryAccessRule
+	err := row.Scan(
+		&rule.Id,
+		&rule.OwnerName,
+		&rule.RepoName,
+		&rule.Access,
+		&rule.Reason,
+		&rule.CreatedBy,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+
+	return rule, err
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type riskScoreRepository struct {
+	BaseRepository
+}
+
+type RiskScoreRepository interface {
+	RepositoryTransaction
+	AddUserRiskScore(ctx context.Context, tx *sqlx.Tx, userId int, points int) (int, error)
+	ResetUserRiskScore(ctx context.Context, tx *sqlx.Tx, userId int) error
+	ListUserRiskScores(ctx context.Context, tx *sqlx.Tx, minScore int, limit int, offset int) ([]UserRiskScore, error)
+}
+
+func NewRiskScoreRepository(db *sqlx.DB) RiskScoreRepository {
+	return &riskScoreRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	// the score never drops below zero when dismissed flags are taken back
+	addUserRiskScoreQuery = `
+	INSERT INTO user_risk_scores (user_id, score, updated_at)
+	VALUES ($1, GREATEST($2, 0), $3)
+	ON CONFLICT (user_id) DO UPDATE SET score=GREATEST(user_risk_scores.score+$2, 0), updated_at=$3
+	RETURNING score`
+
+	resetUserRiskScoreQuery = "UPDATE user_risk_scores SET score=0, updated_at=$1 where user_id=$2"
+
+	listUserRiskScoresQuery = `
+	SELECT r.user_id, u.github_username, u.is_blocked, r.score, r.updated_at
+	from user_risk_scores r
+	join users u on u.id=r.user_id
+	where not u.is_deleted and r.score>=$1
+	order by r.score desc, r.user_id
+	limit $2 offset $3`
+)
+
+// AddUserRiskScore adds points, which may be negative, to the user's risk
+// score and returns the new score.
+func (rr *riskScoreRepository) AddUserRiskScore(ctx context.Context, tx *sqlx.Tx, userId int, points int) (int, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	var score int
+	err := executer.QueryRowContext(ctx, addUserRiskScoreQuery, userId, points, time.Now()).Scan(&score)
+	if err != nil {
+		slog.Error("failed to add user risk score", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return score, nil
+}
+
+func (rr *riskScoreRepository) ResetUserRiskScore(ctx context.Context, tx *sqlx.Tx, userId int) error {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, resetUserRiskScoreQuery, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to reset user risk score", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (rr *riskScoreRepository) ListUserRiskScores(ctx context.Context, tx *sqlx.Tx, minScore int, limit int, offset int) ([]UserRiskScore, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listUserRiskScoresQuery, minScore, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing user risk scores", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	scores := []UserRiskScore{}
+	for rows.Next() {
+		var score UserRiskScore
+		err := rows.Scan(&score.UserId, &score.GithubUsername, &score.IsBlocked, &score.Score, &score.UpdatedAt)
+		if err != nil {
+			slog.Error("error occurred while scanning user risk score", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		scores = append(scores, score)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating user risk scores", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return scores, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type roleRepository struct {
+	BaseRepository
+}
+
+type RoleRepository interface {
+	RepositoryTransaction
+	ListRoles(ctx context.Context, tx *sqlx.Tx) ([]Role, error)
+	GetRole(ctx context.Context, tx *sqlx.Tx, name string) (Role, error)
+	ListUserRoles(ctx context.Context, tx *sqlx.Tx, userId int) ([]UserRole, error)
+	GrantUserRole(ctx context.Context, tx *sqlx.Tx, userId int, role string, grantedBy sql.NullInt64) error
+	RevokeUserRole(ctx context.Context, tx *sqlx.Tx, userId int, role string) error
+	CountUsersWithRole(ctx context.Context, tx *sqlx.Tx, role string) (int, error)
+}
+
+func NewRoleRepository(db *sqlx.DB) RoleRepository {
+	return &roleRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	roleColumns = `
+	r.name,
+	r.description,
+	ARRAY(SELECT rp.permission from role_permissions rp where rp.role=r.name ORDER BY 1)`
+
+	listRolesQuery = "SELECT" + roleColumns + " from roles r order by r.name"
+
+	getRoleQuery = "SELECT" + roleColumns + " from roles r where r.name=$1"
+
+	listUserRolesQuery = "SELECT user_id, role, granted_by, created_at from user_roles where user_id=$1 order by role"
+
+	grantUserRoleQuery = "INSERT INTO user_roles (user_id, role, granted_by) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING"
+
+	revokeUserRoleQuery = "DELETE from user_roles where user_id=$1 and role=$2"
+
+	// FOR UPDATE keeps two revocations from both seeing the other holder
+	countUsersWithRoleQuery = `
+	SELECT count(*) from (
+		SELECT ur.user_id from user_roles ur
+		join users u on u.id=ur.user_id
+		where ur.role=$1 and not u.is_deleted
+		FOR UPDATE OF ur
+	) holders`
+)
+
+func (rr *roleRepository) ListRoles(ctx context.Context, tx *sqlx.Tx) ([]Role, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listRolesQuery)
+	if err != nil {
+		slog.Error("error occurred while listing roles", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	roles := []Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning role", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating roles", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return roles, nil
+}
+
+func (rr *roleRepository) GetRole(ctx context.Context, tx *sqlx.Tx, name string) (Role, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	role, err := scanRole(executer.QueryRowContext(ctx, getRoleQuery, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Role{}, apperrors.ErrRoleNotFound
+		}
+		slog.Error("error occurred while getting role", "error", err)
+		return Role{}, apperrors.ErrInternalServer
+	}
+
+	return role, nil
+}
+
+func (rr *roleRepository) ListUserRoles(ctx context.Context, tx *sqlx.Tx, userId int) ([]UserRole, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listUserRolesQuery, userId)
+	if err != nil {
+		slog.Error("error occurred while listing user roles", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	userRoles := []UserRole{}
+	for rows.Next() {
+		var userRole UserRole
+		err := rows.Scan(&userRole.UserId, &userRole.Role, &userRole.GrantedBy, &userRole.CreatedAt)
+		if err != nil {
+			slog.Error("error occurred while scanning user role", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		userRoles = append(userRoles, userRole)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating user roles", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return userRoles, nil
+}
+
+// GrantUserRole is a no-op when the user already holds the role.
+func (rr *roleRepository) GrantUserRole(ctx context.Context, tx *sqlx.Tx, userId int, role string, grantedBy sql.NullInt64) error {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, grantUserRoleQuery, userId, role, grantedBy)
+	if err != nil {
+		slog.Error("failed to grant user role", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (rr *roleRepository) RevokeUserRole(ctx context.Context, tx *sqlx.Tx, userId int, role string) error {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, revokeUserRoleQuery, userId, role)
+	if err != nil {
+		slog.Error("failed to revoke user role", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrUserRoleNotFound)
+}
+
+// CountUsersWithRole counts active users holding the role, locking their
+// role rows until the transaction ends.
+func (rr *roleRepository) CountUsersWithRole(ctx context.Context, tx *sqlx.Tx, role string) (int, error) {
+	executer := rr.BaseRepository.initiateQueryExecuter(tx)
+
+	var count int
+	err := executer.QueryRowContext(ctx, countUsersWithRoleQuery, role).Scan(&count)
+	if err != nil {
+		slog.Error("error occurred while counting users with role", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return count, nil
+}
+
+func scanRole(row rowScanner) (Role, error) {
+	var role Role
+	err := row.Scan(&role.Name, &role.Description, pq.Array(&role.Permissions))
+
+	return role, err
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type scheduledTaskRunRepository struct {
+	BaseRepository
+}
+
+type ScheduledTaskRunRepository interface {
+	RepositoryTransaction
+	CreateScheduledTaskRun(ctx context.Context, tx *sqlx.Tx, run ScheduledTaskRun) (ScheduledTaskRun, error)
+	FinishScheduledTaskRun(ctx context.Context, tx *sqlx.Tx, runId int64, status string, runErr string) error
+	GetLastScheduledTaskRun(ctx context.Context, tx *sqlx.Tx, taskName string) (ScheduledTaskRun, error)
+	ListScheduledTaskRuns(ctx context.Context, tx *sqlx.Tx, taskName string, limit int, offset int) ([]ScheduledTaskRun, error)
+}
+
+func NewScheduledTaskRunRepository(db *sqlx.DB) ScheduledTaskRunRepository {
+	return &scheduledTaskRunRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	scheduledTaskRunColumns = `
+	id,
+	task_name,
+	scheduled_for,
+	trigger,
+	status,
+	error,
+	started_at,
+	finished_at,
+	created_at,
+	updated_at`
+
+	createScheduledTaskRunQuery = `
+	INSERT INTO scheduled_task_runs (
+	task_name,
+	scheduled_for,
+	trigger
+	)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (task_name, scheduled_for) WHERE trigger <> 'manual' DO NOTHING
+	RETURNING` + scheduledTaskRunColumns
+
+	finishScheduledTaskRunQuery = "UPDATE scheduled_task_runs SET status=$1, error=NULLIF($2, ''), finished_at=$3, updated_at=$3 where id=$4"
+
+	getLastScheduledTaskRunQuery = "SELECT" + scheduledTaskRunColumns + " from scheduled_task_runs where task_name=$1 and trigger <> 'manual' order by scheduled_for desc limit 1"
+
+	listScheduledTaskRunsQuery = "SELECT" + scheduledTaskRunColumns + " from scheduled_task_runs where ($1='' or task_name=$1) order by started_at desc limit $2 offset $3"
+)
+
+// CreateScheduledTaskRun returns ErrTaskRunAlreadyRecorded when the slot was
+// already run, which is what keeps scheduled tasks exactly-once across
+// leader changes.
+func (sr *scheduledTaskRunRepository) CreateScheduledTaskRun(ctx context.Context, tx *sqlx.Tx, runInfo ScheduledTaskRun) (ScheduledTaskRun, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	run, err := scanScheduledTaskRun(executer.QueryRowContext(ctx, createScheduledTaskRunQuery,
+		runInfo.TaskName,
+		runInfo.ScheduledFor,
+		runInfo.Trigger,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ScheduledTaskRun{}, apperrors.ErrTaskRunAlreadyRecorded
+		}
+		slog.Error("error occurred while creating scheduled task run", "error", err)
+		return ScheduledTaskRun{}, apperrors.ErrInternalServer
+	}
+
+	return run, nil
+}
+
+func (sr *scheduledTaskRunRepository) FinishScheduledTaskRun(ctx context.Context, tx *sqlx.Tx, runId int64, status string, runErr string) error {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, finishScheduledTaskRunQuery, status, runErr, time.Now(), runId)
+	if err != nil {
+		slog.Error("failed to finish scheduled task run", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (sr *scheduledTaskRunRepository) GetLastScheduledTaskRun(ctx context.Context, tx *sqlx.Tx, taskName string) (ScheduledTaskRun, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	run, err := scanScheduledTaskRun(executer.QueryRowContext(ctx, getLastScheduledTaskRunQuery, taskName))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ScheduledTaskRun{}, apperrors.ErrTaskRunNotFound
+		}
+		slog.Error("error occurred while getting last scheduled task run", "error", err)
+		return ScheduledTaskRun{}, apperrors.ErrInternalServer
+	}
+
+	return run, nil
+}
+
+func (sr *scheduledTaskRunRepository) ListScheduledTaskRuns(ctx context.Context, tx *sqlx.Tx, taskName string, limit int, offset int) ([]ScheduledTaskRun, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listScheduledTaskRunsQuery, taskName, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing scheduled task runs", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	runs := []ScheduledTaskRun{}
+	for rows.Next() {
+		run, err := scanScheduledTaskRun(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning scheduled task run", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating scheduled task runs", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return runs, nil
+}
+
+func scanScheduledTaskRun(row rowScanner) (ScheduledTaskRun, error) {
+	var run ScheduledTaskRun
+	err := row.Scan(
+		&run.Id,
+		&run.TaskName,
+		&run.ScheduledFor,
+		&run.Trigger,
+		&run.Status,
+		&run.Error,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.CreatedAt,
+		&run.UpdatedAt,
+	)
+
+	return run, err
+}
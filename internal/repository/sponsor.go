@@ -0,0 +1,478 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type sponsorRepository struct {
+	BaseRepository
+}
+
+type SponsorRepository interface {
+	RepositoryTransaction
+	CreateSponsor(ctx context.Context, tx *sqlx.Tx, sponsor Sponsor) (Sponsor, error)
+	GetSponsorById(ctx context.Context, tx *sqlx.Tx, sponsorId int) (Sponsor, error)
+	ListSponsorBudgets(ctx context.Context, tx *sqlx.Tx, activeOnly bool) ([]SponsorBudget, error)
+	GetSponsorBudget(ctx context.Context, tx *sqlx.Tx, sponsorId int) (SponsorBudget, error)
+	ListSponsorContributions(ctx context.Context, tx *sqlx.Tx, sponsorId int, limit int, offset int) ([]SponsorContribution, error)
+	ListSponsorTopContributors(ctx context.Context, tx *sqlx.Tx, sponsorId int, from sql.NullTime, to sql.NullTime, limit int) ([]SponsorContributor, error)
+	SetSponsorEarmarks(ctx context.Context, tx *sqlx.Tx, sponsorId int, ownerNames []string) error
+	CreateSponsorBudgetEntry(ctx context.Context, tx *sqlx.Tx, entry SponsorBudgetEntry) (SponsorBudgetEntry, error)
+	ListSponsorBudgetEntries(ctx context.Context, tx *sqlx.Tx, sponsorId int, limit int, offset int) ([]SponsorBudgetEntry, error)
+	LockSponsorBudgets(ctx context.Context, tx *sqlx.Tx) error
+	FindFundingSponsor(ctx context.Context, tx *sqlx.Tx, userId int, points int) (Sponsor, error)
+}
+
+func NewSponsorRepository(db *sqlx.DB) SponsorRepository {
+	return &sponsorRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	sponsorColumns = `
+	id,
+	name,
+	website_url,
+	points_per_usd,
+	is_active,
+	created_at,
+	updated_at`
+
+	sponsorBudgetEntryColumns = `
+	id,
+	sponsor_id,
+	entry_type,
+	amount_cents,
+	redemption_id,
+	note,
+	created_by,
+	created_at,
+	updated_at`
+
+	createSponsorQuery = `
+	INSERT INTO sponsors (
+	name,
+	website_url,
+	points_per_usd
+	)
+	VALUES ($1, $2, $3)
+	ON CONFLICT DO NOTHING
+	RETURNING` + sponsorColumns
+
+	getSponsorByIdQuery = "SELECT" + sponsorColumns + " from sponsors where id=$1"
+
+	listSponsorBudgetsQuery = `
+	SELECT
+	s.id,
+	s.name,
+	s.website_url,
+	s.points_per_usd,
+	s.is_active,
+	s.created_at,
+	s.updated_at,
+	COALESCE((SELECT array_agg(e.owner_name order by e.owner_name) from sponsor_earmarks e where e.sponsor_id=s.id), '{}'),
+	COALESCE(ledger.pledged, 0),
+	COALESCE(ledger.spent, 0),
+	COALESCE(ledger.balance, 0),
+	(SELECT COALESCE(SUM(r.amount_cents), 0) from redemptions r where r.sponsor_id=s.id and r.status='pending')
+	from sponsors s
+	left join (
+		SELECT
+		sponsor_id,
+		SUM(amount_cents) FILTER (WHERE entry_type='pledge') AS pledged,
+		-SUM(amount_cents) FILTER (WHERE entry_type='redemption') AS spent,
+		SUM(amount_cents) AS balance
+		from sponsor_budget_entries
+		group by sponsor_id
+	) ledger on ledger.sponsor_id=s.id
+	where (not $1 or s.is_active)
+	and ($2=0 or s.id=$2)
+	order by s.name`
+
+	// contributions of users hiding their activity are left out of the
+	// sponsor portal
+	listSponsorContributionsQuery = `
+	SELECT c.id, u.github_username, r.owner_name || '/' || r.repo_name, c.contribution_type, cs.balance_change, c.contributed_at
+	from contributions c
+	join contribution_scores cs on cs.contribution_id=c.id
+	join repositories r on r.id=c.repository_id
+	join sponsor_earmarks e on e.owner_name=lower(r.owner_name)
+	join users u on u.id=c.user_id
+	left join user_privacy_settings p on p.user_id=u.id
+	where e.sponsor_id=$1
+	and cs.status<>'voided'
+	and not u.is_deleted
+	and not COALESCE(p.hide_activity, false)
+	order by c.contributed_at desc, c.id desc
+	limit $2 offset $3`
+
+	listSponsorTopContributorsQuery = `
+	SELECT u.id, u.github_username, u.avatar_url, count(*), COALESCE(SUM(cs.balance_change), 0) AS points
+	from contributions c
+	join contribution_scores cs on cs.contribution_id=c.id
+	join repositories r on r.id=c.repository_id
+	join sponsor_earmarks e on e.owner_name=lower(r.owner_name)
+	join users u on u.id=c.user_id
+	left join user_privacy_settings p on p.user_id=u.id
+	where e.sponsor_id=$1
+	and cs.status<>'voided'
+	and not u.is_deleted
+	and not COALESCE(p.hide_activity, false)
+	and ($2::timestamptz IS NULL or c.contributed_at>=$2)
+	and ($3::timestamptz IS NULL or c.contributed_at<$3)
+	group by u.id
+	order by points desc, u.id
+	limit $4`
+
+	deleteSponsorEarmarksQuery = "DELETE from sponsor_earmarks where sponsor_id=$1"
+
+	createSponsorEarmarkQuery = "INSERT INTO sponsor_earmarks (sponsor_id, owner_name) VALUES ($1, lower($2)) ON CONFLICT DO NOTHING"
+
+	createSponsorBudgetEntryQuery = `
+	INSERT INTO sponsor_budget_entries (
+	sponsor_id,
+	entry_type,
+	amount_cents,
+	redemption_id,
+	note,
+	created_by
+	)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING` + sponsorBudgetEntryColumns
+
+	listSponsorBudgetEntriesQuery = `
+	SELECT` + sponsorBudgetEntryColumns + `
+	from sponsor_budget_entries
+	where sponsor_id=$1
+	order by id desc
+	limit $2 offset $3`
+
+	// funding serializes on the sponsors table so two redemptions cannot
+	// reserve the same budget
+	lockSponsorBudgetsQuery = "LOCK TABLE sponsors IN SHARE ROW EXCLUSIVE MODE"
+
+	// earmarked sponsors go first since they can only fund part of the
+	// points, and a user can only draw on an earmarked sponsor for points
+	// earned in its owners' repositories that it has not funded already
+	findFundingSponsorQuery = `
+	SELECT` + sponsorColumns + `
+	from sponsors s
+	where s.is_active
+	and (SELECT COALESCE(SUM(amount_cents), 0) from sponsor_budget_entries where sponsor_id=s.id)
+		- (SELECT COALESCE(SUM(amount_cents), 0) from redemptions where sponsor_id=s.id and status='pending')
+		>= $2 * 100 / s.points_per_usd
+	and (
+		not exists (SELECT 1 from sponsor_earmarks e where e.sponsor_id=s.id)
+		or (
+			SELECT COALESCE(SUM(cs.balance_change), 0)
+			from contributions c
+			join contribution_scores cs on cs.contribution_id=c.id
+			join repositories r on r.id=c.repository_id
+			join sponsor_earmarks e on e.sponsor_id=s.id and e.owner_name=lower(r.owner_name)
+			where c.user_id=$1 and cs.status<>'voided'
+		) - (
+			SELECT COALESCE(SUM(points), 0) from redemptions
+			where sponsor_id=s.id and user_id=$1 and status in ('pending', 'fulfilled')
+		) >= $2
+	)
+	order by exists (SELECT 1 from sponsor_earmarks e where e.sponsor_id=s.id) desc, s.id
+	limit 1`
+)
+
+// CreateSponsor returns ErrSponsorExists when a sponsor with the same name,
+// ignoring case, is already registered.
+func (sr *sponsorRepository) CreateSponsor(ctx context.Context, tx *sqlx.Tx, sponsor Sponsor) (Sponsor, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanSponsor(executer.QueryRowContext(ctx, createSponsorQuery, sponsor.Name, sponsor.WebsiteUrl, sponsor.PointsPerUsd))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Sponsor{}, apperrors.ErrSponsorExists
+		}
+		slog.Error("error occurred while creating sponsor", "error", err)
+		return Sponsor{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (sr *sponsorRepository) GetSponsorById(ctx context.Context, tx *sqlx.Tx, sponsorId int) (Sponsor, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	sponsor, err := scanSponsor(executer.QueryRowContext(ctx, getSponsorByIdQuery, sponsorId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Sponsor{}, apperrors.ErrSponsorNotFound
+		}
+		slog.Error("error occurred while getting sponsor by id", "error", err)
+		return Sponsor{}, apperrors.ErrInternalServer
+	}
+
+	return sponsor, nil
+}
+
+func (sr *sponsorRepository) ListSponsorBudgets(ctx context.Context, tx *sqlx.Tx, activeOnly bool) ([]SponsorBudget, error) {
+	return sr.listSponsorBudgets(ctx, tx, activeOnly, 0)
+}
+
+func (sr *sponsorRepository) GetSponsorBudget(ctx context.Context, tx *sqlx.Tx, sponsorId int) (SponsorBudget, error) {
+	budgets, err := sr.listSponsorBudgets(ctx, tx, false, sponsorId)
+	if err != nil {
+		return SponsorBudget{}, err
+	}
+	if len(budgets) == 0 {
+		return SponsorBudget{}, apperrors.ErrSponsorNotFound
+	}
+
+	return budgets[0], nil
+}
+
+func (sr *sponsorRepository) listSponsorBudgets(ctx context.Context, tx *sqlx.Tx, activeOnly bool, sponsorId int) ([]SponsorBudget, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listSponsorBudgetsQuery, activeOnly, sponsorId)
+	if err != nil {
+		slog.Error("error occurred while listing sponsor budgets", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	budgets := []SponsorBudget{}
+	for rows.Next() {
+		var budget SponsorBudget
+		err := rows.Scan(
+			&budget.Id,
+			&budget.Name,
+			&budget.WebsiteUrl,
+			&budget.PointsPerUsd,
+			&budget.IsActive,
+			&budget.CreatedAt,
+			&budget.UpdatedAt,
+			pq.Array(&budget.EarmarkedOwners),
+			&budget.PledgedCents,
+			&budget.SpentCents,
+			&budget.BalanceCents,
+			&budget.ReservedCents,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning sponsor budget", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		budgets = append(budgets, budget)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating sponsor budgets", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return budgets, nil
+}
+
+// ListSponsorContributions returns the latest contributions to repositories
+// of the owners the sponsor is earmarked for.
+func (sr *sponsorRepository) ListSponsorContributions(ctx context.Context, tx *sqlx.Tx, sponsorId int, limit int, offset int) ([]SponsorContribution, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listSponsorContributionsQuery, sponsorId, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing sponsor contributions", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	contributions := []SponsorContribution{}
+	for rows.Next() {
+		var contribution SponsorContribution
+		err := rows.Scan(
+			&contribution.Id,
+			&contribution.GithubUsername,
+			&contribution.RepositoryName,
+			&contribution.ContributionType,
+			&contribution.BalanceChange,
+			&contribution.ContributedAt,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning sponsor contribution", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		contributions = append(contributions, contribution)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating sponsor contributions", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return contributions, nil
+}
+
+func (sr *sponsorRepository) ListSponsorTopContributors(ctx context.Context, tx *sqlx.Tx, sponsorId int, from sql.NullTime, to sql.NullTime, limit int) ([]SponsorContributor, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listSponsorTopContributorsQuery, sponsorId, from, to, limit)
+	if err != nil {
+		slog.Error("error occurred while listing sponsor top contributors", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	contributors := []SponsorContributor{}
+	for rows.Next() {
+		var contributor SponsorContributor
+		err := rows.Scan(&contributor.UserId, &contributor.GithubUsername, &contributor.AvatarUrl, &contributor.Contributions, &contributor.Points)
+		if err != nil {
+			slog.Error("error occurred while scanning sponsor top contributor", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		contributors = append(contributors, contributor)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating sponsor top contributors", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return contributors, nil
+}
+
+// SetSponsorEarmarks replaces the owners a sponsor is earmarked for. An
+// empty list lets the sponsor fund any redemption. Run it in a transaction.
+func (sr *sponsorRepository) SetSponsorEarmarks(ctx context.Context, tx *sqlx.Tx, sponsorId int, ownerNames []string) error {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, deleteSponsorEarmarksQuery, sponsorId)
+	if err != nil {
+		slog.Error("failed to delete sponsor earmarks", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	for _, ownerName := range ownerNames {
+		_, err = executer.ExecContext(ctx, createSponsorEarmarkQuery, sponsorId, ownerName)
+		if err != nil {
+			slog.Error("failed to create sponsor earmark", "error", err)
+			return apperrors.ErrInternalServer
+		}
+	}
+
+	return nil
+}
+
+func (sr *sponsorRepository) CreateSponsorBudgetEntry(ctx context.Context, tx *sqlx.Tx, entry SponsorBudgetEntry) (SponsorBudgetEntry, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanSponsorBudgetEntry(executer.QueryRowContext(ctx, createSponsorBudgetEntryQuery,
+		entry.SponsorId,
+		entry.EntryType,
+		entry.AmountCents,
+		entry.RedemptionId,
+		entry.Note,
+		entry.CreatedBy,
+	))
+	if err != nil {
+		slog.Error("error occurred while creating sponsor budget entry", "error", err)
+		return SponsorBudgetEntry{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (sr *sponsorRepository) ListSponsorBudgetEntries(ctx context.Context, tx *sqlx.Tx, sponsorId int, limit int, offset int) ([]SponsorBudgetEntry, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listSponsorBudgetEntriesQuery, sponsorId, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing sponsor budget entries", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	entries := []SponsorBudgetEntry{}
+	for rows.Next() {
+		entry, err := scanSponsorBudgetEntry(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning sponsor budget entry", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating sponsor budget entries", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return entries, nil
+}
+
+// LockSponsorBudgets must run inside the transaction that funds a
+// redemption, before FindFundingSponsor.
+func (sr *sponsorRepository) LockSponsorBudgets(ctx context.Context, tx *sqlx.Tx) error {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, lockSponsorBudgetsQuery)
+	if err != nil {
+		slog.Error("failed to lock sponsor budgets", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// FindFundingSponsor picks a sponsor whose unreserved budget covers the
+// points and whose earmarks allow funding them for the user. It returns
+// ErrSponsorBudgetUnavailable when there is none.
+func (sr *sponsorRepository) FindFundingSponsor(ctx context.Context, tx *sqlx.Tx, userId int, points int) (Sponsor, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	sponsor, err := scanSponsor(executer.QueryRowContext(ctx, findFundingSponsorQuery, userId, points))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Sponsor{}, apperrors.ErrSponsorBudgetUnavailable
+		}
+		slog.Error("error occurred while finding funding sponsor", "error", err)
+		return Sponsor{}, apperrors.ErrInternalServer
+	}
+
+	return sponsor, nil
+}
+
+func scanSponsor(row rowScanner) (Sponsor, error) {
+	var sponsor Sponsor
+	err := row.Scan(
+		&sponsor.Id,
+		&sponsor.Name,
+		&sponsor.WebsiteUrl,
+		&sponsor.PointsPerUsd,
+		&sponsor.IsActive,
+		&sponsor.CreatedAt,
+		&sponsor.UpdatedAt,
+	)
+
+	return sponsor, err
+}
+
+func scanSponsorBudgetEntry(row rowScanner) (SponsorBudgetEntry, error) {
+	var entry SponsorBudgetEntry
+	err := row.Scan(
+		&entry.Id,
+		&entry.SponsorId,
+		&entry.EntryType,
+		&entry.AmountCents,
+		&entry.RedemptionId,
+		&entry.Note,
+		&entry.CreatedBy,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+
+	return entry, err
+}
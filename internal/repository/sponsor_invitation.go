@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type sponsorInvitationRepository struct {
+	BaseRepository
+}
+
+type SponsorInvitationRepository interface {
+	RepositoryTransaction
+	CreateSponsorInvitation(ctx context.Context, tx *sqlx.Tx, invitation SponsorInvitation) (SponsorInvitation, error)
+	ListSponsorInvitations(ctx context.Context, tx *sqlx.Tx, sponsorId int) ([]SponsorInvitation, error)
+	GetSponsorInvitationByTokenHashForUpdate(ctx context.Context, tx *sqlx.Tx, tokenHash string) (SponsorInvitation, error)
+	AcceptSponsorInvitation(ctx context.Context, tx *sqlx.Tx, invitationId int, userId int) error
+}
+
+func NewSponsorInvitationRepository(db *sqlx.DB) SponsorInvitationRepository {
+	return &sponsorInvitationRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	sponsorInvitationColumns = `
+	id,
+	sponsor_id,
+	github_username,
+	token_hash,
+	invited_by,
+	accepted_by,
+	accepted_at,
+	expires_at,
+	created_at,
+	updated_at`
+
+	createSponsorInvitationQuery = `
+	INSERT INTO sponsor_invitations (
+	sponsor_id,
+	github_username,
+	token_hash,
+	invited_by,
+	expires_at
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING` + sponsorInvitationColumns
+
+	listSponsorInvitationsQuery = "SELECT" + sponsorInvitationColumns + " from sponsor_invitations where sponsor_id=$1 order by id desc"
+
+	getSponsorInvitationByTokenHashForUpdateQuery = "SELECT" + sponsorInvitationColumns + " from sponsor_invitations where token_hash=$1 FOR UPDATE"
+
+	acceptSponsorInvitationQuery = "UPDATE sponsor_invitations SET accepted_by=$1, accepted_at=$2, updated_at=$2 where id=$3 and accepted_at IS NULL"
+)
+
+func (ir *sponsorInvitationRepository) CreateSponsorInvitation(ctx context.Context, tx *sqlx.Tx, invitation SponsorInvitation) (SponsorInvitation, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanSponsorInvitation(executer.QueryRowContext(ctx, createSponsorInvitationQuery,
+		invitation.SponsorId,
+		invitation.GithubUsername,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+	))
+	if err != nil {
+		slog.Error("error occurred while creating sponsor invitation", "error", err)
+		return SponsorInvitation{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (ir *sponsorInvitationRepository) ListSponsorInvitations(ctx context.Context, tx *sqlx.Tx, sponsorId int) ([]SponsorInvitation, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listSponsorInvitationsQuery, sponsorId)
+	if err != nil {
+		slog.Error("error occurred while listing sponsor invitations", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	invitations := []SponsorInvitation{}
+	for rows.Next() {
+		invitation, err := scanSponsorInvitation(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning sponsor invitation", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating sponsor invitations", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return invitations, nil
+}
+
+func (ir *sponsorInvitationRepository) GetSponsorInvitationByTokenHashForUpdate(ctx context.Context, tx *sqlx.Tx, tokenHash string) (SponsorInvitation, error) {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	invitation, err := scanSponsorInvitation(executer.QueryRowContext(ctx, getSponsorInvitationByTokenHashForUpdateQuery, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SponsorInvitation{}, apperrors.ErrSponsorInvitationNotFound
+		}
+		slog.Error("error occurred while getting sponsor invitation by token", "error", err)
+		return SponsorInvitation{}, apperrors.ErrInternalServer
+	}
+
+	return invitation, nil
+}
+
+func (ir *sponsorInvitationRepository) AcceptSponsorInvitation(ctx context.Context, tx *sqlx.Tx, invitationId int, userId int) error {
+	executer := ir.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, acceptSponsorInvitationQuery, userId, time.Now(), invitationId)
+	if err != nil {
+		slog.Error("failed to accept sponsor invitation", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrSponsorInvitationUsed)
+}
+
+func scanSponsorInvitation(row rowScanner) (SponsorInvitation, error) {
+	var invitation SponsorInvitation
+	err := row.Scan(
+		&invitation.Id,
+		&invitation.SponsorId,
+		&invitation.GithubUsername,
+		&invitation.TokenHash,
+		&invitation.InvitedBy,
+		&invitation.AcceptedBy,
+		&invitation.AcceptedAt,
+		&invitation.ExpiresAt,
+		&invitation.CreatedAt,
+		&invitation.UpdatedAt,
+	)
+
+	return invitation, err
+}
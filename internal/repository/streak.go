@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
+)
+
+type streakRepository struct {
+	BaseRepository
+}
+
+type StreakRepository interface {
+	RepositoryTransaction
+	RecordDailyActivity(ctx context.Context, tx *sqlx.Tx, userId int, activityDate time.Time, contributions int, points int) error
+	ListDailyActivity(ctx context.Context, tx *sqlx.Tx, userId int, from time.Time, to time.Time) ([]DailyActivity, error)
+	ListStreakDays(ctx context.Context, tx *sqlx.Tx, userId int, from time.Time, to time.Time) ([]StreakDay, error)
+	GetStreak(ctx context.Context, tx *sqlx.Tx, userId int) (Streak, error)
+	LockStreak(ctx context.Context, tx *sqlx.Tx, userId int) (Streak, error)
+	UpdateStreak(ctx context.Context, tx *sqlx.Tx, streakInfo Streak) error
+	CreateStreakFreezes(ctx context.Context, tx *sqlx.Tx, userId int, freezeDates []time.Time) error
+}
+
+func NewStreakRepository(db *sqlx.DB) StreakRepository {
+	return &streakRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	dailyActivityColumns = `
+	user_id,
+	activity_date,
+	contributions,
+	points,
+	created_at,
+	updated_at`
+
+	streakColumns = `
+	user_id,
+	current_streak,
+	longest_streak,
+	last_active_date,
+	freeze_tokens,
+	created_at,
+	updated_at`
+
+	recordDailyActivityQuery = `
+	INSERT INTO daily_activity (
+	user_id,
+	activity_date,
+	contributions,
+	points
+	)
+	VALUES ($1, $2, GREATEST($3, 0), $4)
+	ON CONFLICT (user_id, activity_date) DO UPDATE SET
+	contributions=GREATEST(daily_activity.contributions+$3, 0),
+	points=daily_activity.points+EXCLUDED.points,
+	updated_at=$5`
+
+	// days whose contributions were all voided are no longer active
+	listDailyActivityQuery = "SELECT" + dailyActivityColumns + " from daily_activity where user_id=$1 and activity_date>=$2 and activity_date<=$3 and contributions>0 order by activity_date"
+
+	// a frozen day that later got backfilled activity counts as active
+	listStreakDaysQuery = `
+	SELECT day, bool_and(frozen) from (
+		SELECT activity_date as day, false as frozen from daily_activity where user_id=$1 and activity_date>=$2 and activity_date<=$3 and contributions>0
+		UNION ALL
+		SELECT freeze_date, true from streak_freezes where user_id=$1 and freeze_date>=$2 and freeze_date<=$3
+	) days
+	group by day
+	order by day`
+
+	getStreakQuery = "SELECT" + streakColumns + " from streaks where user_id=$1"
+
+	// creates the row on first use and locks it either way
+	lockStreakQuery = `
+	INSERT INTO streaks (user_id)
+	VALUES ($1)
+	ON CONFLICT (user_id) DO UPDATE SET user_id=EXCLUDED.user_id
+	RETURNING` + streakColumns
+
+	updateStreakQuery = "UPDATE streaks SET current_streak=$1, longest_streak=$2, last_active_date=$3, freeze_tokens=$4, updated_at=$5 where user_id=$6"
+
+	createStreakFreezesQuery = `
+	INSERT INTO streak_freezes (user_id, freeze_date)
+	SELECT $1, unnest($2::date[])
+	ON CONFLICT (user_id, freeze_date) DO NOTHING`
+)
+
+// RecordDailyActivity adds to the user's totals for the day, creating the
+// day on first activity. Pass negative amounts to take voided or rescored
+// contributions back out; the day's contributions never drop below zero.
+func (sr *streakRepository) RecordDailyActivity(ctx context.Context, tx *sqlx.Tx, userId int, activityDate time.Time, contributions int, points int) error {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, recordDailyActivityQuery, userId, activityDate, contributions, points, time.Now())
+	if err != nil {
+		slog.Error("failed to record daily activity", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (sr *streakRepository) ListDailyActivity(ctx context.Context, tx *sqlx.Tx, userId int, from time.Time, to time.Time) ([]DailyActivity, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listDailyActivityQuery, userId, from, to)
+	if err != nil {
+		slog.Error("error occurred while listing daily activity", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	days := []DailyActivity{}
+	for rows.Next() {
+		var day DailyActivity
+		err = rows.Scan(
+			&day.UserId,
+			&day.ActivityDate,
+			&day.Contributions,
+			&day.Points,
+			&day.CreatedAt,
+			&day.UpdatedAt,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning daily activity", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		days = append(days, day)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating daily activity", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return days, nil
+}
+
+// ListStreakDays returns the active and frozen days between from and to in
+// date order.
+func (sr *streakRepository) ListStreakDays(ctx context.Context, tx *sqlx.Tx, userId int, from time.Time, to time.Time) ([]StreakDay, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listStreakDaysQuery, userId, from, to)
+	if err != nil {
+		slog.Error("error occurred while listing streak days", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	days := []StreakDay{}
+	for rows.Next() {
+		var day StreakDay
+		err = rows.Scan(&day.Date, &day.Frozen)
+		if err != nil {
+			slog.Error("error occurred while scanning streak day", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		days = append(days, day)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating streak days", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return days, nil
+}
+
+// GetStreak returns an empty streak for users who were never active.
+func (sr *streakRepository) GetStreak(ctx context.Context, tx *sqlx.Tx, userId int) (Streak, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	streak, err := scanStreak(executer.QueryRowContext(ctx, getStreakQuery, userId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Streak{UserId: userId}, nil
+		}
+		slog.Error("error occurred while getting streak", "error", err)
+		return Streak{}, apperrors.ErrInternalServer
+	}
+
+	return streak, nil
+}
+
+// LockStreak returns the user's streak locked until tx ends.
+func (sr *streakRepository) LockStreak(ctx context.Context, tx *sqlx.Tx, userId int) (Streak, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	streak, err := scanStreak(executer.QueryRowContext(ctx, lockStreakQuery, userId))
+	if err != nil {
+		slog.Error("error occurred while locking streak", "error", err)
+		return Streak{}, apperrors.ErrInternalServer
+	}
+
+	return streak, nil
+}
+
+func (sr *streakRepository) UpdateStreak(ctx context.Context, tx *sqlx.Tx, streakInfo Streak) error {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, updateStreakQuery,
+		streakInfo.CurrentStreak,
+		streakInfo.LongestStreak,
+		streakInfo.LastActiveDate,
+		streakInfo.FreezeTokens,
+		time.Now(),
+		streakInfo.UserId,
+	)
+	if err != nil {
+		slog.Error("failed to update streak", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (sr *streakRepository) CreateStreakFreezes(ctx context.Context, tx *sqlx.Tx, userId int, freezeDates []time.Time) error {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	dates := make([]string, 0, len(freezeDates))
+	for _, freezeDate := range freezeDates {
+		dates = append(dates, freezeDate.Format(time.DateOnly))
+	}
+
+	_, err := executer.ExecContext(ctx, createStreakFreezesQuery, userId, pq.Array(dates))
+	if err != nil {
+		slog.Error("failed to record streak freezes", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func scanStreak(row rowScanner) (Streak, error) {
+	var streak Streak
+	err := row.Scan(
+		&streak.UserId,
+		&streak.CurrentStreak,
+		&streak.LongestStreak,
+		&streak.LastActiveDate,
+		&streak.FreezeTokens,
+		&streak.CreatedAt,
+		&streak.UpdatedAt,
+	)
+
+	return streak, err
+}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type summaryRepository struct {
+	BaseRepository
+}
+
+type SummaryRepository interface {
+	RepositoryTransaction
+	GetUserIdsPendingMonthClose(ctx context.Context, tx *sqlx.Tx, monthYear int, monthStart time.Time, monthEnd time.Time) ([]int, error)
+	CreditMonthContributions(ctx context.Context, tx *sqlx.Tx, userId int, monthStart time.Time, monthEnd time.Time) (int, int, error)
+	GetAchievedGoalLevel(ctx context.Context, tx *sqlx.Tx, userId int, monthStart time.Time, monthEnd time.Time) (string, bool, error)
+	CreateSummary(ctx context.Context, tx *sqlx.Tx, userId int, monthYear int, netBalance int, lastContributionId int, monthStart time.Time, monthEnd time.Time) error
+	RankMonth(ctx context.Context, tx *sqlx.Tx, monthYear int) error
+	GetSummariesByUserId(ctx context.Context, tx *sqlx.Tx, userId int) ([]Summary, error)
+	AdjustSummaryNetBalance(ctx context.Context, tx *sqlx.Tx, userId int, monthYear int, amount int) error
+}
+
+func NewSummaryRepository(db *sqlx.DB) SummaryRepository {
+	return &summaryRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	// users whose contributions for the month were all voided have nothing
+	// to credit and get no summary
+	getUserIdsPendingMonthCloseQuery = `
+	SELECT DISTINCT c.user_id from contributions c
+	join contribution_scores cs on cs.contribution_id=c.id
+	join users u on u.id=c.user_id
+	where c.contributed_at>=$1 and c.contributed_at<$2
+	and cs.status<>'voided'
+	and not u.is_deleted
+	and not u.is_blocked
+	and not exists (SELECT 1 from summary s where s.user_id=c.user_id and s.month_year=$3)
+	order by c.user_id`
+
+	// credits every contribution of the month to the wallet as one
+	// transaction each, returning the net amount and the latest contribution
+	creditMonthContributionsQuery = `
+	WITH credited AS (
+		INSERT INTO transactions (
+		user_id,
+		contribution_id,
+		is_redeemed,
+		is_gained,
+		transacted_balance,
+		transacted_at
+		)
+		SELECT c.user_id, c.id, FALSE, cs.balance_change>=0, ABS(cs.balance_change), $4
+		from contributions c
+		join contribution_scores cs on cs.contribution_id=c.id
+		where c.user_id=$1 and c.contributed_at>=$2 and c.contributed_at<$3 and cs.status<>'voided'
+		RETURNING contribution_id, CASE WHEN is_gained THEN transacted_balance ELSE -transacted_balance END AS amount
+	)
+	SELECT COALESCE(SUM(amount), 0), COALESCE(MAX(contribution_id), 0) from credited`
+
+	createSummaryQuery = `
+	INSERT INTO summary (
+	user_id,
+	month_year,
+	net_balance,
+	badges_count,
+	rank,
+	contribution_id
+	)
+	VALUES (
+	$1,
+	$2,
+	$3,
+	(SELECT count(*) from badges where user_id=$1 and earned_at>=$5 and earned_at<$6),
+	0,
+	$4
+	)`
+
+	rankMonthQuery = `
+	UPDATE summary s SET rank=ranked.rank, updated_at=$2
+	from (
+		SELECT id, RANK() OVER (ORDER BY net_balance DESC) AS rank from summary where month_year=$1
+	) ranked
+	where s.id=ranked.id`
+
+	summaryColumns = `
+	id,
+	user_id,
+	month_year,
+	net_balance,
+	badges_count,
+	rank,
+	contribution_id,
+	created_at,
+	updated_at`
+
+	// a goal is achieved when the month's contributions meet every target
+	// of the user's active goal, counting the default targets and the ones
+	// the user set themselves
+	getAchievedGoalLevelQuery = `
+	SELECT g.level from users u
+	join goal g on g.id=u.current_active_goal_id
+	where u.id=$1
+	and exists (
+		SELECT 1 from goal_contribution gc
+		where gc.goal_id=g.id and (not gc.is_custom or gc.set_by_user_id=u.id)
+	)
+	and not exists (
+		SELECT 1 from goal_contribution gc
+		where gc.goal_id=g.id and (not gc.is_custom or gc.set_by_user_id=u.id)
+		and gc.target_count>(
+			SELECT count(*) from contributions c
+			join contribution_scores cs on cs.contribution_id=c.id
+			where c.user_id=u.id and c.contribution_score_id=gc.contribution_score_id
+			and cs.status<>'voided'
+			and c.contributed_at>=$2 and c.contributed_at<$3
+		)
+	)`
+
+	getSummariesByUserIdQuery = "SELECT" + summaryColumns + " from summary where user_id=$1 order by month_year"
+
+	adjustSummaryNetBalanceQuery = "UPDATE summary SET net_balance=net_balance+$1, updated_at=$2 where user_id=$3 and month_year=$4"
+)
+
+func (sr *summaryRepository) GetUserIdsPendingMonthClose(ctx context.Context, tx *sqlx.Tx, monthYear int, monthStart time.Time, monthEnd time.Time) ([]int, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getUserIdsPendingMonthCloseQuery, monthStart, monthEnd, monthYear)
+	if err != nil {
+		slog.Error("error occurred while listing users pending month close", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	var userIds []int
+	for rows.Next() {
+		var userId int
+		if err := rows.Scan(&userId); err != nil {
+			slog.Error("error occurred while scanning user id", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		userIds = append(userIds, userId)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating users pending month close", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return userIds, nil
+}
+
+// CreditMonthContributions returns the net balance credited and the id of the
+// latest contribution credited.
+func (sr *summaryRepository) CreditMonthContributions(ctx context.Context, tx *sqlx.Tx, userId int, monthStart time.Time, monthEnd time.Time) (int, int, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	var netBalance, lastContributionId int
+	err := executer.QueryRowContext(ctx, creditMonthContributionsQuery, userId, monthStart, monthEnd, time.Now()).Scan(&netBalance, &lastContributionId)
+	if err != nil {
+		slog.Error("error occurred while crediting month contributions", "error", err)
+		return 0, 0, apperrors.ErrInternalServer
+	}
+
+	return netBalance, lastContributionId, nil
+}
+
+// GetAchievedGoalLevel returns the level of the user's active goal and
+// whether the month's contributions achieved it.
+func (sr *summaryRepository) GetAchievedGoalLevel(ctx context.Context, tx *sqlx.Tx, userId int, monthStart time.Time, monthEnd time.Time) (string, bool, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	var level string
+	err := executer.QueryRowContext(ctx, getAchievedGoalLevelQuery, userId, monthStart, monthEnd).Scan(&level)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		slog.Error("error occurred while checking goal achievement", "error", err)
+		return "", false, apperrors.ErrInternalServer
+	}
+
+	return level, true, nil
+}
+
+func (sr *summaryRepository) CreateSummary(ctx context.Context, tx *sqlx.Tx, userId int, monthYear int, netBalance int, lastContributionId int, monthStart time.Time, monthEnd time.Time) error {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, createSummaryQuery, userId, monthYear, netBalance, lastContributionId, monthStart, monthEnd)
+	if err != nil {
+		slog.Error("error occurred while creating summary", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (sr *summaryRepository) RankMonth(ctx context.Context, tx *sqlx.Tx, monthYear int) error {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, rankMonthQuery, monthYear, time.Now())
+	if err != nil {
+		slog.Error("error occurred while ranking month summaries", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (sr *summaryRepository) GetSummariesByUserId(ctx context.Context, tx *sqlx.Tx, userId int) ([]Summary, error) {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getSummariesByUserIdQuery, userId)
+	if err != nil {
+		slog.Error("error occurred while listing summaries", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	summaries := []Summary{}
+	for rows.Next() {
+		summary, err := scanSummary(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning summary", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating summaries", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return summaries, nil
+}
+
+func (sr *summaryRepository) AdjustSummaryNetBalance(ctx context.Context, tx *sqlx.Tx, userId int, monthYear int, amount int) error {
+	executer := sr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, adjustSummaryNetBalanceQuery, amount, time.Now(), userId, monthYear)
+	if err != nil {
+		slog.Error("failed to adjust summary net balance", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func scanSummary(row rowScanner) (Summary, error) {
+	var summary Summary
+	err := row.Scan(
+		&summary.Id,
+		&summary.UserId,
+		&summary.MonthYear,
+		&summary.NetBalance,
+		&summary.BadgesCount,
+		&summary.Rank,
+		&summary.ContributionId,
+		&summary.CreatedAt,
+		&summary.UpdatedAt,
+	)
+
+	return summary, err
+}
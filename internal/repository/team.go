@@ -0,0 +1,584 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type teamRepository struct {
+	BaseRepository
+}
+
+type TeamRepository interface {
+	RepositoryTransaction
+	CreateTeam(ctx context.Context, tx *sqlx.Tx, team Team) (Team, error)
+	GetTeamBySlug(ctx context.Context, tx *sqlx.Tx, slug string) (Team, error)
+	GetTeamByInviteCode(ctx context.Context, tx *sqlx.Tx, inviteCode string) (Team, error)
+	UpdateTeamInviteCode(ctx context.Context, tx *sqlx.Tx, teamId int64, inviteCode string) error
+	AddTeamMember(ctx context.Context, tx *sqlx.Tx, teamId int64, userId int, role string) (TeamMember, error)
+	GetTeamMember(ctx context.Context, tx *sqlx.Tx, teamId int64, userId int) (TeamMember, error)
+	GetTeamOwner(ctx context.Context, tx *sqlx.Tx, teamId int64) (TeamMember, error)
+	GetTeamSuccessor(ctx context.Context, tx *sqlx.Tx, teamId int64) (TeamMember, error)
+	UpdateTeamMemberRole(ctx context.Context, tx *sqlx.Tx, memberId int64, role string) error
+	EndTeamMembership(ctx context.Context, tx *sqlx.Tx, memberId int64, leftAt time.Time) error
+	CountTeamMembers(ctx context.Context, tx *sqlx.Tx, teamId int64) (int, error)
+	ListUserTeams(ctx context.Context, tx *sqlx.Tx, userId int) ([]UserTeam, error)
+	GetTeamPoints(ctx context.Context, tx *sqlx.Tx, teamId int64, from time.Time, to time.Time) (int, error)
+	ListTeamMemberStandings(ctx context.Context, tx *sqlx.Tx, teamId int64, from time.Time, to time.Time) ([]TeamMemberStanding, error)
+	UpsertTeamGoal(ctx context.Context, tx *sqlx.Tx, goal TeamGoal) (TeamGoal, error)
+	GetTeamGoal(ctx context.Context, tx *sqlx.Tx, teamId int64, monthYear int) (TeamGoal, error)
+	ListTeamStandings(ctx context.Context, tx *sqlx.Tx, kind string, limit int, offset int) ([]TeamStanding, error)
+}
+
+func NewTeamRepository(db *sqlx.DB) TeamRepository {
+	return &teamRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	teamColumns = `
+	id,
+	slug,
+	name,
+	description,
+	kind,
+	invite_code,
+	created_by,
+	created_at,
+	updated_at`
+
+	teamMemberColumns = `
+	id,
+	team_id,
+	user_id,
+	role,
+	joined_at,
+	left_at,
+	created_at,
+	updated_at`
+
+	teamGoalColumns = `
+	team_id,
+	month_year,
+	target_points,
+	set_by,
+	created_at,
+	updated_at`
+
+	createTeamQuery = `
+	INSERT INTO teams (
+	slug,
+	name,
+	description,
+	kind,
+	invite_code,
+	created_by
+	)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT DO NOTHING
+	RETURNING` + teamColumns
+
+	getTeamBySlugQuery = "SELECT" + teamColumns + " from teams where slug=lower($1)"
+
+	getTeamByInviteCodeQuery = "SELECT" + teamColumns + " from teams where invite_code=$1"
+
+	updateTeamInviteCodeQuery = "UPDATE teams SET invite_code=$1, updated_at=$2 where id=$3"
+
+	addTeamMemberQuery = `
+	INSERT INTO team_members (
+	team_id,
+	user_id,
+	role,
+	joined_at
+	)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (team_id, user_id) WHERE left_at IS NULL DO NOTHING
+	RETURNING` + teamMemberColumns
+
+	getTeamMemberQuery = "SELECT" + teamMemberColumns + " from team_members where team_id=$1 and user_id=$2 and left_at IS NULL FOR UPDATE"
+
+	getTeamOwnerQuery = "SELECT" + teamMemberColumns + " from team_members where team_id=$1 and role='owner' and left_at IS NULL FOR UPDATE"
+
+	// admins take over before plain members, the longest standing first
+	getTeamSuccessorQuery = `
+	SELECT` + teamMemberColumns + `
+	from team_members
+	where team_id=$1 and left_at IS NULL
+	order by role='admin' desc, joined_at, id
+	limit 1
+	FOR UPDATE`
+	updateTeamMemberRoleQuery = "UPDATE team_members SET role=$1, updated_at=$2 where id=$3 and left_at IS NULL"
+
+	endTeamMembershipQuery = "UPDATE team_members SET left_at=$1, updated_at=$1 where id=$2 and left_at IS NULL"
+
+	countTeamMembersQuery = "SELECT COUNT(*) from team_members where team_id=$1 and left_at IS NULL"
+
+	listUserTeamsQuery = `
+	SELECT
+	t.id,
+	t.slug,
+	t.name,
+	t.description,
+	t.kind,
+	t.invite_code,
+	t.created_by,
+	t.created_at,
+	t.updated_at,
+	m.role,
+	m.joined_at
+	from teams t
+	join team_members m on m.team_id=t.id
+	where m.user_id=$1 and m.left_at IS NULL
+	order by m.joined_at`
+
+	// a contribution counts towards a team only when it was made while its
+	// author was a member, so points earned before leaving stay with the
+	// team and points earned afterwards do not
+	teamContributionsJoin = `
+	join (contributions c join contribution_scores cs on cs.contribution_id=c.id) on c.user_id=m.user_id
+	and cs.status<>'voided'
+	and c.contributed_at>=m.joined_at
+	and (m.left_at IS NULL or c.contributed_at<m.left_at)
+	and c.contributed_at>=$2 and c.contributed_at<$3`
+
+	// blocked and deleted members earn nothing for their teams
+	teamPointsMembersJoin = `
+	join users u on u.id=m.user_id and not u.is_blocked and not u.is_deleted`
+
+	getTeamPointsQuery = `
+	SELECT COALESCE(SUM(cs.balance_change), 0)
+	from team_members m` + teamContributionsJoin + teamPointsMembersJoin + `
+	where m.team_id=$1`
+
+	listTeamMemberStandingsQuery = `
+	SELECT
+	u.id,
+	u.github_username,
+	u.avatar_url,
+	COALESCE(MAX(m.role) FILTER (WHERE m.left_at IS NULL), 'member'),
+	bool_or(m.left_at IS NULL),
+	COUNT(c.id),
+	COALESCE(SUM(cs.balance_change), 0),
+	RANK() OVER (ORDER BY COALESCE(SUM(cs.balance_change), 0) DESC)
+	from team_members m
+	join users u on u.id=m.user_id
+	left` + teamContributionsJoin + `
+	where m.team_id=$1
+	and not u.is_blocked and not u.is_deleted
+	and m.joined_at<$3 and (m.left_at IS NULL or m.left_at>$2)
+	group by u.id, u.github_username, u.avatar_url
+	order by 8, u.id`
+
+	upsertTeamGoalQuery = `
+	INSERT INTO team_goals (
+	team_id,
+	month_year,
+	target_points,
+	set_by
+	)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (team_id, month_year) DO UPDATE SET
+	target_points=EXCLUDED.target_points,
+	set_by=EXCLUDED.set_by,
+	updated_at=$5
+	RETURNING` + teamGoalColumns
+
+	getTeamGoalQuery = "SELECT" + teamGoalColumns + " from team_goals where team_id=$1 and month_year=$2"
+
+	listTeamStandingsQuery = `
+	SELECT l.team_id, t.slug, t.name, t.kind, l.month_year, l.points, l.member_count, l.rank, l.refreshed_at
+	from team_leaderboard_hourly l
+	join teams t on t.id=l.team_id
+	where l.refreshed_at=(SELECT MAX(refreshed_at) from team_leaderboard_hourly)
+	and ($1='' or t.kind=$1)
+	order by l.rank, t.id
+	limit $2 offset $3`
+)
+
+// CreateTeam returns ErrTeamExists when the slug is already taken.
+func (tr *teamRepository) CreateTeam(ctx context.Context, tx *sqlx.Tx, team Team) (Team, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanTeam(executer.QueryRowContext(ctx, createTeamQuery,
+		team.Slug,
+		team.Name,
+		team.Description,
+		team.Kind,
+		team.InviteCode,
+		team.CreatedBy,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Team{}, apperrors.ErrTeamExists
+		}
+		slog.Error("error occurred while creating team", "error", err)
+		return Team{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+func (tr *teamRepository) GetTeamBySlug(ctx context.Context, tx *sqlx.Tx, slug string) (Team, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	team, err := scanTeam(executer.QueryRowContext(ctx, getTeamBySlugQuery, slug))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Team{}, apperrors.ErrTeamNotFound
+		}
+		slog.Error("error occurred while getting team by slug", "error", err)
+		return Team{}, apperrors.ErrInternalServer
+	}
+
+	return team, nil
+}
+
+// GetTeamByInviteCode returns ErrInvalidInviteCode when no team uses the
+// code, including codes that were regenerated since.
+func (tr *teamRepository) GetTeamByInviteCode(ctx context.Context, tx *sqlx.Tx, inviteCode string) (Team, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	team, err := scanTeam(executer.QueryRowContext(ctx, getTeamByInviteCodeQuery, inviteCode))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Team{}, apperrors.ErrInvalidInviteCode
+		}
+		slog.Error("error occurred while getting team by invite code", "error", err)
+		return Team{}, apperrors.ErrInternalServer
+	}
+
+	return team, nil
+}
+
+func (tr *teamRepository) UpdateTeamInviteCode(ctx context.Context, tx *sqlx.Tx, teamId int64, inviteCode string) error {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, updateTeamInviteCodeQuery, inviteCode, time.Now(), teamId)
+	if err != nil {
+		slog.Error("failed to update team invite code", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrTeamNotFound)
+}
+
+// AddTeamMember returns ErrAlreadyTeamMember when the user currently belongs
+// to the team.
+func (tr *teamRepository) AddTeamMember(ctx context.Context, tx *sqlx.Tx, teamId int64, userId int, role string) (TeamMember, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	member, err := scanTeamMember(executer.QueryRowContext(ctx, addTeamMemberQuery, teamId, userId, role, time.Now()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TeamMember{}, apperrors.ErrAlreadyTeamMember
+		}
+		slog.Error("error occurred while adding team member", "error", err)
+		return TeamMember{}, apperrors.ErrInternalServer
+	}
+
+	return member, nil
+}
+
+// GetTeamMember returns the user's current membership locked until tx ends.
+func (tr *teamRepository) GetTeamMember(ctx context.Context, tx *sqlx.Tx, teamId int64, userId int) (TeamMember, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	member, err := scanTeamMember(executer.QueryRowContext(ctx, getTeamMemberQuery, teamId, userId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TeamMember{}, apperrors.ErrTeamMemberNotFound
+		}
+		slog.Error("error occurred while getting team member", "error", err)
+		return TeamMember{}, apperrors.ErrInternalServer
+	}
+
+	return member, nil
+}
+
+// GetTeamOwner returns the owner's membership locked until tx ends.
+func (tr *teamRepository) GetTeamOwner(ctx context.Context, tx *sqlx.Tx, teamId int64) (TeamMember, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	member, err := scanTeamMember(executer.QueryRowContext(ctx, getTeamOwnerQuery, teamId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TeamMember{}, apperrors.ErrTeamMemberNotFound
+		}
+		slog.Error("error occurred while getting team owner", "error", err)
+		return TeamMember{}, apperrors.ErrInternalServer
+	}
+
+	return member, nil
+}
+
+// GetTeamSuccessor returns the current member next in line for ownership,
+// locked until tx ends, or ErrTeamMemberNotFound when the team is empty.
+func (tr *teamRepository) GetTeamSuccessor(ctx context.Context, tx *sqlx.Tx, teamId int64) (TeamMember, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	member, err := scanTeamMember(executer.QueryRowContext(ctx, getTeamSuccessorQuery, teamId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TeamMember{}, apperrors.ErrTeamMemberNotFound
+		}
+		slog.Error("error occurred while getting team successor", "error", err)
+		return TeamMember{}, apperrors.ErrInternalServer
+	}
+
+	return member, nil
+}
+
+func (tr *teamRepository) UpdateTeamMemberRole(ctx context.Context, tx *sqlx.Tx, memberId int64, role string) error {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, updateTeamMemberRoleQuery, role, time.Now(), memberId)
+	if err != nil {
+		slog.Error("failed to update team member role", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrTeamMemberNotFound)
+}
+
+func (tr *teamRepository) EndTeamMembership(ctx context.Context, tx *sqlx.Tx, memberId int64, leftAt time.Time) error {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, endTeamMembershipQuery, leftAt, memberId)
+	if err != nil {
+		slog.Error("failed to end team membership", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrTeamMemberNotFound)
+}
+
+func (tr *teamRepository) CountTeamMembers(ctx context.Context, tx *sqlx.Tx, teamId int64) (int, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	var count int
+	err := executer.QueryRowContext(ctx, countTeamMembersQuery, teamId).Scan(&count)
+	if err != nil {
+		slog.Error("error occurred while counting team members", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return count, nil
+}
+
+func (tr *teamRepository) ListUserTeams(ctx context.Context, tx *sqlx.Tx, userId int) ([]UserTeam, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listUserTeamsQuery, userId)
+	if err != nil {
+		slog.Error("error occurred while listing user teams", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	teams := []UserTeam{}
+	for rows.Next() {
+		var team UserTeam
+		err = rows.Scan(
+			&team.Id,
+			&team.Slug,
+			&team.Name,
+			&team.Description,
+			&team.Kind,
+			&team.InviteCode,
+			&team.CreatedBy,
+			&team.CreatedAt,
+			&team.UpdatedAt,
+			&team.Role,
+			&team.JoinedAt,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning user team", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		teams = append(teams, team)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating user teams", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return teams, nil
+}
+
+// GetTeamPoints sums the points members earned for the team between from
+// and to.
+func (tr *teamRepository) GetTeamPoints(ctx context.Context, tx *sqlx.Tx, teamId int64, from time.Time, to time.Time) (int, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	var points int
+	err := executer.QueryRowContext(ctx, getTeamPointsQuery, teamId, from, to).Scan(&points)
+	if err != nil {
+		slog.Error("error occurred while getting team points", "error", err)
+		return 0, apperrors.ErrInternalServer
+	}
+
+	return points, nil
+}
+
+// ListTeamMemberStandings ranks everyone who was a member at some point
+// between from and to by the points they earned for the team.
+func (tr *teamRepository) ListTeamMemberStandings(ctx context.Context, tx *sqlx.Tx, teamId int64, from time.Time, to time.Time) ([]TeamMemberStanding, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listTeamMemberStandingsQuery, teamId, from, to)
+	if err != nil {
+		slog.Error("error occurred while listing team member standings", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	standings := []TeamMemberStanding{}
+	for rows.Next() {
+		var standing TeamMemberStanding
+		err = rows.Scan(
+			&standing.UserId,
+			&standing.GithubUsername,
+			&standing.AvatarUrl,
+			&standing.Role,
+			&standing.IsMember,
+			&standing.Contributions,
+			&standing.Points,
+			&standing.Rank,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning team member standing", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		standings = append(standings, standing)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating team member standings", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return standings, nil
+}
+
+func (tr *teamRepository) UpsertTeamGoal(ctx context.Context, tx *sqlx.Tx, goal TeamGoal) (TeamGoal, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	saved, err := scanTeamGoal(executer.QueryRowContext(ctx, upsertTeamGoalQuery, goal.TeamId, goal.MonthYear, goal.TargetPoints, goal.SetBy, time.Now()))
+	if err != nil {
+		slog.Error("error occurred while saving team goal", "error", err)
+		return TeamGoal{}, apperrors.ErrInternalServer
+	}
+
+	return saved, nil
+}
+
+func (tr *teamRepository) GetTeamGoal(ctx context.Context, tx *sqlx.Tx, teamId int64, monthYear int) (TeamGoal, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	goal, err := scanTeamGoal(executer.QueryRowContext(ctx, getTeamGoalQuery, teamId, monthYear))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TeamGoal{}, apperrors.ErrTeamGoalNotFound
+		}
+		slog.Error("error occurred while getting team goal", "error", err)
+		return TeamGoal{}, apperrors.ErrInternalServer
+	}
+
+	return goal, nil
+}
+
+// ListTeamStandings reads the latest cross-team leaderboard snapshot, ranked
+// separately for teams and organizations.
+func (tr *teamRepository) ListTeamStandings(ctx context.Context, tx *sqlx.Tx, kind string, limit int, offset int) ([]TeamStanding, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, listTeamStandingsQuery, kind, limit, offset)
+	if err != nil {
+		slog.Error("error occurred while listing team standings", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	standings := []TeamStanding{}
+	for rows.Next() {
+		var standing TeamStanding
+		err = rows.Scan(
+			&standing.TeamId,
+			&standing.Slug,
+			&standing.Name,
+			&standing.Kind,
+			&standing.MonthYear,
+			&standing.Points,
+			&standing.MemberCount,
+			&standing.Rank,
+			&standing.RefreshedAt,
+		)
+		if err != nil {
+			slog.Error("error occurred while scanning team standing", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		standings = append(standings, standing)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating team standings", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return standings, nil
+}
+
+func scanTeam(row rowScanner) (Team, error) {
+	var team Team
+	err := row.Scan(
+		&team.Id,
+		&team.Slug,
+		&team.Name,
+		&team.Description,
+		&team.Kind,
+		&team.InviteCode,
+		&team.CreatedBy,
+		&team.CreatedAt,
+		&team.UpdatedAt,
+	)
+
+	return team, err
+}
+
+func scanTeamMember(row rowScanner) (TeamMember, error) {
+	var member TeamMember
+	err := row.Scan(
+		&member.Id,
+		&member.TeamId,
+		&member.UserId,
+		&member.Role,
+		&member.JoinedAt,
+		&member.LeftAt,
+		&member.CreatedAt,
+		&member.UpdatedAt,
+	)
+
+	return member, err
+}
+
+func scanTeamGoal(row rowScanner) (TeamGoal, error) {
+	var goal TeamGoal
+	err := row.Scan(
+		&goal.TeamId,
+		&goal.MonthYear,
+		&goal.TargetPoints,
+		&goal.SetBy,
+		&goal.CreatedAt,
+		&goal.UpdatedAt,
+	)
+
+	return goal, err
+}
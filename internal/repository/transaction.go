@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type transactionRepository struct {
+	BaseRepository
+}
+
+type TransactionRepository interface {
+	RepositoryTransaction
+	GetTransactionsByUserId(ctx context.Context, tx *sqlx.Tx, userId int) ([]Transaction, error)
+	CreateTransaction(ctx context.Context, tx *sqlx.Tx, transaction Transaction) (Transaction, error)
+	IsContributionCredited(ctx context.Context, tx *sqlx.Tx, contributionId int) (bool, error)
+}
+
+func NewTransactionRepository(db *sqlx.DB) TransactionRepository {
+	return &transactionRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	transactionColumns = `
+	id,
+	user_id,
+	contribution_id,
+	redemption_id,
+	is_redeemed,
+	is_gained,
+	transacted_balance,
+	transacted_at,
+	created_at,
+	updated_at`
+
+	getTransactionsByUserIdQuery = "SELECT" + transactionColumns + " from transactions where user_id=$1 order by transacted_at"
+
+	createTransactionQuery = `
+	INSERT INTO transactions (
+	user_id,
+	contribution_id,
+	redemption_id,
+	is_redeemed,
+	is_gained,
+	transacted_balance,
+	transacted_at
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING` + transactionColumns
+
+	isContributionCreditedQuery = "SELECT exists(SELECT 1 from transactions where contribution_id=$1)"
+)
+
+func (tr *transactionRepository) GetTransactionsByUserId(ctx context.Context, tx *sqlx.Tx, userId int) ([]Transaction, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	rows, err := executer.QueryContext(ctx, getTransactionsByUserIdQuery, userId)
+	if err != nil {
+		slog.Error("error occurred while listing transactions", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+	defer rows.Close()
+
+	transactions := []Transaction{}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			slog.Error("error occurred while scanning transaction", "error", err)
+			return nil, apperrors.ErrInternalServer
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("error occurred while iterating transactions", "error", err)
+		return nil, apperrors.ErrInternalServer
+	}
+
+	return transactions, nil
+}
+
+func (tr *transactionRepository) CreateTransaction(ctx context.Context, tx *sqlx.Tx, transaction Transaction) (Transaction, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	created, err := scanTransaction(executer.QueryRowContext(ctx, createTransactionQuery,
+		transaction.UserId,
+		transaction.ContributionId,
+		transaction.RedemptionId,
+		transaction.IsRedeemed,
+		transaction.IsGained,
+		transaction.TransactedBalance,
+		transaction.TransactedAt,
+	))
+	if err != nil {
+		slog.Error("error occurred while creating transaction", "error", err)
+		return Transaction{}, apperrors.ErrInternalServer
+	}
+
+	return created, nil
+}
+
+// IsContributionCredited reports whether the month close has already paid
+// the contribution into the wallet.
+func (tr *transactionRepository) IsContributionCredited(ctx context.Context, tx *sqlx.Tx, contributionId int) (bool, error) {
+	executer := tr.BaseRepository.initiateQueryExecuter(tx)
+
+	var credited bool
+	err := executer.QueryRowContext(ctx, isContributionCreditedQuery, contributionId).Scan(&credited)
+	if err != nil {
+		slog.Error("error occurred while checking contribution transactions", "error", err)
+		return false, apperrors.ErrInternalServer
+	}
+
+	return credited, nil
+}
+
+func scanTransaction(row rowScanner) (Transaction, error) {
+	var transaction Transaction
+	err := row.Scan(
+		&transaction.Id,
+		&transaction.UserId,
+		&transaction.ContributionId,
+		&transaction.RedemptionId,
+		&transaction.IsRedeemed,
+		&transaction.IsGained,
+		&transaction.TransactedBalance,
+		&transaction.TransactedAt,
+		&transaction.CreatedAt,
+		&transaction.UpdatedAt,
+	)
+
+	return transaction, err
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+	"github.com/lib/pq"
 )
 
 type userRepository struct {
@@ -19,8 +20,19 @@ type UserRepository interface {
 	RepositoryTransaction
 	GetUserById(ctx context.Context, tx *sqlx.Tx, userId int) (User, error)
 	GetUserByGithubId(ctx context.Context, tx *sqlx.Tx, githubId int) (User, error)
+	GetUserByGithubIdIncludingDeleted(ctx context.Context, tx *sqlx.Tx, githubId int) (User, error)
+	GetUserByGithubUsername(ctx context.Context, tx *sqlx.Tx, githubUsername string) (User, error)
 	CreateUser(ctx context.Context, tx *sqlx.Tx, userInfo CreateUserRequestBody) (User, error)
 	UpdateUserEmail(ctx context.Context, tx *sqlx.Tx, userId int, email string) error
+	UpdateUserTimezone(ctx context.Context, tx *sqlx.Tx, userId int, timezone string) error
+	IncrementUserBalance(ctx context.Context, tx *sqlx.Tx, userId int, amount int) error
+	DebitUserBalance(ctx context.Context, tx *sqlx.Tx, userId int, amount int) error
+	GetUserSession(ctx context.Context, tx *sqlx.Tx, userId int, issuedAt time.Time) (UserSession, error)
+	SetUserSponsor(ctx context.Context, tx *sqlx.Tx, userId int, sponsorId int) error
+	RemoveUserFromSponsor(ctx context.Context, tx *sqlx.Tx, userId int, sponsorId int) error
+	SoftDeleteUser(ctx context.Context, tx *sqlx.Tx, userId int) error
+	AnonymizeUser(ctx context.Context, tx *sqlx.Tx, userId int) error
+	SetUserBlocked(ctx context.Context, tx *sqlx.Tx, userId int, blocked bool) error
 }
 
 func NewUserRepository(db *sqlx.DB) UserRepository {
@@ -30,9 +42,30 @@ func NewUserRepository(db *sqlx.DB) UserRepository {
 }
 
 const (
-	getUserByIdQuery = "SELECT * from users where id=$1"
+	userColumns = `
+	id,
+	github_id,
+	github_username,
+	avatar_url,
+	email,
+	current_active_goal_id,
+	current_balance,
+	is_blocked,
+	password,
+	is_deleted,
+	deleted_at,
+	timezone,
+	created_at,
+	updated_at`
 
-	getUserByGithubIdQuery = "SELECT * from users where github_id=$1"
+	// soft-deleted users are excluded from every read
+	getUserByIdQuery = "SELECT" + userColumns + " from users where id=$1 and not is_deleted"
+
+	getUserByGithubIdQuery = "SELECT" + userColumns + " from users where github_id=$1 and not is_deleted"
+
+	getUserByGithubIdIncludingDeletedQuery = "SELECT" + userColumns + " from users where github_id=$1"
+
+	getUserByGithubUsernameQuery = "SELECT" + userColumns + " from users where lower(github_username)=lower($1) and not is_deleted"
 
 	createUserQuery = `
 	INSERT INTO users ( 
@@ -42,31 +75,54 @@ const (
 	avatar_url
 	) 
 	VALUES ($1, $2, $3, $4) 
-	RETURNING *`
+	RETURNING` + userColumns
 
 	updateEmailQuery = "UPDATE users SET email=$1, updated_at=$2 where id=$3"
+
+	updateTimezoneQuery = "UPDATE users SET timezone=$1, updated_at=$2 where id=$3"
+
+	incrementUserBalanceQuery = "UPDATE users SET current_balance=current_balance+$1, updated_at=$2 where id=$3"
+
+	debitUserBalanceQuery = "UPDATE users SET current_balance=current_balance-$1, updated_at=$2 where id=$3 and current_balance>=$1"
+
+	getUserSessionQuery = `
+	SELECT
+	u.sponsor_id,
+	ARRAY(SELECT 'user' UNION SELECT ur.role from user_roles ur where ur.user_id=u.id ORDER BY 1),
+	ARRAY(
+		SELECT DISTINCT rp.permission from role_permissions rp
+		where rp.role='user' or rp.role in (SELECT ur.role from user_roles ur where ur.user_id=u.id)
+		ORDER BY 1
+	)
+	from users u
+	where u.id=$1 and not u.is_deleted
+	and (u.sessions_revoked_at IS NULL or u.sessions_revoked_at<=$2)`
+
+	setUserSponsorQuery = "UPDATE users SET sponsor_id=$1, updated_at=$2 where id=$3 and not is_deleted"
+
+	removeUserFromSponsorQuery = "UPDATE users SET sponsor_id=NULL, updated_at=$1 where id=$2 and sponsor_id=$3"
+
+	softDeleteUserQuery = "UPDATE users SET is_deleted=TRUE, deleted_at=$1, sessions_revoked_at=$1, updated_at=$1 where id=$2 and not is_deleted"
+
+	setUserBlockedQuery = "UPDATE users SET is_blocked=$1, updated_at=$2 where id=$3 and not is_deleted"
+
+	// the github id is negated rather than cleared so it stays unique and
+	// the person can sign up again with the same GitHub account
+	anonymizeUserQuery = `
+	UPDATE users SET
+	github_id=-id,
+	github_username='deleted-user-' || id,
+	avatar_url='',
+	email='',
+	password='',
+	updated_at=$1
+	where id=$2 and is_deleted`
 )
 
 func (ur *userRepository) GetUserById(ctx context.Context, tx *sqlx.Tx, userId int) (User, error) {
 	executer := ur.BaseRepository.initiateQueryExecuter(tx)
 
-	var user User
-	err := executer.QueryRowContext(ctx, getUserByIdQuery, userId).Scan(
-		&user.Id,
-		&user.GithubId,
-		&user.GithubUsername,
-		&user.AvatarUrl,
-		&user.Email,
-		&user.CurrentActiveGoalId,
-		&user.CurrentBalance,
-		&user.IsBlocked,
-		&user.IsAdmin,
-		&user.Password,
-		&user.IsDeleted,
-		&user.DeletedAt,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(executer.QueryRowContext(ctx, getUserByIdQuery, userId))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.Error("user not found", "error", err)
@@ -82,23 +138,7 @@ func (ur *userRepository) GetUserById(ctx context.Context, tx *sqlx.Tx, userId i
 func (ur *userRepository) GetUserByGithubId(ctx context.Context, tx *sqlx.Tx, githubId int) (User, error) {
 	executer := ur.BaseRepository.initiateQueryExecuter(tx)
 
-	var user User
-	err := executer.QueryRowContext(ctx, getUserByGithubIdQuery, githubId).Scan(
-		&user.Id,
-		&user.GithubId,
-		&user.GithubUsername,
-		&user.AvatarUrl,
-		&user.Email,
-		&user.CurrentActiveGoalId,
-		&user.CurrentBalance,
-		&user.IsBlocked,
-		&user.IsAdmin,
-		&user.Password,
-		&user.IsDeleted,
-		&user.DeletedAt,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(executer.QueryRowContext(ctx, getUserByGithubIdQuery, githubId))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.Error("user not found", "error", err)
@@ -111,31 +151,48 @@ func (ur *userRepository) GetUserByGithubId(ctx context.Context, tx *sqlx.Tx, gi
 	return user, nil
 }
 
+// GetUserByGithubIdIncludingDeleted also finds users deleted but not yet
+// anonymized, which still hold their GitHub id.
+func (ur *userRepository) GetUserByGithubIdIncludingDeleted(ctx context.Context, tx *sqlx.Tx, githubId int) (User, error) {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	user, err := scanUser(executer.QueryRowContext(ctx, getUserByGithubIdIncludingDeletedQuery, githubId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return User{}, apperrors.ErrUserNotFound
+		}
+		slog.Error("error occurred while getting user by github id", "error", err)
+		return User{}, apperrors.ErrInternalServer
+	}
+
+	return user, nil
+}
+
+func (ur *userRepository) GetUserByGithubUsername(ctx context.Context, tx *sqlx.Tx, githubUsername string) (User, error) {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	user, err := scanUser(executer.QueryRowContext(ctx, getUserByGithubUsernameQuery, githubUsername))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Error("user not found", "error", err)
+			return User{}, apperrors.ErrUserNotFound
+		}
+		slog.Error("error occurred while getting user by github username", "error", err)
+		return User{}, apperrors.ErrInternalServer
+	}
+
+	return user, nil
+}
+
 func (ur *userRepository) CreateUser(ctx context.Context, tx *sqlx.Tx, userInfo CreateUserRequestBody) (User, error) {
 	executer := ur.BaseRepository.initiateQueryExecuter(tx)
 
-	var user User
-	err := executer.QueryRowContext(ctx, createUserQuery,
+	user, err := scanUser(executer.QueryRowContext(ctx, createUserQuery,
 		userInfo.GithubId,
 		userInfo.GithubUsername,
 		userInfo.Email,
 		userInfo.AvatarUrl,
-	).Scan(
-		&user.Id,
-		&user.GithubId,
-		&user.GithubUsername,
-		&user.AvatarUrl,
-		&user.Email,
-		&user.CurrentActiveGoalId,
-		&user.CurrentBalance,
-		&user.IsBlocked,
-		&user.IsAdmin,
-		&user.Password,
-		&user.IsDeleted,
-		&user.DeletedAt,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	))
 	if err != nil {
 		slog.Error("error occurred while creating user", "error", err)
 		return User{}, apperrors.ErrUserCreationFailed
@@ -156,3 +213,149 @@ func (ur *userRepository) UpdateUserEmail(ctx context.Context, tx *sqlx.Tx, user
 
 	return nil
 }
+
+func (ur *userRepository) UpdateUserTimezone(ctx context.Context, tx *sqlx.Tx, userId int, timezone string) error {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, updateTimezoneQuery, timezone, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to update user timezone", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (ur *userRepository) IncrementUserBalance(ctx context.Context, tx *sqlx.Tx, userId int, amount int) error {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, incrementUserBalanceQuery, amount, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to update user balance", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+// DebitUserBalance takes amount points out of the wallet, returning
+// ErrInsufficientBalance when the user does not have that many.
+func (ur *userRepository) DebitUserBalance(ctx context.Context, tx *sqlx.Tx, userId int, amount int) error {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, debitUserBalanceQuery, amount, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to debit user balance", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrInsufficientBalance)
+}
+
+// GetUserSession returns what the user may do when a token issued at
+// issuedAt still belongs to an active session of the user, and
+// ErrSessionRevoked otherwise.
+func (ur *userRepository) GetUserSession(ctx context.Context, tx *sqlx.Tx, userId int, issuedAt time.Time) (UserSession, error) {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	session := UserSession{UserId: userId}
+	err := executer.QueryRowContext(ctx, getUserSessionQuery, userId, issuedAt).Scan(&session.SponsorId, pq.Array(&session.Roles), pq.Array(&session.Permissions))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return UserSession{}, apperrors.ErrSessionRevoked
+		}
+		slog.Error("error occurred while checking user session", "error", err)
+		return UserSession{}, apperrors.ErrInternalServer
+	}
+
+	return session, nil
+}
+
+// SetUserSponsor makes the user a member of the sponsor organization,
+// replacing any earlier membership.
+func (ur *userRepository) SetUserSponsor(ctx context.Context, tx *sqlx.Tx, userId int, sponsorId int) error {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, setUserSponsorQuery, sponsorId, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to set user sponsor", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrUserNotFound)
+}
+
+// RemoveUserFromSponsor returns ErrUserNotFound when the user is not a
+// member of the sponsor organization.
+func (ur *userRepository) RemoveUserFromSponsor(ctx context.Context, tx *sqlx.Tx, userId int, sponsorId int) error {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, removeUserFromSponsorQuery, time.Now(), userId, sponsorId)
+	if err != nil {
+		slog.Error("failed to remove user from sponsor", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrUserNotFound)
+}
+
+// SoftDeleteUser marks the user deleted and revokes every session issued so far.
+func (ur *userRepository) SoftDeleteUser(ctx context.Context, tx *sqlx.Tx, userId int) error {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, softDeleteUserQuery, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to soft delete user", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrUserNotFound)
+}
+
+// AnonymizeUser scrubs the personal data of a soft-deleted user. Users that
+// are not deleted are left untouched.
+func (ur *userRepository) AnonymizeUser(ctx context.Context, tx *sqlx.Tx, userId int) error {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, anonymizeUserQuery, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to anonymize user", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (ur *userRepository) SetUserBlocked(ctx context.Context, tx *sqlx.Tx, userId int, blocked bool) error {
+	executer := ur.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, setUserBlockedQuery, blocked, time.Now(), userId)
+	if err != nil {
+		slog.Error("failed to update user blocked flag", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrUserNotFound)
+}
+
+func scanUser(row rowScanner) (User, error) {
+	var user User
+	err := row.Scan(
+		&user.Id,
+		&user.GithubId,
+		&user.GithubUsername,
+		&user.AvatarUrl,
+		&user.Email,
+		&user.CurrentActiveGoalId,
+		&user.CurrentBalance,
+		&user.IsBlocked,
+		&user.Password,
+		&user.IsDeleted,
+		&user.DeletedAt,
+		&user.Timezone,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	return user, err
+}
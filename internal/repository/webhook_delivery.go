@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joshsoftware/code-curiosity-2025/internal/pkg/apperrors"
+)
+
+type webhookDeliveryRepository struct {
+	BaseRepository
+}
+
+type WebhookDeliveryRepository interface {
+	RepositoryTransaction
+	CreateWebhookDelivery(ctx context.Context, tx *sqlx.Tx, delivery WebhookDelivery) (WebhookDelivery, error)
+	GetWebhookDeliveryByDeliveryId(ctx context.Context, tx *sqlx.Tx, deliveryId string) (WebhookDelivery, error)
+	MarkWebhookDeliveryProcessing(ctx context.Context, tx *sqlx.Tx, id int) error
+	MarkWebhookDeliveryProcessed(ctx context.Context, tx *sqlx.Tx, id int) error
+	MarkWebhookDeliveryFailed(ctx context.Context, tx *sqlx.Tx, id int, lastError string) error
+	ResetWebhookDelivery(ctx context.Context, tx *sqlx.Tx, deliveryId string) error
+}
+
+func NewWebhookDeliveryRepository(db *sqlx.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		BaseRepository: BaseRepository{db},
+	}
+}
+
+const (
+	webhookDeliveryColumns = `
+	id,
+	delivery_id,
+	event,
+	action,
+	payload,
+	status,
+	attempts,
+	last_error,
+	processed_at,
+	created_at,
+	updated_at`
+
+	createWebhookDeliveryQuery = `
+	INSERT INTO github_webhook_deliveries (
+	delivery_id,
+	event,
+	action,
+	payload
+	)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (delivery_id) DO NOTHING
+	RETURNING` + webhookDeliveryColumns
+
+	getWebhookDeliveryByDeliveryIdQuery = "SELECT" + webhookDeliveryColumns + " from github_webhook_deliveries where delivery_id=$1"
+
+	markWebhookDeliveryProcessingQuery = "UPDATE github_webhook_deliveries SET status='processing', attempts=attempts+1, updated_at=$1 where id=$2"
+
+	markWebhookDeliveryProcessedQuery = "UPDATE github_webhook_deliveries SET status='processed', last_error=NULL, processed_at=$1, updated_at=$1 where id=$2"
+
+	markWebhookDeliveryFailedQuery = "UPDATE github_webhook_deliveries SET status='failed', last_error=$1, updated_at=$2 where id=$3"
+
+	resetWebhookDeliveryQuery = "UPDATE github_webhook_deliveries SET status='pending', attempts=0, updated_at=$1 where delivery_id=$2"
+)
+
+// CreateWebhookDelivery returns ErrWebhookDeliveryAlreadyReceived when GitHub
+// redelivers an event we already stored.
+func (wr *webhookDeliveryRepository) CreateWebhookDelivery(ctx context.Context, tx *sqlx.Tx, deliveryInfo WebhookDelivery) (WebhookDelivery, error) {
+	executer := wr.BaseRepository.initiateQueryExecuter(tx)
+
+	delivery, err := scanWebhookDelivery(executer.QueryRowContext(ctx, createWebhookDeliveryQuery,
+		deliveryInfo.DeliveryId,
+		deliveryInfo.Event,
+		deliveryInfo.Action,
+		deliveryInfo.Payload,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WebhookDelivery{}, apperrors.ErrWebhookDeliveryAlreadyReceived
+		}
+		slog.Error("error occurred while creating webhook delivery", "error", err)
+		return WebhookDelivery{}, apperrors.ErrInternalServer
+	}
+
+	return delivery, nil
+}
+
+func (wr *webhookDeliveryRepository) GetWebhookDeliveryByDeliveryId(ctx context.Context, tx *sqlx.Tx, deliveryId string) (WebhookDelivery, error) {
+	executer := wr.BaseRepository.initiateQueryExecuter(tx)
+
+	delivery, err := scanWebhookDelivery(executer.QueryRowContext(ctx, getWebhookDeliveryByDeliveryIdQuery, deliveryId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WebhookDelivery{}, apperrors.ErrWebhookDeliveryNotFound
+		}
+		slog.Error("error occurred while getting webhook delivery", "error", err)
+		return WebhookDelivery{}, apperrors.ErrInternalServer
+	}
+
+	return delivery, nil
+}
+
+func (wr *webhookDeliveryRepository) MarkWebhookDeliveryProcessing(ctx context.Context, tx *sqlx.Tx, id int) error {
+	executer := wr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markWebhookDeliveryProcessingQuery, time.Now(), id)
+	if err != nil {
+		slog.Error("failed to mark webhook delivery processing", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (wr *webhookDeliveryRepository) MarkWebhookDeliveryProcessed(ctx context.Context, tx *sqlx.Tx, id int) error {
+	executer := wr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markWebhookDeliveryProcessedQuery, time.Now(), id)
+	if err != nil {
+		slog.Error("failed to mark webhook delivery processed", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (wr *webhookDeliveryRepository) MarkWebhookDeliveryFailed(ctx context.Context, tx *sqlx.Tx, id int, lastError string) error {
+	executer := wr.BaseRepository.initiateQueryExecuter(tx)
+
+	_, err := executer.ExecContext(ctx, markWebhookDeliveryFailedQuery, lastError, time.Now(), id)
+	if err != nil {
+		slog.Error("failed to mark webhook delivery failed", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return nil
+}
+
+func (wr *webhookDeliveryRepository) ResetWebhookDelivery(ctx context.Context, tx *sqlx.Tx, deliveryId string) error {
+	executer := wr.BaseRepository.initiateQueryExecuter(tx)
+
+	result, err := executer.ExecContext(ctx, resetWebhookDeliveryQuery, time.Now(), deliveryId)
+	if err != nil {
+		slog.Error("failed to reset webhook delivery", "error", err)
+		return apperrors.ErrInternalServer
+	}
+
+	return requireAffected(result, apperrors.ErrWebhookDeliveryNotFound)
+}
+
+func scanWebhookDelivery(row rowScanner) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := row.Scan(
+		&delivery.Id,
+		&delivery.DeliveryId,
+		&delivery.Event,
+		&delivery.Action,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.LastError,
+		&delivery.ProcessedAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+
+	return delivery, err
+}